@@ -52,6 +52,10 @@ type Action struct {
 	// Redirect
 	RedirectURL      string                // static redirect after action; empty = back to list
 	RedirectResolver func(item any) string // dynamic redirect
+
+	// HandlerFunc runs the action on a record, for row actions posted to
+	// /{slug}/{id}/action/{name}. See Handle.
+	HandlerFunc func(ctx context.Context, item any) error
 }
 
 // New creates a new base action.
@@ -100,6 +104,16 @@ func (a *Action) RequiresDialog(title, desc string) *Action {
 	return a
 }
 
+// RequireConfirmation asks for confirmation in a modal before running the
+// action, titled after its label. Use RequiresDialog for a custom text.
+func (a *Action) RequireConfirmation() *Action {
+	title := a.ModalTitle
+	if title == "" {
+		title = a.Label + "?"
+	}
+	return a.RequiresDialog(title, a.ModalDescription)
+}
+
 // WithConfirmLabels customises the confirm/cancel button labels.
 func (a *Action) WithConfirmLabels(confirm, cancel string) *Action {
 	a.ConfirmLabel = confirm
@@ -107,6 +121,23 @@ func (a *Action) WithConfirmLabels(confirm, cancel string) *Action {
 	return a
 }
 
+// Handle sets the function run on the record when the action is posted.
+// Returning an error flashes the failure message (or the error itself).
+func (a *Action) Handle(fn func(ctx context.Context, item any) error) *Action {
+	a.HandlerFunc = fn
+	a.Type = Button
+	a.Method = "POST"
+	return a
+}
+
+// Run executes the handler set with Handle through the action lifecycle.
+func (a *Action) Run(ctx context.Context, item any) error {
+	if a.HandlerFunc == nil {
+		return fmt.Errorf("action %s has no handler", a.Name)
+	}
+	return a.Execute(ctx, item, func() error { return a.HandlerFunc(ctx, item) })
+}
+
 // Before registers a hook called before the action executes.
 // Return a non-nil error to abort execution.
 func (a *Action) Before(fn func(ctx context.Context, item any) error) *Action {
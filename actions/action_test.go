@@ -1,6 +1,7 @@
 package actions
 
 import (
+	"context"
 	"testing"
 )
 
@@ -141,3 +142,28 @@ func TestSetUrl(t *testing.T) {
 		t.Errorf("Expected '/users/123/edit', got '%s'", url)
 	}
 }
+
+func TestHandle_Run(t *testing.T) {
+	var ran *MockEntity
+	action := New("activate").
+		SetLabel("Activate").
+		RequireConfirmation().
+		Handle(func(_ context.Context, item any) error {
+			ran = item.(*MockEntity)
+			return nil
+		})
+
+	if !action.RequiresConfirmation || action.ModalTitle != "Activate?" {
+		t.Errorf("Expected a confirmation titled 'Activate?', got %v %q", action.RequiresConfirmation, action.ModalTitle)
+	}
+	if action.Method != "POST" {
+		t.Errorf("Expected method 'POST', got '%s'", action.Method)
+	}
+	entity := &MockEntity{ID: 5}
+	if err := action.Run(context.Background(), entity); err != nil || ran != entity {
+		t.Errorf("Expected the handler to run on the entity, got %v", err)
+	}
+	if err := New("noop").Run(context.Background(), entity); err == nil {
+		t.Error("Expected an error for an action without handler")
+	}
+}
@@ -18,4 +18,16 @@
 //		SetUrl(func(item any) string {
 //			return fmt.Sprintf("/users/%s/archive", actions.GetItemID(item))
 //		})
+//
+// Row actions run a handler on the record, posted by the resource list to
+// /{slug}/{id}/action/{name} (see engine.ResourceRowActions):
+//
+//	sendInvoice := actions.New("send-invoice").
+//		SetLabel("Send invoice").
+//		SetIcon("send").
+//		RequireConfirmation().
+//		WithSuccessMessage("Invoice sent").
+//		Handle(func(ctx context.Context, item any) error {
+//			return mailer.SendInvoice(ctx, item.(*ent.Order))
+//		})
 package actions
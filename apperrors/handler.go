@@ -80,14 +80,13 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request, err error) {
 
 	h.logError(r, appErr)
 
-	w.WriteHeader(appErr.StatusCode)
-
 	errorPage := h.getErrorPage(appErr.StatusCode)
 	if errorPage == nil {
 		http.Error(w, appErr.Message, appErr.StatusCode)
 		return
 	}
 
+	w.WriteHeader(appErr.StatusCode)
 	if err := errorPage.Render(r.Context(), w); err != nil {
 		http.Error(w, appErr.Message, appErr.StatusCode)
 	}
@@ -0,0 +1,213 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Actions recorded by the CRUD handlers.
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionDeleted = "deleted"
+)
+
+// Redacted replaces the values of sensitive fields.
+const Redacted = "[redacted]"
+
+// DefaultRedacted lists field names that are always redacted, whatever the
+// record type (compared case-insensitively).
+var DefaultRedacted = []string{"password", "password_confirmation", "token", "secret"}
+
+// Entry is one recorded mutation.
+type Entry struct {
+	ID        int               `json:"id"`
+	ActorID   int               `json:"actor_id,omitempty"`
+	ActorName string            `json:"actor_name,omitempty"`
+	Resource  string            `json:"resource"`
+	RecordID  string            `json:"record_id"`
+	Action    string            `json:"action"`
+	Changes   map[string]Change `json:"changes,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// Change is the old and new value of a field. Old is nil for created
+// records, New for deleted ones.
+type Change struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// Fields returns the changed field names, sorted.
+func (e Entry) Fields() []string {
+	fields := make([]string, 0, len(e.Changes))
+	for f := range e.Changes {
+		fields = append(fields, f)
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// Snapshot returns the auditable fields of a record: the exported scalar
+// fields of a struct (keyed by their JSON name) or the entries of a map.
+// Sensitive fields and the names in redact are kept with a Redacted value,
+// so a change is recorded without its content.
+func Snapshot(record any, redact ...string) map[string]any {
+	out := make(map[string]any)
+	v := reflect.ValueOf(record)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return out
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			sf := t.Field(i)
+			if !sf.IsExported() || sf.Tag.Get("audit") == "-" {
+				continue
+			}
+			name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+			sensitive := name == "-" || sf.Tag.Get("audit") == "redact"
+			if name == "" || name == "-" {
+				name = toSnake(sf.Name)
+			}
+			value, ok := scalar(v.Field(i))
+			if !ok {
+				continue
+			}
+			if sensitive || isRedacted(name, redact) {
+				value = redactValue(value)
+			}
+			out[name] = value
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return out
+		}
+		for _, k := range v.MapKeys() {
+			value, ok := scalar(v.MapIndex(k))
+			if !ok {
+				continue
+			}
+			if isRedacted(k.String(), redact) {
+				value = redactValue(value)
+			}
+			out[k.String()] = value
+		}
+	}
+	return out
+}
+
+// Diff returns the fields whose value differs between two snapshots. Pass
+// a nil before for created records and a nil after for deleted ones.
+func Diff(before, after map[string]any) map[string]Change {
+	changes := make(map[string]Change)
+	for k, old := range before {
+		nv, ok := after[k]
+		if !ok && after != nil {
+			continue
+		}
+		if after == nil || !equal(old, nv) {
+			changes[k] = Change{Old: old, New: nv}
+		}
+	}
+	for k, nv := range after {
+		if _, ok := before[k]; !ok {
+			changes[k] = Change{New: nv}
+		}
+	}
+	return changes
+}
+
+// equal compares snapshot values. Redacted values compare by the hash of
+// their hidden content.
+func equal(a, b any) bool {
+	if ra, ok := a.(redacted); ok {
+		rb, ok := b.(redacted)
+		return ok && ra.hash == rb.hash
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// redacted stands for a hidden value. It keeps a hash so that Diff can tell
+// whether the value changed, and prints and marshals as Redacted.
+type redacted struct{ hash [sha256.Size]byte }
+
+func (redacted) String() string { return Redacted }
+
+func (redacted) MarshalJSON() ([]byte, error) { return json.Marshal(Redacted) }
+
+func redactValue(v any) any {
+	if v == nil || v == "" {
+		return v
+	}
+	return redacted{hash: sha256.Sum256([]byte(fmt.Sprint(v)))}
+}
+
+func isRedacted(name string, redact []string) bool {
+	for _, r := range slices.Concat(DefaultRedacted, redact) {
+		if strings.EqualFold(name, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// scalar returns the value of strings, numbers, booleans, times and slices
+// of those; ok is false for anything else (edges, nested structs...).
+func scalar(v reflect.Value) (any, bool) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, true
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return v.Interface(), true
+	case reflect.Struct:
+		if t, ok := v.Interface().(time.Time); ok {
+			return t.Format(time.RFC3339), true
+		}
+	case reflect.Slice:
+		if v.Len() == 1 {
+			return scalar(v.Index(0))
+		}
+		items := make([]any, 0, v.Len())
+		for i := range v.Len() {
+			item, ok := scalar(v.Index(i))
+			if !ok {
+				return nil, false
+			}
+			items = append(items, item)
+		}
+		return items, true
+	}
+	return nil, false
+}
+
+// toSnake converts a Go field name to snake_case (CreatedAt => created_at).
+func toSnake(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 && !(s[i-1] >= 'A' && s[i-1] <= 'Z') {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package audit_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/bozz33/sublimego/audit"
+	"github.com/bozz33/sublimego/internal/ent"
+	_ "modernc.org/sqlite"
+)
+
+func TestDiff_RedactsSensitiveFields(t *testing.T) {
+	before := audit.Snapshot(&ent.User{ID: 1, Name: "Ada", Email: "ada@example.com", Password: "hash-1"})
+	after := audit.Snapshot(&ent.User{ID: 1, Name: "Ada L.", Email: "ada@example.com", Password: "hash-2"})
+
+	changes := audit.Diff(before, after)
+	if len(changes) != 2 {
+		t.Fatalf("expected name and password to change, got %v", changes)
+	}
+	if c := changes["name"]; c.Old != "Ada" || c.New != "Ada L." {
+		t.Errorf("unexpected name change %+v", c)
+	}
+
+	b, err := json.Marshal(changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]audit.Change
+	_ = json.Unmarshal(b, &decoded)
+	if c := decoded["password"]; c.Old != audit.Redacted || c.New != audit.Redacted {
+		t.Errorf("expected the password to be redacted, got %+v", c)
+	}
+
+	form := audit.Snapshot(map[string][]string{"email": {"a@b.c"}, "api_key": {"k"}}, "api_key")
+	if form["email"] != "a@b.c" {
+		t.Errorf("expected form values to be flattened, got %v", form)
+	}
+	if b, _ := json.Marshal(form["api_key"]); string(b) != `"`+audit.Redacted+`"` {
+		t.Errorf("expected api_key to be redacted, got %s", b)
+	}
+}
+
+func TestEntStore_History(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+t.Name()+"?mode=memory&cache=shared&_pragma=foreign_keys(1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := ent.NewClient(ent.Driver(entsql.OpenDB("sqlite3", db)))
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+	if err := client.Schema.Create(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	store := audit.NewEntStore(client)
+	entries := []audit.Entry{
+		{ActorID: 7, ActorName: "Ada", Resource: "users", RecordID: "1", Action: audit.ActionCreated,
+			Changes: map[string]audit.Change{"name": {New: "Linus"}}},
+		{ActorID: 7, Resource: "users", RecordID: "1", Action: audit.ActionUpdated,
+			Changes: map[string]audit.Change{"name": {Old: "Linus", New: "Linus T."}}},
+		{Resource: "users", RecordID: "2", Action: audit.ActionDeleted},
+	}
+	for _, e := range entries {
+		if err := store.Record(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err := store.History(ctx, "users", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].Action != audit.ActionUpdated {
+		t.Fatalf("expected 2 entries newest first, got %+v", history)
+	}
+	if c := history[0].Changes["name"]; c.Old != "Linus" || c.New != "Linus T." || history[1].ActorName != "Ada" {
+		t.Errorf("unexpected entries %+v", history)
+	}
+}
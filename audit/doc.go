@@ -0,0 +1,20 @@
+// Package audit records who created, updated or deleted which record and
+// what changed.
+//
+// The engine's CRUD handlers call an auditor after every mutation when the
+// panel has one (see engine.Panel.WithAuditor). Each Entry holds the actor,
+// the resource slug, the record ID and the changed fields with their old and
+// new values. Sensitive fields are redacted: fields tagged json:"-" (the tag
+// Ent generates for Sensitive() fields such as User.password), fields tagged
+// audit:"redact", and the names listed in DefaultRedacted.
+//
+// Basic usage:
+//
+//	store := audit.NewEntStore(client)
+//	panel.WithAuditor(store)
+//
+//	// Read the history of a record
+//	entries, err := store.History(ctx, "users", "42")
+//
+// The history of a record is rendered at {panel}/{slug}/{id}/history.
+package audit
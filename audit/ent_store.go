@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bozz33/sublimego/internal/ent"
+	entaudit "github.com/bozz33/sublimego/internal/ent/auditlog"
+)
+
+// EntStore persists audit entries in the audit_logs table through Ent.
+type EntStore struct {
+	db *ent.Client
+}
+
+// NewEntStore creates an EntStore using the provided Ent client.
+func NewEntStore(db *ent.Client) *EntStore {
+	return &EntStore{db: db}
+}
+
+// Record stores an entry. Changes are saved as a JSON object.
+func (s *EntStore) Record(ctx context.Context, e Entry) error {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	create := s.db.AuditLog.Create().
+		SetResource(e.Resource).
+		SetRecordID(e.RecordID).
+		SetAction(e.Action).
+		SetCreatedAt(e.CreatedAt)
+	if e.ActorID != 0 {
+		create.SetActorID(e.ActorID)
+	}
+	if e.ActorName != "" {
+		create.SetActorName(e.ActorName)
+	}
+	if len(e.Changes) > 0 {
+		changes, err := json.Marshal(e.Changes)
+		if err != nil {
+			return fmt.Errorf("audit: failed to encode changes: %w", err)
+		}
+		create.SetChanges(string(changes))
+	}
+	if _, err := create.Save(ctx); err != nil {
+		return fmt.Errorf("audit: failed to save entry: %w", err)
+	}
+	return nil
+}
+
+// History returns the entries of a record, newest first.
+func (s *EntStore) History(ctx context.Context, resource, recordID string) ([]Entry, error) {
+	rows, err := s.db.AuditLog.Query().
+		Where(entaudit.Resource(resource), entaudit.RecordID(recordID)).
+		Order(ent.Desc(entaudit.FieldCreatedAt), ent.Desc(entaudit.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("audit: failed to load history: %w", err)
+	}
+	entries := make([]Entry, len(rows))
+	for i, row := range rows {
+		entries[i] = Entry{
+			ID:        row.ID,
+			ActorID:   row.ActorID,
+			ActorName: row.ActorName,
+			Resource:  row.Resource,
+			RecordID:  row.RecordID,
+			Action:    row.Action,
+			CreatedAt: row.CreatedAt,
+		}
+		if row.Changes != "" {
+			if err := json.Unmarshal([]byte(row.Changes), &entries[i].Changes); err != nil {
+				return nil, fmt.Errorf("audit: failed to decode changes of entry %d: %w", row.ID, err)
+			}
+		}
+	}
+	return entries, nil
+}
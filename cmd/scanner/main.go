@@ -40,7 +40,7 @@ func main() {
 		log.Fatalf("Scan failed: %s", result.Message)
 	}
 
-	if len(result.Resources) == 0 {
+	if len(result.Resources) == 0 && len(result.Pages) == 0 {
 		fmt.Println("No resources found")
 		os.Exit(0)
 	}
@@ -52,6 +52,13 @@ func main() {
 		}
 	}
 
+	fmt.Printf("Found %d page(s)\n", len(result.Pages))
+	if *verbose {
+		for _, page := range result.Pages {
+			fmt.Printf("   - %s.%s (slug: %s)\n", page.PackageName, page.TypeName, page.Slug)
+		}
+	}
+
 	// Display conflicts
 	if len(result.Conflicts) > 0 {
 		detector := scanner.NewDetector(result.Resources)
@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bozz33/sublimego/engine"
+	"github.com/bozz33/sublimego/export"
+	importer "github.com/bozz33/sublimego/import"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat string
+	exportOutput string
+	importFormat string
+)
+
+// DATA:EXPORT - Exporte les enregistrements d'une resource
+
+var dataExportCmd = &cobra.Command{
+	Use:   "data:export <resource>",
+	Short: "Export the records of a resource",
+	Long: `Export every record of a resource, with the same columns as the
+panel's export, to a CSV or Excel file.
+
+The resource is looked up by slug among the panels registered with
+engine.Register.` + dataEmbedHelp,
+	Example: `  # Export users as CSV on stdout
+  sublimego data:export users --format=csv
+
+  # Backup products to an Excel file
+  sublimego data:export products --format=xlsx -o products.xlsx`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		res, err := findDataResource(args[0])
+		if err != nil {
+			return err
+		}
+
+		format := export.Format(strings.ToLower(exportFormat))
+		if format != export.FormatCSV && format != export.FormatExcel {
+			return fmt.Errorf("unsupported export format %q (csv or xlsx)", exportFormat)
+		}
+
+		var w io.Writer = cmd.OutOrStdout()
+		if exportOutput != "" {
+			f, err := os.Create(exportOutput)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", exportOutput, err)
+			}
+			defer f.Close()
+			w = f
+		} else if format == export.FormatExcel {
+			return fmt.Errorf("xlsx exports need an output file (--output)")
+		}
+
+		n, err := engine.ExportResource(context.Background(), res, w, format)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d record(s) from %s\n", n, res.Slug())
+		return nil
+	},
+}
+
+// DATA:IMPORT - Importe des enregistrements dans une resource
+
+var dataImportCmd = &cobra.Command{
+	Use:   "data:import <resource> <file>",
+	Short: "Import records into a resource",
+	Long: `Import records from a CSV, Excel or JSON file into a resource, e.g. a
+file written by data:export.
+
+Rows are passed to the resource's ImportRow when it implements
+engine.ResourceImportable, otherwise to its Create method as form values.
+The format is detected from the file extension unless --format is given.` + dataEmbedHelp,
+	Example: `  # Restore users from a backup
+  sublimego data:import users users.csv
+
+  # Import a JSON file with an unusual extension
+  sublimego data:import products dump.txt --format=json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		res, err := findDataResource(args[0])
+		if err != nil {
+			return err
+		}
+
+		path := args[1]
+		format := importer.Format(strings.ToLower(importFormat))
+		if importFormat == "" {
+			format = importer.Format(strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), "."))
+		}
+		switch format {
+		case importer.FormatCSV, importer.FormatExcel, importer.FormatJSON:
+		default:
+			return fmt.Errorf("unsupported import format %q (csv, xlsx or json)", format)
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		defer f.Close()
+
+		cfg := importer.DefaultConfig()
+		cfg.Format = format
+		result, err := engine.ImportResource(context.Background(), res, f, cfg)
+		if err != nil {
+			return fmt.Errorf("import failed: %w", err)
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Imported %s in %s\n", res.Slug(), result.Duration.Round(time.Millisecond))
+		fmt.Fprintf(out, "  Imported: %d\n", result.SuccessCount)
+		fmt.Fprintf(out, "  Skipped:  %d\n", result.SkippedCount)
+		fmt.Fprintf(out, "  Errors:   %d\n", result.ErrorCount)
+		for _, re := range result.RowErrors {
+			fmt.Fprintf(out, "  line %d: %v\n", re.Line, re.Err)
+		}
+		if result.ErrorCount > 0 {
+			return fmt.Errorf("%d row(s) failed", result.ErrorCount)
+		}
+		return nil
+	},
+}
+
+// dataEmbedHelp explains how the data commands see the application's
+// resources: the stock binary knows none of them.
+const dataEmbedHelp = `
+
+The sublimego binary itself registers no panel: run the command from the
+application's own CLI, which registers its panels before executing the
+SublimeGo commands:
+
+  func main() {
+      engine.Register(app.NewAdminPanel())
+      if err := commands.Execute(); err != nil {
+          log.Fatal(err)
+      }
+  }`
+
+// findDataResource returns the registered resource with the given slug.
+func findDataResource(slug string) (engine.Resource, error) {
+	if res := engine.FindResource(slug); res != nil {
+		return res, nil
+	}
+	if len(engine.All()) == 0 {
+		return nil, fmt.Errorf("resource %q not found: no panel is registered; run data commands from an application that calls engine.Register before commands.Execute", slug)
+	}
+	return nil, fmt.Errorf("resource %q not found in the registered panels", slug)
+}
+
+func init() {
+	dataExportCmd.Flags().StringVarP(&exportFormat, "format", "f", "csv", "export format (csv or xlsx)")
+	dataExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "output file (default: stdout)")
+	dataImportCmd.Flags().StringVarP(&importFormat, "format", "f", "", "file format (csv, xlsx or json; default: from the extension)")
+}
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bozz33/sublimego/engine"
+	"github.com/bozz33/sublimego/table"
+)
+
+type contact struct {
+	ID   int
+	Name string
+}
+
+type contactResource struct {
+	*engine.SimpleResource
+}
+
+func (contactResource) Columns(context.Context) []table.Column {
+	return []table.Column{table.Text("Name")}
+}
+
+// TestDataExport_EmbeddedPanel runs data:export as an application embedding
+// the CLI would: its panels are registered before the command executes.
+func TestDataExport_EmbeddedPanel(t *testing.T) {
+	if _, err := findDataResource("cli-contacts"); err == nil || !strings.Contains(err.Error(), "engine.Register") {
+		t.Fatalf("expected a hint to register the panels, got %v", err)
+	}
+
+	contacts := contactResource{engine.NewSimpleResource("cli-contacts", "Contact", "Contacts").
+		WithList(func(context.Context) ([]any, error) {
+			return []any{contact{ID: 1, Name: "Ada"}, contact{ID: 2, Name: "Grace"}}, nil
+		})}
+	engine.Register(engine.NewPanel("cli-test").WithPath("/admin").AddResources(contacts))
+
+	var out, status bytes.Buffer
+	dataExportCmd.SetOut(&out)
+	dataExportCmd.SetErr(&status)
+	t.Cleanup(func() {
+		dataExportCmd.SetOut(nil)
+		dataExportCmd.SetErr(nil)
+	})
+	if err := dataExportCmd.RunE(dataExportCmd, []string{"cli-contacts"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Ada") || !strings.Contains(out.String(), "Grace") {
+		t.Errorf("expected both contacts in the CSV, got %q", out.String())
+	}
+	if got := status.String(); got != "Exported 2 record(s) from cli-contacts\n" {
+		t.Errorf("unexpected status line %q", got)
+	}
+
+	if _, err := findDataResource("cli-orders"); err == nil || !strings.Contains(err.Error(), "registered panels") {
+		t.Errorf("expected an unknown resource error, got %v", err)
+	}
+}
@@ -70,7 +70,7 @@ Cette commande doit être exécutée :
 			return fmt.Errorf("scan failed: %s", result.Message)
 		}
 
-		if len(result.Resources) == 0 {
+		if len(result.Resources) == 0 && len(result.Pages) == 0 && len(result.Widgets) == 0 {
 			fmt.Println("No resources found.")
 			fmt.Println()
 			fmt.Println("Make sure you have resources in internal/resources/")
@@ -88,6 +88,22 @@ Cette commande doit être exécutée :
 		}
 		fmt.Println()
 
+		if len(result.Pages) > 0 {
+			fmt.Printf("📄 Discovered %d page(s):\n", len(result.Pages))
+			for _, m := range result.Pages {
+				fmt.Printf("  - %s.%s (slug: %s)\n", m.PackageName, m.TypeName, m.Slug)
+			}
+			fmt.Println()
+		}
+
+		if len(result.Widgets) > 0 {
+			fmt.Printf("📊 Discovered %d widget(s):\n", len(result.Widgets))
+			for _, m := range result.Widgets {
+				fmt.Printf("  - %s.%s\n", m.PackageName, m.TypeName)
+			}
+			fmt.Println()
+		}
+
 		// Afficher les conflits détectés
 		if len(result.Conflicts) > 0 {
 			detector := scanner.NewDetector(result.Resources)
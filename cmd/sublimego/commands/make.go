@@ -26,6 +26,8 @@ var (
 	skipFlag     []string
 	noBackupFlag bool
 	verboseFlag  bool
+	slugFlag     string
+	schemaFlag   string
 )
 
 var makeResourceCmd = &cobra.Command{
@@ -40,17 +42,26 @@ var makeResourceCmd = &cobra.Command{
 
 Flags:
   --force      Overwrite existing files
-  --dry-run    Preview without creating files
+  --dry-run    Print the files as new, modified or skipped with a diff,
+               without writing them (fails if --force would overwrite one)
   --skip       Skip specific files (resource,schema,table,form)
-  --no-backup  Disable automatic backups
+  --no-backup  Do not copy files overwritten with --force to {file}.bak.{timestamp}
   --verbose    Show detailed output
+  --slug       URL slug (default: the pluralized name)
+  --from-schema  Generate the resource of an existing Ent schema file, with
+               its form fields, table columns and relation managers
 
 Example: sublimego make:resource Product
-Example: sublimego make:resource Product --force --skip=form`,
+Example: sublimego make:resource Product --force --skip=form
+Example: sublimego make:resource --from-schema internal/ent/schema/user.go`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var name string
 
+		if schemaFlag != "" {
+			return makeResourceFromSchema()
+		}
+
 		// Mode interactif si aucun argument fourni
 		if len(args) == 0 {
 			fmt.Print("Nom de la resource (ex: Product): ")
@@ -67,11 +78,11 @@ Example: sublimego make:resource Product --force --skip=form`,
 
 		// Créer le générateur avec options
 		g, err := generator.New(&generator.Options{
-			Force:    forceFlag,
-			DryRun:   dryRunFlag,
-			Skip:     skipFlag,
-			NoBackup: noBackupFlag,
-			Verbose:  verboseFlag,
+			Force:   forceFlag,
+			DryRun:  dryRunFlag,
+			Skip:    skipFlag,
+			Backup:  !noBackupFlag,
+			Verbose: verboseFlag,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to create generator: %w", err)
@@ -85,9 +96,12 @@ Example: sublimego make:resource Product --force --skip=form`,
 		fmt.Printf("Génération de la resource: %s\n", name)
 
 		// Générer la resource complète
-		if err := generator.GenerateResource(g, name, "."); err != nil {
+		if err := generator.GenerateResourceWithSlug(g, name, slugFlag, "."); err != nil {
 			return fmt.Errorf("generation failed: %w", err)
 		}
+		if err := dryRunResult(g); err != nil {
+			return err
+		}
 
 		if !dryRunFlag {
 			fmt.Printf("\nResource '%s' générée avec succès\n", name)
@@ -101,6 +115,38 @@ Example: sublimego make:resource Product --force --skip=form`,
 	},
 }
 
+// makeResourceFromSchema génère la resource d'un schéma Ent existant
+func makeResourceFromSchema() error {
+	g, err := generator.New(&generator.Options{
+		Force:   forceFlag,
+		DryRun:  dryRunFlag,
+		Backup:  !noBackupFlag,
+		Verbose: verboseFlag,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create generator: %w", err)
+	}
+
+	if dryRunFlag {
+		fmt.Println("Dry-run mode: no files will be generated")
+	}
+
+	if err := generator.GenerateResourceFromSchema(g, schemaFlag, "."); err != nil {
+		return fmt.Errorf("generation failed: %w", err)
+	}
+	if err := dryRunResult(g); err != nil {
+		return err
+	}
+
+	if !dryRunFlag {
+		fmt.Printf("\nResource générée depuis le schéma '%s'\n", schemaFlag)
+		fmt.Printf("\nProchaines étapes:\n")
+		fmt.Printf("   1. Compléter les TODO de Create et Update\n")
+		fmt.Printf("   2. Exécuter: sublimego generate (auto-discovery)\n")
+	}
+	return nil
+}
+
 // MAKE:MIGRATION - Génère une migration de base de données
 
 var makeMigrationCmd = &cobra.Command{
@@ -109,6 +155,9 @@ var makeMigrationCmd = &cobra.Command{
 	Short:   "Generate a new database migration",
 	Long: `Generate a versioned SQL migration file.
 
+The SQL is generated from the diff between the Ent schemas and the
+snapshot saved by the previous migration (migrations/schema_state.json).
+
 Example: sublimego make:migration add_status_to_users`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -133,7 +182,7 @@ Example: sublimego make:migration add_status_to_users`,
 		}
 
 		fmt.Printf("Migration créée: %s\n", name)
-		fmt.Printf("Éditez le fichier pour ajouter vos changements SQL\n")
+		fmt.Printf("Vérifiez le SQL généré depuis les schémas Ent avant de l'appliquer\n")
 
 		return nil
 	},
@@ -176,6 +225,148 @@ Example: sublimego make:seeder UserSeeder`,
 	},
 }
 
+// MAKE:RELATION - Génère un relation manager pour une resource
+
+var relationTypeFlag string
+
+var makeRelationCmd = &cobra.Command{
+	Use:     "relation [resource] [relation]",
+	Aliases: []string{"rel"},
+	Short:   "Generate a relation manager for a resource",
+	Long: `Generate a relation manager ({relation}_relation.go) in the resource
+package and register it in the resource's GetRelationManagers.
+
+Flags:
+  --type     Relation type: belongs_to, has_one, has_many, many_to_many (default: has_many)
+  --force    Overwrite existing files
+  --dry-run  Preview without creating files
+
+Example: sublimego make:relation Post comments
+Example: sublimego make:relation Post tags --type many_to_many`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		g, err := generator.New(&generator.Options{
+			Force:   forceFlag,
+			DryRun:  dryRunFlag,
+			Verbose: verboseFlag,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create generator: %w", err)
+		}
+
+		if err := generator.GenerateRelationManager(g, args[0], args[1], relationTypeFlag); err != nil {
+			return fmt.Errorf("generation failed: %w", err)
+		}
+		if err := dryRunResult(g); err != nil {
+			return err
+		}
+
+		if !dryRunFlag {
+			fmt.Printf("Relation manager '%s' généré pour la resource '%s'\n", args[1], args[0])
+			fmt.Printf("\nProchaines étapes:\n")
+			fmt.Printf("   1. Vérifier que l'edge '%s' existe dans le schéma Ent\n", args[1])
+			fmt.Printf("   2. Compléter les colonnes et les TODO du fichier généré\n")
+		}
+
+		return nil
+	},
+}
+
+// MAKE:PAGE / MAKE:WIDGET - Génèrent une page personnalisée ou un widget
+
+var (
+	pageGroupFlag string
+	pageIconFlag  string
+	pageSortFlag  int
+)
+
+var makePageCmd = &cobra.Command{
+	Use:     "page [name]",
+	Aliases: []string{"p"},
+	Short:   "Generate a custom page",
+	Long: `Generate a custom page under internal/pages/{name}:
+- Page file (page.go)
+- Templ content (content.templ)
+
+The page is registered by the next 'sublimego generate'.
+
+Example: sublimego make:page Settings
+Example: sublimego make:page Analytics --group Reports --icon chart --sort 50`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		g, err := generator.New(&generator.Options{
+			Force:   forceFlag,
+			DryRun:  dryRunFlag,
+			Backup:  !noBackupFlag,
+			Verbose: verboseFlag,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create generator: %w", err)
+		}
+
+		if err := generator.GeneratePageWithOptions(g, args[0], ".", pageGroupFlag, pageIconFlag, pageSortFlag); err != nil {
+			return fmt.Errorf("generation failed: %w", err)
+		}
+		if err := dryRunResult(g); err != nil {
+			return err
+		}
+
+		if !dryRunFlag {
+			fmt.Printf("Page '%s' générée avec succès\n", args[0])
+		}
+		return nil
+	},
+}
+
+var makeWidgetCmd = &cobra.Command{
+	Use:     "widget [name]",
+	Aliases: []string{"w"},
+	Short:   "Generate a dashboard widget",
+	Long: `Generate a dashboard widget provider under internal/widgets/{name}.
+
+The widget is registered by the next 'sublimego generate'.
+
+Example: sublimego make:widget Sales`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		g, err := generator.New(&generator.Options{
+			Force:   forceFlag,
+			DryRun:  dryRunFlag,
+			Backup:  !noBackupFlag,
+			Verbose: verboseFlag,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create generator: %w", err)
+		}
+
+		if err := generator.GenerateWidget(g, args[0], "."); err != nil {
+			return fmt.Errorf("generation failed: %w", err)
+		}
+		return dryRunResult(g)
+	},
+}
+
+// dryRunResult affiche le bilan d'un dry-run et échoue si --force écraserait
+// des fichiers existants
+func dryRunResult(g *generator.Generator) error {
+	if !dryRunFlag {
+		return nil
+	}
+
+	counts := make(map[generator.ChangeStatus]int)
+	for _, c := range g.Changes() {
+		counts[c.Status]++
+	}
+	fmt.Printf("\nDry-run: %d new, %d modified, %d unchanged, %d skipped\n",
+		counts[generator.ChangeNew], counts[generator.ChangeModified],
+		counts[generator.ChangeUnchanged], counts[generator.ChangeSkipped])
+
+	if overwrites := g.Overwrites(); forceFlag && len(overwrites) > 0 {
+		return fmt.Errorf("dry-run: --force would overwrite %d existing file(s)", len(overwrites))
+	}
+	return nil
+}
+
 // Fonctions utilitaires supprimées - maintenant dans pkg/generator
 
 func init() {
@@ -185,8 +376,27 @@ func init() {
 	makeResourceCmd.Flags().StringSliceVar(&skipFlag, "skip", []string{}, "Skip specific files (resource,schema,table,form)")
 	makeResourceCmd.Flags().BoolVar(&noBackupFlag, "no-backup", false, "Disable automatic backups")
 	makeResourceCmd.Flags().BoolVarP(&verboseFlag, "verbose", "v", false, "Show detailed output")
+	makeResourceCmd.Flags().StringVar(&slugFlag, "slug", "", "URL slug of the resource (default: the pluralized name)")
+	makeResourceCmd.Flags().StringVar(&schemaFlag, "from-schema", "", "Ent schema file to generate the resource from")
+
+	makeRelationCmd.Flags().StringVar(&relationTypeFlag, "type", "has_many", "Relation type (belongs_to, has_one, has_many, many_to_many)")
+	makeRelationCmd.Flags().BoolVar(&forceFlag, "force", false, "Overwrite existing files")
+	makeRelationCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "Preview without creating files")
+
+	for _, c := range []*cobra.Command{makePageCmd, makeWidgetCmd} {
+		c.Flags().BoolVar(&forceFlag, "force", false, "Overwrite existing files")
+		c.Flags().BoolVar(&dryRunFlag, "dry-run", false, "Preview without creating files")
+		c.Flags().BoolVar(&noBackupFlag, "no-backup", false, "Disable automatic backups")
+		c.Flags().BoolVarP(&verboseFlag, "verbose", "v", false, "Show detailed output")
+	}
+	makePageCmd.Flags().StringVar(&pageGroupFlag, "group", "", "Navigation group")
+	makePageCmd.Flags().StringVar(&pageIconFlag, "icon", "", "Navigation icon")
+	makePageCmd.Flags().IntVar(&pageSortFlag, "sort", 100, "Navigation sort order")
 
 	makeCmd.AddCommand(makeResourceCmd)
+	makeCmd.AddCommand(makePageCmd)
+	makeCmd.AddCommand(makeWidgetCmd)
+	makeCmd.AddCommand(makeRelationCmd)
 	makeCmd.AddCommand(makeMigrationCmd)
 	makeCmd.AddCommand(makeSeederCmd)
 }
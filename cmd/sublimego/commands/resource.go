@@ -73,6 +73,13 @@ Utile pour :
 			fmt.Printf("%d. %s\n", i+1, m.TypeName)
 			fmt.Printf("   Package: %s\n", m.PackageName)
 			fmt.Printf("   Slug:    %s\n", m.Slug)
+			fmt.Printf("   Label:   %s\n", m.Label)
+			if m.Group != "" {
+				fmt.Printf("   Group:   %s\n", m.Group)
+			}
+			if m.Icon != "" {
+				fmt.Printf("   Icon:    %s\n", m.Icon)
+			}
 
 			if verbose {
 				fmt.Printf("   File:    %s\n", m.FilePath)
@@ -95,6 +95,8 @@ func init() {
 	rootCmd.AddCommand(routesCmd)
 	rootCmd.AddCommand(generateCmd)
 	rootCmd.AddCommand(resourceCmd)
+	rootCmd.AddCommand(dataExportCmd)
+	rootCmd.AddCommand(dataImportCmd)
 }
 
 // GetConfig retourne la configuration chargée
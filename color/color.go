@@ -14,26 +14,70 @@ type Color struct{}
 // Hex generates a full color palette (50-950) from a hex color code.
 // Example: Color{}.Hex("#3b82f6") returns a blue palette.
 func (Color) Hex(hex string) *Palette {
-	hex = strings.TrimPrefix(hex, "#")
-	if len(hex) != 6 {
-		// Fallback to gray if invalid
-		return generatePaletteFromRGB(156, 163, 175)
+	r, g, b, ok := parseHex(hex)
+	if !ok {
+		return generatePaletteFromRGB(156, 163, 175) // gray-400 fallback
 	}
+	return generatePaletteFromRGB(r, g, b)
+}
 
-	r, err := strconv.ParseInt(hex[0:2], 16, 64)
-	if err != nil {
-		return generatePaletteFromRGB(156, 163, 175) // gray-400 fallback
+// GeneratePalette generates a named palette (50-950) from a single brand
+// color. Shades are lighter and darker variants of baseHex in HSL space,
+// with baseHex itself as the 500 shade. Returns nil if baseHex is not a
+// valid hex color ("#3b82f6", "3b82f6" or "#38f").
+func GeneratePalette(name, baseHex string) *Palette {
+	r, g, b, ok := parseHex(baseHex)
+	if !ok {
+		return nil
 	}
-	g, err := strconv.ParseInt(hex[2:4], 16, 64)
-	if err != nil {
-		return generatePaletteFromRGB(156, 163, 175)
+	p := generatePaletteFromRGB(r, g, b)
+	p.Name = name
+	return p
+}
+
+// parseHex parses a 3- or 6-digit hex color, with or without the leading #.
+func parseHex(hex string) (r, g, b int, ok bool) {
+	hex = strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return 0, 0, 0, false
 	}
-	b, err := strconv.ParseInt(hex[4:6], 16, 64)
+	v, err := strconv.ParseUint(hex, 16, 32)
 	if err != nil {
-		return generatePaletteFromRGB(156, 163, 175)
+		return 0, 0, 0, false
 	}
+	return int(v >> 16), int(v >> 8 & 0xff), int(v & 0xff), true
+}
 
-	return generatePaletteFromRGB(int(r), int(g), int(b))
+// ContrastRatio returns the WCAG 2 contrast ratio between two hex colors,
+// from 1 (no contrast) to 21 (black on white). Returns 0 if either color is
+// not a valid hex color. WCAG AA requires 4.5 for normal text.
+func ContrastRatio(fg, bg string) float64 {
+	r1, g1, b1, ok1 := parseHex(fg)
+	r2, g2, b2, ok2 := parseHex(bg)
+	if !ok1 || !ok2 {
+		return 0
+	}
+	l1 := relativeLuminance(r1, g1, b1)
+	l2 := relativeLuminance(r2, g2, b2)
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// relativeLuminance returns the WCAG relative luminance of an RGB color (0-1).
+func relativeLuminance(r, g, b int) float64 {
+	linear := func(c int) float64 {
+		v := float64(c) / 255.0
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(r) + 0.7152*linear(g) + 0.0722*linear(b)
 }
 
 // RGB generates a full color palette from an RGB string.
@@ -62,23 +106,36 @@ func (Color) FromRGB(r, g, b int) *Palette {
 	return generatePaletteFromRGB(r, g, b)
 }
 
+// shadeSteps places each shade relative to the base color (500): lighter
+// shades move its lightness toward white, darker ones toward black.
+var shadeSteps = []struct {
+	Number int
+	Mix    float64 // > 0 toward white, < 0 toward black
+}{
+	{50, 0.95}, {100, 0.88}, {200, 0.75}, {300, 0.58}, {400, 0.32},
+	{500, 0},
+	{600, -0.17}, {700, -0.33}, {800, -0.48}, {900, -0.6}, {950, -0.75},
+}
+
 // generatePaletteFromRGB creates a full Tailwind-style palette (50-950) from a base RGB color.
-// Uses HSL color space to generate lighter and darker shades.
+// Uses HSL color space to generate lighter and darker shades around the base,
+// so that the ramp stays ordered whatever the lightness of the base.
 func generatePaletteFromRGB(r, g, b int) *Palette {
 	h, s, l := rgbToHSL(r, g, b)
 
-	shades := []Shade{
-		{Number: 50, Hex: hslToHex(h, s, 0.97)},
-		{Number: 100, Hex: hslToHex(h, s, 0.94)},
-		{Number: 200, Hex: hslToHex(h, s, 0.86)},
-		{Number: 300, Hex: hslToHex(h, s, 0.77)},
-		{Number: 400, Hex: hslToHex(h, s, 0.66)},
-		{Number: 500, Hex: hslToHex(h, s, l)}, // base color
-		{Number: 600, Hex: hslToHex(h, s, l*0.83)},
-		{Number: 700, Hex: hslToHex(h, s, l*0.67)},
-		{Number: 800, Hex: hslToHex(h, s, l*0.52)},
-		{Number: 900, Hex: hslToHex(h, s, l*0.35)},
-		{Number: 950, Hex: hslToHex(h, s, l*0.15)},
+	shades := make([]Shade, len(shadeSteps))
+	for i, step := range shadeSteps {
+		shadeL := l
+		switch {
+		case step.Mix > 0:
+			shadeL = l + (math.Max(l, 0.98)-l)*step.Mix
+		case step.Mix < 0:
+			shadeL = l * (1 + step.Mix)
+		}
+		shades[i] = Shade{Number: step.Number, Hex: hslToHex(h, s, shadeL)}
+	}
+	if r == clamp(r) && g == clamp(g) && b == clamp(b) {
+		shades[5].Hex = fmt.Sprintf("#%02x%02x%02x", r, g, b) // base color, exactly
 	}
 
 	return &Palette{
@@ -87,6 +144,11 @@ func generatePaletteFromRGB(r, g, b int) *Palette {
 	}
 }
 
+// clamp limits a color channel to 0-255.
+func clamp(v int) int {
+	return max(0, min(255, v))
+}
+
 // rgbToHSL converts RGB (0-255) to HSL (0-360, 0-1, 0-1).
 func rgbToHSL(r, g, b int) (h, s, l float64) {
 	rf := float64(r) / 255.0
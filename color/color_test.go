@@ -1,6 +1,7 @@
 package color_test
 
 import (
+	"strconv"
 	"strings"
 	"testing"
 
@@ -146,3 +147,143 @@ func TestColorInvalidHex(t *testing.T) {
 		t.Errorf("expected 11 shades in fallback, got %d", len(palette.Shades))
 	}
 }
+
+func TestGeneratePalette(t *testing.T) {
+	p := color.GeneratePalette("brand", "#0EA5E9")
+	if p == nil {
+		t.Fatal("expected a palette")
+	}
+	if p.Name != "brand" {
+		t.Errorf("expected name 'brand', got %q", p.Name)
+	}
+	want := []int{50, 100, 200, 300, 400, 500, 600, 700, 800, 900, 950}
+	if len(p.Shades) != len(want) {
+		t.Fatalf("expected %d shades, got %d", len(want), len(p.Shades))
+	}
+	if got := p.Hex(500); got != "#0ea5e9" {
+		t.Errorf("expected 500 to be the base color, got %q", got)
+	}
+
+	// Shades get darker from 50 to 950.
+	prev := 3 * 255
+	for i, s := range p.Shades {
+		if s.Number != want[i] {
+			t.Errorf("shade %d: expected number %d, got %d", i, want[i], s.Number)
+		}
+		v, err := strconv.ParseUint(s.Hex[1:], 16, 32)
+		if err != nil {
+			t.Fatalf("shade %d: invalid hex %q", s.Number, s.Hex)
+		}
+		sum := int(v>>16) + int(v>>8&0xff) + int(v&0xff)
+		if sum > prev {
+			t.Errorf("shade %d (%s) is lighter than the previous one", s.Number, s.Hex)
+		}
+		prev = sum
+	}
+}
+
+func TestGeneratePaletteHexForms(t *testing.T) {
+	for _, hex := range []string{"#38f", "38f", "3388ff", " #3388FF "} {
+		p := color.GeneratePalette("brand", hex)
+		if p == nil {
+			t.Fatalf("%q: expected a palette", hex)
+		}
+		if got := p.Hex(500); got != "#3388ff" {
+			t.Errorf("%q: expected 500 #3388ff, got %q", hex, got)
+		}
+	}
+	for _, hex := range []string{"", "#12", "#zzzzzz", "#1234567"} {
+		if p := color.GeneratePalette("brand", hex); p != nil {
+			t.Errorf("%q: expected nil for an invalid hex", hex)
+		}
+	}
+}
+
+func TestContrastRatio(t *testing.T) {
+	if got := color.ContrastRatio("#000", "#fff"); got < 20.99 || got > 21.01 {
+		t.Errorf("expected 21 for black on white, got %.2f", got)
+	}
+	if got := color.ContrastRatio("ffffff", "FFFFFF"); got != 1 {
+		t.Errorf("expected 1 for identical colors, got %.2f", got)
+	}
+	// Order does not matter.
+	if a, b := color.ContrastRatio("#3b82f6", "#fff"), color.ContrastRatio("#fff", "#3b82f6"); a != b {
+		t.Errorf("expected a symmetric ratio, got %.2f and %.2f", a, b)
+	}
+	if got := color.ContrastRatio("#3b82f6", "#ffffff"); got < 3.6 || got > 3.7 {
+		t.Errorf("expected ~3.68 for blue-500 on white, got %.2f", got)
+	}
+	if got := color.ContrastRatio("nope", "#fff"); got != 0 {
+		t.Errorf("expected 0 for an invalid color, got %.2f", got)
+	}
+}
+
+func TestShadeBestTextColor(t *testing.T) {
+	cases := map[string]string{
+		"#1e3a8a": "#ffffff", // blue-900
+		"#22c55e": "#000000", // green-500
+		"fde68a":  "#000000", // amber-200, no #
+		"#000":    "#ffffff",
+		"#fff":    "#000000",
+		"invalid": "",
+	}
+	for hex, want := range cases {
+		if got := (color.Shade{Number: 500, Hex: hex}).BestTextColor(); got != want {
+			t.Errorf("%q: expected %q, got %q", hex, want, got)
+		}
+	}
+}
+
+func TestDeriveDarkShades(t *testing.T) {
+	dark := color.Blue.Dark()
+	if len(dark) != len(color.Blue.Shades) {
+		t.Fatalf("expected %d dark shades, got %d", len(color.Blue.Shades), len(dark))
+	}
+	if dark[0].Number != 50 || dark[0].Hex != color.Blue.Hex(950) {
+		t.Errorf("expected dark 50 to be blue-950, got %+v", dark[0])
+	}
+	if dark[10].Number != 950 || dark[10].Hex != color.Blue.Hex(50) {
+		t.Errorf("expected dark 950 to be blue-50, got %+v", dark[10])
+	}
+	if dark[5].Hex != color.Blue.Hex(500) {
+		t.Errorf("expected dark 500 to keep the base color, got %+v", dark[5])
+	}
+}
+
+func TestPaletteWithDarkShades(t *testing.T) {
+	p := (&color.Palette{Name: "brand", Shades: []color.Shade{{Number: 500, Hex: "#808080"}}}).
+		WithDarkShades([]color.Shade{{Number: 500, Hex: "#a0a0a0"}})
+	if got := p.Dark(); len(got) != 1 || got[0].Hex != "#a0a0a0" {
+		t.Errorf("expected the explicit dark shades, got %+v", got)
+	}
+
+	m := color.NewManager()
+	m.Register("brand", p)
+	_ = m.SetPrimary("brand")
+	css := m.PrimaryCSSVars()
+	if !strings.Contains(css, ":root {\n  --color-primary-500: #808080;") {
+		t.Errorf("missing the light block, got:\n%s", css)
+	}
+	if !strings.Contains(css, ".dark {\n  --color-primary-500: #a0a0a0;") {
+		t.Errorf("missing the dark block, got:\n%s", css)
+	}
+}
+
+func TestManagerDarkMode(t *testing.T) {
+	m := color.NewManager()
+	if css := m.PrimaryCSSVars(); strings.Contains(css, ".dark") {
+		t.Errorf("expected no dark block by default, got:\n%s", css)
+	}
+	if css := m.AllCSSVars(); strings.Contains(css, ".dark") {
+		t.Errorf("expected no dark block by default, got:\n%s", css)
+	}
+
+	m.SetDarkMode(true)
+	css := m.PrimaryCSSVars()
+	if !strings.Contains(css, ".dark {\n  --color-primary-50: #052e16;") {
+		t.Errorf("expected derived dark shades, got:\n%s", css)
+	}
+	if css := m.AllCSSVars(); !strings.Contains(css, "--color-blue-50: #172554;") {
+		t.Errorf("expected derived dark shades for all palettes, got:\n%s", css)
+	}
+}
@@ -2,6 +2,7 @@ package color
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -12,6 +13,7 @@ type Manager struct {
 	mu       sync.RWMutex
 	palettes map[string]*Palette
 	primary  string
+	darkMode bool
 }
 
 // NewManager creates a Manager pre-loaded with all built-in palettes.
@@ -46,6 +48,24 @@ func (m *Manager) SetPrimary(name string) error {
 	return nil
 }
 
+// SetDarkMode makes the CSS variable blocks include dark mode shades for
+// every palette, derived from the light ones when a palette has none.
+// Palettes with explicit dark shades (see Palette.WithDarkShades) get them
+// either way.
+func (m *Manager) SetDarkMode(enabled bool) *Manager {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.darkMode = enabled
+	return m
+}
+
+// DarkMode reports whether dark mode shades are derived for all palettes.
+func (m *Manager) DarkMode() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.darkMode
+}
+
 // Get returns a palette by name, or nil if not found.
 func (m *Manager) Get(name string) *Palette {
 	m.mu.RLock()
@@ -68,7 +88,8 @@ func (m *Manager) PrimaryName() string {
 }
 
 // PrimaryCSSVars returns a <style> block injecting the primary palette
-// as --color-primary-* CSS custom properties.
+// as --color-primary-* CSS custom properties, followed by a .dark block
+// with its dark mode shades when it has some (see SetDarkMode).
 func (m *Manager) PrimaryCSSVars() string {
 	p := m.Primary()
 	if p == nil {
@@ -76,29 +97,51 @@ func (m *Manager) PrimaryCSSVars() string {
 	}
 	var sb strings.Builder
 	sb.WriteString(":root {\n")
-	for _, s := range p.Shades {
-		sb.WriteString(fmt.Sprintf("  --color-primary-%d: %s;\n", s.Number, s.Hex))
-	}
+	sb.WriteString(p.CSSVars("primary"))
 	sb.WriteString("}")
+	if m.hasDark(p) {
+		sb.WriteString("\n.dark {\n")
+		sb.WriteString(p.DarkCSSVars("primary"))
+		sb.WriteString("}")
+	}
 	return sb.String()
 }
 
 // AllCSSVars returns CSS custom properties for all registered palettes.
 // Useful for injecting a full color system into the page.
+// Dark mode shades go in a .dark block, as in PrimaryCSSVars.
 func (m *Manager) AllCSSVars() string {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	var sb strings.Builder
+	names := make([]string, 0, len(m.palettes))
+	for name := range m.palettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb, dark strings.Builder
 	sb.WriteString(":root {\n")
-	for name, p := range m.palettes {
-		for _, s := range p.Shades {
-			sb.WriteString(fmt.Sprintf("  --color-%s-%d: %s;\n", name, s.Number, s.Hex))
+	for _, name := range names {
+		p := m.palettes[name]
+		sb.WriteString(p.CSSVars(name))
+		if m.darkMode || len(p.DarkShades) > 0 {
+			dark.WriteString(p.DarkCSSVars(name))
 		}
 	}
 	sb.WriteString("}")
+	if dark.Len() > 0 {
+		sb.WriteString("\n.dark {\n" + dark.String() + "}")
+	}
 	return sb.String()
 }
 
+// hasDark reports whether p gets a dark mode block.
+func (m *Manager) hasDark(p *Palette) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.darkMode || len(p.DarkShades) > 0
+}
+
 // Hex returns the hex value for a given palette name and shade number.
 // Returns empty string if not found.
 func (m *Manager) Hex(paletteName string, shade int) string {
@@ -108,12 +151,7 @@ func (m *Manager) Hex(paletteName string, shade int) string {
 	if !ok {
 		return ""
 	}
-	for _, s := range p.Shades {
-		if s.Number == shade {
-			return s.Hex
-		}
-	}
-	return ""
+	return p.Hex(shade)
 }
 
 // Default is the global color manager instance.
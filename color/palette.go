@@ -10,22 +10,87 @@ type Shade struct {
 	Hex    string
 }
 
-// Palette is a named set of color shades (50–950).
+// BestTextColor returns the text color to use on the shade: white if it
+// reaches a WCAG contrast of 4.5 on it, black otherwise. Returns "" if the
+// shade's hex is not a valid color.
+func (s Shade) BestTextColor() string {
+	const white, black = "#ffffff", "#000000"
+	ratio := ContrastRatio(white, s.Hex)
+	switch {
+	case ratio == 0:
+		return ""
+	case ratio >= 4.5:
+		return white
+	default:
+		return black
+	}
+}
+
+// Palette is a named set of color shades (50–950), with optional variants
+// for dark mode.
 type Palette struct {
-	Name   string
-	Shades []Shade
+	Name       string
+	Shades     []Shade
+	DarkShades []Shade // shades used in dark mode, nil if none
+}
+
+// WithDarkShades sets the shades used in dark mode.
+func (p *Palette) WithDarkShades(shades []Shade) *Palette {
+	p.DarkShades = shades
+	return p
+}
+
+// Dark returns the dark mode shades of the palette, derived from its light
+// shades with DeriveDarkShades when none were set.
+func (p *Palette) Dark() []Shade {
+	if len(p.DarkShades) > 0 {
+		return p.DarkShades
+	}
+	return DeriveDarkShades(p.Shades)
+}
+
+// DeriveDarkShades inverts the lightness ramp of shades: each shade number
+// takes the color of its mirror (50 <-> 950, 100 <-> 900, ...), so that
+// "light" shades stay readable on a dark background.
+func DeriveDarkShades(shades []Shade) []Shade {
+	dark := make([]Shade, len(shades))
+	for i, s := range shades {
+		dark[i] = Shade{Number: s.Number, Hex: shades[len(shades)-1-i].Hex}
+	}
+	return dark
 }
 
 // CSSVars returns the palette as CSS custom property declarations.
 // Example: --color-primary-500: #22c55e;
 func (p *Palette) CSSVars(prefix string) string {
+	return shadeVars(prefix, p.Shades)
+}
+
+// DarkCSSVars returns the dark mode shades as CSS custom property
+// declarations (see Dark).
+func (p *Palette) DarkCSSVars(prefix string) string {
+	return shadeVars(prefix, p.Dark())
+}
+
+func shadeVars(prefix string, shades []Shade) string {
 	out := ""
-	for _, s := range p.Shades {
+	for _, s := range shades {
 		out += fmt.Sprintf("  --%s-%s-%d: %s;\n", "color", prefix, s.Number, s.Hex)
 	}
 	return out
 }
 
+// Hex returns the hex value of a shade number, or "" if the palette has no
+// such shade.
+func (p *Palette) Hex(shade int) string {
+	for _, s := range p.Shades {
+		if s.Number == shade {
+			return s.Hex
+		}
+	}
+	return ""
+}
+
 // ---------------------------------------------------------------------------
 // Built-in palettes (Tailwind v3 defaults)
 // ---------------------------------------------------------------------------
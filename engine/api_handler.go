@@ -0,0 +1,317 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bozz33/sublimego/apperrors"
+	"github.com/bozz33/sublimego/audit"
+)
+
+// APIHandler serves a resource as JSON under {path}/api/{slug}:
+//
+//	GET    /api/{slug}       list, with the filter, search, sort and page params of the list page
+//	POST   /api/{slug}       create
+//	GET    /api/{slug}/{id}  read
+//	PUT    /api/{slug}/{id}  update
+//	DELETE /api/{slug}/{id}  delete
+//
+// Request bodies are JSON objects handed to the resource's Create and
+// Update as form values, so the resource code is the same as for the HTML
+// forms. Resources report invalid input by returning
+// apperrors.ValidationError(validation.ValidateStruct(...)), answered with
+// a 422 and the field errors. Mount it with Panel.EnableAPI.
+type APIHandler struct {
+	Resource Resource
+	// BasePath is the panel path the resource is mounted under (e.g. "/admin").
+	BasePath string
+	// Auditor records creates, updates and deletes, as for CRUDHandler.
+	Auditor Auditor
+	// AfterHookRollback makes After* hook errors fail the request, as for
+	// CRUDHandler.
+	AfterHookRollback bool
+}
+
+// NewAPIHandler creates a JSON API handler for a resource.
+func NewAPIHandler(r Resource) *APIHandler {
+	return &APIHandler{Resource: r}
+}
+
+// WithBasePath sets the panel path the API is mounted under.
+func (h *APIHandler) WithBasePath(base string) *APIHandler {
+	h.BasePath = strings.TrimRight(base, "/")
+	return h
+}
+
+// WithAuditor records mutations in the audit log.
+func (h *APIHandler) WithAuditor(a Auditor) *APIHandler {
+	h.Auditor = a
+	return h
+}
+
+// WithAfterHookRollback sets whether an error from an After* lifecycle hook
+// fails the request instead of only being logged.
+func (h *APIHandler) WithAfterHookRollback(enabled bool) *APIHandler {
+	h.AfterHookRollback = enabled
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, h.BasePath+"/api/"+h.Resource.Slug())
+	key = strings.Trim(key, "/")
+	if strings.Contains(key, "/") {
+		writeJSONError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyResource, h.Resource))
+
+	switch {
+	case key == "" && r.Method == http.MethodGet:
+		h.list(w, r)
+	case key == "" && r.Method == http.MethodPost:
+		h.create(w, r)
+	case key != "" && r.Method == http.MethodGet:
+		h.show(w, r, key)
+	case key != "" && r.Method == http.MethodPut:
+		h.update(w, r, key)
+	case key != "" && r.Method == http.MethodDelete:
+		h.delete(w, r, key)
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+	}
+}
+
+func (h *APIHandler) list(w http.ResponseWriter, r *http.Request) {
+	ctx, lq := listContext(r, h.Resource)
+	if !h.Resource.CanRead(ctx) {
+		writeJSONError(w, http.StatusForbidden, "Forbidden")
+		return
+	}
+	writeListJSON(ctx, w, h.Resource, lq, h.encoder(ctx))
+}
+
+func (h *APIHandler) show(w http.ResponseWriter, r *http.Request, key string) {
+	ctx := r.Context()
+	if ActionDisabled(h.Resource, ActionView) {
+		writeJSONError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	if !h.Resource.CanRead(ctx) {
+		writeJSONError(w, http.StatusForbidden, "Forbidden")
+		return
+	}
+	item, err := h.crud().getRecord(ctx, key)
+	if err != nil || item == nil {
+		h.writeError(w, err)
+		return
+	}
+	if !policyAllows(ctx, h.Resource, Policy.CanView, item) {
+		writeJSONError(w, http.StatusForbidden, "Forbidden")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": h.encoder(ctx)(item)})
+}
+
+func (h *APIHandler) create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if ActionDisabled(h.Resource, ActionCreate) {
+		writeJSONError(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+		return
+	}
+	if !h.Resource.CanCreate(ctx) {
+		writeJSONError(w, http.StatusForbidden, "Forbidden")
+		return
+	}
+	req, ok := h.formRequest(w, r)
+	if !ok {
+		return
+	}
+	crud := h.crud()
+	created, err := crud.hooks().create(req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	crud.audit(ctx, audit.ActionCreated, createdID(req, created), nil, crud.formSnapshot(req))
+	writeJSON(w, http.StatusCreated, map[string]string{"message": h.Resource.Label() + " created successfully"})
+}
+
+func (h *APIHandler) update(w http.ResponseWriter, r *http.Request, key string) {
+	ctx := r.Context()
+	if ActionDisabled(h.Resource, ActionEdit) {
+		writeJSONError(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+		return
+	}
+	if !h.Resource.CanUpdate(ctx) {
+		writeJSONError(w, http.StatusForbidden, "Forbidden")
+		return
+	}
+	crud := h.crud()
+	if err := crud.checkPolicy(ctx, key, Policy.CanUpdate); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	id, err := crud.recordID(ctx, key)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	req, ok := h.formRequest(w, r)
+	if !ok {
+		return
+	}
+	before := crud.auditSnapshot(ctx, id)
+	if err := crud.hooks().update(req, id); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	crud.audit(ctx, audit.ActionUpdated, id, before, crud.auditSnapshot(ctx, id))
+	item, err := h.Resource.Get(ctx, id)
+	if err != nil || item == nil {
+		writeJSON(w, http.StatusOK, map[string]string{"message": h.Resource.Label() + " updated successfully"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": h.encoder(ctx)(item)})
+}
+
+func (h *APIHandler) delete(w http.ResponseWriter, r *http.Request, key string) {
+	ctx := r.Context()
+	if ActionDisabled(h.Resource, ActionDelete) {
+		writeJSONError(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+		return
+	}
+	if !h.Resource.CanDelete(ctx) {
+		writeJSONError(w, http.StatusForbidden, "Forbidden")
+		return
+	}
+	crud := h.crud()
+	if err := crud.checkPolicy(ctx, key, Policy.CanDelete); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	id, err := crud.recordID(ctx, key)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	before := crud.auditSnapshot(ctx, id)
+	err = crud.hooks().delete(ctx, []string{id}, func(ctx context.Context) error {
+		return h.Resource.Delete(ctx, id)
+	})
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	crud.audit(ctx, audit.ActionDeleted, id, before, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// crud returns a CRUDHandler to share its record lookups and auditing.
+func (h *APIHandler) crud() *CRUDHandler {
+	return &CRUDHandler{Resource: h.Resource, BasePath: h.BasePath, Auditor: h.Auditor, AfterHookRollback: h.AfterHookRollback}
+}
+
+// encoder returns how records are written: the id and API columns when the
+// resource declares columns, the record as is otherwise.
+func (h *APIHandler) encoder(ctx context.Context) func(item any) any {
+	if encode, ok := columnsEncoder(ctx, h.Resource); ok {
+		return encode
+	}
+	return func(item any) any { return item }
+}
+
+// formRequest decodes the JSON object of r into a form request for the
+// resource's Create and Update. Only application/json bodies are accepted,
+// which browsers cannot send cross-site without a CORS preflight.
+func (h *APIHandler) formRequest(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		writeJSONError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return nil, false
+	}
+	var values map[string]any
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&values); err != nil {
+		if writeBodyTooLarge(w, err) {
+			return nil, false
+		}
+		writeJSONError(w, http.StatusBadRequest, "Invalid JSON body: "+err.Error())
+		return nil, false
+	}
+	req, err := formRequest(r.Context(), r.Method, r.URL.Path, values)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	return req, true
+}
+
+// writeError answers a failed resource call: 422 with the field errors for
+// validation errors, 403 when the policy refuses, 404 for missing records, 413 for oversized bodies,
+// 422 with the message for failed lifecycle hooks, 500 otherwise.
+func (h *APIHandler) writeError(w http.ResponseWriter, err error) {
+	if writeBodyTooLarge(w, err) {
+		return
+	}
+	var appErr *apperrors.AppError
+	switch {
+	case errors.Is(err, errPolicyDenied):
+		writeJSONError(w, http.StatusForbidden, "Forbidden")
+	case err == nil || isNotFound(err):
+		writeJSONError(w, http.StatusNotFound, h.Resource.Label()+" not found")
+	case errors.As(err, &appErr) && apperrors.IsValidation(appErr):
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{
+			"message": appErr.Message,
+			"errors":  apperrors.GetValidationErrors(appErr),
+		})
+	case isHookError(err):
+		writeJSONError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// formRequest builds a form-encoded request from decoded JSON values, for
+// resources that read their input with r.FormValue. Arrays become repeated
+// fields and null values are left out.
+func formRequest(ctx context.Context, method, target string, values map[string]any) (*http.Request, error) {
+	form := url.Values{}
+	for key, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case []any:
+			for _, item := range v {
+				if item != nil {
+					form.Add(key, fmt.Sprint(item))
+				}
+			}
+		default:
+			form.Set(key, fmt.Sprint(v))
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
+
+// writeJSON writes v as JSON with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeJSONError writes {"message": message} with the given status.
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
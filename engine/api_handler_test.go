@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bozz33/sublimego/apperrors"
+	"github.com/bozz33/sublimego/validation"
+)
+
+type apiContact struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email" validate:"required,email"`
+}
+
+func newAPIContacts() (*SimpleResource, *[]apiContact) {
+	store := &[]apiContact{{Name: "Ada", Email: "ada@example.com"}, {Name: "Linus", Email: "linus@example.com"}}
+	res := NewSimpleResource("contacts", "Contact", "Contacts").
+		WithList(func(context.Context) ([]any, error) {
+			items := make([]any, len(*store))
+			for i, c := range *store {
+				items[i] = c
+			}
+			return items, nil
+		}).
+		WithCreate(func(_ context.Context, r *http.Request) error {
+			c := apiContact{Name: r.FormValue("name"), Email: r.FormValue("email")}
+			if errs := validation.ValidateStruct(c); len(errs) > 0 {
+				return apperrors.ValidationError(errs)
+			}
+			*store = append(*store, c)
+			return nil
+		}).
+		WithDelete(func(_ context.Context, id string) error {
+			i, err := strconv.Atoi(id)
+			if err != nil || i < 0 || i >= len(*store) {
+				return ErrNotFound
+			}
+			*store = append((*store)[:i], (*store)[i+1:]...)
+			return nil
+		})
+	return res, store
+}
+
+func TestAPIHandler(t *testing.T) {
+	res, store := newAPIContacts()
+	h := NewAPIHandler(res).WithBasePath("/admin")
+
+	serve := func(method, target, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodGet, "/admin/api/contacts?per_page=1&page=2", "", "")
+	var list struct {
+		Data     []apiContact `json:"data"`
+		Page     int          `json:"page"`
+		Total    int          `json:"total"`
+		LastPage int          `json:"last_page"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("list: %v (%s)", err, rec.Body)
+	}
+	if list.Page != 2 || list.Total != 2 || list.LastPage != 2 || len(list.Data) != 1 || list.Data[0].Name != "Linus" {
+		t.Errorf("unexpected list page %+v", list)
+	}
+
+	rec = serve(http.MethodPost, "/admin/api/contacts", "application/json", `{"name":"Grace","email":"nope"}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", rec.Code)
+	}
+	var invalid struct {
+		Errors map[string]string `json:"errors"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &invalid)
+	if invalid.Errors["email"] == "" {
+		t.Errorf("expected an email field error, got %v", invalid.Errors)
+	}
+
+	if rec = serve(http.MethodPost, "/admin/api/contacts", "application/x-www-form-urlencoded", "name=Grace"); rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected 415 for form bodies, got %d", rec.Code)
+	}
+
+	rec = serve(http.MethodPost, "/admin/api/contacts", "application/json", `{"name":"Grace","email":"grace@example.com"}`)
+	if rec.Code != http.StatusCreated || len(*store) != 3 {
+		t.Fatalf("expected 201 and 3 contacts, got %d and %d", rec.Code, len(*store))
+	}
+
+	if rec = serve(http.MethodDelete, "/admin/api/contacts/0", "", ""); rec.Code != http.StatusNoContent || (*store)[0].Name != "Linus" {
+		t.Errorf("expected 204 and Ada deleted, got %d", rec.Code)
+	}
+	if rec = serve(http.MethodDelete, "/admin/api/contacts/9", "", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
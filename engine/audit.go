@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/bozz33/sublimego/audit"
+	"github.com/bozz33/sublimego/auth"
+	"github.com/bozz33/sublimego/views/components"
+)
+
+// Auditor records the creates, updates and deletes made through the CRUD
+// handlers and returns the history of a record. audit.EntStore implements
+// it; enable it with Panel.WithAuditor.
+type Auditor interface {
+	Record(ctx context.Context, e audit.Entry) error
+	History(ctx context.Context, resource, recordID string) ([]audit.Entry, error)
+}
+
+// auditRedacted returns the fields res asks to redact.
+func auditRedacted(res Resource) []string {
+	if r, ok := res.(ResourceAuditRedactor); ok {
+		return r.AuditRedacted()
+	}
+	return nil
+}
+
+// auditSnapshot returns the auditable fields of record id, or nil when no
+// auditor is set or the record cannot be loaded.
+func (h *CRUDHandler) auditSnapshot(ctx context.Context, id string) map[string]any {
+	if h.Auditor == nil {
+		return nil
+	}
+	item, err := h.Resource.Get(ctx, id)
+	if err != nil || item == nil {
+		return nil
+	}
+	return audit.Snapshot(item, auditRedacted(h.Resource)...)
+}
+
+// formSnapshot returns the submitted form fields, for created records the
+// handler cannot load. Fields starting with "_" (_token, _method) are left
+// out.
+func (h *CRUDHandler) formSnapshot(r *http.Request) map[string]any {
+	if h.Auditor == nil {
+		return nil
+	}
+	form := make(map[string][]string, len(r.PostForm))
+	for k, v := range r.PostForm {
+		if !strings.HasPrefix(k, "_") {
+			form[k] = v
+		}
+	}
+	return audit.Snapshot(form, auditRedacted(h.Resource)...)
+}
+
+// audit records a mutation with the changes between before and after.
+// Updates that change nothing are not recorded. Failures are logged: the
+// mutation itself already succeeded.
+func (h *CRUDHandler) audit(ctx context.Context, action, id string, before, after map[string]any) {
+	if h.Auditor == nil {
+		return
+	}
+	changes := audit.Diff(before, after)
+	if action == audit.ActionUpdated && len(changes) == 0 {
+		return
+	}
+	e := audit.Entry{
+		Resource: h.Resource.Slug(),
+		RecordID: id,
+		Action:   action,
+		Changes:  changes,
+	}
+	if user := auth.UserFromContext(ctx); user.IsAuthenticated() {
+		e.ActorID = user.ID
+		e.ActorName = user.Name
+		if e.ActorName == "" {
+			e.ActorName = user.Email
+		}
+	}
+	if err := h.Auditor.Record(ctx, e); err != nil {
+		slog.ErrorContext(ctx, "audit: failed to record mutation",
+			slog.String("resource", e.Resource),
+			slog.String("record_id", id),
+			slog.String("action", action),
+			slog.Any("error", err))
+	}
+}
+
+// History displays the audit log of a record. It is only routed when the
+// handler has an auditor.
+func (h *CRUDHandler) History(w http.ResponseWriter, r *http.Request, key string) {
+	ctx := r.Context()
+
+	if !h.Resource.CanRead(ctx) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if err := h.checkPolicy(ctx, key, Policy.CanView); err != nil {
+		h.writePolicyError(w, r, err)
+		return
+	}
+
+	id, err := h.recordID(ctx, key)
+	if err != nil {
+		writeLookupError(w, r, h.Resource.Label(), err)
+		return
+	}
+
+	entries, err := h.Auditor.History(ctx, h.Resource.Slug(), id)
+	if err != nil {
+		http.Error(w, "Failed to load history: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	title := h.Resource.Label() + " history"
+	render(w, r, title, components.AuditLog(title, entries))
+}
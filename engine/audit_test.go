@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bozz33/sublimego/audit"
+	"github.com/bozz33/sublimego/auth"
+)
+
+type memoryAuditor struct{ entries []audit.Entry }
+
+func (a *memoryAuditor) Record(_ context.Context, e audit.Entry) error {
+	a.entries = append(a.entries, e)
+	return nil
+}
+
+func (a *memoryAuditor) History(_ context.Context, resource, recordID string) ([]audit.Entry, error) {
+	var out []audit.Entry
+	for _, e := range a.entries {
+		if e.Resource == resource && e.RecordID == recordID {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+type account struct {
+	Name     string `json:"name"`
+	Password string `json:"-"`
+}
+
+func TestCRUDHandler_AuditsUpdate(t *testing.T) {
+	stored := account{Name: "Ada", Password: "hash-1"}
+	res := NewSimpleResource("accounts", "Account", "Accounts").
+		WithGet(func(context.Context, string) (any, error) { return stored, nil }).
+		WithUpdate(func(_ context.Context, _ string, r *http.Request) error {
+			stored = account{Name: r.FormValue("name"), Password: "hash-2"}
+			return nil
+		})
+	auditor := &memoryAuditor{}
+	h := NewCRUDHandler(res).WithAuditor(auditor)
+
+	form := url.Values{"name": {"Ada L."}}
+	req := httptest.NewRequest(http.MethodPost, "/accounts/1", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req = req.WithContext(auth.WithUser(req.Context(), &auth.User{ID: 3, Name: "Root"}))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(auditor.entries) != 1 {
+		t.Fatalf("expected 1 audit entry, got %d", len(auditor.entries))
+	}
+	e := auditor.entries[0]
+	if e.Action != audit.ActionUpdated || e.RecordID != "1" || e.ActorID != 3 || e.ActorName != "Root" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if c := e.Changes["name"]; c.Old != "Ada" || c.New != "Ada L." {
+		t.Errorf("unexpected name change %+v", c)
+	}
+	if _, ok := e.Changes["password"]; !ok {
+		t.Error("expected the password change to be recorded")
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/accounts/1/history", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Ada L.") {
+		t.Errorf("expected the history page to list the change, got %d", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "hash-") {
+		t.Error("expected the password to be redacted on the history page")
+	}
+}
+
+func TestCRUDHandler_AuditsInlineUpdate(t *testing.T) {
+	res := &inlineResource{
+		SimpleResource: NewSimpleResource("orders", "Order", "Orders"),
+		canUpdate:      true,
+		updated:        map[string]string{"7.status": "draft"},
+	}
+	res.WithGet(func(_ context.Context, id string) (any, error) {
+		return map[string]any{"status": res.updated[id+".status"]}, nil
+	})
+	auditor := &memoryAuditor{}
+	h := NewCRUDHandler(res).WithAuditor(auditor)
+
+	postInline(h, "/orders/7/inline", url.Values{"column": {"status"}, "value": {"shipped"}})
+
+	if len(auditor.entries) != 1 {
+		t.Fatalf("expected 1 audit entry, got %d", len(auditor.entries))
+	}
+	e := auditor.entries[0]
+	if e.Action != audit.ActionUpdated || e.RecordID != "7" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if c := e.Changes["status"]; c.Old != "draft" || c.New != "shipped" {
+		t.Errorf("unexpected status change %+v", c)
+	}
+}
@@ -15,9 +15,10 @@ import (
 
 // AuthHandler handles authentication routes.
 type AuthHandler struct {
-	authManager *authpkg.Manager
-	db          *ent.Client
-	basePath    string
+	authManager       *authpkg.Manager
+	db                *ent.Client
+	basePath          string
+	postLoginRedirect func(user *authpkg.User) string
 }
 
 // NewAuthHandler creates a new authentication handler.
@@ -29,6 +30,14 @@ func NewAuthHandler(authManager *authpkg.Manager, db *ent.Client, basePath strin
 	}
 }
 
+// WithPostLoginRedirect sets the function choosing where users land after
+// logging in when no intended URL was saved. An empty result falls back to
+// the dashboard.
+func (h *AuthHandler) WithPostLoginRedirect(fn func(user *authpkg.User) string) *AuthHandler {
+	h.postLoginRedirect = fn
+	return h
+}
+
 // ServeHTTP implements http.Handler for routing.
 func (h *AuthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	path := r.URL.Path
@@ -95,6 +104,7 @@ func (h *AuthHandler) handleLogin(w http.ResponseWriter, r *http.Request) {
 		ID:    user.ID,
 		Name:  user.Name,
 		Email: user.Email,
+		Roles: []string{user.Role},
 	}
 
 	if err := h.authManager.LoginWithRequest(r, authUser); err != nil {
@@ -114,11 +124,7 @@ func (h *AuthHandler) handleLogin(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	intendedURL := h.getIntendedURL(r)
-	if intendedURL == "" {
-		intendedURL = h.basePath + "/"
-	}
-	http.Redirect(w, r, intendedURL, http.StatusFound)
+	http.Redirect(w, r, h.postLoginURL(r, authUser), http.StatusFound)
 }
 
 // showRegister displays the registration page.
@@ -210,7 +216,21 @@ func (h *AuthHandler) showRegisterWithError(w http.ResponseWriter, r *http.Reque
 }
 
 func (h *AuthHandler) getIntendedURL(r *http.Request) string {
-	return ""
+	return h.authManager.IntendedURLFromRequest(r, "")
+}
+
+// postLoginURL returns where user goes after logging in: the page they were
+// sent away from, else the panel's post-login redirect, else the dashboard.
+func (h *AuthHandler) postLoginURL(r *http.Request, user *authpkg.User) string {
+	if intended := h.getIntendedURL(r); intended != "" {
+		return intended
+	}
+	if h.postLoginRedirect != nil {
+		if url := h.postLoginRedirect(user); url != "" {
+			return url
+		}
+	}
+	return h.basePath + "/"
 }
 
 func (h *AuthHandler) verifyPassword(password, hash string) bool {
@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/bozz33/sublimego/auth"
+)
+
+func TestAuthHandler_PostLoginURL(t *testing.T) {
+	sessions := scs.New()
+	am := auth.NewManager(sessions)
+	h := NewAuthHandler(am, nil, "/admin").WithPostLoginRedirect(func(u *auth.User) string {
+		if u.HasRole("support") {
+			return "/admin/tickets"
+		}
+		return ""
+	})
+
+	resolve := func(intended string, user *auth.User) string {
+		var got string
+		handler := sessions.LoadAndSave(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			if intended != "" {
+				am.SetIntendedURL(r.Context(), intended)
+			}
+			got = h.postLoginURL(r, user)
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/admin/login", nil))
+		return got
+	}
+
+	support := &auth.User{ID: 2, Roles: []string{"support"}}
+	admin := &auth.User{ID: 1, Roles: []string{"admin"}}
+	if got := resolve("", support); got != "/admin/tickets" {
+		t.Errorf("expected support users on /admin/tickets, got %q", got)
+	}
+	if got := resolve("", admin); got != "/admin/" {
+		t.Errorf("expected the dashboard when the redirect is empty, got %q", got)
+	}
+	if got := resolve("/admin/orders/7", support); got != "/admin/orders/7" {
+		t.Errorf("expected the intended URL to win, got %q", got)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"io"
 	"net/http"
 	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/a-h/templ"
 )
@@ -19,6 +21,7 @@ type BaseResource struct {
 	icon        string
 	group       string
 	sort        int
+	routeKey    string
 
 	// Table configuration
 	tableColumns       []Column
@@ -27,6 +30,8 @@ type BaseResource struct {
 	tableHeaderActions []HeaderAction
 	tableExportURL     string
 	tableImportURL     string
+	disabledActions    []string
+	exportEnabled      bool
 }
 
 // NewBaseResource creates a BaseResource with required values.
@@ -50,6 +55,14 @@ func (b *BaseResource) Icon() string        { return b.icon }
 func (b *BaseResource) Group() string       { return b.group }
 func (b *BaseResource) Sort() int           { return b.sort }
 
+// RouteKey returns the field identifying records in URLs ("id" by default).
+func (b *BaseResource) RouteKey() string {
+	if b.routeKey == "" {
+		return "id"
+	}
+	return b.routeKey
+}
+
 // Fluent setters for configuration
 func (b *BaseResource) SetSlug(slug string) *BaseResource {
 	b.slug = slug
@@ -81,12 +94,35 @@ func (b *BaseResource) SetSort(sort int) *BaseResource {
 	return b
 }
 
+// SetRouteKey sets the field identifying records in URLs, e.g. "slug".
+// The resource should implement ResourceKeyLookup to resolve it.
+func (b *BaseResource) SetRouteKey(key string) *BaseResource {
+	b.routeKey = key
+	return b
+}
+
 // SetTableColumns sets the columns for BuildTableState.
 func (b *BaseResource) SetTableColumns(cols ...Column) *BaseResource {
 	b.tableColumns = cols
 	return b
 }
 
+// TableColumns returns the columns set with SetTableColumns.
+func (b *BaseResource) TableColumns() []Column {
+	return b.tableColumns
+}
+
+// SortableColumns returns the keys of the columns marked Sortable.
+func (b *BaseResource) SortableColumns() []string {
+	keys := make([]string, 0, len(b.tableColumns))
+	for _, col := range b.tableColumns {
+		if col.Sortable {
+			keys = append(keys, col.Key)
+		}
+	}
+	return keys
+}
+
 // SetTableFilters sets the filters for BuildTableState.
 func (b *BaseResource) SetTableFilters(filters ...FilterDef) *BaseResource {
 	b.tableFilters = filters
@@ -102,9 +138,37 @@ func (b *BaseResource) SetTableBulkActions(actions ...BulkActionDef) *BaseResour
 // SetExportURL enables the export button with the given URL.
 func (b *BaseResource) SetExportURL(url string) *BaseResource {
 	b.tableExportURL = url
+	b.exportEnabled = true
+	return b
+}
+
+// EnableExport turns on the export routes mounted by the panel, along with
+// the export button and bulk action of the list (off by default).
+func (b *BaseResource) EnableExport() *BaseResource {
+	b.exportEnabled = true
+	return b
+}
+
+// ExportEnabled implements ResourceExportEnabler.
+func (b *BaseResource) ExportEnabled() bool { return b.exportEnabled }
+
+// DisableExport turns the export off again, even for a resource implementing
+// ResourceExportable. It is a shorthand for DisableActions(ActionExport).
+func (b *BaseResource) DisableExport() *BaseResource {
+	return b.DisableActions(ActionExport)
+}
+
+// DisableActions turns off CRUD actions (ActionCreate, ActionView,
+// ActionEdit, ActionDelete) or the export (ActionExport): their routes are
+// refused and their buttons hidden.
+func (b *BaseResource) DisableActions(actions ...string) *BaseResource {
+	b.disabledActions = append(b.disabledActions, actions...)
 	return b
 }
 
+// DisabledActions implements ResourceActionsDisabler.
+func (b *BaseResource) DisabledActions() []string { return b.disabledActions }
+
 // SetImportURL enables the import button with the given URL.
 func (b *BaseResource) SetImportURL(url string) *BaseResource {
 	b.tableImportURL = url
@@ -123,70 +187,177 @@ func (b *BaseResource) BuildTableState(ctx context.Context, canCreate, canDelete
 	lq := GetListQuery(ctx)
 	activeFilters := GetActiveFilters(ctx)
 
-	items, total, err := b.fetchItems(ctx, lq, activeFilters)
+	var res Resource = b
+	if r := GetResourceFromContext(ctx); r != nil {
+		res = r
+	}
+	items, pagination, err := fetchListPage(ctx, res, lq, activeFilters)
 	if err != nil {
 		return TableState{}, err
 	}
-
-	rows := b.buildRows(items)
-	pagination := buildPagination(lq, total)
+	columns, rows := b.tableColumns, []Row(nil)
+	if declared, ok := declaredColumns(ctx, res); ok {
+		columns = listColumns(declared)
+		rows = b.buildRowsWith(items, func(item any) []string {
+			cells := make([]string, len(declared))
+			for i, col := range declared {
+				cells[i] = col.Value(item)
+			}
+			return cells
+		})
+	} else {
+		rows = b.buildRows(items)
+	}
+	withRowActions(ctx, res, items, rows)
 	search, sortKey, sortDir := extractSortSearch(lq)
+	var filterValues map[string][]string
+	if lq != nil {
+		filterValues = lq.FilterValues
+	}
+	baseURL := GetBasePath(ctx) + "/" + b.slug
+	newURL := baseURL + "/create"
+	disabled := b.disabledActions
+	if d, ok := GetResourceFromContext(ctx).(ResourceActionsDisabler); ok {
+		disabled = d.DisabledActions()
+		if slices.Contains(disabled, ActionCreate) {
+			canCreate, newURL = false, ""
+		}
+		canDelete = canDelete && !slices.Contains(disabled, ActionDelete)
+	}
+	exportDisabled := slices.Contains(disabled, ActionExport) || !ExportEnabled(res)
+	_, inline := res.(ResourceInlineUpdatable)
 
 	return TableState{
 		Title:         b.pluralLabel,
 		Slug:          b.slug,
-		Columns:       b.tableColumns,
+		Columns:       columns,
 		Rows:          rows,
 		CanCreate:     canCreate,
 		CanDelete:     canDelete,
-		NewURL:        "/" + b.slug + "/create",
-		BaseURL:       "/" + b.slug,
+		NewURL:        newURL,
+		BaseURL:       baseURL,
 		Filters:       b.tableFilters,
 		ActiveFilters: activeFilters,
-		BulkActions:   b.tableBulkActions,
+		FilterValues:  filterValues,
+		BulkActions:   b.bulkActions(baseURL, exportDisabled),
 		HeaderActions: b.tableHeaderActions,
-		ExportURL:     b.tableExportURL,
+		ExportURL:     b.exportURL(baseURL, exportDisabled),
 		ImportURL:     b.tableImportURL,
 		Pagination:    pagination,
 		Search:        search,
 		SortKey:       sortKey,
 		SortDir:       sortDir,
+
+		DisabledActions: disabled,
+		InlineEdit:      inline && !slices.Contains(disabled, ActionEdit),
 	}, nil
 }
 
-// fetchItems resolves which data-fetching strategy to use based on available interfaces.
-func (b *BaseResource) fetchItems(ctx context.Context, lq *ListQuery, activeFilters map[string]string) ([]any, int, error) {
-	self := interface{}(b)
+// fetchListPage fetches the items of the requested page from res, along
+// with the pagination (nil when lq is nil). A page beyond the last one is
+// clamped to the last page.
+func fetchListPage(ctx context.Context, res Resource, lq *ListQuery, activeFilters map[string]string) ([]any, *Pagination, error) {
+	items, total, paged, err := fetchListItems(ctx, res, lq, activeFilters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := buildPagination(lq, total)
+	switch {
+	case pagination == nil:
+	case !paged:
+		items = pageSlice(items, pagination)
+	case pagination.CurrentPage != lq.Page:
+		// The requested page was out of range: fetch the last valid one.
+		clamped := *lq
+		clamped.Page = pagination.CurrentPage
+		if items, _, _, err = fetchListItems(ctx, res, &clamped, activeFilters); err != nil {
+			return nil, nil, err
+		}
+	}
+	return items, pagination, nil
+}
+
+// fetchListItems fetches the items of a list request from res.
+// Resolution order: ResourceQueryable > ResourceSearchable > ResourceFilterable > List.
+// paged reports whether the items are already limited to the requested page.
+func fetchListItems(ctx context.Context, res Resource, lq *ListQuery, activeFilters map[string]string) (items []any, total int, paged bool, err error) {
 	if lq == nil {
-		items, err := b.List(ctx)
-		return items, len(items), err
+		items, err = res.List(ctx)
+		return items, len(items), false, err
 	}
-	if q, ok := self.(ResourceQueryable); ok {
-		return q.ListQuery(ctx, *lq)
+	if q, ok := res.(ResourceQueryable); ok {
+		items, total, err = q.ListQuery(ctx, *lq)
+		return items, total, true, err
 	}
 	if lq.Search != "" {
-		if s, ok := self.(ResourceSearchable); ok {
-			items, err := s.Search(ctx, lq.Search)
-			return items, len(items), err
+		if s, ok := res.(ResourceSearchable); ok {
+			items, err = s.Search(ctx, lq.Search)
+			return items, len(items), false, err
 		}
 	}
 	if len(activeFilters) > 0 {
-		if f, ok := self.(ResourceFilterable); ok {
-			items, err := f.ListFiltered(ctx, activeFilters)
-			return items, len(items), err
+		if f, ok := res.(ResourceFilterable); ok {
+			items, err = f.ListFiltered(ctx, activeFilters)
+			return items, len(items), false, err
 		}
 	}
-	items, err := b.List(ctx)
-	return items, len(items), err
+	items, err = res.List(ctx)
+	return items, len(items), false, err
+}
+
+// exportURL returns the export button URL ("" when disabled), under the
+// list URL baseURL.
+func (b *BaseResource) exportURL(baseURL string, disabled bool) string {
+	switch {
+	case disabled:
+		return ""
+	case b.tableExportURL != "":
+		return b.tableExportURL
+	}
+	return baseURL + "/export"
+}
+
+// bulkActions returns the configured bulk actions, plus "Export selected"
+// under the list URL baseURL unless export is disabled.
+func (b *BaseResource) bulkActions(baseURL string, exportDisabled bool) []BulkActionDef {
+	if exportDisabled {
+		return b.tableBulkActions
+	}
+	return append(slices.Clone(b.tableBulkActions), BulkActionDef{
+		Key:   "export",
+		Label: "Export selected",
+		Icon:  "download",
+		Color: "primary",
+		URL:   baseURL + "/bulk/export?format=csv",
+	})
+}
+
+// pageSlice returns the items of the current page from a full result set.
+func pageSlice(items []any, p *Pagination) []any {
+	start := min((p.CurrentPage-1)*p.PerPage, len(items))
+	end := min(start+p.PerPage, len(items))
+	return items[start:end]
 }
 
 // buildRows converts items to table rows using reflection.
 func (b *BaseResource) buildRows(items []any) []Row {
+	return b.buildRowsWith(items, func(item any) []string {
+		cells := make([]string, len(b.tableColumns))
+		for i, col := range b.tableColumns {
+			cells[i] = getColumnValue(col, item)
+		}
+		return cells
+	})
+}
+
+// buildRowsWith converts items to table rows, with cells computed by cells.
+func (b *BaseResource) buildRowsWith(items []any, cells func(item any) []string) []Row {
 	rows := make([]Row, 0, len(items))
 	for _, item := range items {
-		row := Row{ID: getItemID(item)}
-		for _, col := range b.tableColumns {
-			row.Cells = append(row.Cells, getColumnValue(col, item))
+		row := Row{ID: getItemID(item), Cells: cells(item)}
+		if key := b.RouteKey(); key != "id" {
+			row.Key = getFieldString(item, key)
 		}
 		rows = append(rows, row)
 	}
@@ -194,6 +365,7 @@ func (b *BaseResource) buildRows(items []any) []Row {
 }
 
 // buildPagination constructs a Pagination struct from ListQuery + total count.
+// A page beyond the last one is clamped to the last page.
 func buildPagination(lq *ListQuery, total int) *Pagination {
 	if lq == nil || lq.PerPage <= 0 {
 		return nil
@@ -203,7 +375,7 @@ func buildPagination(lq *ListQuery, total int) *Pagination {
 		lastPage = 1
 	}
 	return &Pagination{
-		CurrentPage: lq.Page,
+		CurrentPage: min(max(lq.Page, 1), lastPage),
 		PerPage:     lq.PerPage,
 		Total:       total,
 		LastPage:    lastPage,
@@ -304,6 +476,25 @@ func getItemID(item any) string {
 	return ""
 }
 
+// getFieldString returns a struct field by name as a string. The name may be
+// given in snake_case ("created_at" matches CreatedAt).
+func getFieldString(item any, name string) string {
+	v := reflect.ValueOf(item)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	f := v.FieldByNameFunc(func(field string) bool {
+		return strings.EqualFold(field, strings.ReplaceAll(name, "_", ""))
+	})
+	if !f.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("%v", f.Interface())
+}
+
 // getColumnValue returns the string value of a column field from an item.
 func getColumnValue(col Column, item any) string {
 	if item == nil {
@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// DefaultMaxBodySize is the request body limit applied to resource routes
+// when the panel does not set one (8 MB).
+const DefaultMaxBodySize int64 = 8 << 20
+
+// limitBody caps the body of requests that carry one (POST, PUT, PATCH,
+// DELETE) at n bytes. Reads beyond the limit fail with *http.MaxBytesError.
+// A non-positive n disables the limit.
+func limitBody(n int64, next http.Handler) http.Handler {
+	if n <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body != nil && r.Method != http.MethodGet && r.Method != http.MethodHead {
+			r.Body = http.MaxBytesReader(w, r.Body, n)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeBodyTooLarge writes a 413 response for err if it was caused by a body
+// exceeding the limit, and reports whether it did.
+func writeBodyTooLarge(w http.ResponseWriter, err error) bool {
+	var mbe *http.MaxBytesError
+	if !errors.As(err, &mbe) {
+		return false
+	}
+	http.Error(w, fmt.Sprintf("Request body too large: the limit is %s", formatByteSize(mbe.Limit)),
+		http.StatusRequestEntityTooLarge)
+	return true
+}
+
+// formatByteSize renders a byte count as B, KB or MB.
+func formatByteSize(n int64) string {
+	switch {
+	case n >= 1<<20 && n%(1<<20) == 0:
+		return fmt.Sprintf("%d MB", n>>20)
+	case n >= 1<<10 && n%(1<<10) == 0:
+		return fmt.Sprintf("%d KB", n>>10)
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
@@ -3,8 +3,12 @@ package engine
 import (
 	"context"
 	"net/http"
+	"slices"
 
 	"github.com/a-h/templ"
+	"github.com/bozz33/sublimego/actions"
+	"github.com/bozz33/sublimego/form"
+	"github.com/bozz33/sublimego/table"
 )
 
 // ResourceMeta defines resource metadata.
@@ -66,16 +70,52 @@ type ResourceViewable interface {
 type Column struct {
 	Key        string
 	Label      string
-	Type       string // "text", "boolean", "date", "badge", "image"
+	Type       string // "text", "boolean", "date", "badge", "image", "select", "toggle"
 	Sortable   bool
 	Searchable bool
+	Options    map[string]string // "select" columns: values offered inline (value -> label)
+}
+
+// GetValue returns the cell value of the column for item.
+func (c Column) GetValue(item any) string {
+	return getColumnValue(c, item)
+}
+
+// OptionValues returns the values of Options sorted by label.
+func (c Column) OptionValues() []string {
+	return table.SelectCol(c.Key).Options(c.Options).OptionValues()
+}
+
+// ResourceTableColumns is an optional interface exposing a resource's table
+// columns, used e.g. by the CSV export. BaseResource implements it.
+type ResourceTableColumns interface {
+	TableColumns() []Column
+}
+
+// ResourceColumns is an optional interface declaring a resource's columns
+// once. When implemented it is the single source of truth for the list view
+// (BuildTableState), exports and JSON list responses. Use
+// table.ExcludeFromExport and table.ExcludeFromAPI to leave a column out of
+// one surface.
+type ResourceColumns interface {
+	Columns(ctx context.Context) []table.Column
 }
 
 // Row represents a table row.
 type Row struct {
 	ID        string
+	Key       string // route key used in record URLs (empty = ID)
 	Cells     []string
-	RecordURL string // optional: custom URL when clicking the row/first cell
+	RecordURL string            // optional: custom URL when clicking the row/first cell
+	Actions   []*actions.Action // row actions authorized for the record (ResourceRowActions)
+}
+
+// URLKey returns the value identifying the row in record URLs.
+func (r Row) URLKey() string {
+	if r.Key != "" {
+		return r.Key
+	}
+	return r.ID
 }
 
 // EmptyState configures the empty table placeholder.
@@ -114,14 +154,37 @@ type TableState struct {
 	ColumnManager  bool              // show column visibility toggle button
 	ColumnOrder    []string          // ordered list of column keys (empty = default order)
 	ReorderColumns bool              // allow drag & drop column reordering
+
+	// FilterValues holds all values of each active filter (multi-select).
+	FilterValues map[string][]string
+
+	// DisabledActions lists the CRUD actions turned off for the resource.
+	DisabledActions []string
+
+	// InlineEdit renders "select" and "toggle" columns as inline editors
+	// (the resource implements ResourceInlineUpdatable and edit is enabled).
+	InlineEdit bool
+}
+
+// InlineURL returns the inline update endpoint of a row.
+func (s TableState) InlineURL(row Row) string {
+	return s.BaseURL + "/" + row.URLKey() + "/inline"
+}
+
+// ActionEnabled reports whether a CRUD action (ActionEdit, ...) is available.
+func (s TableState) ActionEnabled(action string) bool {
+	return !slices.Contains(s.DisabledActions, action)
 }
 
 // FilterDef describes a filter available on the table.
 type FilterDef struct {
 	Key     string
 	Label   string
-	Type    string // "select", "boolean", "text"
+	Type    string // "select", "boolean", "ternary", "text", "range", "date_range"
 	Options []FilterOption
+	// Multiple allows selecting several options (select filters only); the
+	// values are submitted as repeated filter_{key} params.
+	Multiple bool
 }
 
 // FilterOption is a single option in a select filter.
@@ -165,6 +228,7 @@ const (
 	ContextKeyResource      contextKey = "resource"
 	ContextKeyUser          contextKey = "user"
 	ContextKeyActiveFilters contextKey = "active_filters"
+	ContextKeyBasePath      contextKey = "base_path"
 )
 
 // GetPanelFromContext retrieves the Panel from context.
@@ -183,6 +247,16 @@ func GetResourceFromContext(ctx context.Context) Resource {
 	return nil
 }
 
+// GetBasePath retrieves the panel path the current resource is mounted
+// under (e.g. "/admin"), or "" at the root. Prefix the URLs built for the
+// resource with it.
+func GetBasePath(ctx context.Context) string {
+	if base, ok := ctx.Value(ContextKeyBasePath).(string); ok {
+		return base
+	}
+	return ""
+}
+
 // GetActiveFilters retrieves the active filter map from context.
 func GetActiveFilters(ctx context.Context) map[string]string {
 	if f, ok := ctx.Value(ContextKeyActiveFilters).(map[string]string); ok {
@@ -206,6 +280,12 @@ type ListQuery struct {
 	SortDir string            // ?dir=asc|desc
 	Page    int               // ?page=N (1-indexed)
 	PerPage int               // ?per_page=N
+	// FilterValues holds every value of each filter_* param, for filters
+	// submitting several values (multi-select); Filters keeps the first one.
+	FilterValues map[string][]string
+	// Ranges groups paired bound params (filter_{key}_min/_max and
+	// filter_{key}_from/_to) by key.
+	Ranges map[string]FilterRange
 }
 
 // ResourceQueryable is an optional interface for resources that handle
@@ -221,13 +301,102 @@ type ResourceSearchable interface {
 	Search(ctx context.Context, query string) ([]any, error)
 }
 
+// ResourceMiddleware is an optional interface for resources that need extra
+// middleware on their own routes only (e.g. a feature flag gate).
+//
+// The middlewares run after the panel chain: panel middlewares (WithMiddleware)
+// → authentication → resource middlewares → handler. They are applied in
+// declaration order, the first one being the outermost.
+type ResourceMiddleware interface {
+	Middleware() []func(http.Handler) http.Handler
+}
+
+// ResourceBodyLimiter is an optional interface for resources that need a
+// request body limit other than the panel's (e.g. a larger one for resources
+// with file uploads). A non-positive value disables the limit.
+type ResourceBodyLimiter interface {
+	MaxBodySize() int64
+}
+
+// ResourceKeyLookup is an optional interface for resources whose record URLs
+// use a route key other than the ID (e.g. /articles/my-post-title). The CRUD
+// handler passes the path value to GetByKey, falling back to Get when the
+// key does not resolve. Update, Delete and InlineUpdate still receive the
+// record ID. Pair it with BaseResource.SetRouteKey so list links use the key.
+type ResourceKeyLookup interface {
+	RouteKey() string
+	GetByKey(ctx context.Context, key string) (any, error)
+}
+
+// ResourceInlineUpdatable is an optional interface for resources whose table
+// cells can be edited in place (e.g. table.SelectColumn). The CRUD handler
+// routes POST /{slug}/{id}/inline with "column" and "value" form fields here.
+// InlineUpdate returns the value actually stored, which may differ from the
+// requested one if a rule or hook adjusted or vetoed the change.
+type ResourceInlineUpdatable interface {
+	InlineUpdate(ctx context.Context, id, column, value string) (string, error)
+}
+
+// ResourceActionsDisabler is an optional interface for resources that turn
+// off some CRUD actions entirely, e.g. []string{ActionCreate, ActionDelete}
+// for a read-only resource. The CRUD handler answers the disabled routes with
+// 404 (pages) or 405 (writes), and the list hides the matching buttons.
+type ResourceActionsDisabler interface {
+	DisabledActions() []string
+}
+
+// Actions that can be disabled with ResourceActionsDisabler. ActionExport
+// covers the export routes, full and bulk.
+const (
+	ActionCreate = "create"
+	ActionView   = "view"
+	ActionEdit   = "edit"
+	ActionDelete = "delete"
+	ActionExport = "export"
+)
+
 // ResourceHookable is an optional interface for resources that need
-// lifecycle hooks around CRUD operations.
+// lifecycle hooks around CRUD operations. Resources can also implement only
+// the hooks they need; see ResourceBeforeCreate and the others in hooks.go.
 type ResourceHookable interface {
-	BeforeCreate(ctx context.Context, r *http.Request) error
-	AfterCreate(ctx context.Context, item any) error
-	BeforeUpdate(ctx context.Context, id string, r *http.Request) error
-	AfterUpdate(ctx context.Context, id string, item any) error
-	BeforeDelete(ctx context.Context, id string) error
-	AfterDelete(ctx context.Context, id string) error
+	ResourceBeforeCreate
+	ResourceAfterCreate
+	ResourceBeforeUpdate
+	ResourceAfterUpdate
+	ResourceBeforeDelete
+	ResourceAfterDelete
+}
+
+// ResourceWizard is an optional interface for resources whose create and
+// edit forms are multi-step wizards. The CRUD handler validates each step
+// before showing the next, keeps the values entered in the session and only
+// calls Create or Update once the last step is submitted. Form must render
+// the same wizard with generics.Wizard, which shows the step the handler
+// puts in the context. item is nil when creating.
+type ResourceWizard interface {
+	Wizard(ctx context.Context, item any) *form.Wizard
+}
+
+// ResourceAuditRedactor is an optional interface for resources with fields
+// whose values must not appear in the audit log, on top of the ones
+// audit.Snapshot already redacts (json:"-", audit:"redact", passwords).
+type ResourceAuditRedactor interface {
+	AuditRedacted() []string
+}
+
+// ResourcePolicy is an optional interface for resources that authorize
+// actions per record, e.g. "users can only edit their own posts". The CRUD
+// and API handlers consult the policy with the loaded record after the
+// coarse CanRead/CanUpdate/CanDelete checks, which still apply.
+type ResourcePolicy interface {
+	Policy() Policy
+}
+
+// ResourceRowActions is an optional interface for resources with custom
+// per-row actions (e.g. "Send invoice", "Activate"), listed in a dropdown on
+// each table row. Actions need a handler (actions.Action.Handle); the CRUD
+// handler runs it on POST /{slug}/{id}/action/{name} and flashes the result.
+// Actions whose Authorize function refuses a record are hidden and rejected.
+type ResourceRowActions interface {
+	RowActions() []*actions.Action
 }
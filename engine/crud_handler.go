@@ -2,12 +2,19 @@ package engine
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/a-h/templ"
+	"github.com/alexedwards/scs/v2"
+	"github.com/bozz33/sublimego/audit"
+	"github.com/bozz33/sublimego/flash"
+	"github.com/bozz33/sublimego/table"
 	"github.com/bozz33/sublimego/ui/layouts"
 )
 
@@ -21,9 +28,30 @@ func GetListQuery(ctx context.Context) *ListQuery {
 	return nil
 }
 
+// GetFilterValues returns every value of each active filter, e.g. the
+// statuses picked in a multi-select filter, to build IN (...) queries.
+func GetFilterValues(ctx context.Context) map[string][]string {
+	if q := GetListQuery(ctx); q != nil {
+		return q.FilterValues
+	}
+	return nil
+}
+
 // CRUDHandler automatically handles CRUD operations for a resource.
 type CRUDHandler struct {
 	Resource Resource
+	// BasePath is the panel path the resource is mounted under (e.g. "/admin").
+	// Empty when the panel is mounted at the root.
+	BasePath string
+	// Session stores the flash messages shown after create, update and
+	// delete. Requests must go through Session.LoadAndSave.
+	Session *scs.SessionManager
+	// Auditor records creates, updates and deletes, and enables the
+	// /{slug}/{id}/history page.
+	Auditor Auditor
+	// AfterHookRollback makes After* hook errors fail the request, rolling
+	// the mutation back for resources implementing ResourceTransactional.
+	AfterHookRollback bool
 }
 
 // NewCRUDHandler creates a CRUD handler for a given resource.
@@ -31,24 +59,178 @@ func NewCRUDHandler(r Resource) *CRUDHandler {
 	return &CRUDHandler{Resource: r}
 }
 
+// WithBasePath sets the panel path the resource is mounted under.
+func (h *CRUDHandler) WithBasePath(base string) *CRUDHandler {
+	h.BasePath = strings.TrimRight(base, "/")
+	return h
+}
+
+// WithSession enables flash messages after create, update and delete.
+func (h *CRUDHandler) WithSession(s *scs.SessionManager) *CRUDHandler {
+	h.Session = s
+	return h
+}
+
+// WithAuditor records mutations in the audit log.
+func (h *CRUDHandler) WithAuditor(a Auditor) *CRUDHandler {
+	h.Auditor = a
+	return h
+}
+
+// WithAfterHookRollback sets whether an error from an After* lifecycle hook
+// fails the request instead of only being logged.
+func (h *CRUDHandler) WithAfterHookRollback(enabled bool) *CRUDHandler {
+	h.AfterHookRollback = enabled
+	return h
+}
+
+// hooks returns the runner of the resource's lifecycle hooks.
+func (h *CRUDHandler) hooks() hookRunner {
+	return hookRunner{res: h.Resource, rollback: h.AfterHookRollback}
+}
+
+// indexURL returns the URL of the resource list page.
+func (h *CRUDHandler) indexURL() string {
+	return h.BasePath + "/" + h.Resource.Slug()
+}
+
 // List displays the list of items.
 // Extracts filter_*, search, sort, dir, page, per_page from query params
-// and injects them into context as both ActiveFilters and ListQuery.
+// and injects them into context as ActiveFilters, ListQuery, Sort and
+// table.PageParams (for tables built with table.New). Sorting by a column
+// the resource does not declare sortable is ignored.
 func (h *CRUDHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx, lq := listContext(r, h.Resource)
+
+	if wantsJSON(r) {
+		if encode, ok := columnsEncoder(ctx, h.Resource); ok {
+			writeListJSON(ctx, w, h.Resource, lq, encode)
+			return
+		}
+	}
+
+	component := h.Resource.Table(ctx)
+	render(w, r, h.Resource.PluralLabel(), component)
+}
+
+// listContext parses the list parameters of r and injects them into the
+// request context as ListQuery, Sort, ActiveFilters, FilterRanges and
+// table.PageParams.
+func listContext(r *http.Request, res Resource) (context.Context, *ListQuery) {
 	ctx := r.Context()
-	q := r.URL.Query()
 
-	// Build ListQuery from all relevant params
+	lq, sort, sorted := parseListQuery(ctx, res, r.URL.Query())
+	if sorted {
+		ctx = context.WithValue(ctx, ContextKeySort, sort)
+	}
+
+	// Inject into context
+	ctx = context.WithValue(ctx, contextKeyListQuery, lq)
+	ctx = table.WithPageParams(ctx, table.ParsePageParams(r))
+	ctx = table.WithResourceURL(ctx, GetBasePath(ctx)+"/"+res.Slug())
+	if len(lq.Filters) > 0 {
+		ctx = context.WithValue(ctx, ContextKeyActiveFilters, lq.Filters)
+	}
+	if len(lq.Ranges) > 0 {
+		ctx = context.WithValue(ctx, ContextKeyFilterRanges, lq.Ranges)
+	}
+	return ctx, lq
+}
+
+// wantsJSON reports whether the client asked for JSON rather than HTML.
+func wantsJSON(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	return strings.Contains(accept, "application/json") && !strings.Contains(accept, "text/html")
+}
+
+// columnsEncoder returns an encoder turning a record into an object of its
+// id and the columns exposed in JSON: the declared columns (ResourceColumns)
+// included in the API, or the resource's table columns. ok is false if res
+// declares neither.
+func columnsEncoder(ctx context.Context, res Resource) (encode func(item any) any, ok bool) {
+	keys, values, ok := apiColumns(ctx, res)
+	if !ok {
+		return nil, false
+	}
+	return func(item any) any {
+		record := map[string]string{"id": getItemID(item)}
+		for i, value := range values(item) {
+			record[keys[i]] = value
+		}
+		return record
+	}, true
+}
+
+// apiColumns returns the keys and cell formatter of the columns exposed in
+// JSON. ok is false if res declares no columns.
+func apiColumns(ctx context.Context, res Resource) (keys []string, values func(item any) []string, ok bool) {
+	if declared, isDeclared := declaredColumns(ctx, res); isDeclared {
+		cols := table.APIColumns(declared)
+		for _, col := range cols {
+			keys = append(keys, col.Key())
+		}
+		return keys, func(item any) []string {
+			row := make([]string, len(cols))
+			for i, col := range cols {
+				row[i] = col.Value(item)
+			}
+			return row
+		}, true
+	}
+	tc, isTC := res.(ResourceTableColumns)
+	if !isTC || len(tc.TableColumns()) == 0 {
+		return nil, nil, false
+	}
+	cols := tc.TableColumns()
+	for _, col := range cols {
+		keys = append(keys, col.Key)
+	}
+	return keys, func(item any) []string {
+		row := make([]string, len(cols))
+		for i, col := range cols {
+			row[i] = col.GetValue(item)
+		}
+		return row
+	}, true
+}
+
+// writeListJSON writes the requested list page as JSON: the records
+// converted by encode, plus the pagination.
+func writeListJSON(ctx context.Context, w http.ResponseWriter, res Resource, lq *ListQuery, encode func(item any) any) {
+	items, pagination, err := fetchListPage(ctx, res, lq, lq.Filters)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Failed to list items: "+err.Error())
+		return
+	}
+
+	data := make([]any, len(items))
+	for i, item := range items {
+		data[i] = encode(item)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data":      data,
+		"page":      pagination.CurrentPage,
+		"per_page":  pagination.PerPage,
+		"total":     pagination.Total,
+		"last_page": pagination.LastPage,
+	})
+}
+
+// parseListQuery builds a ListQuery from filter_*, search, sort, dir, page
+// and per_page. Paired range bounds (_min/_max, _from/_to) are also grouped
+// into Ranges. The sort is only kept (and returned) if the column is
+// sortable for the resource.
+func parseListQuery(ctx context.Context, res Resource, q url.Values) (*ListQuery, Sort, bool) {
 	lq := &ListQuery{
 		Filters: make(map[string]string),
 		Search:  q.Get("search"),
-		SortKey: q.Get("sort"),
-		SortDir: q.Get("dir"),
 		Page:    1,
 		PerPage: 20,
+		SortDir: "asc",
 	}
-	if lq.SortDir != "desc" {
-		lq.SortDir = "asc"
+	sort, sorted := resolveSort(ctx, res, q.Get("sort"), q.Get("dir"))
+	if sorted {
+		lq.SortKey, lq.SortDir = sort.Column, sort.Direction
 	}
 	if p, err := strconv.Atoi(q.Get("page")); err == nil && p > 0 {
 		lq.Page = p
@@ -57,39 +239,55 @@ func (h *CRUDHandler) List(w http.ResponseWriter, r *http.Request) {
 		lq.PerPage = pp
 	}
 	for key, vals := range q {
-		if strings.HasPrefix(key, "filter_") && len(vals) > 0 && vals[0] != "" {
-			lq.Filters[strings.TrimPrefix(key, "filter_")] = vals[0]
+		if !strings.HasPrefix(key, "filter_") {
+			continue
 		}
+		vals = slices.DeleteFunc(slices.Clone(vals), func(v string) bool { return v == "" })
+		if len(vals) == 0 {
+			continue
+		}
+		key = strings.TrimPrefix(key, "filter_")
+		lq.Filters[key] = vals[0]
+		if lq.FilterValues == nil {
+			lq.FilterValues = make(map[string][]string)
+		}
+		lq.FilterValues[key] = vals
 	}
-
-	// Inject into context
-	ctx = context.WithValue(ctx, contextKeyListQuery, lq)
-	if len(lq.Filters) > 0 {
-		ctx = context.WithValue(ctx, ContextKeyActiveFilters, lq.Filters)
-	}
-
-	component := h.Resource.Table(ctx)
-	render(w, r, h.Resource.PluralLabel(), component)
+	lq.Ranges = collectRanges(lq.Filters)
+	return lq, sort, sorted
 }
 
 // Create displays the creation form.
 func (h *CRUDHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	if h.actionDisabled(w, r, ActionCreate) {
+		return
+	}
+
 	if !h.Resource.CanCreate(ctx) {
 		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
 
+	if h.wizardFor(ctx, nil) != nil {
+		ctx = h.wizardContext(ctx, "", h.indexURL(), nil)
+		r = r.WithContext(ctx)
+	}
 	component := h.Resource.Form(ctx, nil)
 	render(w, r, "Create "+h.Resource.Label(), component)
 }
 
 // View displays the read-only detail view (Infolist) for a resource.
 // Only available if the resource implements ResourceViewable.
-func (h *CRUDHandler) View(w http.ResponseWriter, r *http.Request, id string) {
+// key is the record's route key (see ResourceKeyLookup).
+func (h *CRUDHandler) View(w http.ResponseWriter, r *http.Request, key string) {
 	ctx := r.Context()
 
+	if h.actionDisabled(w, r, ActionView) {
+		return
+	}
+
 	if !h.Resource.CanRead(ctx) {
 		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
@@ -98,13 +296,17 @@ func (h *CRUDHandler) View(w http.ResponseWriter, r *http.Request, id string) {
 	viewable, ok := h.Resource.(ResourceViewable)
 	if !ok {
 		// Resource has no View — redirect to edit
-		http.Redirect(w, r, fmt.Sprintf("/%s/%s/edit", h.Resource.Slug(), id), http.StatusSeeOther)
+		http.Redirect(w, r, fmt.Sprintf("%s/%s/edit", h.indexURL(), key), http.StatusSeeOther)
 		return
 	}
 
-	item, err := h.Resource.Get(ctx, id)
+	item, err := h.getRecord(ctx, key)
 	if err != nil || item == nil {
-		http.NotFound(w, r)
+		writeLookupError(w, r, h.Resource.Label(), err)
+		return
+	}
+	if !policyAllows(ctx, h.Resource, Policy.CanView, item) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
 
@@ -113,70 +315,274 @@ func (h *CRUDHandler) View(w http.ResponseWriter, r *http.Request, id string) {
 }
 
 // Edit displays the edit form.
-func (h *CRUDHandler) Edit(w http.ResponseWriter, r *http.Request, id string) {
+func (h *CRUDHandler) Edit(w http.ResponseWriter, r *http.Request, key string) {
 	ctx := r.Context()
 
-	item, err := h.Resource.Get(ctx, id)
-	if err != nil {
-		http.NotFound(w, r)
+	if h.actionDisabled(w, r, ActionEdit) {
+		return
+	}
+
+	if !h.Resource.CanUpdate(ctx) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
 
+	item, err := h.getRecord(ctx, key)
+	if err != nil || item == nil {
+		writeLookupError(w, r, h.Resource.Label(), err)
+		return
+	}
+	if !policyAllows(ctx, h.Resource, Policy.CanUpdate, item) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if h.wizardFor(ctx, item) != nil {
+		ctx = h.wizardContext(ctx, key, h.indexURL()+"/"+key, nil)
+		r = r.WithContext(ctx)
+	}
 	component := h.Resource.Form(ctx, item)
 	render(w, r, "Edit "+h.Resource.Label(), component)
 }
 
 // Store handles creation.
 func (h *CRUDHandler) Store(w http.ResponseWriter, r *http.Request) {
+	if h.actionDisabled(w, r, ActionCreate) {
+		return
+	}
 	if !h.Resource.CanCreate(r.Context()) {
 		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
 
-	if err := h.Resource.Create(r.Context(), r); err != nil {
+	if wz := h.wizardFor(r.Context(), nil); wz != nil {
+		if r = h.wizardStep(w, r, wz, "", h.indexURL()+"/create", h.indexURL(), nil); r == nil {
+			return
+		}
+	}
+
+	created, err := h.hooks().create(r)
+	if err != nil {
+		if writeBodyTooLarge(w, err) || h.writeHookError(w, r, err, h.indexURL()+"/create") {
+			return
+		}
+		if isNotFound(err) {
+			writeLookupError(w, r, h.Resource.Label(), err)
+			return
+		}
 		http.Error(w, "Creation error: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	http.Redirect(w, r, "/"+h.Resource.Slug(), http.StatusSeeOther)
+	h.audit(r.Context(), audit.ActionCreated, createdID(r, created), nil, h.formSnapshot(r))
+	h.endWizard(r.Context(), "")
+	Flash(r.Context(), flash.TypeSuccess, h.Resource.Label()+" created successfully")
+	http.Redirect(w, r, h.indexURL(), http.StatusSeeOther)
 }
 
 // Update handles updates.
-func (h *CRUDHandler) Update(w http.ResponseWriter, r *http.Request, id string) {
+func (h *CRUDHandler) Update(w http.ResponseWriter, r *http.Request, key string) {
+	if h.actionDisabled(w, r, ActionEdit) {
+		return
+	}
 	if !h.Resource.CanUpdate(r.Context()) {
 		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
 
-	if err := h.Resource.Update(r.Context(), id, r); err != nil {
+	if err := h.checkPolicy(r.Context(), key, Policy.CanUpdate); err != nil {
+		h.writePolicyError(w, r, err)
+		return
+	}
+
+	id, err := h.recordID(r.Context(), key)
+	if err != nil {
+		writeLookupError(w, r, h.Resource.Label(), err)
+		return
+	}
+
+	if _, ok := h.Resource.(ResourceWizard); ok {
+		item, _ := h.getRecord(r.Context(), key)
+		if wz := h.wizardFor(r.Context(), item); wz != nil {
+			if r = h.wizardStep(w, r, wz, key, h.indexURL()+"/"+key+"/edit", h.indexURL()+"/"+key, item); r == nil {
+				return
+			}
+		}
+	}
+
+	before := h.auditSnapshot(r.Context(), id)
+	if err := h.hooks().update(r, id); err != nil {
+		if writeBodyTooLarge(w, err) || h.writeHookError(w, r, err, h.indexURL()+"/"+key+"/edit") {
+			return
+		}
+		if isNotFound(err) {
+			writeLookupError(w, r, h.Resource.Label(), err)
+			return
+		}
 		http.Error(w, "Update error: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	http.Redirect(w, r, "/"+h.Resource.Slug(), http.StatusSeeOther)
+	h.audit(r.Context(), audit.ActionUpdated, id, before, h.auditSnapshot(r.Context(), id))
+	h.endWizard(r.Context(), key)
+	Flash(r.Context(), flash.TypeSuccess, h.Resource.Label()+" updated successfully")
+	http.Redirect(w, r, h.indexURL(), http.StatusSeeOther)
+}
+
+// InlineUpdate updates a single field from an inline-editable table cell.
+// Only available if the resource implements ResourceInlineUpdatable, for the
+// select and toggle columns it declares (ResourceColumns). The request must
+// carry the CSRF token.
+func (h *CRUDHandler) InlineUpdate(w http.ResponseWriter, r *http.Request, key string) {
+	ctx := r.Context()
+
+	if h.actionDisabled(w, r, ActionEdit) {
+		return
+	}
+
+	if !h.Resource.CanUpdate(ctx) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	updatable, ok := h.Resource.(ResourceInlineUpdatable)
+	if !ok {
+		http.Error(w, "Inline update not supported", http.StatusNotImplemented)
+		return
+	}
+
+	if !validCSRFToken(r) {
+		http.Error(w, "CSRF token missing or invalid", http.StatusForbidden)
+		return
+	}
+
+	column := r.PostFormValue("column")
+	if column == "" {
+		http.Error(w, "Missing column", http.StatusBadRequest)
+		return
+	}
+	if !inlineColumn(ctx, h.Resource, column) {
+		http.Error(w, "Column is not editable inline", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.checkPolicy(ctx, key, Policy.CanUpdate); err != nil {
+		h.writePolicyError(w, r, err)
+		return
+	}
+
+	id, err := h.recordID(ctx, key)
+	if err != nil {
+		writeLookupError(w, r, h.Resource.Label(), err)
+		return
+	}
+
+	before := h.auditSnapshot(ctx, id)
+	stored, err := updatable.InlineUpdate(ctx, id, column, r.PostFormValue("value"))
+	if isNotFound(err) {
+		writeLookupError(w, r, h.Resource.Label(), err)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Update error: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.audit(ctx, audit.ActionUpdated, id, before, h.auditSnapshot(ctx, id))
+
+	// Echo the stored value so the cell can reflect what the server kept.
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"column": column, "value": stored})
 }
 
 // Delete handles deletion.
-func (h *CRUDHandler) Delete(w http.ResponseWriter, r *http.Request, id string) {
+func (h *CRUDHandler) Delete(w http.ResponseWriter, r *http.Request, key string) {
 	ctx := r.Context()
 
+	if h.actionDisabled(w, r, ActionDelete) {
+		return
+	}
+
 	if !h.Resource.CanDelete(ctx) {
 		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
 
-	if err := h.Resource.Delete(ctx, id); err != nil {
+	if err := h.checkPolicy(ctx, key, Policy.CanDelete); err != nil {
+		h.writePolicyError(w, r, err)
+		return
+	}
+
+	id, err := h.recordID(ctx, key)
+	if err != nil {
+		writeLookupError(w, r, h.Resource.Label(), err)
+		return
+	}
+
+	before := h.auditSnapshot(ctx, id)
+	err = h.hooks().delete(ctx, []string{id}, func(ctx context.Context) error {
+		return h.Resource.Delete(ctx, id)
+	})
+	if err != nil {
+		if h.writeHookError(w, r, err, h.indexURL()) {
+			return
+		}
+		if isNotFound(err) {
+			writeLookupError(w, r, h.Resource.Label(), err)
+			return
+		}
 		http.Error(w, "Delete error: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	http.Redirect(w, r, "/"+h.Resource.Slug(), http.StatusSeeOther)
+	h.audit(ctx, audit.ActionDeleted, id, before, nil)
+	Flash(ctx, flash.TypeSuccess, deletedMessage(h.Resource, 1))
+	http.Redirect(w, r, h.indexURL(), http.StatusSeeOther)
+}
+
+// getRecord loads the record for a route key: GetByKey when the resource
+// implements ResourceKeyLookup, falling back to an ID lookup with Get.
+// Errors other than "not found" are returned as is.
+func (h *CRUDHandler) getRecord(ctx context.Context, key string) (any, error) {
+	if kl, ok := h.Resource.(ResourceKeyLookup); ok {
+		item, err := kl.GetByKey(ctx, key)
+		if err == nil && item != nil {
+			return item, nil
+		}
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+	}
+	return h.Resource.Get(ctx, key)
+}
+
+// recordID translates a route key into the record ID expected by Update,
+// Delete and InlineUpdate. Keys that do not resolve are taken as IDs; the
+// error is only set when the lookup itself failed.
+func (h *CRUDHandler) recordID(ctx context.Context, key string) (string, error) {
+	kl, ok := h.Resource.(ResourceKeyLookup)
+	if !ok {
+		return key, nil
+	}
+	item, err := kl.GetByKey(ctx, key)
+	if err != nil && !isNotFound(err) {
+		return "", err
+	}
+	if item != nil {
+		if id := getItemID(item); id != "" {
+			return id, nil
+		}
+	}
+	return key, nil
 }
 
 // BulkDelete handles bulk deletion.
 func (h *CRUDHandler) BulkDelete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	if h.actionDisabled(w, r, ActionDelete) {
+		return
+	}
+
 	if !h.Resource.CanDelete(ctx) {
 		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
@@ -192,24 +598,67 @@ func (h *CRUDHandler) BulkDelete(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "No items selected", http.StatusBadRequest)
 		return
 	}
+	for _, id := range ids {
+		if err := h.checkPolicy(ctx, id, Policy.CanDelete); err != nil {
+			h.writePolicyError(w, r, err)
+			return
+		}
+	}
 
-	if err := h.Resource.BulkDelete(ctx, ids); err != nil {
+	before := make([]map[string]any, len(ids))
+	for i, id := range ids {
+		before[i] = h.auditSnapshot(ctx, id)
+	}
+	err := h.hooks().delete(ctx, ids, func(ctx context.Context) error {
+		return h.Resource.BulkDelete(ctx, ids)
+	})
+	if err != nil {
+		if h.writeHookError(w, r, err, h.indexURL()) {
+			return
+		}
 		http.Error(w, "Bulk delete error: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	for i, id := range ids {
+		h.audit(ctx, audit.ActionDeleted, id, before[i], nil)
+	}
 
-	http.Redirect(w, r, "/"+h.Resource.Slug(), http.StatusSeeOther)
+	Flash(ctx, flash.TypeSuccess, deletedMessage(h.Resource, len(ids)))
+	http.Redirect(w, r, h.indexURL(), http.StatusSeeOther)
+}
+
+// deletedMessage returns the flash message after deleting n records,
+// e.g. "3 Users deleted".
+func deletedMessage(res Resource, n int) string {
+	label := res.Label()
+	if n != 1 {
+		label = res.PluralLabel()
+	}
+	return fmt.Sprintf("%d %s deleted", n, label)
 }
 
 // ServeHTTP implements http.Handler with automatic routing.
 func (h *CRUDHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/"+h.Resource.Slug())
+	path := strings.TrimPrefix(r.URL.Path, h.indexURL())
 	path = strings.TrimPrefix(path, "/")
 	parts := strings.Split(path, "/")
 
+	// Method overrides are only read from POST bodies.
+	if r.URL.Query().Has("_method") {
+		http.Error(w, "Method override is only accepted in a POST body", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), ContextKeyResource, h.Resource)
+	ctx = context.WithValue(ctx, ContextKeyBasePath, h.BasePath)
+	if h.Session != nil && flash.ManagerFromContext(ctx) == nil {
+		ctx = flash.WithManager(ctx, flash.NewManager(h.Session))
+	}
+	r = r.WithContext(ctx)
+
 	switch r.Method {
 	case http.MethodGet:
-		h.routeGET(w, r, path, parts)
+		h.routeGET(w, withCSRFToken(w, r), path, parts)
 	case http.MethodPost:
 		h.routePOST(w, r, path, parts)
 	case http.MethodDelete:
@@ -228,6 +677,8 @@ func (h *CRUDHandler) routeGET(w http.ResponseWriter, r *http.Request, path stri
 		h.Create(w, r)
 	case len(parts) == 2 && parts[1] == "edit":
 		h.Edit(w, r, parts[0])
+	case len(parts) == 2 && parts[1] == "history" && h.Auditor != nil:
+		h.History(w, r, parts[0])
 	case len(parts) == 1 && parts[0] != "":
 		h.View(w, r, parts[0])
 	default:
@@ -235,14 +686,35 @@ func (h *CRUDHandler) routeGET(w http.ResponseWriter, r *http.Request, path stri
 	}
 }
 
-// routePOST dispatches POST requests (including _method override).
+// routePOST dispatches POST requests. A _method override must be DELETE,
+// PUT or PATCH and carry the CSRF token.
 func (h *CRUDHandler) routePOST(w http.ResponseWriter, r *http.Request, path string, parts []string) {
 	if err := r.ParseForm(); err != nil {
+		if writeBodyTooLarge(w, err) {
+			return
+		}
 		http.Error(w, "Bad request", http.StatusBadRequest)
 		return
 	}
-	if r.FormValue("_method") == "DELETE" && len(parts) >= 1 {
-		h.Delete(w, r, parts[0])
+	method, ok := methodOverride(r)
+	if !ok {
+		http.Error(w, "Invalid method override", http.StatusBadRequest)
+		return
+	}
+	if method != "" {
+		if !validCSRFToken(r) {
+			http.Error(w, "CSRF token missing or invalid", http.StatusForbidden)
+			return
+		}
+		if len(parts) < 1 || parts[0] == "" || path == "bulk-delete" {
+			http.Error(w, "Method override requires a record", http.StatusBadRequest)
+			return
+		}
+		if method == http.MethodDelete {
+			h.Delete(w, r, parts[0])
+		} else {
+			h.Update(w, r, parts[0])
+		}
 		return
 	}
 	switch {
@@ -250,13 +722,22 @@ func (h *CRUDHandler) routePOST(w http.ResponseWriter, r *http.Request, path str
 		h.BulkDelete(w, r)
 	case path == "" || path == "/":
 		h.Store(w, r)
+	case len(parts) == 2 && parts[1] == "inline":
+		h.InlineUpdate(w, r, parts[0])
+	case len(parts) == 3 && parts[1] == "action":
+		h.RunAction(w, r, parts[0], parts[2])
 	case len(parts) >= 1:
 		h.Update(w, r, parts[0])
 	}
 }
 
-// render is a helper to display a component in the layout.
+// render is a helper to display a component in the layout. Requests with
+// ?fragment=1, such as a table's record slideover, get the component alone.
 func render(w http.ResponseWriter, r *http.Request, title string, content templ.Component) {
+	if r.URL.Query().Get("fragment") == "1" {
+		_ = content.Render(r.Context(), w)
+		return
+	}
 	fullPage := layouts.Page(title, content)
-	_ = fullPage.Render(r.Context(), w)
+	_ = fullPage.Render(withFlashes(r.Context()), w)
 }
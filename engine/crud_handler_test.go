@@ -0,0 +1,535 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/alexedwards/scs/v2"
+	"github.com/bozz33/sublimego/internal/ent"
+	"github.com/bozz33/sublimego/table"
+)
+
+type inlineResource struct {
+	*SimpleResource
+	canUpdate bool
+	updated   map[string]string
+}
+
+func (r *inlineResource) CanUpdate(_ context.Context) bool { return r.canUpdate }
+
+func (r *inlineResource) Columns(context.Context) []table.Column {
+	return []table.Column{
+		table.Text("Ref"),
+		table.SelectCol("status").Options(map[string]string{"draft": "Draft", "shipped": "Shipped"}),
+		table.Toggle("paid"),
+	}
+}
+
+func (r *inlineResource) InlineUpdate(_ context.Context, id, column, value string) (string, error) {
+	// Archived orders cannot be reopened: keep the stored value.
+	if r.updated[id+"."+column] == "archived" {
+		return "archived", nil
+	}
+	r.updated[id+"."+column] = value
+	return value, nil
+}
+
+// postInline posts form to path with a valid CSRF token.
+func postInline(h http.Handler, path string, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-CSRF-Token", "t")
+	req.AddCookie(&http.Cookie{Name: "_csrf", Value: "t"})
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+	return rw
+}
+
+func TestCRUDHandler_InlineUpdate(t *testing.T) {
+	res := &inlineResource{
+		SimpleResource: NewSimpleResource("orders", "Order", "Orders"),
+		canUpdate:      true,
+		updated:        make(map[string]string),
+	}
+	h := NewCRUDHandler(res).WithBasePath("/admin")
+
+	rw := postInline(h, "/admin/orders/7/inline", url.Values{"column": {"status"}, "value": {"shipped"}})
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rw.Code)
+	}
+	if res.updated["7.status"] != "shipped" {
+		t.Errorf("expected status=shipped for id 7, got %v", res.updated)
+	}
+	if !strings.Contains(rw.Body.String(), `"value":"shipped"`) {
+		t.Errorf("expected stored value in response, got %s", rw.Body.String())
+	}
+
+	// A vetoed change echoes the value the resource kept.
+	res.updated["8.status"] = "archived"
+	rw = postInline(h, "/admin/orders/8/inline", url.Values{"column": {"status"}, "value": {"draft"}})
+	if !strings.Contains(rw.Body.String(), `"value":"archived"`) {
+		t.Errorf("expected vetoed value in response, got %s", rw.Body.String())
+	}
+
+	rw = postInline(h, "/admin/orders/7/inline", url.Values{"column": {"paid"}, "value": {"true"}})
+	if rw.Code != http.StatusOK || res.updated["7.paid"] != "true" {
+		t.Errorf("expected the toggle column updated, got %d %v", rw.Code, res.updated)
+	}
+
+	rw = postInline(h, "/admin/orders/7/inline", url.Values{"value": {"shipped"}})
+	if rw.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without column, got %d", rw.Code)
+	}
+
+	// Only the declared select and toggle columns are writable.
+	for _, column := range []string{"Ref", "role"} {
+		rw = postInline(h, "/admin/orders/7/inline", url.Values{"column": {column}, "value": {"admin"}})
+		if rw.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400 for a column not editable inline, got %d", column, rw.Code)
+		}
+		if _, ok := res.updated["7."+column]; ok {
+			t.Errorf("%s: expected no update", column)
+		}
+	}
+
+	// The CSRF token is required.
+	req := httptest.NewRequest(http.MethodPost, "/admin/orders/9/inline", strings.NewReader("column=status&value=shipped"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+	if rw.Code != http.StatusForbidden || res.updated["9.status"] != "" {
+		t.Errorf("expected 403 without CSRF token, got %d", rw.Code)
+	}
+
+	// The list renders the select and toggle columns as inline editors.
+	ctx := context.WithValue(context.Background(), ContextKeyResource, res)
+	ctx = context.WithValue(ctx, ContextKeyBasePath, "/admin")
+	state, err := res.BuildTableState(ctx, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.InlineEdit || state.Columns[1].Type != "select" || len(state.Columns[1].OptionValues()) != 2 {
+		t.Errorf("expected inline select options in the table state, got %+v", state.Columns)
+	}
+	if got := state.InlineURL(Row{ID: "7"}); got != "/admin/orders/7/inline" {
+		t.Errorf("expected the prefixed inline URL, got %s", got)
+	}
+}
+
+func TestCRUDHandler_InlineUpdate_Forbidden(t *testing.T) {
+	res := &inlineResource{
+		SimpleResource: NewSimpleResource("orders", "Order", "Orders"),
+		updated:        make(map[string]string),
+	}
+	h := NewCRUDHandler(res)
+
+	rw := postInline(h, "/orders/7/inline", url.Values{"column": {"status"}, "value": {"shipped"}})
+	if rw.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rw.Code)
+	}
+	if len(res.updated) != 0 {
+		t.Error("expected no update when CanUpdate is false")
+	}
+}
+
+func TestCRUDHandler_InlineUpdate_NotSupported(t *testing.T) {
+	h := NewCRUDHandler(NewSimpleResource("orders", "Order", "Orders"))
+
+	rw := postInline(h, "/orders/7/inline", url.Values{"column": {"status"}, "value": {"shipped"}})
+	if rw.Code != http.StatusNotImplemented {
+		t.Errorf("expected 501, got %d", rw.Code)
+	}
+}
+
+type article struct {
+	ID   int
+	Slug string
+}
+
+type keyedResource struct {
+	*SimpleResource
+	articles []*article
+}
+
+func (r *keyedResource) GetByKey(_ context.Context, key string) (any, error) {
+	for _, a := range r.articles {
+		if a.Slug == key {
+			return a, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestCRUDHandler_RouteKey(t *testing.T) {
+	var edited any
+	var deleted string
+	res := &keyedResource{articles: []*article{{ID: 42, Slug: "my-post-title"}}}
+	res.SimpleResource = NewSimpleResource("articles", "Article", "Articles").
+		WithGet(func(_ context.Context, id string) (any, error) {
+			for _, a := range res.articles {
+				if strconv.Itoa(a.ID) == id {
+					return a, nil
+				}
+			}
+			return nil, nil
+		}).
+		WithForm(func(_ context.Context, item any) templ.Component {
+			edited = item
+			return templ.NopComponent
+		}).
+		WithDelete(func(_ context.Context, id string) error {
+			deleted = id
+			return nil
+		})
+	res.SetRouteKey("slug")
+	h := NewCRUDHandler(res)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/articles/my-post-title/edit", nil))
+	if a, ok := edited.(*article); !ok || a.ID != 42 {
+		t.Errorf("expected article 42 resolved by slug, got %v", edited)
+	}
+
+	// Numeric IDs still resolve through Get.
+	edited = nil
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/articles/42/edit", nil))
+	if a, ok := edited.(*article); !ok || a.Slug != "my-post-title" {
+		t.Errorf("expected fallback to ID lookup, got %v", edited)
+	}
+
+	// Delete receives the record ID, not the slug.
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/articles/my-post-title", nil))
+	if deleted != "42" {
+		t.Errorf("expected Delete with ID 42, got %q", deleted)
+	}
+
+	rows := res.buildRows([]any{res.articles[0]})
+	if rows[0].ID != "42" || rows[0].URLKey() != "my-post-title" {
+		t.Errorf("expected row ID 42 with URL key my-post-title, got %+v", rows[0])
+	}
+}
+
+func TestParseListQuery_Ranges(t *testing.T) {
+	res := NewSimpleResource("products", "Product", "Products")
+	q := url.Values{
+		"filter_price_min": {"10"},
+		"filter_price_max": {"99.5"},
+		"filter_stock_min": {"1"},
+		"filter_status":    {"active"},
+	}
+	lq, _, _ := parseListQuery(context.Background(), res, q)
+
+	if len(lq.Ranges) != 2 {
+		t.Fatalf("expected 2 ranges, got %v", lq.Ranges)
+	}
+	price := lq.Ranges["price"]
+	if lo, ok := price.MinFloat(); !ok || lo != 10 {
+		t.Errorf("expected price min 10, got %v (%v)", lo, ok)
+	}
+	if hi, ok := price.MaxFloat(); !ok || hi != 99.5 {
+		t.Errorf("expected price max 99.5, got %v (%v)", hi, ok)
+	}
+
+	// Open-ended: only the lower bound is set.
+	stock := lq.Ranges["stock"]
+	if _, ok := stock.MaxFloat(); ok {
+		t.Error("expected stock range to have no upper bound")
+	}
+	if !stock.ContainsFloat(1e6) || stock.ContainsFloat(0) {
+		t.Errorf("unexpected ContainsFloat for open range %+v", stock)
+	}
+
+	// Flat entries are kept for re-populating the inputs.
+	if lq.Filters["price_min"] != "10" || lq.Filters["status"] != "active" {
+		t.Errorf("unexpected filters %v", lq.Filters)
+	}
+}
+
+func TestParseListQuery_DateRange(t *testing.T) {
+	res := NewSimpleResource("orders", "Order", "Orders")
+	q := url.Values{
+		"filter_created_at_from": {"2024-03-01"},
+		"filter_updated_at_to":   {"2024-03-31"},
+	}
+	lq, _, _ := parseListQuery(context.Background(), res, q)
+
+	// Only "from" set: everything since that day.
+	since := lq.Ranges["created_at"]
+	from, ok := since.FromTime()
+	if !ok || !from.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected from %v (%v)", from, ok)
+	}
+	if _, ok := since.ToTime(); ok {
+		t.Error("expected no upper bound")
+	}
+	if !since.ContainsTime(time.Now()) || since.ContainsTime(from.Add(-time.Second)) {
+		t.Errorf("unexpected ContainsTime for %+v", since)
+	}
+
+	// "to" includes the whole day.
+	until := lq.Ranges["updated_at"]
+	if !until.ContainsTime(time.Date(2024, 3, 31, 23, 59, 0, 0, time.UTC)) {
+		t.Error("expected the last day to be included")
+	}
+	if until.ContainsTime(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("expected the next day to be excluded")
+	}
+}
+
+func TestParseListQuery_TernaryAll(t *testing.T) {
+	res := NewSimpleResource("users", "User", "Users")
+
+	// "All" submits an empty value: the filter is not applied.
+	lq, _, _ := parseListQuery(context.Background(), res, url.Values{"filter_verified": {""}})
+	if _, ok := lq.Filters["verified"]; ok {
+		t.Errorf("expected no verified filter, got %v", lq.Filters)
+	}
+
+	lq, _, _ = parseListQuery(context.Background(), res, url.Values{"filter_verified": {"false"}})
+	if lq.Filters["verified"] != "false" {
+		t.Errorf("expected verified=false, got %v", lq.Filters)
+	}
+}
+
+func TestCRUDHandler_LookupErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"ent not found", &ent.NotFoundError{}, http.StatusNotFound},
+		{"wrapped sentinel", fmt.Errorf("get order: %w", ErrNotFound), http.StatusNotFound},
+		{"sentinel", ErrNotFound, http.StatusNotFound},
+		{"database down", errors.New("dial tcp: connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &viewableResource{NewSimpleResource("orders", "Order", "Orders").
+				WithGet(func(context.Context, string) (any, error) { return nil, tt.err }).
+				WithUpdate(func(context.Context, string, *http.Request) error { return tt.err })}
+			h := NewCRUDHandler(res)
+
+			for _, path := range []string{"/orders/1", "/orders/1/edit"} {
+				rw := httptest.NewRecorder()
+				h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, path, nil))
+				if rw.Code != tt.want {
+					t.Errorf("GET %s: expected %d, got %d", path, tt.want, rw.Code)
+				}
+			}
+			rw := postInline(h, "/orders/1", url.Values{"name": {"x"}})
+			if rw.Code != tt.want {
+				t.Errorf("POST update: expected %d, got %d", tt.want, rw.Code)
+			}
+		})
+	}
+}
+
+type viewableResource struct {
+	*SimpleResource
+}
+
+func (r *viewableResource) View(_ context.Context, _ any) templ.Component { return templ.NopComponent }
+
+func TestParseListQuery_MultiValueFilter(t *testing.T) {
+	res := NewSimpleResource("orders", "Order", "Orders")
+	q, _ := url.ParseQuery("filter_status=paid&filter_status=&filter_status=shipped&filter_user=7")
+	lq, _, _ := parseListQuery(context.Background(), res, q)
+
+	if got := strings.Join(lq.FilterValues["status"], ","); got != "paid,shipped" {
+		t.Errorf("expected status values [paid shipped], got %v", lq.FilterValues["status"])
+	}
+	if lq.Filters["status"] != "paid" {
+		t.Errorf("expected first status in Filters, got %q", lq.Filters["status"])
+	}
+	if got := lq.FilterValues["user"]; len(got) != 1 || got[0] != "7" {
+		t.Errorf("expected single user value, got %v", got)
+	}
+
+	ctx := context.WithValue(context.Background(), contextKeyListQuery, lq)
+	if len(GetFilterValues(ctx)["status"]) != 2 {
+		t.Errorf("expected GetFilterValues to expose both statuses")
+	}
+}
+
+func TestCRUDHandler_MethodOverride(t *testing.T) {
+	var deleted, updated string
+	res := NewSimpleResource("posts", "Post", "Posts").
+		WithDelete(func(_ context.Context, id string) error {
+			deleted = id
+			return nil
+		}).
+		WithUpdate(func(_ context.Context, id string, _ *http.Request) error {
+			updated = id
+			return nil
+		})
+	h := NewCRUDHandler(res)
+
+	send := func(method, target string, form url.Values, token string) int {
+		req := httptest.NewRequest(method, target, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if token != "" {
+			req.AddCookie(&http.Cookie{Name: "_csrf", Value: token})
+		}
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		return rw.Code
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		form   url.Values
+		cookie string
+		want   int
+	}{
+		{"GET override", http.MethodPost, "/posts/1", url.Values{"_method": {"GET"}, "_token": {"t"}}, "t", http.StatusBadRequest},
+		{"unknown verb", http.MethodPost, "/posts/1", url.Values{"_method": {"TRACE"}, "_token": {"t"}}, "t", http.StatusBadRequest},
+		{"repeated override", http.MethodPost, "/posts/1", url.Values{"_method": {"PATCH", "DELETE"}, "_token": {"t"}}, "t", http.StatusBadRequest},
+		{"override in query", http.MethodGet, "/posts/1?_method=DELETE", nil, "t", http.StatusBadRequest},
+		{"override in POST query", http.MethodPost, "/posts/1?_method=DELETE", url.Values{"_token": {"t"}}, "t", http.StatusBadRequest},
+		{"missing token", http.MethodPost, "/posts/1", url.Values{"_method": {"DELETE"}}, "t", http.StatusForbidden},
+		{"wrong token", http.MethodPost, "/posts/1", url.Values{"_method": {"DELETE"}, "_token": {"x"}}, "t", http.StatusForbidden},
+		{"no cookie", http.MethodPost, "/posts/1", url.Values{"_method": {"DELETE"}, "_token": {"t"}}, "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		if got := send(tt.method, tt.target, tt.form, tt.cookie); got != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, got)
+		}
+	}
+	if deleted != "" || updated != "" {
+		t.Fatalf("expected no dispatch, got deleted=%q updated=%q", deleted, updated)
+	}
+
+	if got := send(http.MethodPost, "/posts/7", url.Values{"_method": {"delete"}, "_token": {"t"}}, "t"); got != http.StatusSeeOther || deleted != "7" {
+		t.Errorf("expected DELETE override to delete 7, got %d (deleted=%q)", got, deleted)
+	}
+	if got := send(http.MethodPost, "/posts/8", url.Values{"_method": {"PATCH"}, "_token": {"t"}}, "t"); got != http.StatusSeeOther || updated != "8" {
+		t.Errorf("expected PATCH override to update 8, got %d (updated=%q)", got, updated)
+	}
+}
+
+func TestCRUDHandler_IssuesCSRFCookie(t *testing.T) {
+	h := NewCRUDHandler(NewSimpleResource("posts", "Post", "Posts"))
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/posts", nil))
+
+	var found bool
+	for _, c := range rw.Result().Cookies() {
+		found = found || (c.Name == "_csrf" && c.Value != "")
+	}
+	if !found {
+		t.Error("expected a _csrf cookie on GET")
+	}
+}
+
+func TestCRUDHandler_DisabledActions(t *testing.T) {
+	called := false
+	noop := func(context.Context, string) error {
+		called = true
+		return nil
+	}
+	res := NewSimpleResource("logs", "Log", "Logs").
+		WithGet(func(_ context.Context, id string) (any, error) { return id, nil }).
+		WithDelete(noop).
+		WithCreate(func(ctx context.Context, _ *http.Request) error { return noop(ctx, "") })
+	res.DisableActions(ActionCreate, ActionDelete)
+	h := NewCRUDHandler(res)
+
+	tests := []struct {
+		method string
+		target string
+		want   int
+	}{
+		{http.MethodGet, "/logs/create", http.StatusNotFound},
+		{http.MethodPost, "/logs", http.StatusMethodNotAllowed},
+		{http.MethodDelete, "/logs/1", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/logs/bulk-delete", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/logs/1/edit", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, strings.NewReader("ids[]=1"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		if rw.Code != tt.want {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.target, tt.want, rw.Code)
+		}
+	}
+	if called {
+		t.Error("expected disabled actions not to reach the resource")
+	}
+
+	ctx := context.WithValue(context.Background(), ContextKeyResource, Resource(res))
+	state, err := res.BuildTableState(ctx, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.CanCreate || state.NewURL != "" || state.CanDelete {
+		t.Errorf("expected create and delete hidden, got CanCreate=%v NewURL=%q CanDelete=%v", state.CanCreate, state.NewURL, state.CanDelete)
+	}
+	if !state.ActionEnabled(ActionEdit) || state.ActionEnabled(ActionDelete) {
+		t.Errorf("unexpected ActionEnabled results for %v", state.DisabledActions)
+	}
+}
+
+func TestCRUDHandler_Fragment(t *testing.T) {
+	res := NewSimpleResource("customers", "Customer", "Customers").
+		WithGet(func(_ context.Context, id string) (any, error) { return id, nil }).
+		WithForm(func(_ context.Context, item any) templ.Component {
+			return templ.Raw(fmt.Sprintf("<p>customer %v</p>", item))
+		})
+	h := NewCRUDHandler(res)
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/customers/7/edit?fragment=1", nil))
+	if body := rw.Body.String(); body != "<p>customer 7</p>" {
+		t.Errorf("expected the bare component, got %q", body)
+	}
+
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/customers/7/edit", nil))
+	if body := rw.Body.String(); !strings.Contains(body, "<html") || !strings.Contains(body, "<p>customer 7</p>") {
+		t.Errorf("expected the component inside the page layout, got %q", body)
+	}
+}
+
+func TestCRUDHandler_FlashAfterDelete(t *testing.T) {
+	sessions := scs.New()
+	res := NewSimpleResource("items", "Item", "Items")
+	srv := httptest.NewServer(sessions.LoadAndSave(NewCRUDHandler(res).WithSession(sessions)))
+	t.Cleanup(srv.Close)
+
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar}
+
+	resp, err := client.PostForm(srv.URL+"/items/bulk-delete", url.Values{"ids[]": {"1", "2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if !strings.Contains(string(body), "2 Items deleted") {
+		t.Errorf("expected the redirected list to show the flash, got %s", body)
+	}
+
+	resp, err = client.Get(srv.URL + "/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if strings.Contains(string(body), "deleted") {
+		t.Error("expected the flash to be shown only once")
+	}
+}
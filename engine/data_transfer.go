@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/bozz33/sublimego/export"
+	importer "github.com/bozz33/sublimego/import"
+	"github.com/bozz33/sublimego/table"
+)
+
+// ExportResource writes every record of res to w, with the same columns as
+// the panel's export, and returns the number of records written. It backs
+// the data:export CLI command.
+func ExportResource(ctx context.Context, res Resource, w io.Writer, format export.Format) (int, error) {
+	lq, _, _ := parseListQuery(ctx, res, url.Values{})
+	items, err := fetchAllItems(ctx, res, lq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list %s: %w", res.Slug(), err)
+	}
+	if err := writeExport(ctx, w, res, format, items); err != nil {
+		return 0, err
+	}
+	return len(items), nil
+}
+
+// ImportResource loads records into res from r, e.g. a file written by
+// ExportResource. Rows go to ImportRow when res implements
+// ResourceImportable, otherwise to Create as a submitted form, so the
+// resource's form handling applies. A nil cfg means importer.DefaultConfig;
+// without Mappings, exported column labels are mapped back to column keys.
+func ImportResource(ctx context.Context, res Resource, r io.Reader, cfg *importer.ImportConfig) (*importer.ImportResult, error) {
+	if cfg == nil {
+		cfg = importer.DefaultConfig()
+	}
+	if len(cfg.Mappings) == 0 {
+		if declared, ok := declaredColumns(ctx, res); ok {
+			for _, col := range table.ExportColumns(declared) {
+				cfg.Mappings = append(cfg.Mappings, importer.ColumnMapping{SourceColumn: col.Label(), TargetField: col.Key()})
+			}
+		} else if tc, ok := res.(ResourceTableColumns); ok {
+			for _, col := range tc.TableColumns() {
+				cfg.Mappings = append(cfg.Mappings, importer.ColumnMapping{SourceColumn: col.Label, TargetField: col.Key})
+			}
+		}
+	}
+
+	handler := createFromRow(res)
+	if importable, ok := res.(ResourceImportable); ok {
+		handler = importable.ImportRow
+	}
+	return importer.New(cfg).ImportFromReader(ctx, r, handler)
+}
+
+// createFromRow returns an import handler posting each row to res.Create as
+// form values, with the create lifecycle hooks.
+func createFromRow(res Resource) func(ctx context.Context, row map[string]any) error {
+	return func(ctx context.Context, row map[string]any) error {
+		req, err := formRequest(ctx, http.MethodPost, "/"+res.Slug(), row)
+		if err != nil {
+			return err
+		}
+		_, err = hookRunner{res: res}.create(req)
+		return err
+	}
+}
+
+// FindResource returns the resource with the given slug among the panels of
+// the global registry, or nil.
+func FindResource(slug string) Resource {
+	for _, p := range All() {
+		for _, res := range p.Resources {
+			if res.Slug() == slug {
+				return res
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package engine
+
+import (
+	"net/http"
+	"slices"
+)
+
+// ActionDisabled reports whether res turns off action through
+// ResourceActionsDisabler.
+func ActionDisabled(res Resource, action string) bool {
+	d, ok := res.(ResourceActionsDisabler)
+	return ok && slices.Contains(d.DisabledActions(), action)
+}
+
+// actionDisabled answers requests for a disabled action: 404 for pages,
+// 405 for writes. It reports whether the request was answered.
+func (h *CRUDHandler) actionDisabled(w http.ResponseWriter, r *http.Request, action string) bool {
+	return refuseDisabled(w, r, h.Resource, action)
+}
+
+// refuseDisabled answers the request with 404 or 405 if res turns off action.
+func refuseDisabled(w http.ResponseWriter, r *http.Request, res Resource, action string) bool {
+	if !ActionDisabled(res, action) {
+		return false
+	}
+	if r.Method == http.MethodGet {
+		http.NotFound(w, r)
+	} else {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+	return true
+}
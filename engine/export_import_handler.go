@@ -2,11 +2,16 @@ package engine
 
 import (
 	"context"
+	"encoding/csv"
 	"fmt"
+	"io"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/bozz33/sublimego/export"
 	importer "github.com/bozz33/sublimego/import"
+	"github.com/bozz33/sublimego/table"
 )
 
 // ExportHandler serves CSV/Excel exports for a resource.
@@ -21,42 +26,229 @@ func NewExportHandler(r Resource, format export.Format) *ExportHandler {
 	return &ExportHandler{resource: r, format: format}
 }
 
-// ServeHTTP streams the export file to the client.
+// exportBatchSize is the page size used to walk a ResourceQueryable.
+const exportBatchSize = 500
+
+// ServeHTTP streams the export file to the client. It honors the same
+// filter_*, search, sort and dir parameters as the list page, so the export
+// matches what the user sees (across all pages). Records the resource policy
+// does not let the user view are left out.
 func (h *ExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	items, err := h.resource.List(r.Context())
+	if refuseExport(w, r, h.resource) {
+		return
+	}
+	lq, _, _ := parseListQuery(r.Context(), h.resource, r.URL.Query())
+	items, err := fetchAllItems(r.Context(), h.resource, lq)
 	if err != nil {
 		http.Error(w, "Failed to list items: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	items = slices.DeleteFunc(items, func(item any) bool {
+		return !policyAllows(r.Context(), h.resource, Policy.CanView, item)
+	})
 
-	format := h.format
-	if q := r.URL.Query().Get("format"); q == "xlsx" {
-		format = export.FormatExcel
-	} else if q == "csv" {
-		format = export.FormatCSV
+	h.write(w, r, items)
+}
+
+// BulkExport exports the records selected in the list (ids[] form values),
+// using the same columns as the full export. Unknown ids and records the
+// resource policy does not let the user view are skipped.
+// Register it at POST /{slug}/bulk/export?format=csv|xlsx
+func (h *ExportHandler) BulkExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if refuseExport(w, r, h.resource) {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		if writeBodyTooLarge(w, err) {
+			return
+		}
+		http.Error(w, "Form parsing error", http.StatusBadRequest)
+		return
+	}
+	ids := r.Form["ids[]"]
+	if len(ids) == 0 {
+		http.Error(w, "No items selected", http.StatusBadRequest)
+		return
 	}
 
+	items := make([]any, 0, len(ids))
+	for _, id := range ids {
+		item, err := h.resource.Get(r.Context(), id)
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			http.Error(w, "Failed to load item "+id+": "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if item != nil && policyAllows(r.Context(), h.resource, Policy.CanView, item) {
+			items = append(items, item)
+		}
+	}
+	h.write(w, r, items)
+}
+
+// requestFormat returns the format requested by ?format=, or the handler's
+// default format.
+func (h *ExportHandler) requestFormat(r *http.Request) export.Format {
+	switch r.URL.Query().Get("format") {
+	case "xlsx":
+		return export.FormatExcel
+	case "csv":
+		return export.FormatCSV
+	}
+	return h.format
+}
+
+// write sends items as an attachment in the requested format.
+func (h *ExportHandler) write(w http.ResponseWriter, r *http.Request, items []any) {
+	format := h.requestFormat(r)
 	filename := export.GenerateFilename(h.resource.Slug(), format)
 	w.Header().Set("Content-Type", export.GetContentType(format))
 	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
 
-	exp := export.New(format).FromStructs(items)
-	if err := exp.Write(w); err != nil {
+	if err := writeExport(r.Context(), w, h.resource, format, items); err != nil {
 		http.Error(w, "Export failed: "+err.Error(), http.StatusInternalServerError)
 	}
 }
 
+// writeExport writes items of res in the given format.
+func writeExport(ctx context.Context, w io.Writer, res Resource, format export.Format, items []any) error {
+	headers, rowFn, ok := exportColumns(ctx, res)
+	if !ok {
+		return export.New(format).FromStructs(items).Write(w)
+	}
+
+	if format == export.FormatCSV {
+		// Stream rows as they are formatted.
+		cw := csv.NewWriter(w)
+		_ = cw.Write(headers)
+		for _, item := range items {
+			_ = cw.Write(rowFn(item))
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	exp := export.New(format).SetHeaders(headers)
+	for _, item := range items {
+		exp.AddRow(rowFn(item))
+	}
+	return exp.Write(w)
+}
+
+// fetchAllItems returns every item of res matching the list query, walking
+// all pages of a ResourceQueryable.
+func fetchAllItems(ctx context.Context, res Resource, lq *ListQuery) ([]any, error) {
+	if _, ok := res.(ResourceQueryable); !ok {
+		items, _, _, err := fetchListItems(ctx, res, lq, lq.Filters)
+		return items, err
+	}
+
+	var all []any
+	q := *lq
+	q.PerPage = exportBatchSize
+	for q.Page = 1; ; q.Page++ {
+		items, total, _, err := fetchListItems(ctx, res, &q, q.Filters)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if len(items) < q.PerPage || len(all) >= total {
+			return all, nil
+		}
+	}
+}
+
+// exportColumns returns the export headers and row formatter of res:
+// ResourceExportable first, then the declared columns (ResourceColumns)
+// included in exports, then the resource's table columns. ok is false if
+// none is available, in which case struct fields are exported by reflection.
+func exportColumns(ctx context.Context, res Resource) (headers []string, rowFn func(item any) []string, ok bool) {
+	if exp, isExp := res.(ResourceExportable); isExp {
+		return exp.ExportHeaders(), exp.ExportRow, true
+	}
+	if declared, isDeclared := declaredColumns(ctx, res); isDeclared {
+		cols := table.ExportColumns(declared)
+		for _, col := range cols {
+			headers = append(headers, col.Label())
+		}
+		return headers, func(item any) []string {
+			row := make([]string, len(cols))
+			for i, col := range cols {
+				row[i] = col.Value(item)
+			}
+			return row
+		}, true
+	}
+	tc, isTC := res.(ResourceTableColumns)
+	if !isTC || len(tc.TableColumns()) == 0 {
+		return nil, nil, false
+	}
+	cols := tc.TableColumns()
+	for _, col := range cols {
+		headers = append(headers, col.Label)
+	}
+	return headers, func(item any) []string {
+		row := make([]string, len(cols))
+		for i, col := range cols {
+			row[i] = col.GetValue(item)
+		}
+		return row
+	}, true
+}
+
 // ResourceExportable is an optional interface for resources that support export.
 // Implement it to customise headers and row data instead of using reflection.
+// Implementing it turns the export on.
 type ResourceExportable interface {
 	ExportHeaders() []string
 	ExportRow(item any) []string
 }
 
+// ResourceExportEnabler is an optional interface for resources that turn the
+// export on without customising it (see BaseResource.EnableExport).
+type ResourceExportEnabler interface {
+	ExportEnabled() bool
+}
+
+// ExportEnabled reports whether res offers the export routes and buttons.
+// Export is opt-in: res must implement ResourceExportable or enable it with
+// ResourceExportEnabler, and not disable ActionExport.
+func ExportEnabled(res Resource) bool {
+	if ActionDisabled(res, ActionExport) {
+		return false
+	}
+	if _, ok := res.(ResourceExportable); ok {
+		return true
+	}
+	e, ok := res.(ResourceExportEnabler)
+	return ok && e.ExportEnabled()
+}
+
+// refuseExport answers the request like a disabled action (404 for pages,
+// 405 for writes) if res does not offer the export.
+func refuseExport(w http.ResponseWriter, r *http.Request, res Resource) bool {
+	if ExportEnabled(res) {
+		return false
+	}
+	if r.Method == http.MethodGet {
+		http.NotFound(w, r)
+	} else {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+	return true
+}
+
 // ImportHandler handles CSV/Excel/JSON file uploads and delegates to the resource.
 // Register it at e.g. GET+POST /{slug}/import
 type ImportHandler struct {
 	resource Resource
+	basePath string
 }
 
 // NewImportHandler creates an import handler for the given resource.
@@ -64,6 +256,13 @@ func NewImportHandler(r Resource) *ImportHandler {
 	return &ImportHandler{resource: r}
 }
 
+// WithBasePath sets the panel path the resource is mounted under, used by
+// the link back to the list.
+func (h *ImportHandler) WithBasePath(base string) *ImportHandler {
+	h.basePath = strings.TrimRight(base, "/")
+	return h
+}
+
 // ServeHTTP handles GET (show form) and POST (process upload).
 func (h *ImportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
@@ -90,6 +289,9 @@ func (h *ImportHandler) showForm(w http.ResponseWriter, r *http.Request) {
 
 func (h *ImportHandler) handleUpload(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		if writeBodyTooLarge(w, err) {
+			return
+		}
 		http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -117,8 +319,8 @@ func (h *ImportHandler) handleUpload(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	_, _ = fmt.Fprintf(w, `<p>Import complete: %d success, %d errors, %d skipped.</p>
-<a href="/%s">Back to list</a>`,
-		result.SuccessCount, result.ErrorCount, result.SkippedCount, h.resource.Slug())
+<a href="%s/%s">Back to list</a>`,
+		result.SuccessCount, result.ErrorCount, result.SkippedCount, h.basePath, h.resource.Slug())
 }
 
 // ResourceImportable is an optional interface for resources that support import.
@@ -0,0 +1,296 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bozz33/sublimego/export"
+)
+
+type exportItem struct {
+	ID     int
+	Name   string
+	Status string
+}
+
+type filterableResource struct {
+	*SimpleResource
+	items []any
+}
+
+func (r *filterableResource) ListFiltered(_ context.Context, filters map[string]string) ([]any, error) {
+	var out []any
+	for _, it := range r.items {
+		if it.(exportItem).Status == filters["status"] {
+			out = append(out, it)
+		}
+	}
+	return out, nil
+}
+
+func TestExportHandler_CSVHonorsFilters(t *testing.T) {
+	items := []any{
+		exportItem{ID: 1, Name: "Alice", Status: "active"},
+		exportItem{ID: 2, Name: "Bob", Status: "banned"},
+		exportItem{ID: 3, Name: "Carol", Status: "active"},
+	}
+	res := &filterableResource{
+		SimpleResource: NewSimpleResource("users", "User", "Users").
+			WithList(func(context.Context) ([]any, error) { return items, nil }),
+		items: items,
+	}
+	res.SetTableColumns(Column{Key: "Name", Label: "Full name"}, Column{Key: "Status", Label: "Status"}).EnableExport()
+
+	rec := httptest.NewRecorder()
+	NewExportHandler(res, export.FormatCSV).ServeHTTP(rec, httptest.NewRequest("GET", "/users/export?filter_status=active", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("expected text/csv, got %q", ct)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment;") {
+		t.Errorf("expected attachment disposition, got %q", cd)
+	}
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"Full name", "Status"}, {"Alice", "active"}, {"Carol", "active"}}
+	if len(records) != len(want) {
+		t.Fatalf("expected %v, got %v", want, records)
+	}
+	for i := range want {
+		if strings.Join(records[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("row %d: expected %v, got %v", i, want[i], records[i])
+		}
+	}
+}
+
+type queryableResource struct {
+	*SimpleResource
+	total int
+}
+
+func (r *queryableResource) ListQuery(_ context.Context, q ListQuery) ([]any, int, error) {
+	var out []any
+	for i := (q.Page - 1) * q.PerPage; i < min(q.Page*q.PerPage, r.total); i++ {
+		out = append(out, exportItem{ID: i + 1, Name: "n"})
+	}
+	return out, r.total, nil
+}
+
+func TestExportHandler_WalksAllPages(t *testing.T) {
+	res := &queryableResource{SimpleResource: NewSimpleResource("users", "User", "Users"), total: 1234}
+	res.SetTableColumns(Column{Key: "ID", Label: "ID"}).EnableExport()
+
+	rec := httptest.NewRecorder()
+	NewExportHandler(res, export.FormatCSV).ServeHTTP(rec, httptest.NewRequest("GET", "/users/export?page=3", nil))
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != res.total+1 {
+		t.Fatalf("expected %d records, got %d", res.total+1, len(records))
+	}
+	if records[len(records)-1][0] != "1234" {
+		t.Errorf("expected last ID 1234, got %s", records[len(records)-1][0])
+	}
+}
+
+type privateExportResource struct {
+	*SimpleResource
+}
+
+// Policy hides the banned users.
+func (privateExportResource) Policy() Policy {
+	return PolicyFuncs{View: func(_ context.Context, item any) bool {
+		return item.(exportItem).Status != "banned"
+	}}
+}
+
+func TestExportHandler_HonorsPolicy(t *testing.T) {
+	res := privateExportResource{NewSimpleResource("users", "User", "Users").
+		WithList(func(context.Context) ([]any, error) {
+			return []any{
+				exportItem{ID: 1, Name: "Alice", Status: "active"},
+				exportItem{ID: 2, Name: "Bob", Status: "banned"},
+				exportItem{ID: 3, Name: "Carol", Status: "active"},
+			}, nil
+		})}
+	res.SetTableColumns(Column{Key: "Name", Label: "Name"}).EnableExport()
+
+	rec := httptest.NewRecorder()
+	NewExportHandler(res, export.FormatCSV).ServeHTTP(rec, httptest.NewRequest("GET", "/users/export", nil))
+
+	if body := rec.Body.String(); strings.Contains(body, "Bob") {
+		t.Errorf("expected the hidden record left out, got %q", body)
+	}
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Errorf("expected a header and 2 records, got %v", records)
+	}
+}
+
+func TestExportHandler_BulkExport(t *testing.T) {
+	res := NewSimpleResource("users", "User", "Users").
+		WithGet(func(_ context.Context, id string) (any, error) {
+			n, _ := strconv.Atoi(id)
+			return exportItem{ID: n, Name: "user" + id}, nil
+		})
+	res.SetTableColumns(Column{Key: "ID", Label: "ID"}, Column{Key: "Name", Label: "Name"}).EnableExport()
+	h := NewExportHandler(res, export.FormatCSV)
+
+	form := url.Values{"ids[]": {"7", "3"}}
+	req := httptest.NewRequest("POST", "/users/bulk/export?format=csv", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	h.BulkExport(rec, req)
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "ID,Name|7,user7|3,user3"
+	var got []string
+	for _, r := range records {
+		got = append(got, strings.Join(r, ","))
+	}
+	if strings.Join(got, "|") != want {
+		t.Errorf("expected %q, got %q", want, strings.Join(got, "|"))
+	}
+
+	// An empty selection is rejected.
+	req = httptest.NewRequest("POST", "/users/bulk/export", strings.NewReader(""))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	h.BulkExport(rec, req)
+	if rec.Code != 400 {
+		t.Errorf("expected 400 without ids, got %d", rec.Code)
+	}
+}
+
+func TestExportHandler_BulkExportSkipsHiddenAndUnknown(t *testing.T) {
+	base := NewSimpleResource("users", "User", "Users").
+		WithGet(func(_ context.Context, id string) (any, error) {
+			switch id {
+			case "1":
+				return exportItem{ID: 1, Name: "Alice", Status: "active"}, nil
+			case "2":
+				return exportItem{ID: 2, Name: "Bob", Status: "banned"}, nil
+			}
+			return nil, ErrNotFound
+		})
+	base.SetTableColumns(Column{Key: "Name", Label: "Name"}).EnableExport()
+	res := privateExportResource{base}
+
+	post := func(h *ExportHandler) *httptest.ResponseRecorder {
+		form := url.Values{"ids[]": {"1", "2", "404"}}
+		req := httptest.NewRequest("POST", "/users/bulk/export?format=csv", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		h.BulkExport(rec, req)
+		return rec
+	}
+
+	rec := post(NewExportHandler(res, export.FormatCSV))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 despite the unknown id, got %d: %s", rec.Code, rec.Body)
+	}
+	if got := strings.TrimSpace(rec.Body.String()); got != "Name\nAlice" {
+		t.Errorf("expected only the visible record, got %q", got)
+	}
+
+	// A disabled export refuses the bulk action too.
+	base.DisableExport()
+	if rec := post(NewExportHandler(res, export.FormatCSV)); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 with export disabled, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	NewExportHandler(res, export.FormatCSV).ServeHTTP(rec, httptest.NewRequest("GET", "/users/export", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 on the export page with export disabled, got %d", rec.Code)
+	}
+}
+
+func TestExportHandler_OptIn(t *testing.T) {
+	res := NewSimpleResource("users", "User", "Users").
+		WithList(func(context.Context) ([]any, error) {
+			return []any{exportItem{ID: 1, Name: "Alice"}}, nil
+		})
+	res.SetTableColumns(Column{Key: "Name", Label: "Name"})
+	ctx := context.WithValue(context.Background(), ContextKeyResource, Resource(res))
+
+	// Off by default: no routes, no button, no bulk action.
+	rec := httptest.NewRecorder()
+	NewExportHandler(res, export.FormatCSV).ServeHTTP(rec, httptest.NewRequest("GET", "/users/export", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 on the export page by default, got %d", rec.Code)
+	}
+	state, err := res.BuildTableState(ctx, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.ExportURL != "" || len(state.BulkActions) != 0 {
+		t.Errorf("expected no export button by default, got %q and %+v", state.ExportURL, state.BulkActions)
+	}
+
+	res.EnableExport()
+	rec = httptest.NewRecorder()
+	NewExportHandler(res, export.FormatCSV).ServeHTTP(rec, httptest.NewRequest("GET", "/users/export", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 once enabled, got %d", rec.Code)
+	}
+	if state, _ = res.BuildTableState(ctx, true, true); state.ExportURL != "/users/export" || len(state.BulkActions) != 1 {
+		t.Errorf("expected the export button once enabled, got %q and %+v", state.ExportURL, state.BulkActions)
+	}
+}
+
+func TestExportImportResource_RoundTrip(t *testing.T) {
+	items := []any{
+		exportItem{ID: 1, Name: "Alice", Status: "active"},
+		exportItem{ID: 2, Name: "Bob", Status: "banned"},
+	}
+	var created []url.Values
+	res := NewSimpleResource("users", "User", "Users").
+		WithList(func(context.Context) ([]any, error) { return items, nil }).
+		WithCreate(func(_ context.Context, r *http.Request) error {
+			if err := r.ParseForm(); err != nil {
+				return err
+			}
+			if r.PostForm.Get("Name") == "" {
+				return errors.New("name is required")
+			}
+			created = append(created, r.PostForm)
+			return nil
+		})
+	res.SetTableColumns(Column{Key: "Name", Label: "Full name"}, Column{Key: "Status", Label: "Status"})
+
+	var buf bytes.Buffer
+	n, err := ExportResource(context.Background(), res, &buf, export.FormatCSV)
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 exported records, got %d (%v)", n, err)
+	}
+	buf.WriteString(",archived\n")
+
+	result, err := ImportResource(context.Background(), res, &buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.SuccessCount != 2 || result.ErrorCount != 1 {
+		t.Errorf("expected 2 imported and 1 failed row, got %d/%d", result.SuccessCount, result.ErrorCount)
+	}
+	if len(created) != 2 || created[1].Get("Name") != "Bob" || created[1].Get("Status") != "banned" {
+		t.Errorf("expected rows posted with column keys, got %v", created)
+	}
+}
@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContextKeyFilterRanges holds the map[string]FilterRange of a list request.
+const ContextKeyFilterRanges contextKey = "filter_ranges"
+
+// rangeSuffixes maps the suffix of a paired filter param to its bound.
+var rangeSuffixes = map[string]bool{
+	"_min":  true, // lower bound of a table.RangeFilter
+	"_max":  false,
+	"_from": true, // start date of a table.DateRangeFilter
+	"_to":   false,
+}
+
+// filterDateLayout is the format submitted by date inputs.
+const filterDateLayout = "2006-01-02"
+
+// FilterRange holds the bounds of a range filter, taken from
+// filter_{key}_min and filter_{key}_max (or _from and _to for date ranges).
+// An empty bound means the range is open on that side.
+type FilterRange struct {
+	Min string
+	Max string
+}
+
+// MinFloat returns the lower bound as a number; ok is false if it is unset
+// or not numeric.
+func (r FilterRange) MinFloat() (float64, bool) { return parseBound(r.Min) }
+
+// MaxFloat returns the upper bound as a number; ok is false if it is unset
+// or not numeric.
+func (r FilterRange) MaxFloat() (float64, bool) { return parseBound(r.Max) }
+
+// ContainsFloat reports whether n lies within the range (bounds included).
+// Unset or invalid bounds are ignored.
+func (r FilterRange) ContainsFloat(n float64) bool {
+	if lo, ok := r.MinFloat(); ok && n < lo {
+		return false
+	}
+	if hi, ok := r.MaxFloat(); ok && n > hi {
+		return false
+	}
+	return true
+}
+
+// FromTime returns the start of the lower bound day; ok is false if it is
+// unset or not a YYYY-MM-DD date.
+func (r FilterRange) FromTime() (time.Time, bool) {
+	return parseDateBound(r.Min)
+}
+
+// ToTime returns the end of the upper bound day, so records created at any
+// time on that day are included; ok is false if it is unset or invalid.
+func (r FilterRange) ToTime() (time.Time, bool) {
+	t, ok := parseDateBound(r.Max)
+	if !ok {
+		return t, false
+	}
+	return t.AddDate(0, 0, 1).Add(-time.Nanosecond), true
+}
+
+// ContainsTime reports whether t lies within the date range (both days
+// included). Unset or invalid bounds are ignored.
+func (r FilterRange) ContainsTime(t time.Time) bool {
+	if from, ok := r.FromTime(); ok && t.Before(from) {
+		return false
+	}
+	if to, ok := r.ToTime(); ok && t.After(to) {
+		return false
+	}
+	return true
+}
+
+func parseDateBound(s string) (time.Time, bool) {
+	if s == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(filterDateLayout, strings.TrimSpace(s))
+	return t, err == nil
+}
+
+func parseBound(s string) (float64, bool) {
+	if s == "" {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return f, err == nil
+}
+
+// GetFilterRanges retrieves the active range filters from context, keyed by
+// filter key (set by CRUDHandler.List).
+func GetFilterRanges(ctx context.Context) map[string]FilterRange {
+	if r, ok := ctx.Value(ContextKeyFilterRanges).(map[string]FilterRange); ok {
+		return r
+	}
+	return nil
+}
+
+// collectRanges groups the paired bound params of filters into ranges.
+// The flat entries (e.g. "price_min") are left in filters so templates can
+// re-populate the inputs.
+func collectRanges(filters map[string]string) map[string]FilterRange {
+	var ranges map[string]FilterRange
+	for key, value := range filters {
+		for suffix, lower := range rangeSuffixes {
+			base, ok := strings.CutSuffix(key, suffix)
+			if !ok || base == "" {
+				continue
+			}
+			if ranges == nil {
+				ranges = make(map[string]FilterRange)
+			}
+			r := ranges[base]
+			if lower {
+				r.Min = value
+			} else {
+				r.Max = value
+			}
+			ranges[base] = r
+		}
+	}
+	return ranges
+}
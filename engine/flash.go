@@ -0,0 +1,27 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/bozz33/sublimego/flash"
+)
+
+// Flash queues a message shown on the next full page, e.g. after the
+// redirect following a create. level is one of the flash.Type* constants.
+// It is a no-op when the panel has no session manager.
+func Flash(ctx context.Context, level, message string) {
+	if m := flash.ManagerFromContext(ctx); m != nil {
+		m.Add(ctx, flash.NewMessage(level, message))
+	}
+}
+
+// withFlashes moves the pending flash messages into ctx, where the layout
+// renders them. Call it before writing the response: the session is saved
+// on the first write, so clearing the messages later would not stick.
+func withFlashes(ctx context.Context) context.Context {
+	m := flash.ManagerFromContext(ctx)
+	if m == nil || !m.Has(ctx) {
+		return ctx
+	}
+	return flash.WithMessages(ctx, m.GetAndClear(ctx))
+}
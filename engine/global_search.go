@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bozz33/sublimego/search"
+)
+
+// ResourceGloballySearchable is an optional interface for resources listed
+// in the global search (/api/search). Searchable returns the fields matched
+// against the query, the first one giving the title of the results:
+//
+//	func (r *UserResource) Searchable() []string { return []string{"name", "email"} }
+//
+// The candidates come from the resource's ResourceSearchable or, failing
+// that, ResourceQueryable implementation: resources with neither are not
+// listed. Results hidden by the resource Policy (CanView) are left out.
+// The panel registers these resources with search.RegisterFunc under their
+// slug when it builds its router.
+type ResourceGloballySearchable interface {
+	Searchable() []string
+}
+
+// globalSearchLimit caps the candidates of a resource queried through
+// ResourceQueryable.
+const globalSearchLimit = 50
+
+// registerGlobalSearch registers res in the global search if it opts in.
+func (p *Panel) registerGlobalSearch(res Resource) {
+	gs, ok := res.(ResourceGloballySearchable)
+	if !ok || len(gs.Searchable()) == 0 || !globallySearchable(res) {
+		return
+	}
+	fields := gs.Searchable()
+	base := strings.TrimRight(p.Path, "/") + "/" + res.Slug()
+
+	search.RegisterFunc(res.Slug(), func(ctx context.Context, query string) []search.Result {
+		if !res.CanRead(ctx) {
+			return nil
+		}
+		items, err := globalSearchItems(ctx, res, query)
+		if err != nil {
+			return nil
+		}
+		var results []search.Result
+		for _, item := range items {
+			if !policyAllows(ctx, res, Policy.CanView, item) {
+				continue
+			}
+			score, matched := 0.0, ""
+			for _, f := range fields {
+				value := getFieldString(item, f)
+				if s := search.CalculateScore(query, value); s > score {
+					score, matched = s, value
+				}
+			}
+			if score == 0 {
+				continue
+			}
+			id := getItemID(item)
+			result := search.Result{
+				ID:           id,
+				Title:        getFieldString(item, fields[0]),
+				URL:          recordURL(res, base, id),
+				Icon:         res.Icon(),
+				ResourceType: res.PluralLabel(),
+				Slug:         res.Slug(),
+				Score:        score,
+			}
+			if matched != result.Title {
+				result.Subtitle = matched
+			}
+			results = append(results, result)
+		}
+		return results
+	}).SetIcon(res.Icon()).SetPriority(res.Sort()).SetFields(fields...)
+}
+
+// globallySearchable reports whether res can look up global search
+// candidates without loading all its records.
+func globallySearchable(res Resource) bool {
+	switch res.(type) {
+	case ResourceSearchable, ResourceQueryable:
+		return true
+	}
+	return false
+}
+
+// globalSearchItems returns the candidates of a global search: the items
+// found by the resource's own search, or the first page of its query.
+func globalSearchItems(ctx context.Context, res Resource, query string) ([]any, error) {
+	if s, ok := res.(ResourceSearchable); ok {
+		return s.Search(ctx, query)
+	}
+	items, _, err := res.(ResourceQueryable).ListQuery(ctx, ListQuery{
+		Filters: map[string]string{},
+		Search:  query,
+		Page:    1,
+		PerPage: globalSearchLimit,
+		SortDir: "asc",
+	})
+	return items, err
+}
+
+// recordURL returns the page a search result links to: the record's view,
+// else its edit form, else the resource list when both are disabled.
+func recordURL(res Resource, base, id string) string {
+	if _, ok := res.(ResourceViewable); ok && !ActionDisabled(res, ActionView) {
+		return base + "/" + id
+	}
+	if !ActionDisabled(res, ActionEdit) {
+		return base + "/" + id + "/edit"
+	}
+	return base
+}
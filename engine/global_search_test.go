@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/bozz33/sublimego/auth"
+	"github.com/bozz33/sublimego/search"
+)
+
+type person struct {
+	ID    int
+	Name  string
+	Email string
+}
+
+type searchableContacts struct {
+	*SimpleResource
+	policy   Policy
+	disabled []string
+}
+
+func (r *searchableContacts) Searchable() []string { return []string{"name", "email"} }
+
+func (r *searchableContacts) Search(ctx context.Context, _ string) ([]any, error) {
+	return r.List(ctx)
+}
+
+func (r *searchableContacts) Policy() Policy { return r.policy }
+
+// listedContacts opts in the global search without a search method.
+type listedContacts struct {
+	*SimpleResource
+}
+
+func (r *listedContacts) Searchable() []string { return []string{"name"} }
+
+func (r *searchableContacts) DisabledActions() []string { return r.disabled }
+
+// contactList lists three people, two of them matching "ada".
+func contactList(context.Context) ([]any, error) {
+	return []any{
+		&person{ID: 1, Name: "Ada Lovelace", Email: "ada@example.com"},
+		&person{ID: 2, Name: "Grace Hopper", Email: "grace@navy.mil"},
+		&person{ID: 3, Name: "Alan Turing", Email: "alan@ada-labs.org"},
+	}, nil
+}
+
+// mountSearch builds a panel with resources, registering the global search.
+func mountSearch(resources ...Resource) {
+	sessions := scs.New()
+	NewPanel("search-test").
+		WithAuthManager(auth.NewManager(sessions)).
+		WithSession(sessions).
+		EnableNotifications(false).
+		AddResources(resources...).
+		Handler("/admin")
+}
+
+func TestPanel_GlobalSearch(t *testing.T) {
+	search.Clear()
+	defer search.Clear()
+
+	contacts := &searchableContacts{SimpleResource: NewSimpleResource("contacts", "Contact", "Contacts").WithIcon("person").WithList(contactList)}
+	notes := NewSimpleResource("notes", "Note", "Notes").WithList(contactList) // not opted in
+	// Opted in, but without a way to search short of listing everything.
+	people := &listedContacts{NewSimpleResource("people", "Person", "People").WithList(contactList)}
+	mountSearch(contacts, notes, people)
+
+	if search.Count() != 1 {
+		t.Fatalf("expected only the opted-in resource registered, got %d", search.Count())
+	}
+
+	results, err := search.QuickSearch(context.Background(), "ada")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %+v", results)
+	}
+	first := results[0]
+	if first.Title != "Ada Lovelace" || first.Slug != "contacts" || first.URL != "/admin/contacts/1/edit" {
+		t.Errorf("unexpected first result: %+v", first)
+	}
+	if first.ResourceType != "Contacts" || first.Icon != "person" {
+		t.Errorf("expected the resource label and icon, got %+v", first)
+	}
+	// Matched on the email: the title is still the name.
+	if second := results[1]; second.Title != "Alan Turing" || second.Subtitle != "alan@ada-labs.org" {
+		t.Errorf("unexpected second result: %+v", second)
+	}
+}
+
+func TestPanel_GlobalSearch_PolicyAndDisabledActions(t *testing.T) {
+	search.Clear()
+	defer search.Clear()
+
+	contacts := &searchableContacts{
+		SimpleResource: NewSimpleResource("contacts", "Contact", "Contacts").WithList(contactList),
+		policy: PolicyFuncs{View: func(_ context.Context, item any) bool {
+			return item.(*person).ID != 3
+		}},
+		disabled: []string{ActionEdit},
+	}
+	mountSearch(contacts)
+
+	results, err := search.QuickSearch(context.Background(), "ada")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Title != "Ada Lovelace" {
+		t.Fatalf("expected the policy to hide Alan Turing, got %+v", results)
+	}
+	if results[0].URL != "/admin/contacts" {
+		t.Errorf("expected no edit link with edit disabled, got %q", results[0].URL)
+	}
+}
@@ -0,0 +1,277 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/bozz33/sublimego/flash"
+)
+
+// Lifecycle hooks a resource can implement, one interface per hook. The CRUD
+// and API handlers call them around Create, Update, Delete and BulkDelete
+// (once per record); imports run the create hooks too. Inline cell edits and
+// row actions do not run them.
+//
+// An error from a Before* hook aborts the mutation and its message is shown
+// to the user. An error from an After* hook is logged and the mutation kept,
+// unless the handler was configured with WithAfterHookRollback.
+type (
+	// ResourceBeforeCreate runs before Create, e.g. to hash a password.
+	ResourceBeforeCreate interface {
+		BeforeCreate(ctx context.Context, r *http.Request) error
+	}
+	// ResourceAfterCreate runs after a successful Create. item is the record
+	// passed to SetCreatedRecord by Create, or the submitted form values
+	// (url.Values) when Create did not call it.
+	ResourceAfterCreate interface {
+		AfterCreate(ctx context.Context, item any) error
+	}
+	// ResourceBeforeUpdate runs before Update.
+	ResourceBeforeUpdate interface {
+		BeforeUpdate(ctx context.Context, id string, r *http.Request) error
+	}
+	// ResourceAfterUpdate runs after a successful Update with the record as
+	// reloaded by Get (nil if it cannot be loaded).
+	ResourceAfterUpdate interface {
+		AfterUpdate(ctx context.Context, id string, item any) error
+	}
+	// ResourceBeforeDelete runs before Delete.
+	ResourceBeforeDelete interface {
+		BeforeDelete(ctx context.Context, id string) error
+	}
+	// ResourceAfterDelete runs after a successful Delete.
+	ResourceAfterDelete interface {
+		AfterDelete(ctx context.Context, id string) error
+	}
+)
+
+// ResourceTransactional is an optional interface for resources that can run
+// a mutation and its hooks in one database transaction. fn must use the
+// context it is given; returning its error rolls the transaction back. With
+// Ent:
+//
+//	func (r *UserResource) Transaction(ctx context.Context, fn func(context.Context) error) error {
+//		tx, err := r.client.Tx(ctx)
+//		if err != nil {
+//			return err
+//		}
+//		if err := fn(ent.NewTxContext(ctx, tx)); err != nil {
+//			_ = tx.Rollback()
+//			return err
+//		}
+//		return tx.Commit()
+//	}
+//
+// and Create, Update and Delete use ent.TxFromContext(ctx) when it is set.
+// Without it, WithAfterHookRollback reports After* errors as failures but
+// cannot undo the mutation.
+type ResourceTransactional interface {
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// HookError is returned when a lifecycle hook fails. Its message is the
+// hook's, so it can be shown as is.
+type HookError struct {
+	Hook string // e.g. "BeforeCreate"
+	Err  error
+}
+
+func (e *HookError) Error() string { return e.Err.Error() }
+
+func (e *HookError) Unwrap() error { return e.Err }
+
+// isHookError reports whether err comes from a lifecycle hook.
+func isHookError(err error) bool {
+	var he *HookError
+	return errors.As(err, &he)
+}
+
+type createdRecordKey struct{}
+
+// SetCreatedRecord hands the record Create just inserted to the AfterCreate
+// hook and the audit log. Call it from Create once the record is saved:
+//
+//	u, err := r.client.User.Create().SetName(name).Save(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	engine.SetCreatedRecord(ctx, u)
+func SetCreatedRecord(ctx context.Context, item any) {
+	if slot, ok := ctx.Value(createdRecordKey{}).(*any); ok {
+		*slot = item
+	}
+}
+
+// hookRunner runs mutations of a resource with its lifecycle hooks.
+type hookRunner struct {
+	res      Resource
+	rollback bool // After* errors fail the mutation
+}
+
+// run calls before, mutate and after in order, in a transaction when the
+// resource implements ResourceTransactional. Each step gets the context of
+// the transaction.
+func (hr hookRunner) run(ctx context.Context, before, mutate, after func(ctx context.Context) error) error {
+	steps := func(ctx context.Context) error {
+		if err := before(ctx); err != nil {
+			return err
+		}
+		if err := mutate(ctx); err != nil {
+			return err
+		}
+		return after(ctx)
+	}
+	if tx, ok := hr.res.(ResourceTransactional); ok {
+		return tx.Transaction(ctx, steps)
+	}
+	return steps(ctx)
+}
+
+// after handles the error of an After* hook: returned when rollback is on,
+// logged otherwise.
+func (hr hookRunner) after(ctx context.Context, hook, id string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if hr.rollback {
+		return &HookError{Hook: hook, Err: err}
+	}
+	slog.ErrorContext(ctx, "resource hook failed",
+		slog.String("resource", hr.res.Slug()),
+		slog.String("hook", hook),
+		slog.String("record_id", id),
+		slog.Any("error", err))
+	return nil
+}
+
+// create runs Create with the BeforeCreate and AfterCreate hooks and
+// returns the record passed to SetCreatedRecord, if any.
+func (hr hookRunner) create(r *http.Request) (created any, err error) {
+	// Parse the form once so the copies handed to the hooks and Create share
+	// it with r.
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	err = hr.run(r.Context(),
+		func(ctx context.Context) error {
+			if hook, ok := hr.res.(ResourceBeforeCreate); ok {
+				if err := hook.BeforeCreate(ctx, r.WithContext(ctx)); err != nil {
+					return &HookError{Hook: "BeforeCreate", Err: err}
+				}
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			created = nil
+			ctx = context.WithValue(ctx, createdRecordKey{}, &created)
+			return hr.res.Create(ctx, r.WithContext(ctx))
+		},
+		func(ctx context.Context) error {
+			hook, ok := hr.res.(ResourceAfterCreate)
+			if !ok {
+				return nil
+			}
+			item := created
+			if item == nil {
+				item = r.PostForm
+			}
+			id := ""
+			if created != nil {
+				id = getItemID(created)
+			}
+			return hr.after(ctx, "AfterCreate", id, hook.AfterCreate(ctx, item))
+		})
+	return created, err
+}
+
+// update runs Update with the BeforeUpdate and AfterUpdate hooks.
+func (hr hookRunner) update(r *http.Request, id string) error {
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	return hr.run(r.Context(),
+		func(ctx context.Context) error {
+			if hook, ok := hr.res.(ResourceBeforeUpdate); ok {
+				if err := hook.BeforeUpdate(ctx, id, r.WithContext(ctx)); err != nil {
+					return &HookError{Hook: "BeforeUpdate", Err: err}
+				}
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			return hr.res.Update(ctx, id, r.WithContext(ctx))
+		},
+		func(ctx context.Context) error {
+			hook, ok := hr.res.(ResourceAfterUpdate)
+			if !ok {
+				return nil
+			}
+			item, err := hr.res.Get(ctx, id)
+			if err != nil {
+				item = nil
+			}
+			return hr.after(ctx, "AfterUpdate", id, hook.AfterUpdate(ctx, id, item))
+		})
+}
+
+// delete runs mutate, a Delete or BulkDelete of ids, with the BeforeDelete
+// and AfterDelete hooks called for each id. A failing BeforeDelete aborts
+// the whole batch.
+func (hr hookRunner) delete(ctx context.Context, ids []string, mutate func(ctx context.Context) error) error {
+	return hr.run(ctx,
+		func(ctx context.Context) error {
+			hook, ok := hr.res.(ResourceBeforeDelete)
+			if !ok {
+				return nil
+			}
+			for _, id := range ids {
+				if err := hook.BeforeDelete(ctx, id); err != nil {
+					return &HookError{Hook: "BeforeDelete", Err: err}
+				}
+			}
+			return nil
+		},
+		mutate,
+		func(ctx context.Context) error {
+			hook, ok := hr.res.(ResourceAfterDelete)
+			if !ok {
+				return nil
+			}
+			for _, id := range ids {
+				if err := hr.after(ctx, "AfterDelete", id, hook.AfterDelete(ctx, id)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+}
+
+// createdID returns the ID of a created record: the one passed to
+// SetCreatedRecord, or the submitted "id" field.
+func createdID(r *http.Request, created any) string {
+	if created != nil {
+		if id := getItemID(created); id != "" {
+			return id
+		}
+	}
+	return r.PostFormValue("id")
+}
+
+// writeHookError answers a request whose lifecycle hook failed: the hook's
+// message is flashed and the user sent back to back, or written as a 422
+// when there is no session to flash it with. It returns false for other
+// errors.
+func (h *CRUDHandler) writeHookError(w http.ResponseWriter, r *http.Request, err error, back string) bool {
+	if !isHookError(err) {
+		return false
+	}
+	if flash.ManagerFromContext(r.Context()) == nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return true
+	}
+	Flash(r.Context(), flash.TypeError, err.Error())
+	http.Redirect(w, r, back, http.StatusSeeOther)
+	return true
+}
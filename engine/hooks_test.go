@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type member struct {
+	ID   int
+	Name string
+}
+
+type memberResource struct {
+	*SimpleResource
+	calls []string
+	fail  string // hook returning an error
+}
+
+func (m *memberResource) hook(name string) error {
+	m.calls = append(m.calls, name)
+	if m.fail == name {
+		return errors.New(name + " refused")
+	}
+	return nil
+}
+
+func (m *memberResource) BeforeCreate(_ context.Context, r *http.Request) error {
+	r.Form.Set("name", strings.ToUpper(r.FormValue("name")))
+	return m.hook("BeforeCreate")
+}
+
+func (m *memberResource) AfterCreate(_ context.Context, item any) error {
+	if item.(member).Name != "ADA" {
+		return errors.New("unexpected record")
+	}
+	return m.hook("AfterCreate")
+}
+
+func (m *memberResource) AfterUpdate(context.Context, string, any) error {
+	return m.hook("AfterUpdate")
+}
+
+func (m *memberResource) BeforeDelete(_ context.Context, id string) error {
+	return m.hook("BeforeDelete " + id)
+}
+
+func newMemberResource() *memberResource {
+	m := &memberResource{}
+	m.SimpleResource = NewSimpleResource("members", "Member", "Members").
+		WithGet(func(_ context.Context, id string) (any, error) {
+			return member{ID: 1}, nil
+		}).
+		WithCreate(func(ctx context.Context, r *http.Request) error {
+			m.calls = append(m.calls, "Create")
+			SetCreatedRecord(ctx, member{ID: 2, Name: r.FormValue("name")})
+			return nil
+		}).
+		WithUpdate(func(context.Context, string, *http.Request) error {
+			m.calls = append(m.calls, "Update")
+			return nil
+		}).
+		WithDelete(func(context.Context, string) error {
+			m.calls = append(m.calls, "Delete")
+			return nil
+		})
+	return m
+}
+
+func TestCRUDHandler_Hooks(t *testing.T) {
+	serve := func(h http.Handler, method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	res := newMemberResource()
+	if rec := serve(NewCRUDHandler(res), http.MethodPost, "/members", "name=ada"); rec.Code != http.StatusSeeOther {
+		t.Fatalf("create: expected 303, got %d (%s)", rec.Code, rec.Body)
+	}
+	if got := strings.Join(res.calls, ","); got != "BeforeCreate,Create,AfterCreate" {
+		t.Errorf("unexpected create calls %s", got)
+	}
+
+	res = newMemberResource()
+	res.fail = "BeforeDelete 1"
+	rec := serve(NewCRUDHandler(res), http.MethodDelete, "/members/1", "")
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), "BeforeDelete 1 refused") {
+		t.Errorf("delete: expected 422 with the hook message, got %d (%s)", rec.Code, rec.Body)
+	}
+	if got := strings.Join(res.calls, ","); got != "BeforeDelete 1" {
+		t.Errorf("expected the delete to be aborted, got calls %s", got)
+	}
+
+	res = newMemberResource()
+	res.fail = "AfterUpdate"
+	if rec := serve(NewCRUDHandler(res), http.MethodPost, "/members/1", "name=x"); rec.Code != http.StatusSeeOther {
+		t.Errorf("update: expected After* errors to be logged only, got %d", rec.Code)
+	}
+	req := httptest.NewRequest(http.MethodPut, "/api/members/1", strings.NewReader(`{"name":"x"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	NewAPIHandler(res).WithAfterHookRollback(true).ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), "AfterUpdate refused") {
+		t.Errorf("API update with rollback: expected 422, got %d (%s)", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,44 @@
+package engine
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/bozz33/sublimego/apperrors"
+	"github.com/bozz33/sublimego/internal/ent"
+)
+
+// ErrNotFound can be returned (or wrapped) by resources when a record does
+// not exist. CRUD handlers answer it with a 404.
+var ErrNotFound = errors.New("record not found")
+
+// isNotFound reports whether err means "no such record" rather than a real
+// failure: ErrNotFound, sql.ErrNoRows, an apperrors 404 or a NotFoundError
+// of the framework's Ent client. Resources using another Ent client should
+// wrap ErrNotFound.
+func isNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrNotFound) || errors.Is(err, sql.ErrNoRows) || ent.IsNotFound(err) {
+		return true
+	}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if appErr, ok := e.(*apperrors.AppError); ok && appErr.StatusCode == http.StatusNotFound {
+			return true
+		}
+	}
+	return false
+}
+
+// writeLookupError answers a failed record lookup: 404 when the record does
+// not exist (or item is nil), 500 through the error handler otherwise, so a
+// database outage is not reported as a missing record.
+func writeLookupError(w http.ResponseWriter, r *http.Request, label string, err error) {
+	if err == nil || isNotFound(err) {
+		apperrors.Handle(w, r, apperrors.NotFoundf("%s not found", label))
+		return
+	}
+	apperrors.Handle(w, r, apperrors.Internalf(err, "Failed to load %s", label))
+}
@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/bozz33/sublimego/middleware"
+)
+
+// overrideMethods lists the methods a POST form may emulate with _method.
+var overrideMethods = map[string]bool{
+	http.MethodDelete: true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+}
+
+// methodOverride returns the method requested by the _method field of a
+// POST body ("" if none). ok is false for any value other than DELETE, PUT
+// or PATCH. The query string is never consulted.
+func methodOverride(r *http.Request) (method string, ok bool) {
+	values, present := r.PostForm["_method"]
+	if !present {
+		return "", true
+	}
+	method = strings.ToUpper(strings.TrimSpace(values[0]))
+	return method, len(values) == 1 && overrideMethods[method]
+}
+
+// validCSRFToken reports whether the request carries the CSRF token of its
+// cookie, in the X-CSRF-Token header or the _token form field (the same
+// double-submit check as middleware.CSRFManager).
+func validCSRFToken(r *http.Request) bool {
+	cfg := middleware.DefaultCSRFConfig()
+	cookie, err := r.Cookie(cfg.CookieName)
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+	token := r.Header.Get(cfg.HeaderName)
+	if token == "" {
+		token = r.PostFormValue(cfg.FormFieldName)
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(cookie.Value)) == 1
+}
+
+// withCSRFToken makes sure the client holds a CSRF cookie and stores its
+// token in the request context, so rendered forms can submit it.
+func withCSRFToken(w http.ResponseWriter, r *http.Request) *http.Request {
+	cfg := middleware.DefaultCSRFConfig()
+	if cookie, err := r.Cookie(cfg.CookieName); err == nil && cookie.Value != "" {
+		return r.WithContext(middleware.WithCSRFToken(r.Context(), cookie.Value))
+	}
+	b := make([]byte, cfg.TokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return r
+	}
+	token := base64.URLEncoding.EncodeToString(b)
+	http.SetCookie(w, &http.Cookie{
+		Name:     cfg.CookieName,
+		Value:    token,
+		Path:     cfg.CookiePath,
+		MaxAge:   cfg.CookieMaxAge,
+		Secure:   cfg.Secure,
+		SameSite: cfg.SameSite,
+	})
+	return r.WithContext(middleware.WithCSRFToken(r.Context(), token))
+}
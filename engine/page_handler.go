@@ -30,5 +30,5 @@ func (h *PageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	content := h.page.Render(ctx, r)
 
 	// Wrap in the base layout
-	layouts.Page(h.page.Label(), content).Render(ctx, w)
+	layouts.Page(h.page.Label(), content).Render(withFlashes(ctx), w)
 }
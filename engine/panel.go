@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"bytes"
 	"compress/gzip"
 	"encoding/json"
 	"fmt"
@@ -12,7 +13,9 @@ import (
 
 	"github.com/alexedwards/scs/v2"
 	"github.com/bozz33/sublimego/auth"
+	"github.com/bozz33/sublimego/color"
 	"github.com/bozz33/sublimego/export"
+	"github.com/bozz33/sublimego/flash"
 	"github.com/bozz33/sublimego/internal/ent"
 	"github.com/bozz33/sublimego/mailer"
 	"github.com/bozz33/sublimego/middleware"
@@ -41,7 +44,7 @@ type Panel struct {
 	BrandName    string
 	Logo         string
 	Favicon      string
-	PrimaryColor string // blue, green, red, purple, orange, pink, indigo
+	PrimaryColor string // blue, green, red, purple, orange, pink, indigo or a hex color
 	DarkMode     bool
 
 	Registration      bool
@@ -49,6 +52,7 @@ type Panel struct {
 	PasswordReset     bool
 	Profile           bool
 	Notifications     bool
+	API               bool
 
 	DB          *ent.Client
 	Resources   []Resource
@@ -63,6 +67,38 @@ type Panel struct {
 	// Custom middleware applied to all protected routes
 	Middlewares []func(http.Handler) http.Handler
 
+	// MaxBodySize caps request bodies on resource routes, in bytes.
+	// Resources can override it by implementing ResourceBodyLimiter.
+	MaxBodySize int64
+
+	// APIToken, when set, lets protected routes be read with an
+	// "Authorization: Bearer" token (GET/HEAD only). See WithAPIToken.
+	APIToken func(token string) (userID int, ok bool)
+
+	// PostLoginRedirect chooses the landing page after login when no
+	// intended URL was saved. See SetPostLoginRedirect.
+	PostLoginRedirect func(user *auth.User) string
+
+	// NotificationStore backs the notification endpoints. Defaults to an
+	// Ent-backed store when DB is set. See WithNotificationStore.
+	NotificationStore notifications.NotificationStore
+
+	// Auditor records resource mutations. See WithAuditor.
+	Auditor Auditor
+
+	// AfterHookRollback makes After* resource hook errors fail the request.
+	// See WithAfterHookRollback.
+	AfterHookRollback bool
+
+	// AuthRateLimit throttles login, registration and password reset
+	// submissions. Defaults to 5 per minute per IP; behind a reverse proxy,
+	// declare it with middleware.TrustProxies. See WithAuthRateLimit.
+	AuthRateLimit middleware.Middleware
+
+	// RateLimit throttles protected routes once the user is authenticated.
+	// See WithRateLimit.
+	RateLimit middleware.Middleware
+
 	// Lifecycle hooks
 	beforeBootHooks []BootHook
 	afterBootHooks  []BootHook
@@ -85,6 +121,8 @@ func NewPanel(id string) *Panel {
 		Profile:           true,
 		Notifications:     true,
 
+		MaxBodySize: DefaultMaxBodySize,
+
 		Resources: make([]Resource, 0),
 		Pages:     make([]Page, 0),
 	}
@@ -117,11 +155,16 @@ func (p *Panel) WithFavicon(url string) *Panel {
 	return p
 }
 
-// WithPrimaryColor sets the UI accent color.
-// Accepted values: "green", "blue", "red", "purple", "orange", "pink", "indigo"
-// For custom colors, use WithCustomColor() instead.
-func (p *Panel) WithPrimaryColor(color string) *Panel {
-	p.PrimaryColor = color
+// WithPrimaryColor sets the UI accent color: a palette name ("green",
+// "blue", "red", "purple", "orange", "pink", "indigo") or a brand hex color
+// ("#0ea5e9"). A hex color gets a full 50-950 palette generated around it,
+// registered as "primary" in color.Default.
+func (p *Panel) WithPrimaryColor(name string) *Panel {
+	p.PrimaryColor = name
+	if palette := color.GeneratePalette("primary", name); palette != nil {
+		color.Default.Register("primary", palette)
+		_ = color.Default.SetPrimary("primary")
+	}
 	return p
 }
 
@@ -132,13 +175,17 @@ func (p *Panel) WithPrimaryColor(color string) *Panel {
 //
 // This generates a full Tailwind-style palette and registers it as "primary".
 func (p *Panel) WithCustomColor(colorValue string) *Panel {
-	// This will be handled by the color manager during syncConfig
-	p.PrimaryColor = colorValue
-	return p
+	if strings.HasPrefix(strings.TrimSpace(colorValue), "rgb") {
+		colorValue = color.Color{}.RGB(colorValue).Hex(500)
+	}
+	return p.WithPrimaryColor(colorValue)
 }
 
+// WithDarkMode enables dark mode by default. It also makes the CSS variables
+// of color.Default include dark mode shades (see color.Manager.SetDarkMode).
 func (p *Panel) WithDarkMode(enabled bool) *Panel {
 	p.DarkMode = enabled
+	color.Default.SetDarkMode(enabled)
 	return p
 }
 
@@ -167,12 +214,92 @@ func (p *Panel) EnableNotifications(enabled bool) *Panel {
 	return p
 }
 
+// EnableAPI mounts a JSON API for every resource under {path}/api/{slug}.
+// See APIHandler.
+func (p *Panel) EnableAPI(enabled bool) *Panel {
+	p.API = enabled
+	return p
+}
+
+// WithAuthRateLimit replaces the limiter of the login, registration and
+// password reset forms, e.g. middleware.RateLimit(0.1, 5). Only
+// submissions (POST) count against it.
+func (p *Panel) WithAuthRateLimit(mw middleware.Middleware) *Panel {
+	p.AuthRateLimit = mw
+	return p
+}
+
+// WithRateLimit throttles the protected routes, e.g. with
+// middleware.RateLimit(10, 20). Unlike WithMiddleware, mw runs after
+// authentication, so middleware.KeyByUser counts requests per user.
+func (p *Panel) WithRateLimit(mw middleware.Middleware) *Panel {
+	p.RateLimit = mw
+	return p
+}
+
+// WithAuditor records every create, update and delete made through the
+// panel's resources, e.g. with audit.NewEntStore(client), and adds a
+// /{slug}/{id}/history page per record. A create is only linked to its
+// record when the resource's Create calls SetCreatedRecord; otherwise it is
+// logged with the submitted "id" field, usually empty.
+func (p *Panel) WithAuditor(a Auditor) *Panel {
+	p.Auditor = a
+	return p
+}
+
+// WithAfterHookRollback makes errors of the resources' After* lifecycle
+// hooks (AfterCreate, AfterUpdate, AfterDelete) fail the request instead of
+// only being logged. Resources implementing ResourceTransactional then have
+// the mutation rolled back.
+func (p *Panel) WithAfterHookRollback(enabled bool) *Panel {
+	p.AfterHookRollback = enabled
+	return p
+}
+
+// WithNotificationStore sets the store behind the notification endpoints
+// and notifications.Notify.
+func (p *Panel) WithNotificationStore(store notifications.NotificationStore) *Panel {
+	p.NotificationStore = store
+	return p
+}
+
 // WithMiddleware adds custom middleware to all protected routes.
 func (p *Panel) WithMiddleware(mw ...func(http.Handler) http.Handler) *Panel {
 	p.Middlewares = append(p.Middlewares, mw...)
 	return p
 }
 
+// SetMaxBodySize sets the request body limit for resource routes, in bytes
+// (default DefaultMaxBodySize). A non-positive value disables the limit.
+func (p *Panel) SetMaxBodySize(n int64) *Panel {
+	p.MaxBodySize = n
+	return p
+}
+
+// WithAPIToken exposes the panel's protected routes, such as resource lists
+// and details, to clients sending "Authorization: Bearer <token>", read-only.
+// validator resolves a token to its user ID. Requests without a token still
+// need a session.
+func (p *Panel) WithAPIToken(validator func(token string) (userID int, ok bool)) *Panel {
+	p.APIToken = validator
+	return p
+}
+
+// SetPostLoginRedirect routes users to their most relevant starting page after
+// login, e.g. by role. It is only consulted when the user was not sent to the
+// login page from another page, and an empty result means the dashboard.
+//
+//	panel.SetPostLoginRedirect(func(u *auth.User) string {
+//		if u.HasRole("support") {
+//			return "/admin/tickets"
+//		}
+//		return ""
+//	})
+func (p *Panel) SetPostLoginRedirect(fn func(user *auth.User) string) *Panel {
+	p.PostLoginRedirect = fn
+	return p
+}
+
 func (p *Panel) WithAuthManager(authManager *auth.Manager) *Panel {
 	p.AuthManager = authManager
 	return p
@@ -306,6 +433,10 @@ func toNavItems(items []navItem) []layouts.NavItem {
 
 // Router generates the standard HTTP Handler with automatic CRUD.
 // It also calls syncConfig() and plugin.BootAll() exactly once.
+//
+// Routes are registered under p.Path. To mount the panel inside an existing
+// mux under a prefix, prefer Handler, which keeps Path and the mount point
+// in sync.
 func (p *Panel) Router() http.Handler {
 	if err := p.runBeforeBoot(); err != nil {
 		panic("sublimego: before_boot hook failed: " + err.Error())
@@ -332,6 +463,7 @@ func (p *Panel) Router() http.Handler {
 		})
 	}
 	p.registerStaticRoutes(mux)
+	p.registerBrandingRoutes(mux)
 	p.registerAuthRoutes(mux)
 	p.registerCoreRoutes(mux)
 	p.registerResourceRoutes(mux)
@@ -347,6 +479,40 @@ func (p *Panel) Router() http.Handler {
 	return handler
 }
 
+// Handler sets the panel Path to prefix and returns its router, ready to be
+// mounted under an existing mux. Requests are accepted both with and without
+// the prefix, so the handler works whether or not the parent strips it:
+//
+//	mux.Handle("/admin/", panel.Handler("/admin"))
+//	mux.Handle("/admin/", http.StripPrefix("/admin", panel.Handler("/admin")))
+//
+// Redirects and the links generated by the panel (navigation, resource
+// tables, exports, imports) always include the prefix.
+func (p *Panel) Handler(prefix string) http.Handler {
+	base := strings.TrimRight(prefix, "/")
+	if base != "" && !strings.HasPrefix(base, "/") {
+		base = "/" + base
+	}
+	p.Path = base
+	router := p.Router()
+	if base == "" {
+		return router
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == base || strings.HasPrefix(r.URL.Path, base+"/") {
+			router.ServeHTTP(w, r)
+			return
+		}
+		// The parent stripped the prefix: restore it.
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = base + "/" + strings.TrimPrefix(r.URL.Path, "/")
+		if r.URL.RawPath != "" {
+			r2.URL.RawPath = base + "/" + strings.TrimPrefix(r.URL.RawPath, "/")
+		}
+		router.ServeHTTP(w, r2)
+	})
+}
+
 func (p *Panel) registerStaticRoutes(mux *http.ServeMux) {
 	fs := http.FileServer(http.FS(assets.FS))
 	base := strings.TrimRight(p.Path, "/")
@@ -359,36 +525,62 @@ func (p *Panel) registerAuthRoutes(mux *http.ServeMux) {
 		return
 	}
 	base := strings.TrimRight(p.Path, "/")
-	authHandler := NewAuthHandler(p.AuthManager, p.DB, base)
-	loginLimiter := middleware.NewRateLimiter(&middleware.RateLimitConfig{
-		RequestsPerMinute: 5, Burst: 3, KeyFunc: middleware.KeyByIP,
-	})
-	mux.Handle(base+"/login", middleware.RequireGuest(p.AuthManager, base+"/")(loginLimiter.Middleware()(authHandler)))
+	authHandler := NewAuthHandler(p.AuthManager, p.DB, base).WithPostLoginRedirect(p.PostLoginRedirect)
+	limit := p.AuthRateLimit
+	if limit == nil {
+		limit = middleware.NewRateLimiter(&middleware.RateLimitConfig{
+			RequestsPerMinute: 5, Burst: 3, KeyFunc: middleware.KeyByIP,
+		}).Middleware()
+	}
+	mux.Handle(base+"/login", middleware.RequireGuest(p.AuthManager, base+"/")(limitPOST(limit, authHandler)))
 	mux.Handle(base+"/logout", authHandler)
 	if p.Registration {
-		mux.Handle(base+"/register", middleware.RequireGuest(p.AuthManager, base+"/")(authHandler))
+		mux.Handle(base+"/register", middleware.RequireGuest(p.AuthManager, base+"/")(limitPOST(limit, authHandler)))
 	}
 	if p.Profile {
 		mux.Handle(base+"/profile", gzipMiddleware(p.protect(NewProfileHandler(p.AuthManager, p.DB))))
 	}
 	if p.PasswordReset {
-		rh := NewPasswordResetHandler(p.AuthManager, p.DB, p.Mailer, p.BaseURL)
-		mux.Handle(base+"/forgot-password", rh)
-		mux.Handle(base+"/reset-password", rh)
+		rh := limitPOST(limit, NewPasswordResetHandler(p.AuthManager, p.DB, p.Mailer, p.BaseURL))
+		mux.Handle(base+"/forgot-password", http.StripPrefix(base, rh))
+		mux.Handle(base+"/reset-password", http.StripPrefix(base, rh))
 	}
 }
 
+// limitPOST applies the rate limiting middleware mw to POST requests only,
+// so displaying a form does not use up the attempts.
+func limitPOST(mw middleware.Middleware, h http.Handler) http.Handler {
+	limited := mw(h)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			limited.ServeHTTP(w, r)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
 func (p *Panel) registerCoreRoutes(mux *http.ServeMux) {
 	base := strings.TrimRight(p.Path, "/")
 	// Dashboard
 	mux.Handle(base+"/", gzipMiddleware(p.protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		_ = dashboard.Index(widget.GetAllWidgets(r.Context())).Render(r.Context(), w)
+		_ = dashboard.Index(widget.GetAllWidgets(r.Context())).Render(withFlashes(r.Context()), w)
 	}))))
 	// Global search
 	mux.Handle(base+"/api/search", p.protect(http.HandlerFunc(p.handleSearch)))
+	// Polled dashboard widgets
+	mux.Handle(base+"/api/widgets/", p.protect(http.HandlerFunc(p.handleWidget)))
 	// Notifications
 	if p.Notifications {
-		notifHandler := notifications.NewHandler(nil, func(r *http.Request) string {
+		store := p.NotificationStore
+		if store == nil && p.DB != nil {
+			store = notifications.NewDatabaseStore(p.DB, 0)
+		}
+		if store != nil {
+			// Route notifications.Notify to the same store the bell reads.
+			notifications.SetGlobalStore(store)
+		}
+		notifHandler := notifications.NewHandler(store, func(r *http.Request) string {
 			if p.AuthManager != nil {
 				if id := p.AuthManager.UserIDFromRequest(r); id > 0 {
 					return fmt.Sprintf("%d", id)
@@ -415,6 +607,36 @@ func (p *Panel) handleSearch(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(results)
 }
 
+// widgetResponse is the JSON body of GET /api/widgets/{id}.
+type widgetResponse struct {
+	ID           string `json:"id"`
+	HTML         string `json:"html"`
+	PollInterval int64  `json:"pollInterval"` // milliseconds
+}
+
+// handleWidget renders a polled dashboard widget (see widget.Live) again,
+// with fresh data, for the dashboard to swap it in.
+func (p *Panel) handleWidget(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, strings.TrimRight(p.Path, "/")+"/api/widgets/")
+	live := widget.Find(r.Context(), id)
+	if live == nil {
+		http.NotFound(w, r)
+		return
+	}
+	var buf bytes.Buffer
+	if err := live.Render().Render(r.Context(), &buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(widgetResponse{
+		ID:           id,
+		HTML:         buf.String(),
+		PollInterval: live.GetPollInterval().Milliseconds(),
+	})
+}
+
 func (p *Panel) registerResourceRoutes(mux *http.ServeMux) {
 	for _, res := range p.Resources {
 		p.mountResource(mux, res)
@@ -424,15 +646,23 @@ func (p *Panel) registerResourceRoutes(mux *http.ServeMux) {
 func (p *Panel) mountResource(mux *http.ServeMux, res Resource) {
 	base := strings.TrimRight(p.Path, "/")
 	slug := res.Slug()
-	h := gzipMiddleware(p.protect(NewCRUDHandler(res)))
+	p.registerGlobalSearch(res)
+	h := gzipMiddleware(p.protectResource(res, NewCRUDHandler(res).WithBasePath(base).WithSession(p.Session).WithAuditor(p.Auditor).WithAfterHookRollback(p.AfterHookRollback)))
 	mux.Handle(base+"/"+slug+"/", h)
 	mux.Handle(base+"/"+slug, h)
-	mux.Handle(base+"/"+slug+"/export", p.protect(NewExportHandler(res, export.FormatCSV)))
+	exp := NewExportHandler(res, export.FormatCSV)
+	mux.Handle(base+"/"+slug+"/export", p.protectResource(res, exp))
+	mux.Handle(base+"/"+slug+"/bulk/export", p.protectResource(res, http.HandlerFunc(exp.BulkExport)))
 	if _, ok := res.(ResourceImportable); ok {
-		mux.Handle(base+"/"+slug+"/import", p.protect(NewImportHandler(res)))
+		mux.Handle(base+"/"+slug+"/import", p.protectResource(res, NewImportHandler(res).WithBasePath(base)))
 	}
 	if rm := NewRelationManagerHandler(res); rm.HasManagers() {
-		mux.Handle(base+"/"+slug+"/relations/", p.protect(rm))
+		mux.Handle(base+"/"+slug+"/relations/", p.protectResource(res, rm))
+	}
+	if p.API {
+		api := gzipMiddleware(p.protectAPI(wrapResourceMiddleware(res, limitBody(p.maxBodySizeFor(res), NewAPIHandler(res).WithBasePath(base).WithAuditor(p.Auditor).WithAfterHookRollback(p.AfterHookRollback)))))
+		mux.Handle(base+"/api/"+slug, api)
+		mux.Handle(base+"/api/"+slug+"/", api)
 	}
 }
 
@@ -451,25 +681,77 @@ func (p *Panel) EnableDebug(mux *http.ServeMux) {
 
 // protect wraps a handler with auth + any custom middlewares.
 func (p *Panel) protect(h http.Handler) http.Handler {
-	base := strings.TrimRight(p.Path, "/")
+	return p.protectWith(h, strings.TrimRight(p.Path, "/")+"/login")
+}
+
+// protectAPI is protect for JSON routes: unauthenticated requests get a 401
+// instead of a redirect to the login page.
+func (p *Panel) protectAPI(h http.Handler) http.Handler {
+	return p.protectWith(h, "")
+}
+
+// protectWith wraps a handler with auth + any custom middlewares,
+// redirecting unauthenticated visitors to redirectURL (401 when empty).
+func (p *Panel) protectWith(h http.Handler, redirectURL string) http.Handler {
+	if p.RateLimit != nil {
+		h = p.RateLimit(h)
+	}
 	h = middleware.RequireAuthWithConfig(&middleware.AuthConfig{
 		Manager:         p.AuthManager,
-		RedirectURL:     base + "/login",
-		SaveIntendedURL: true,
+		RedirectURL:     redirectURL,
+		SaveIntendedURL: redirectURL != "",
 	})(h)
+	if p.APIToken != nil {
+		h = middleware.APIToken(p.APIToken)(h)
+	}
 	for i := len(p.Middlewares) - 1; i >= 0; i-- {
 		h = p.Middlewares[i](h)
 	}
 	return h
 }
 
+// protectResource wraps a resource handler with the body size limit, the
+// resource's own middlewares (if it implements ResourceMiddleware), then
+// with protect.
+func (p *Panel) protectResource(res Resource, h http.Handler) http.Handler {
+	return p.protect(wrapResourceMiddleware(res, limitBody(p.maxBodySizeFor(res), h)))
+}
+
+// maxBodySizeFor returns the body limit for a resource's routes.
+func (p *Panel) maxBodySizeFor(res Resource) int64 {
+	if bl, ok := res.(ResourceBodyLimiter); ok {
+		return bl.MaxBodySize()
+	}
+	return p.MaxBodySize
+}
+
+// wrapResourceMiddleware applies ResourceMiddleware in declaration order.
+func wrapResourceMiddleware(res Resource, h http.Handler) http.Handler {
+	rm, ok := res.(ResourceMiddleware)
+	if !ok {
+		return h
+	}
+	mws := rm.Middleware()
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
 // injectConfig injects the Panel's PanelConfig and NavGroups into every request context.
 // This enables multi-panel setups where each panel has its own config and navigation.
 func (p *Panel) injectConfig(next http.Handler) http.Handler {
 	cfg := layouts.GetPanelConfig()
+	var flashes *flash.Manager
+	if p.Session != nil {
+		flashes = flash.NewManager(p.Session)
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := layouts.WithPanelConfig(r.Context(), cfg)
 		ctx = layouts.WithNavGroups(ctx, layouts.GetNavGroups(ctx))
+		if flashes != nil {
+			ctx = flash.WithManager(ctx, flashes)
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
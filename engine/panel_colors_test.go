@@ -1,6 +1,7 @@
 package engine_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/bozz33/sublimego/color"
@@ -77,3 +78,34 @@ func TestGenerateColorCSS(t *testing.T) {
 		t.Errorf("expected substantial CSS output, got %d bytes", len(css))
 	}
 }
+
+func TestPanelWithPrimaryColorHex(t *testing.T) {
+	p := engine.NewPanel("admin").WithPrimaryColor("#0ea5e9")
+	if p.PrimaryColor != "#0ea5e9" {
+		t.Errorf("expected PrimaryColor #0ea5e9, got %q", p.PrimaryColor)
+	}
+	if color.Default.PrimaryName() != "primary" {
+		t.Errorf("expected the generated palette to be the primary, got %q", color.Default.PrimaryName())
+	}
+	if got := color.Default.Hex("primary", 500); got != "#0ea5e9" {
+		t.Errorf("expected primary-500 #0ea5e9, got %q", got)
+	}
+
+	p.WithCustomColor("rgb(14, 165, 233)")
+	if p.PrimaryColor != "#0ea5e9" {
+		t.Errorf("expected the RGB color as hex, got %q", p.PrimaryColor)
+	}
+	_ = color.Default.SetPrimary("green")
+}
+
+func TestPanelWithDarkMode(t *testing.T) {
+	engine.NewPanel("admin").WithDarkMode(true)
+	defer color.Default.SetDarkMode(false)
+
+	if !color.Default.DarkMode() {
+		t.Fatal("expected dark mode on the color manager")
+	}
+	if css := color.Default.PrimaryCSSVars(); !strings.Contains(css, ".dark {") {
+		t.Errorf("expected a .dark block, got:\n%s", css)
+	}
+}
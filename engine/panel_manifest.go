@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/bozz33/sublimego/ui/layouts"
+)
+
+// webManifest is the subset of the Web App Manifest spec the panel fills in.
+type webManifest struct {
+	Name            string         `json:"name"`
+	ShortName       string         `json:"short_name"`
+	StartURL        string         `json:"start_url"`
+	Scope           string         `json:"scope"`
+	Display         string         `json:"display"`
+	ThemeColor      string         `json:"theme_color"`
+	BackgroundColor string         `json:"background_color"`
+	Icons           []manifestIcon `json:"icons,omitempty"`
+}
+
+type manifestIcon struct {
+	Src   string `json:"src"`
+	Sizes string `json:"sizes"`
+	Type  string `json:"type,omitempty"`
+}
+
+// registerBrandingRoutes serves /favicon.ico and /manifest.webmanifest
+// without authentication. The favicon is also served at the site root,
+// where browsers request it regardless of the panel path.
+func (p *Panel) registerBrandingRoutes(mux *http.ServeMux) {
+	base := strings.TrimRight(p.Path, "/")
+	mux.HandleFunc(base+"/favicon.ico", p.handleFavicon)
+	if base != "" {
+		mux.HandleFunc("/favicon.ico", p.handleFavicon)
+	}
+	mux.HandleFunc(base+"/manifest.webmanifest", p.handleManifest)
+}
+
+// handleFavicon redirects to the configured favicon, or answers 204 when
+// none is set so browsers stop asking.
+func (p *Panel) handleFavicon(w http.ResponseWriter, r *http.Request) {
+	if p.Favicon == "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	http.Redirect(w, r, p.Favicon, http.StatusFound)
+}
+
+func (p *Panel) handleManifest(w http.ResponseWriter, r *http.Request) {
+	start := p.Path
+	if start == "" {
+		start = "/"
+	}
+	m := webManifest{
+		Name:            p.BrandName,
+		ShortName:       p.BrandName,
+		StartURL:        start,
+		Scope:           start,
+		Display:         "standalone",
+		ThemeColor:      layouts.PrimaryHex(p.PrimaryColor),
+		BackgroundColor: "#ffffff",
+	}
+	if p.Logo != "" {
+		m.Icons = append(m.Icons, manifestIcon{
+			Src:   p.Logo,
+			Sizes: "any",
+			Type:  mime.TypeByExtension(path.Ext(p.Logo)),
+		})
+	}
+	w.Header().Set("Content-Type", "application/manifest+json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	_ = json.NewEncoder(w).Encode(m)
+}
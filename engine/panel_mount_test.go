@@ -0,0 +1,382 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/alexedwards/scs/v2"
+	"github.com/bozz33/sublimego/auth"
+	"github.com/bozz33/sublimego/widget"
+)
+
+type mountedItems struct {
+	*SimpleResource
+}
+
+func (mountedItems) ImportRow(context.Context, map[string]any) error { return nil }
+
+// newMountedPanel builds a panel with a single "items" resource, mounted
+// under /admin in a parent mux, plus a /login-as helper that opens a session.
+// The list page renders the links of the table state.
+func newMountedPanel(t *testing.T, strip bool) (*httptest.Server, *http.Client, *bool) {
+	t.Helper()
+
+	sessions := scs.New()
+	am := auth.NewManager(sessions)
+
+	created := false
+	res := mountedItems{NewSimpleResource("items", "Item", "Items").
+		WithCreate(func(_ context.Context, _ *http.Request) error {
+			created = true
+			return nil
+		})}
+	res.EnableExport()
+	res.WithTable(func(ctx context.Context) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			state, err := res.BuildTableState(ctx, true, true)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, `<a href="%s">List</a><a href="%s">New</a><a href="%s">Export</a>`,
+				state.BaseURL, state.NewURL, state.ExportURL)
+			for _, a := range state.BulkActions {
+				fmt.Fprintf(w, `<button data-url="%s">%s</button>`, a.URL, a.Label)
+			}
+			return nil
+		})
+	})
+
+	p := NewPanel("mount-test").
+		WithAuthManager(am).
+		WithSession(sessions).
+		EnableRegistration(false).
+		EnablePasswordReset(false).
+		EnableNotifications(false).
+		AddResources(res)
+
+	var h http.Handler = p.Handler("/admin")
+	if strip {
+		h = http.StripPrefix("/admin", h)
+	}
+
+	parent := http.NewServeMux()
+	parent.Handle("/admin/", h)
+	parent.Handle("/login-as", sessions.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := am.LoginWithRequest(r, auth.NewUser(1, "admin@example.com", "Admin")); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})))
+
+	srv := httptest.NewServer(parent)
+	t.Cleanup(srv.Close)
+
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{
+		Jar: jar,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return srv, client, &created
+}
+
+func TestPanel_Handler_MountedUnderParentMux(t *testing.T) {
+	for _, strip := range []bool{false, true} {
+		name := "full-path"
+		if strip {
+			name = "strip-prefix"
+		}
+		t.Run(name, func(t *testing.T) {
+			srv, client, created := newMountedPanel(t, strip)
+
+			// Guests are redirected to the prefixed login page.
+			resp, err := client.Get(srv.URL + "/admin/items")
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusFound {
+				t.Fatalf("expected 302 for guest, got %d", resp.StatusCode)
+			}
+			if loc := resp.Header.Get("Location"); loc != "/admin/login" {
+				t.Errorf("expected redirect to /admin/login, got %s", loc)
+			}
+
+			resp, err = client.Get(srv.URL + "/login-as")
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			// Authenticated list page is served by the CRUD handler, with
+			// prefixed links.
+			resp, err = client.Get(srv.URL + "/admin/items")
+			if err != nil {
+				t.Fatal(err)
+			}
+			page, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("expected 200 for list, got %d", resp.StatusCode)
+			}
+			for _, link := range []string{
+				`<a href="/admin/items">List</a>`,
+				`<a href="/admin/items/create">New</a>`,
+				`<a href="/admin/items/export">Export</a>`,
+				`<button data-url="/admin/items/bulk/export?format=csv">Export selected</button>`,
+			} {
+				if !strings.Contains(string(page), link) {
+					t.Errorf("expected %s in the list page", link)
+				}
+			}
+
+			// The import result links back to the prefixed list.
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			fw, _ := mw.CreateFormFile("file", "items.csv")
+			_, _ = io.WriteString(fw, "name\nx\n")
+			mw.Close()
+			resp, err = client.Post(srv.URL+"/admin/items/import", mw.FormDataContentType(), &body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			page, _ = io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if !strings.Contains(string(page), `href="/admin/items"`) {
+				t.Errorf("expected a link back to /admin/items after import, got:\n%s", page)
+			}
+
+			// Store redirects back to the prefixed list URL.
+			resp, err = client.Post(srv.URL+"/admin/items", "application/x-www-form-urlencoded",
+				strings.NewReader(url.Values{"name": {"x"}}.Encode()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if !*created {
+				t.Error("expected Create to be called")
+			}
+			if resp.StatusCode != http.StatusSeeOther {
+				t.Fatalf("expected 303 after store, got %d", resp.StatusCode)
+			}
+			if loc := resp.Header.Get("Location"); loc != "/admin/items" {
+				t.Errorf("expected redirect to /admin/items, got %s", loc)
+			}
+		})
+	}
+}
+
+type gatedResource struct {
+	*SimpleResource
+	mws []func(http.Handler) http.Handler
+}
+
+func (r *gatedResource) Middleware() []func(http.Handler) http.Handler { return r.mws }
+
+func TestPanel_ResourceMiddleware_Order(t *testing.T) {
+	var order []string
+	record := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	gated := &gatedResource{
+		SimpleResource: NewSimpleResource("gated", "Gated", "Gated").
+			WithTable(func(_ context.Context) templ.Component {
+				order = append(order, "handler")
+				return templ.NopComponent
+			}),
+		mws: []func(http.Handler) http.Handler{record("res1"), record("res2")},
+	}
+	plain := NewSimpleResource("plain", "Plain", "Plain").
+		WithTable(func(_ context.Context) templ.Component {
+			order = append(order, "plain-handler")
+			return templ.NopComponent
+		})
+
+	sessions := scs.New()
+	am := auth.NewManager(sessions)
+	p := NewPanel("resource-mw").
+		WithAuthManager(am).
+		WithSession(sessions).
+		EnableRegistration(false).
+		EnablePasswordReset(false).
+		EnableNotifications(false).
+		WithMiddleware(record("panel")).
+		AddResources(gated, plain)
+
+	parent := http.NewServeMux()
+	parent.Handle("/admin/", p.Handler("/admin"))
+	parent.Handle("/login-as", sessions.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = am.LoginWithRequest(r, auth.NewUser(1, "admin@example.com", "Admin"))
+	})))
+	srv := httptest.NewServer(parent)
+	defer srv.Close()
+
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar, CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	// Guests are stopped by auth before resource middlewares run.
+	resp, err := client.Get(srv.URL + "/admin/gated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if len(order) != 1 || order[0] != "panel" {
+		t.Errorf("expected only [panel] for guest, got %v", order)
+	}
+
+	order = nil
+	resp, _ = client.Get(srv.URL + "/login-as")
+	resp.Body.Close()
+	resp, err = client.Get(srv.URL + "/admin/gated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	want := []string{"panel", "res1", "res2", "handler"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %v, got %v", want, order)
+	}
+
+	// Other resources are not wrapped.
+	order = nil
+	resp, err = client.Get(srv.URL + "/admin/plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if strings.Join(order, ",") != "panel,plain-handler" {
+		t.Errorf("expected [panel plain-handler], got %v", order)
+	}
+}
+
+type uploadResource struct {
+	*SimpleResource
+}
+
+func (r *uploadResource) MaxBodySize() int64 { return 64 << 10 }
+
+func TestPanel_MaxBodySize(t *testing.T) {
+	sessions := scs.New()
+	am := auth.NewManager(sessions)
+	noop := func(_ context.Context, _ *http.Request) error { return nil }
+	p := NewPanel("body-limit").
+		WithAuthManager(am).
+		WithSession(sessions).
+		EnableRegistration(false).
+		EnablePasswordReset(false).
+		EnableNotifications(false).
+		SetMaxBodySize(1<<10).
+		AddResources(
+			NewSimpleResource("notes", "Note", "Notes").WithCreate(noop),
+			&uploadResource{NewSimpleResource("files", "File", "Files").WithCreate(noop)},
+		)
+
+	parent := http.NewServeMux()
+	parent.Handle("/admin/", p.Handler("/admin"))
+	parent.Handle("/login-as", sessions.LoadAndSave(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = am.LoginWithRequest(r, auth.NewUser(1, "admin@example.com", "Admin"))
+	})))
+	srv := httptest.NewServer(parent)
+	defer srv.Close()
+
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar, CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, _ := client.Get(srv.URL + "/login-as")
+	resp.Body.Close()
+
+	body := url.Values{"name": {strings.Repeat("x", 4<<10)}}.Encode()
+	tests := []struct {
+		slug string
+		want int
+	}{
+		{"notes", http.StatusRequestEntityTooLarge},
+		{"files", http.StatusSeeOther}, // per-resource limit is higher
+	}
+	for _, tt := range tests {
+		resp, err := client.Post(srv.URL+"/admin/"+tt.slug, "application/x-www-form-urlencoded", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.slug, tt.want, resp.StatusCode)
+		}
+	}
+}
+
+func TestPanel_PolledWidget(t *testing.T) {
+	widget.Clear()
+	defer widget.Clear()
+	count := 0
+	widget.RegisterWidget("signups", 10, func(ctx context.Context) widget.Widget {
+		count++
+		return widget.NewStats(widget.Stat{Label: "Sign-ups", Value: strconv.Itoa(count)})
+	}).PollInterval(15 * time.Second)
+
+	srv, client, _ := newMountedPanel(t, false)
+	resp, err := client.Get(srv.URL + "/login-as")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	resp, err = client.Get(srv.URL + "/admin/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(page), `data-widget-id="signups"`) || !strings.Contains(string(page), "/admin/api/widgets/signups") {
+		t.Errorf("expected the dashboard to poll the widget, got:\n%s", page)
+	}
+
+	resp, err = client.Get(srv.URL + "/admin/api/widgets/signups")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body struct {
+		ID           string `json:"id"`
+		HTML         string `json:"html"`
+		PollInterval int64  `json:"pollInterval"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body.ID != "signups" || body.PollInterval != 15000 || !strings.Contains(body.HTML, ">2</div>") {
+		t.Errorf("expected the widget rendered again, got %+v", body)
+	}
+
+	resp, err = client.Get(srv.URL + "/admin/api/widgets/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown widget, got %d", resp.StatusCode)
+	}
+}
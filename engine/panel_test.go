@@ -2,11 +2,20 @@ package engine
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/alexedwards/scs/v2"
+	"github.com/bozz33/sublimego/auth"
+	"github.com/bozz33/sublimego/internal/ent"
+	"github.com/bozz33/sublimego/middleware"
 	"github.com/bozz33/sublimego/ui/layouts"
+	_ "modernc.org/sqlite"
 )
 
 func TestNewPanel_Defaults(t *testing.T) {
@@ -211,3 +220,118 @@ func TestPanel_WithNavGroups_Context(t *testing.T) {
 		t.Errorf("expected G1, got %s", got[0].Label)
 	}
 }
+
+func TestPanel_FaviconAndManifest(t *testing.T) {
+	sessions := scs.New()
+	p := NewPanel("brand").
+		WithAuthManager(auth.NewManager(sessions)).
+		WithSession(sessions).
+		WithPath("/admin").
+		WithBrandName("Acme").
+		WithLogo("/static/logo.png").
+		WithFavicon("https://cdn.example.com/acme.ico").
+		EnableNotifications(false)
+	p.PrimaryColor = "blue"
+	h := p.Router()
+
+	for _, target := range []string{"/favicon.ico", "/admin/favicon.ico"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusFound {
+			t.Fatalf("%s: expected 302, got %d", target, rec.Code)
+		}
+		if loc := rec.Header().Get("Location"); loc != "https://cdn.example.com/acme.ico" {
+			t.Errorf("%s: unexpected Location %q", target, loc)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/manifest.webmanifest", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("manifest: expected 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/manifest+json" {
+		t.Errorf("unexpected Content-Type %q", ct)
+	}
+	var m webManifest
+	if err := json.Unmarshal(rec.Body.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "Acme" || m.StartURL != "/admin" || m.ThemeColor != "#3b82f6" {
+		t.Errorf("unexpected manifest %+v", m)
+	}
+	if len(m.Icons) != 1 || m.Icons[0].Src != "/static/logo.png" || m.Icons[0].Type != "image/png" {
+		t.Errorf("unexpected icons %+v", m.Icons)
+	}
+}
+
+func TestPanel_AuthRateLimit(t *testing.T) {
+	sessions := scs.New()
+	db, err := sql.Open("sqlite", "file:"+t.Name()+"?mode=memory&cache=shared&_pragma=foreign_keys(1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := ent.NewClient(ent.Driver(entsql.OpenDB("sqlite3", db)))
+	t.Cleanup(func() { _ = client.Close() })
+	if err := client.Schema.Create(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewPanel("limits").
+		WithDatabase(client).
+		WithAuthManager(auth.NewManager(sessions)).
+		WithSession(sessions).
+		WithPath("/admin").
+		WithAuthRateLimit(middleware.RateLimit(0.001, 1)).
+		EnableNotifications(false).
+		Router()
+
+	serve := func(method string) int {
+		req := httptest.NewRequest(method, "/admin/login", strings.NewReader("email=a@b.c&password=x"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := serve(http.MethodPost); code == http.StatusTooManyRequests {
+		t.Fatal("expected the first attempt to go through")
+	}
+	if code := serve(http.MethodPost); code != http.StatusTooManyRequests {
+		t.Errorf("expected the second attempt to be throttled, got %d", code)
+	}
+	if code := serve(http.MethodGet); code != http.StatusOK {
+		t.Errorf("expected the login page to stay available, got %d", code)
+	}
+}
+
+func TestPanel_RateLimitPerUser(t *testing.T) {
+	tokens := map[string]int{"alice": 1, "bob": 2}
+	p := NewPanel("user-limits").
+		WithAuthManager(auth.NewManager(scs.New())).
+		WithAPIToken(func(token string) (int, bool) {
+			id, ok := tokens[token]
+			return id, ok
+		}).
+		WithRateLimit(middleware.RateLimit(0.001, 1))
+	h := p.protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	serve := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/admin/posts", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := serve("alice"); code != http.StatusOK {
+		t.Fatalf("expected alice's first request to go through, got %d", code)
+	}
+	if code := serve("alice"); code != http.StatusTooManyRequests {
+		t.Errorf("expected alice's second request to be throttled, got %d", code)
+	}
+	// Same IP, other user: counted separately.
+	if code := serve("bob"); code != http.StatusOK {
+		t.Errorf("expected bob's request to go through, got %d", code)
+	}
+}
@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Policy authorizes actions on a specific record. Return one from
+// ResourcePolicy.Policy.
+type Policy interface {
+	CanView(ctx context.Context, item any) bool
+	CanUpdate(ctx context.Context, item any) bool
+	CanDelete(ctx context.Context, item any) bool
+}
+
+// PolicyFuncs is a Policy built from functions. A nil function allows the
+// action.
+//
+//	func (r *PostResource) Policy() engine.Policy {
+//		owns := func(ctx context.Context, item any) bool {
+//			return item.(*ent.Post).AuthorID == auth.UserFromContext(ctx).ID
+//		}
+//		return engine.PolicyFuncs{Update: owns, Delete: owns}
+//	}
+type PolicyFuncs struct {
+	View   func(ctx context.Context, item any) bool
+	Update func(ctx context.Context, item any) bool
+	Delete func(ctx context.Context, item any) bool
+}
+
+// CanView implements Policy.
+func (p PolicyFuncs) CanView(ctx context.Context, item any) bool {
+	return p.View == nil || p.View(ctx, item)
+}
+
+// CanUpdate implements Policy.
+func (p PolicyFuncs) CanUpdate(ctx context.Context, item any) bool {
+	return p.Update == nil || p.Update(ctx, item)
+}
+
+// CanDelete implements Policy.
+func (p PolicyFuncs) CanDelete(ctx context.Context, item any) bool {
+	return p.Delete == nil || p.Delete(ctx, item)
+}
+
+// errPolicyDenied is returned by checkPolicy when the policy refuses.
+var errPolicyDenied = errors.New("forbidden by the resource policy")
+
+// policyFor returns the policy of res, or nil.
+func policyFor(res Resource) Policy {
+	if rp, ok := res.(ResourcePolicy); ok {
+		return rp.Policy()
+	}
+	return nil
+}
+
+// policyAllows reports whether the policy of res, if any, allows check
+// (Policy.CanView, Policy.CanUpdate or Policy.CanDelete) on item.
+func policyAllows(ctx context.Context, res Resource, check func(Policy, context.Context, any) bool, item any) bool {
+	p := policyFor(res)
+	return p == nil || check(p, ctx, item)
+}
+
+// checkPolicy loads the record behind key and checks it against the
+// resource policy. It returns nil without loading anything when the
+// resource has no policy.
+func (h *CRUDHandler) checkPolicy(ctx context.Context, key string, check func(Policy, context.Context, any) bool) error {
+	if policyFor(h.Resource) == nil {
+		return nil
+	}
+	item, err := h.getRecord(ctx, key)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return ErrNotFound
+	}
+	if !policyAllows(ctx, h.Resource, check, item) {
+		return errPolicyDenied
+	}
+	return nil
+}
+
+// writePolicyError answers a failed checkPolicy: 403 when denied, 404 or
+// 500 when the record could not be loaded.
+func (h *CRUDHandler) writePolicyError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, errPolicyDenied) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	writeLookupError(w, r, h.Resource.Label(), err)
+}
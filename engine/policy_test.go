@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bozz33/sublimego/auth"
+)
+
+type post struct {
+	ID       int
+	AuthorID int
+}
+
+type postResource struct {
+	*SimpleResource
+}
+
+// Policy lets authors edit and delete their own posts only.
+func (postResource) Policy() Policy {
+	owns := func(ctx context.Context, item any) bool {
+		return item.(post).AuthorID == auth.UserFromContext(ctx).ID
+	}
+	return PolicyFuncs{Update: owns, Delete: owns}
+}
+
+func TestCRUDHandler_Policy(t *testing.T) {
+	var deleted []string
+	res := postResource{NewSimpleResource("posts", "Post", "Posts").
+		WithGet(func(_ context.Context, id string) (any, error) {
+			if id == "1" {
+				return post{ID: 1, AuthorID: 7}, nil
+			}
+			return post{ID: 2, AuthorID: 8}, nil
+		}).
+		WithDelete(func(_ context.Context, id string) error {
+			deleted = append(deleted, id)
+			return nil
+		})}
+	h := NewCRUDHandler(res)
+
+	tests := []struct {
+		method string
+		target string
+		body   string
+		want   int
+	}{
+		{http.MethodGet, "/posts/1/edit", "", http.StatusOK},
+		{http.MethodGet, "/posts/2/edit", "", http.StatusForbidden},
+		{http.MethodPost, "/posts/2", "title=x", http.StatusForbidden},
+		{http.MethodDelete, "/posts/2", "", http.StatusForbidden},
+		{http.MethodPost, "/posts/bulk-delete", "ids[]=1&ids[]=2", http.StatusForbidden},
+		{http.MethodDelete, "/posts/1", "", http.StatusSeeOther},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(auth.WithUser(req.Context(), &auth.User{ID: 7}))
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		if rw.Code != tt.want {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.target, tt.want, rw.Code)
+		}
+	}
+	if len(deleted) != 1 || deleted[0] != "1" {
+		t.Errorf("expected only post 1 to be deleted, got %v", deleted)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/admin/api/posts/2", nil)
+	req = req.WithContext(auth.WithUser(req.Context(), &auth.User{ID: 7}))
+	rw := httptest.NewRecorder()
+	NewAPIHandler(res).WithBasePath("/admin").ServeHTTP(rw, req)
+	if rw.Code != http.StatusForbidden {
+		t.Errorf("API delete: expected 403, got %d", rw.Code)
+	}
+}
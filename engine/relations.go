@@ -3,10 +3,15 @@ package engine
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+
+	"github.com/bozz33/sublimego/form"
 )
 
 // RelationType defines the type of relationship.
@@ -155,7 +160,19 @@ type SelectOption struct {
 	Selected bool
 }
 
-// GetRelationOptions fetches options for a relation from the registry.
+// relationOptionsLimit caps the records listed by GetRelationOptions.
+const relationOptionsLimit = 200
+
+// GetRelationOptions lists the records of the related resource as select
+// options, labelled by relation.DisplayField and valued by relation.OwnerKey
+// (the record ID by default), after a blank option. The resource is looked
+// up in the panel of ctx, then in the panels of the global registry.
+//
+// The user must be able to read the related resource, and records its
+// policy hides are left out. At most relationOptionsLimit records are
+// listed, fetched through ListQuery when the resource implements
+// ResourceQueryable; a selected record beyond them is added when the
+// options are valued by ID.
 func GetRelationOptions(ctx context.Context, relation *Relation, selectedID any) (*RelationOptions, error) {
 	opts := &RelationOptions{
 		Relation:    relation,
@@ -166,13 +183,87 @@ func GetRelationOptions(ctx context.Context, relation *Relation, selectedID any)
 		EmptyLabel:  "-- None --",
 	}
 
-	// This would be implemented to fetch from the related resource
-	// For now, return empty options - the actual implementation would
-	// use the registry to find the related resource and fetch its data
+	res := relatedResource(ctx, relation.RelatedSlug)
+	if res == nil {
+		return nil, fmt.Errorf("relation %s: resource %q is not registered", relation.Name, relation.RelatedSlug)
+	}
+	if !res.CanRead(ctx) {
+		return nil, fmt.Errorf("relation %s: resource %q is not readable", relation.Name, relation.RelatedSlug)
+	}
+	items, err := relationOptionItems(ctx, res)
+	if err != nil {
+		return nil, fmt.Errorf("relation %s: %w", relation.Name, err)
+	}
 
+	selected := ""
+	if selectedID != nil {
+		selected = fmt.Sprint(selectedID)
+	}
+	if opts.AllowEmpty {
+		opts.Options = append(opts.Options, SelectOption{Value: "", Label: opts.EmptyLabel, Selected: selected == ""})
+	}
+	found := false
+	add := func(item any) {
+		if !policyAllows(ctx, res, Policy.CanView, item) {
+			return
+		}
+		value := getItemID(item)
+		if relation.OwnerKey != "" {
+			if v := ExtractRelatedID(item, relation.OwnerKey); v != nil {
+				value = fmt.Sprint(v)
+			}
+		}
+		label := value
+		if v := ExtractRelatedID(item, relation.DisplayField); v != nil {
+			label = fmt.Sprint(v)
+		}
+		isSelected := value == selected && selected != ""
+		found = found || isSelected
+		opts.Options = append(opts.Options, SelectOption{Value: value, Label: label, Selected: isSelected})
+	}
+	for _, item := range items {
+		add(item)
+	}
+	// Keep the current value selectable when it is beyond the limit.
+	if selected != "" && !found && (relation.OwnerKey == "" || strings.EqualFold(relation.OwnerKey, "id")) {
+		if item, err := res.Get(ctx, selected); err == nil && item != nil {
+			add(item)
+		}
+	}
 	return opts, nil
 }
 
+// relationOptionItems fetches the first relationOptionsLimit records of res.
+func relationOptionItems(ctx context.Context, res Resource) ([]any, error) {
+	if q, ok := res.(ResourceQueryable); ok {
+		items, _, err := q.ListQuery(ctx, ListQuery{
+			Filters: map[string]string{},
+			Page:    1,
+			PerPage: relationOptionsLimit,
+			SortDir: "asc",
+		})
+		return items, err
+	}
+	items, err := res.List(ctx)
+	if len(items) > relationOptionsLimit {
+		items = items[:relationOptionsLimit]
+	}
+	return items, err
+}
+
+// relatedResource returns the resource with the given slug in the panel of
+// ctx, or among the panels of the global registry.
+func relatedResource(ctx context.Context, slug string) Resource {
+	if p := GetPanelFromContext(ctx); p != nil {
+		for _, res := range p.Resources {
+			if res.Slug() == slug {
+				return res
+			}
+		}
+	}
+	return FindResource(slug)
+}
+
 // ExtractRelatedID extracts the related ID from an item using reflection.
 func ExtractRelatedID(item any, foreignKey string) any {
 	val := reflect.ValueOf(item)
@@ -185,9 +276,18 @@ func ExtractRelatedID(item any, foreignKey string) any {
 
 	field := val.FieldByName(foreignKey)
 	if !field.IsValid() {
-		// Try with different casing
+		// Try the JSON name, then with different casing
+		for i := 0; i < val.NumField(); i++ {
+			sf := val.Type().Field(i)
+			if name, _, _ := strings.Cut(sf.Tag.Get("json"), ","); name == foreignKey {
+				field = val.Field(i)
+				break
+			}
+		}
+	}
+	if !field.IsValid() {
 		for i := 0; i < val.NumField(); i++ {
-			if val.Type().Field(i).Tag.Get("json") == foreignKey {
+			if strings.EqualFold(val.Type().Field(i).Name, foreignKey) {
 				field = val.Field(i)
 				break
 			}
@@ -263,21 +363,35 @@ type RelationManager interface {
 
 	// ListRelated returns the related items for a given parent ID.
 	ListRelated(ctx context.Context, parentID string) ([]any, error)
+	// ListRelatedPaged returns one page (from 1) of the related items
+	// matching search, and the number of matching items. Managers that
+	// cannot page return ErrRelatedPagingUnsupported, as BaseRelationManager
+	// does, and ListRelated is paged in memory instead.
+	ListRelatedPaged(ctx context.Context, parentID string, page, perPage int, search string) ([]any, int, error)
 	// AttachRelated attaches a related item to the parent (ManyToMany).
-	AttachRelated(ctx context.Context, parentID, relatedID string) error
+	// pivot holds the values of the PivotFields, validated and keyed by
+	// field name; it is empty for relations without pivot attributes.
+	AttachRelated(ctx context.Context, parentID, relatedID string, pivot map[string]any) error
 	// DetachRelated detaches a related item from the parent (ManyToMany).
 	DetachRelated(ctx context.Context, parentID, relatedID string) error
 	// CreateRelated creates a new related item linked to the parent (HasMany).
 	CreateRelated(ctx context.Context, parentID string, r *http.Request) error
+	// UpdateRelated updates a related item from the submitted form.
+	UpdateRelated(ctx context.Context, parentID, relatedID string, r *http.Request) error
 	// DeleteRelated deletes a related item.
 	DeleteRelated(ctx context.Context, parentID, relatedID string) error
 
 	// Columns returns the columns to display in the sub-table.
 	Columns() []Column
+	// PivotFields returns the fields of the extra pivot columns set when
+	// attaching (e.g. a role on a membership), nil when there are none.
+	PivotFields() []form.Component
 	// CanAttach returns whether the user can attach items.
 	CanAttach(ctx context.Context) bool
 	// CanCreate returns whether the user can create related items.
 	CanCreate(ctx context.Context) bool
+	// CanUpdate returns whether the user can edit related items.
+	CanUpdate(ctx context.Context) bool
 	// CanDelete returns whether the user can delete related items.
 	CanDelete(ctx context.Context) bool
 }
@@ -312,15 +426,25 @@ func (b *BaseRelationManager) RelationType() RelationType { return b.relationTyp
 func (b *BaseRelationManager) ListRelated(_ context.Context, _ string) ([]any, error) {
 	return []any{}, nil
 }
-func (b *BaseRelationManager) AttachRelated(_ context.Context, _, _ string) error { return nil }
+func (b *BaseRelationManager) ListRelatedPaged(_ context.Context, _ string, _, _ int, _ string) ([]any, int, error) {
+	return nil, 0, ErrRelatedPagingUnsupported
+}
+func (b *BaseRelationManager) AttachRelated(_ context.Context, _, _ string, _ map[string]any) error {
+	return nil
+}
 func (b *BaseRelationManager) DetachRelated(_ context.Context, _, _ string) error { return nil }
 func (b *BaseRelationManager) CreateRelated(_ context.Context, _ string, _ *http.Request) error {
 	return nil
 }
+func (b *BaseRelationManager) UpdateRelated(_ context.Context, _, _ string, _ *http.Request) error {
+	return nil
+}
 func (b *BaseRelationManager) DeleteRelated(_ context.Context, _, _ string) error { return nil }
 func (b *BaseRelationManager) Columns() []Column                                  { return []Column{} }
+func (b *BaseRelationManager) PivotFields() []form.Component                      { return nil }
 func (b *BaseRelationManager) CanAttach(_ context.Context) bool                   { return true }
 func (b *BaseRelationManager) CanCreate(_ context.Context) bool                   { return true }
+func (b *BaseRelationManager) CanUpdate(_ context.Context) bool                   { return true }
 func (b *BaseRelationManager) CanDelete(_ context.Context) bool                   { return true }
 
 // SetIcon sets the icon on the base manager.
@@ -329,6 +453,48 @@ func (b *BaseRelationManager) SetIcon(icon string) *BaseRelationManager {
 	return b
 }
 
+// ErrRelatedPagingUnsupported is returned by ListRelatedPaged when the
+// manager only implements ListRelated.
+var ErrRelatedPagingUnsupported = errors.New("relation manager does not page related items")
+
+// ListRelatedPage returns one page of the related items of rm matching
+// search, with ListRelatedPaged or, when the manager does not support it,
+// by searching and paging the result of ListRelated in memory. The search
+// matches the searchable columns, or all columns when none is. A perPage of
+// 0 returns every match.
+func ListRelatedPage(ctx context.Context, rm RelationManager, parentID string, page, perPage int, search string) ([]any, int, error) {
+	items, total, err := rm.ListRelatedPaged(ctx, parentID, page, perPage, search)
+	if !errors.Is(err, ErrRelatedPagingUnsupported) {
+		return items, total, err
+	}
+	all, err := rm.ListRelated(ctx, parentID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if search = strings.ToLower(strings.TrimSpace(search)); search != "" {
+		cols := rm.Columns()
+		if searchable := slices.DeleteFunc(slices.Clone(cols), func(c Column) bool { return !c.Searchable }); len(searchable) > 0 {
+			cols = searchable
+		}
+		all = slices.DeleteFunc(all, func(item any) bool {
+			return !slices.ContainsFunc(cols, func(c Column) bool {
+				return strings.Contains(strings.ToLower(c.GetValue(item)), search)
+			})
+		})
+	}
+	if perPage <= 0 {
+		return all, len(all), nil
+	}
+	p := buildPagination(&ListQuery{Page: page, PerPage: perPage}, len(all))
+	return pageSlice(all, p), len(all), nil
+}
+
+// RelatedGetter is an optional interface for relation managers that load a
+// single related item. Updates then answer with the item as stored.
+type RelatedGetter interface {
+	GetRelated(ctx context.Context, parentID, relatedID string) (any, error)
+}
+
 // RelationManagerAware is the interface for resources that expose relation managers.
 type RelationManagerAware interface {
 	GetRelationManagers() []RelationManager
@@ -341,10 +507,12 @@ type RelationManagerAware interface {
 // RelationManagerHandler handles HTTP requests for relation manager sub-tables.
 // Routes handled:
 //
-//	GET    /{parentID}/relations/{name}              -> list related items (JSON)
+//	GET    /{parentID}/relations/{name}              -> list related items (JSON, ?page=&per_page=&q=)
 //	POST   /{parentID}/relations/{name}              -> create related item
-//	POST   /{parentID}/relations/{name}/attach       -> attach (ManyToMany)
+//	POST   /{parentID}/relations/{name}/attach       -> attach (ManyToMany, related_id and pivot fields)
 //	POST   /{parentID}/relations/{name}/detach/{id}  -> detach (ManyToMany)
+//	PUT    /{parentID}/relations/{name}/{id}         -> update related item (JSON row, see RelatedGetter)
+//	POST   /{parentID}/relations/{name}/{id}         -> update, or delete with _method=DELETE
 //	DELETE /{parentID}/relations/{name}/{id}         -> delete related item
 type RelationManagerHandler struct {
 	resource Resource
@@ -392,9 +560,19 @@ func (h *RelationManagerHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 	ctx := r.Context()
 	switch r.Method {
 	case http.MethodGet:
-		h.handleRelationGET(w, rm, parentID, relationName, ctx)
+		h.handleRelationGET(w, r, rm, parentID, relationName, ctx)
 	case http.MethodPost:
+		if relatedID != "" && subAction == "" {
+			h.handleRelatedRecordPOST(w, r, rm, parentID, relatedID, ctx)
+			return
+		}
 		h.handleRelationPOST(w, r, rm, parentID, subAction, ctx)
+	case http.MethodPut, http.MethodPatch:
+		if relatedID == "" || subAction != "" {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.handleRelationUpdate(w, r, rm, parentID, relatedID, ctx)
 	case http.MethodDelete:
 		h.handleRelationDELETE(w, rm, parentID, relatedID, subAction, ctx)
 	default:
@@ -424,19 +602,33 @@ func (h *RelationManagerHandler) parseRelationPath(r *http.Request) (parentID, r
 	return parentID, relationName, subAction, relatedID, true
 }
 
-func (h *RelationManagerHandler) handleRelationGET(w http.ResponseWriter, rm RelationManager, parentID, relationName string, ctx context.Context) {
-	items, err := rm.ListRelated(ctx, parentID)
+func (h *RelationManagerHandler) handleRelationGET(w http.ResponseWriter, r *http.Request, rm RelationManager, parentID, relationName string, ctx context.Context) {
+	q := r.URL.Query()
+	lq := &ListQuery{Page: 1, PerPage: 25}
+	if p, err := strconv.Atoi(q.Get("page")); err == nil && p > 0 {
+		lq.Page = p
+	}
+	if pp, err := strconv.Atoi(q.Get("per_page")); err == nil && pp > 0 && pp <= 200 {
+		lq.PerPage = pp
+	}
+	items, total, err := ListRelatedPage(ctx, rm, parentID, lq.Page, lq.PerPage, q.Get("q"))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	p := buildPagination(lq, total)
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]any{
 		"relation":   relationName,
 		"columns":    rm.Columns(),
 		"items":      items,
+		"page":       p.CurrentPage,
+		"per_page":   p.PerPage,
+		"total":      total,
+		"last_page":  p.LastPage,
 		"can_create": rm.CanCreate(ctx),
 		"can_attach": rm.CanAttach(ctx),
+		"can_update": rm.CanUpdate(ctx),
 		"can_delete": rm.CanDelete(ctx),
 	})
 }
@@ -452,7 +644,16 @@ func (h *RelationManagerHandler) handleRelationPOST(w http.ResponseWriter, r *ht
 			http.Error(w, "related_id required", http.StatusBadRequest)
 			return
 		}
-		if err := rm.AttachRelated(ctx, parentID, relID); err != nil {
+		pivot := form.New().SetSchema(rm.PivotFields()...)
+		values := pivot.Values(r.PostForm)
+		if !pivot.Validate(values) {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]any{
+				"message": "invalid pivot values",
+				"errors":  pivot.Errors,
+			})
+			return
+		}
+		if err := rm.AttachRelated(ctx, parentID, relID, values); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
@@ -464,6 +665,9 @@ func (h *RelationManagerHandler) handleRelationPOST(w http.ResponseWriter, r *ht
 		return
 	}
 	if err := rm.CreateRelated(ctx, parentID, r); err != nil {
+		if writeBodyTooLarge(w, err) {
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -492,3 +696,68 @@ func (h *RelationManagerHandler) handleRelationDELETE(w http.ResponseWriter, rm
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// handleRelatedRecordPOST handles a form posted to a related item: an update,
+// or the method given by _method. Like resource records, it must carry the
+// CSRF token.
+func (h *RelationManagerHandler) handleRelatedRecordPOST(w http.ResponseWriter, r *http.Request, rm RelationManager, parentID, relatedID string, ctx context.Context) {
+	if err := r.ParseForm(); err != nil {
+		if writeBodyTooLarge(w, err) {
+			return
+		}
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+	method, ok := methodOverride(r)
+	if !ok {
+		http.Error(w, "Invalid method override", http.StatusBadRequest)
+		return
+	}
+	if !validCSRFToken(r) {
+		http.Error(w, "CSRF token missing or invalid", http.StatusForbidden)
+		return
+	}
+	if method == http.MethodDelete {
+		h.handleRelationDELETE(w, rm, parentID, relatedID, "", ctx)
+		return
+	}
+	h.handleRelationUpdate(w, r, rm, parentID, relatedID, ctx)
+}
+
+// handleRelationUpdate updates a related item, provided the parent record
+// can be updated too, and answers with the item when the manager implements
+// RelatedGetter, or 204 otherwise.
+func (h *RelationManagerHandler) handleRelationUpdate(w http.ResponseWriter, r *http.Request, rm RelationManager, parentID, relatedID string, ctx context.Context) {
+	if !h.resource.CanUpdate(ctx) || !rm.CanUpdate(ctx) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	parent := h.parent()
+	if err := parent.checkPolicy(ctx, parentID, Policy.CanUpdate); err != nil {
+		parent.writePolicyError(w, r, err)
+		return
+	}
+	if err := rm.UpdateRelated(ctx, parentID, relatedID, r); err != nil {
+		if writeBodyTooLarge(w, err) {
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	getter, ok := rm.(RelatedGetter)
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	item, err := getter.GetRelated(ctx, parentID, relatedID)
+	if err != nil || item == nil {
+		writeLookupError(w, r, rm.Label(), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, item)
+}
+
+// parent returns a CRUD handler of the parent resource, to check its policy.
+func (h *RelationManagerHandler) parent() *CRUDHandler {
+	return &CRUDHandler{Resource: h.resource}
+}
@@ -0,0 +1,130 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// EntRelationLoader is a RelationLoader for the entities of an Ent client.
+// It relies on the code Ent generates, found by reflection:
+//
+//   - the client has one field per entity type, named after it (client.User),
+//     whose Query method returns the entity's query builder;
+//   - entities have an ID field stored in the "id" column, and an Edges
+//     struct holding the loaded edges;
+//   - a relation is loaded with the query's With<Edge> method, and read from
+//     Edges.<Edge>. <Edge> is the relation name in Go case, as Ent names
+//     edges: "author" → Author, "order_items" → OrderItems. Name relations
+//     after their Ent edge.
+//
+// Every relation type is loaded the same way: belongs_to and has_one edges
+// come back as a pointer (nil when unset), has_many and many_to_many edges as
+// a slice.
+//
+//	loader := engine.NewEntRelationLoader(client)
+//	rels, err := loader.LoadMany(ctx, posts, []*engine.Relation{engine.BelongsTo("author", "users").Build()})
+type EntRelationLoader struct {
+	client reflect.Value
+}
+
+// NewEntRelationLoader returns a loader for the entities of client, a
+// generated *ent.Client.
+func NewEntRelationLoader(client any) *EntRelationLoader {
+	return &EntRelationLoader{client: reflect.Indirect(reflect.ValueOf(client))}
+}
+
+// LoadRelation loads one relation of item.
+func (l *EntRelationLoader) LoadRelation(ctx context.Context, item any, relation *Relation) (any, error) {
+	loaded, err := l.LoadMany(ctx, []any{item}, []*Relation{relation})
+	if err != nil {
+		return nil, err
+	}
+	return loaded[0][relation.Name], nil
+}
+
+// LoadRelations loads the relations of item, keyed by relation name.
+func (l *EntRelationLoader) LoadRelations(ctx context.Context, item any, relations []*Relation) (map[string]any, error) {
+	loaded, err := l.LoadMany(ctx, []any{item}, relations)
+	if err != nil {
+		return nil, err
+	}
+	return loaded[0], nil
+}
+
+// LoadMany loads the relations of every item of a list, keyed by relation
+// name, at the same index as the item. The entities are queried once with
+// all the relations eager loaded, so the number of queries does not grow
+// with the number of items. items must all be of the same entity type.
+func (l *EntRelationLoader) LoadMany(ctx context.Context, items []any, relations []*Relation) ([]map[string]any, error) {
+	result := make([]map[string]any, len(items))
+	for i := range result {
+		result[i] = make(map[string]any, len(relations))
+	}
+	if len(items) == 0 || len(relations) == 0 {
+		return result, nil
+	}
+
+	typ := reflect.Indirect(reflect.ValueOf(items[0])).Type()
+	ids := make([]any, len(items))
+	index := make(map[any][]int, len(items))
+	for i, item := range items {
+		v := reflect.Indirect(reflect.ValueOf(item))
+		if v.Type() != typ {
+			return nil, fmt.Errorf("load relations: mixed entity types %s and %s", typ, v.Type())
+		}
+		id := v.FieldByName("ID")
+		if !id.IsValid() {
+			return nil, fmt.Errorf("load relations: %s has no ID field", typ)
+		}
+		ids[i] = id.Interface()
+		index[ids[i]] = append(index[ids[i]], i)
+	}
+
+	entClient := l.client.FieldByName(typ.Name())
+	if !entClient.IsValid() {
+		return nil, fmt.Errorf("load relations: the Ent client has no %s entity", typ.Name())
+	}
+	query := entClient.MethodByName("Query").Call(nil)[0]
+	where := query.MethodByName("Where")
+	pred := reflect.ValueOf(sql.FieldIn("id", ids...)).Convert(where.Type().In(0).Elem())
+	query = where.Call([]reflect.Value{pred})[0]
+	for _, rel := range relations {
+		with := query.MethodByName("With" + entEdgeName(rel.Name))
+		if !with.IsValid() {
+			return nil, fmt.Errorf("load relations: %s has no edge %q", typ.Name(), rel.Name)
+		}
+		query = with.Call(nil)[0]
+	}
+
+	out := query.MethodByName("All").Call([]reflect.Value{reflect.ValueOf(ctx)})
+	if err, _ := out[1].Interface().(error); err != nil {
+		return nil, fmt.Errorf("load relations of %s: %w", typ.Name(), err)
+	}
+	entities := out[0]
+	for i := 0; i < entities.Len(); i++ {
+		entity := reflect.Indirect(entities.Index(i))
+		edges := entity.FieldByName("Edges")
+		for _, at := range index[entity.FieldByName("ID").Interface()] {
+			for _, rel := range relations {
+				result[at][rel.Name] = edges.FieldByName(entEdgeName(rel.Name)).Interface()
+			}
+		}
+	}
+	return result, nil
+}
+
+// entEdgeName returns the Go name Ent gives the edge name ("order_items" →
+// "OrderItems").
+func entEdgeName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
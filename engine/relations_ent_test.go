@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// The types below mimic the code Ent generates for an Author entity with a
+// has-many "posts" edge and a belongs-to "publisher" edge.
+
+type predicateAuthor func(*sql.Selector)
+
+type Post struct{ ID int }
+
+type Publisher struct{ ID int }
+
+type Author struct {
+	ID    int
+	Edges struct {
+		Posts     []*Post
+		Publisher *Publisher
+	}
+}
+
+type AuthorQuery struct {
+	client *AuthorClient
+	where  []predicateAuthor
+	with   []string
+}
+
+func (q *AuthorQuery) Where(ps ...predicateAuthor) *AuthorQuery {
+	q.where = append(q.where, ps...)
+	return q
+}
+
+func (q *AuthorQuery) WithPosts(...func(*AuthorQuery)) *AuthorQuery {
+	q.with = append(q.with, "posts")
+	return q
+}
+
+func (q *AuthorQuery) WithPublisher(...func(*AuthorQuery)) *AuthorQuery {
+	q.with = append(q.with, "publisher")
+	return q
+}
+
+func (q *AuthorQuery) All(context.Context) ([]*Author, error) {
+	q.client.queries = append(q.client.queries, q)
+	var out []*Author
+	for _, a := range q.client.rows {
+		e := &Author{ID: a.ID}
+		if slices.Contains(q.with, "posts") {
+			e.Edges.Posts = a.Edges.Posts
+		}
+		if slices.Contains(q.with, "publisher") {
+			e.Edges.Publisher = a.Edges.Publisher
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+type AuthorClient struct {
+	rows    []*Author
+	queries []*AuthorQuery
+}
+
+func (c *AuthorClient) Query() *AuthorQuery { return &AuthorQuery{client: c} }
+
+func TestEntRelationLoader(t *testing.T) {
+	ada := &Author{ID: 1}
+	ada.Edges.Posts = []*Post{{ID: 10}, {ID: 11}}
+	ada.Edges.Publisher = &Publisher{ID: 5}
+	grace := &Author{ID: 2}
+	client := &AuthorClient{rows: []*Author{ada, grace}}
+
+	// The entity's field of the client is named after the entity type.
+	if _, err := NewEntRelationLoader(&struct{ Book *AuthorClient }{}).LoadRelations(context.Background(), ada, []*Relation{HasMany("posts", "posts").Build()}); err == nil {
+		t.Error("expected an error for a client without the entity")
+	}
+
+	loader := NewEntRelationLoader(&struct{ Author *AuthorClient }{client})
+	rels := []*Relation{HasMany("posts", "posts").Build(), BelongsTo("publisher", "publishers").Build()}
+	loaded, err := loader.LoadMany(context.Background(), []any{&Author{ID: 1}, &Author{ID: 2}}, rels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(client.queries); n != 1 {
+		t.Fatalf("expected one query for the whole list, got %d", n)
+	}
+	q := client.queries[0]
+	s := sql.Select("*").From(sql.Table("authors"))
+	for _, p := range q.where {
+		p(s)
+	}
+	if query, args := s.Query(); !strings.Contains(query, "IN") || len(args) != 2 {
+		t.Errorf("expected the authors to be selected by ID, got %s %v", query, args)
+	}
+	if posts := loaded[0]["posts"].([]*Post); len(posts) != 2 {
+		t.Errorf("unexpected posts %v", posts)
+	}
+	if pub := loaded[0]["publisher"].(*Publisher); pub == nil || pub.ID != 5 {
+		t.Errorf("unexpected publisher %v", pub)
+	}
+	if pub := loaded[1]["publisher"].(*Publisher); pub != nil {
+		t.Errorf("expected no publisher for the second author, got %v", pub)
+	}
+
+	if _, err := loader.LoadRelation(context.Background(), ada, HasMany("books", "books").Build()); err == nil {
+		t.Error("expected an error for an unknown edge")
+	}
+}
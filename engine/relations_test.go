@@ -0,0 +1,327 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bozz33/sublimego/form"
+)
+
+type comment struct {
+	ID   int    `json:"id"`
+	Body string `json:"body"`
+}
+
+// commentsManager only implements ListRelated, as managers written before
+// ListRelatedPaged do.
+type commentsManager struct {
+	*BaseRelationManager
+}
+
+func (commentsManager) ListRelated(context.Context, string) ([]any, error) {
+	return []any{
+		comment{ID: 1, Body: "First!"},
+		comment{ID: 2, Body: "Nice post"},
+		comment{ID: 3, Body: "Nice photo"},
+	}, nil
+}
+
+func (commentsManager) Columns() []Column {
+	return []Column{{Key: "Body", Label: "Body", Searchable: true}}
+}
+
+type commentedResource struct {
+	*SimpleResource
+	managers []RelationManager
+}
+
+func (r commentedResource) GetRelationManagers() []RelationManager { return r.managers }
+
+func TestRelationManagerHandler_Paging(t *testing.T) {
+	res := commentedResource{
+		SimpleResource: NewSimpleResource("posts", "Post", "Posts"),
+		managers:       []RelationManager{commentsManager{NewBaseRelationManager("comments", "Comments", "comments", RelationHasMany)}},
+	}
+	h := NewRelationManagerHandler(res)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/7/relations/comments?q=nice&page=2&per_page=1", nil))
+	var got struct {
+		Items    []comment `json:"items"`
+		Page     int       `json:"page"`
+		Total    int       `json:"total"`
+		LastPage int       `json:"last_page"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v (%s)", err, rec.Body)
+	}
+	if got.Total != 2 || got.Page != 2 || got.LastPage != 2 || len(got.Items) != 1 || got.Items[0].ID != 3 {
+		t.Errorf("unexpected page %+v", got)
+	}
+}
+
+// membersManager attaches users to a team with a role stored on the pivot.
+type membersManager struct {
+	*BaseRelationManager
+	attached map[string]any
+}
+
+func (m *membersManager) PivotFields() []form.Component {
+	return []form.Component{form.Select("role").Options(map[string]string{"admin": "Admin", "member": "Member"}).Required()}
+}
+
+func (m *membersManager) AttachRelated(_ context.Context, parentID, relatedID string, pivot map[string]any) error {
+	m.attached = map[string]any{"team": parentID, "user": relatedID, "role": pivot["role"]}
+	return nil
+}
+
+func TestRelationManagerHandler_AttachPivot(t *testing.T) {
+	rm := &membersManager{BaseRelationManager: NewBaseRelationManager("members", "Members", "members", RelationManyToMany)}
+	h := NewRelationManagerHandler(commentedResource{
+		SimpleResource: NewSimpleResource("teams", "Team", "Teams"),
+		managers:       []RelationManager{rm},
+	})
+	attach := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/3/relations/members/attach", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := attach("related_id=9"); rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), `"role"`) {
+		t.Errorf("expected the missing role to be refused, got %d (%s)", rec.Code, rec.Body)
+	}
+	if rm.attached != nil {
+		t.Fatal("expected nothing attached with invalid pivot values")
+	}
+	if rec := attach("related_id=9&role=admin"); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d (%s)", rec.Code, rec.Body)
+	}
+	if rm.attached["team"] != "3" || rm.attached["user"] != "9" || rm.attached["role"] != "admin" {
+		t.Errorf("unexpected attach %v", rm.attached)
+	}
+}
+
+// editableComments stores comments that can be edited inline.
+type editableComments struct {
+	*BaseRelationManager
+	items []any
+}
+
+func (m *editableComments) ListRelated(context.Context, string) ([]any, error) { return m.items, nil }
+
+func (m *editableComments) GetRelated(_ context.Context, _, relatedID string) (any, error) {
+	for _, item := range m.items {
+		if getItemID(item) == relatedID {
+			return item, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (m *editableComments) UpdateRelated(_ context.Context, _, relatedID string, r *http.Request) error {
+	for i, item := range m.items {
+		if c := item.(comment); getItemID(c) == relatedID {
+			c.Body = r.FormValue("body")
+			m.items[i] = c
+		}
+	}
+	return nil
+}
+
+// guardedPosts is a parent resource whose records may be read-only.
+type guardedPosts struct {
+	commentedResource
+	readOnly bool
+	policy   Policy
+}
+
+func (r guardedPosts) CanUpdate(context.Context) bool { return !r.readOnly }
+
+func (r guardedPosts) Policy() Policy { return r.policy }
+
+func TestRelationManagerHandler_Update(t *testing.T) {
+	rm := &editableComments{
+		BaseRelationManager: NewBaseRelationManager("comments", "Comments", "comments", RelationHasMany),
+		items:               []any{comment{ID: 1, Body: "Frist"}},
+	}
+	h := NewRelationManagerHandler(commentedResource{
+		SimpleResource: NewSimpleResource("posts", "Post", "Posts"),
+		managers:       []RelationManager{rm},
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/7/relations/comments/1", strings.NewReader("body=First"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var got comment
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("expected the updated row, got %d (%s)", rec.Code, rec.Body)
+	}
+	if got.Body != "First" {
+		t.Errorf("unexpected row %+v", got)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/7/relations/comments/2", strings.NewReader("body=x"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown related item, got %d", rec.Code)
+	}
+}
+
+func TestRelationManagerHandler_UpdateGuards(t *testing.T) {
+	newManager := func() *editableComments {
+		return &editableComments{
+			BaseRelationManager: NewBaseRelationManager("comments", "Comments", "comments", RelationHasMany),
+			items:               []any{comment{ID: 1, Body: "Frist"}},
+		}
+	}
+	post := func(h http.Handler, csrf bool) int {
+		req := httptest.NewRequest(http.MethodPost, "/7/relations/comments/1", strings.NewReader("body=First"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if csrf {
+			req.Header.Set("X-CSRF-Token", "t")
+			req.AddCookie(&http.Cookie{Name: "_csrf", Value: "t"})
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	posts := func(rm RelationManager) commentedResource {
+		return commentedResource{
+			SimpleResource: NewSimpleResource("posts", "Post", "Posts").
+				WithGet(func(context.Context, string) (any, error) { return comment{ID: 7}, nil }),
+			managers: []RelationManager{rm},
+		}
+	}
+	denyAll := PolicyFuncs{Update: func(context.Context, any) bool { return false }}
+
+	tests := []struct {
+		name   string
+		parent func(rm RelationManager) Resource
+		csrf   bool
+		want   int
+	}{
+		{"plain post without token", func(rm RelationManager) Resource { return posts(rm) }, false, http.StatusForbidden},
+		{"plain post with token", func(rm RelationManager) Resource { return posts(rm) }, true, http.StatusOK},
+		{"read-only parent", func(rm RelationManager) Resource { return guardedPosts{commentedResource: posts(rm), readOnly: true} }, true, http.StatusForbidden},
+		{"parent policy", func(rm RelationManager) Resource { return guardedPosts{commentedResource: posts(rm), policy: denyAll} }, true, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm := newManager()
+			if code := post(NewRelationManagerHandler(tt.parent(rm)), tt.csrf); code != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, code)
+			}
+			updated := rm.items[0].(comment).Body == "First"
+			if updated != (tt.want == http.StatusOK) {
+				t.Errorf("unexpected update state %+v", rm.items[0])
+			}
+		})
+	}
+}
+
+func TestGetRelationOptions(t *testing.T) {
+	type author struct {
+		ID   int
+		Name string
+	}
+	authors := NewSimpleResource("authors", "Author", "Authors").
+		WithList(func(context.Context) ([]any, error) {
+			return []any{author{ID: 1, Name: "Ada"}, &author{ID: 2, Name: "Grace"}}, nil
+		})
+	ctx := context.WithValue(context.Background(), ContextKeyPanel, NewPanel("admin").AddResources(authors))
+
+	opts, err := GetRelationOptions(ctx, BelongsTo("author", "authors").Build(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SelectOption{
+		{Value: "", Label: "-- None --"},
+		{Value: "1", Label: "Ada"},
+		{Value: "2", Label: "Grace", Selected: true},
+	}
+	if !slices.Equal(opts.Options, want) {
+		t.Errorf("unexpected options %+v", opts.Options)
+	}
+
+	if _, err := GetRelationOptions(ctx, BelongsTo("editor", "editors").Build(), nil); err == nil {
+		t.Error("expected an error for an unregistered resource")
+	}
+}
+
+type optionAuthor struct {
+	ID   int
+	Name string
+}
+
+// manyAuthors pages through 1000 authors and hides author 3 from its policy.
+type manyAuthors struct {
+	*SimpleResource
+	readable bool
+	perPage  int
+}
+
+func (r *manyAuthors) CanRead(context.Context) bool { return r.readable }
+
+func (r *manyAuthors) ListQuery(_ context.Context, q ListQuery) ([]any, int, error) {
+	r.perPage = q.PerPage
+	var items []any
+	for id := (q.Page-1)*q.PerPage + 1; id <= q.Page*q.PerPage && id <= 1000; id++ {
+		items = append(items, optionAuthor{ID: id, Name: "author " + strconv.Itoa(id)})
+	}
+	return items, 1000, nil
+}
+
+func (r *manyAuthors) Get(_ context.Context, id string) (any, error) {
+	n, err := strconv.Atoi(id)
+	if err != nil || n < 1 || n > 1000 {
+		return nil, ErrNotFound
+	}
+	return optionAuthor{ID: n, Name: "author " + id}, nil
+}
+
+func (r *manyAuthors) Policy() Policy {
+	return PolicyFuncs{View: func(_ context.Context, item any) bool {
+		return item.(optionAuthor).ID != 3
+	}}
+}
+
+func TestGetRelationOptions_Guarded(t *testing.T) {
+	authors := &manyAuthors{SimpleResource: NewSimpleResource("authors", "Author", "Authors"), readable: true}
+	ctx := context.WithValue(context.Background(), ContextKeyPanel, NewPanel("admin").AddResources(authors))
+	relation := BelongsTo("author", "authors").Build()
+
+	opts, err := GetRelationOptions(ctx, relation, 750)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authors.perPage != relationOptionsLimit {
+		t.Errorf("expected a page of %d records, got %d", relationOptionsLimit, authors.perPage)
+	}
+	// The blank option, the first page without author 3, then the
+	// selected author beyond the limit.
+	if len(opts.Options) != relationOptionsLimit+1 {
+		t.Fatalf("expected %d options, got %d", relationOptionsLimit+1, len(opts.Options))
+	}
+	if slices.ContainsFunc(opts.Options, func(o SelectOption) bool { return o.Value == "3" }) {
+		t.Error("expected the record hidden by the policy left out")
+	}
+	if last := opts.Options[len(opts.Options)-1]; last != (SelectOption{Value: "750", Label: "author 750", Selected: true}) {
+		t.Errorf("expected the selected record kept, got %+v", last)
+	}
+
+	authors.readable = false
+	if _, err := GetRelationOptions(ctx, relation, nil); err == nil {
+		t.Error("expected an error when the related resource is not readable")
+	}
+}
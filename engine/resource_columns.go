@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/bozz33/sublimego/table"
+)
+
+// declaredColumns returns the columns of res when it implements
+// ResourceColumns.
+func declaredColumns(ctx context.Context, res any) ([]table.Column, bool) {
+	rc, ok := res.(ResourceColumns)
+	if !ok {
+		return nil, false
+	}
+	return rc.Columns(ctx), true
+}
+
+// listColumns converts declared columns to the list view's columns.
+func listColumns(cols []table.Column) []Column {
+	out := make([]Column, len(cols))
+	for i, col := range cols {
+		out[i] = Column{
+			Key:        col.Key(),
+			Label:      col.Label(),
+			Type:       col.Type(),
+			Sortable:   col.IsSortable(),
+			Searchable: col.IsSearchable(),
+		}
+		if sel, ok := col.(*table.SelectColumn); ok {
+			out[i].Options = sel.OptionsMap
+		}
+	}
+	return out
+}
+
+// inlineColumn reports whether key is a declared select or toggle column of
+// res, the only columns InlineUpdate accepts.
+func inlineColumn(ctx context.Context, res any, key string) bool {
+	cols, _ := declaredColumns(ctx, res)
+	for _, col := range cols {
+		switch col.(type) {
+		case *table.SelectColumn, *table.ToggleColumn:
+			if col.Key() == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sortableKeys returns the keys of the sortable declared columns.
+func sortableKeys(cols []table.Column) []string {
+	keys := make([]string, 0, len(cols))
+	for _, col := range cols {
+		if col.IsSortable() {
+			keys = append(keys, col.Key())
+		}
+	}
+	return keys
+}
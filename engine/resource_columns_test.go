@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bozz33/sublimego/export"
+	"github.com/bozz33/sublimego/table"
+)
+
+type contact struct {
+	ID    int
+	Name  string
+	Email string
+	Notes string
+}
+
+// contactResource declares its columns once for the list, export and API.
+type contactResource struct {
+	*SimpleResource
+}
+
+func (contactResource) Columns(context.Context) []table.Column {
+	return []table.Column{
+		table.Text("Name").WithLabel("Name").Sortable(),
+		table.ExcludeFromExport(table.Text("Email").WithLabel("Email")),
+		table.ExcludeFromAPI(table.Text("Notes").WithLabel("Notes")),
+	}
+}
+
+func newContactResource() contactResource {
+	return contactResource{NewSimpleResource("contacts", "Contact", "Contacts").
+		WithList(func(context.Context) ([]any, error) {
+			return []any{
+				contact{ID: 1, Name: "Ada", Email: "ada@example.com", Notes: "vip"},
+				contact{ID: 2, Name: "Linus", Email: "linus@example.com"},
+			}, nil
+		})}
+}
+
+func TestResourceColumns_Surfaces(t *testing.T) {
+	res := newContactResource()
+	ctx := context.WithValue(context.Background(), ContextKeyResource, Resource(res))
+
+	state, err := res.BuildTableState(ctx, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Columns) != 3 || len(state.Rows) != 2 {
+		t.Fatalf("expected 3 columns and 2 rows, got %d and %d", len(state.Columns), len(state.Rows))
+	}
+	if got := state.Rows[0].Cells; got[0] != "Ada" || got[2] != "vip" {
+		t.Errorf("unexpected cells %v", got)
+	}
+
+	var buf bytes.Buffer
+	if _, err := ExportResource(ctx, res, &buf, export.FormatCSV); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Name,Notes\nAda,vip\nLinus,\n"; buf.String() != want {
+		t.Errorf("expected export %q, got %q", want, buf.String())
+	}
+
+	if s, ok := resolveSort(ctx, res, "Name", "desc"); !ok || s.Direction != "desc" {
+		t.Errorf("expected Name to be sortable, got %+v %v", s, ok)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/contacts?per_page=1", nil)
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	NewCRUDHandler(res).ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var body struct {
+		Data  []map[string]string `json:"data"`
+		Total int                 `json:"total"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Total != 2 || len(body.Data) != 1 {
+		t.Fatalf("expected 1 of 2 records, got %d of %d", len(body.Data), body.Total)
+	}
+	want := map[string]string{"id": "1", "Name": "Ada", "Email": "ada@example.com"}
+	for k, v := range want {
+		if body.Data[0][k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, body.Data[0][k])
+		}
+	}
+	if _, ok := body.Data[0]["Notes"]; ok {
+		t.Error("expected Notes to be excluded from the API")
+	}
+}
@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bozz33/sublimego/actions"
+	"github.com/bozz33/sublimego/flash"
+)
+
+// rowActions returns the row actions of res that can be run (those with a
+// handler).
+func rowActions(res Resource) []*actions.Action {
+	ra, ok := res.(ResourceRowActions)
+	if !ok {
+		return nil
+	}
+	var out []*actions.Action
+	for _, a := range ra.RowActions() {
+		if a.HandlerFunc != nil {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// withRowActions sets on each row the actions authorized for its record.
+// rows[i] must be built from items[i].
+func withRowActions(ctx context.Context, res Resource, items []any, rows []Row) {
+	acts := rowActions(res)
+	if len(acts) == 0 {
+		return
+	}
+	for i, item := range items {
+		for _, a := range acts {
+			if a.IsAuthorized(ctx, item) {
+				rows[i].Actions = append(rows[i].Actions, a)
+			}
+		}
+	}
+}
+
+// RunAction runs the row action name on the record behind key, then
+// flashes its success or failure message and redirects to the action's
+// redirect URL (the list by default). The form must carry the CSRF token.
+func (h *CRUDHandler) RunAction(w http.ResponseWriter, r *http.Request, key, name string) {
+	ctx := r.Context()
+
+	if !validCSRFToken(r) {
+		http.Error(w, "CSRF token missing or invalid", http.StatusForbidden)
+		return
+	}
+
+	var action *actions.Action
+	for _, a := range rowActions(h.Resource) {
+		if a.Name == name {
+			action = a
+			break
+		}
+	}
+	if action == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if !h.Resource.CanRead(ctx) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	item, err := h.getRecord(ctx, key)
+	if err != nil || item == nil {
+		writeLookupError(w, r, h.Resource.Label(), err)
+		return
+	}
+	if !policyAllows(ctx, h.Resource, Policy.CanView, item) || !action.IsAuthorized(ctx, item) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if err := action.Run(ctx, item); err != nil {
+		msg := action.FailureMessage
+		if msg == "" {
+			msg = action.Label + " failed: " + err.Error()
+		}
+		Flash(ctx, flash.TypeError, msg)
+	} else {
+		msg := action.SuccessMessage
+		if msg == "" {
+			msg = action.Label + " completed"
+		}
+		Flash(ctx, flash.TypeSuccess, msg)
+	}
+
+	redirect := action.ResolveRedirect(item)
+	if redirect == "" {
+		redirect = h.indexURL()
+	}
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
+}
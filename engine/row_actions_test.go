@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bozz33/sublimego/actions"
+)
+
+type invoice struct {
+	ID   int
+	Paid bool
+}
+
+type invoiceResource struct {
+	*SimpleResource
+	sent []int
+}
+
+func (r *invoiceResource) RowActions() []*actions.Action {
+	return []*actions.Action{
+		actions.New("send").
+			SetLabel("Send invoice").
+			RequireConfirmation().
+			Authorize(func(_ context.Context, item any) bool { return !item.(invoice).Paid }).
+			Handle(func(_ context.Context, item any) error {
+				r.sent = append(r.sent, item.(invoice).ID)
+				return nil
+			}),
+		actions.New("fail").Handle(func(context.Context, any) error { return errors.New("boom") }),
+		actions.New("no-handler"),
+	}
+}
+
+func TestCRUDHandler_RowActions(t *testing.T) {
+	invoices := map[string]invoice{"1": {ID: 1}, "2": {ID: 2, Paid: true}}
+	res := &invoiceResource{SimpleResource: NewSimpleResource("invoices", "Invoice", "Invoices").
+		WithList(func(context.Context) ([]any, error) { return []any{invoices["1"], invoices["2"]}, nil }).
+		WithGet(func(_ context.Context, id string) (any, error) {
+			if inv, ok := invoices[id]; ok {
+				return inv, nil
+			}
+			return nil, ErrNotFound
+		})}
+
+	ctx := context.WithValue(context.Background(), ContextKeyResource, Resource(res))
+	state, err := res.BuildTableState(ctx, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(state.Rows[0].Actions); n != 2 {
+		t.Errorf("expected 2 actions on the unpaid invoice, got %d", n)
+	}
+	if n := len(state.Rows[1].Actions); n != 1 {
+		t.Errorf("expected only 'fail' on the paid invoice, got %d", n)
+	}
+
+	h := NewCRUDHandler(res)
+	post := func(target string, withToken bool) int {
+		body := ""
+		if withToken {
+			body = "_token=tok"
+		}
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(&http.Cookie{Name: "_csrf", Value: "tok"})
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		return rw.Code
+	}
+
+	tests := []struct {
+		target    string
+		withToken bool
+		want      int
+	}{
+		{"/invoices/1/action/send", false, http.StatusForbidden},
+		{"/invoices/1/action/send", true, http.StatusSeeOther},
+		{"/invoices/2/action/send", true, http.StatusForbidden},
+		{"/invoices/1/action/fail", true, http.StatusSeeOther},
+		{"/invoices/1/action/no-handler", true, http.StatusNotFound},
+		{"/invoices/9/action/send", true, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if got := post(tt.target, tt.withToken); got != tt.want {
+			t.Errorf("POST %s (token=%v): expected %d, got %d", tt.target, tt.withToken, tt.want, got)
+		}
+	}
+	if len(res.sent) != 1 || res.sent[0] != 1 {
+		t.Errorf("expected invoice 1 to be sent once, got %v", res.sent)
+	}
+}
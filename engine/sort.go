@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// ContextKeySort holds the validated Sort of a list request.
+const ContextKeySort contextKey = "sort"
+
+// Sort is the column ordering requested with ?sort= and ?dir=.
+type Sort struct {
+	Column    string // column key, as declared on the table
+	Direction string // "asc" or "desc"
+}
+
+// Desc reports whether the sort is descending.
+func (s Sort) Desc() bool { return s.Direction == "desc" }
+
+// ResourceSortable is an optional interface listing the column keys a
+// resource can be sorted by. The CRUD handler ignores ?sort= for any other
+// column so that results can never be ordered by arbitrary fields.
+// BaseResource implements it from the columns marked Sortable.
+type ResourceSortable interface {
+	SortableColumns() []string
+}
+
+// GetSort retrieves the validated sort from context (set by CRUDHandler.List).
+func GetSort(ctx context.Context) (Sort, bool) {
+	s, ok := ctx.Value(ContextKeySort).(Sort)
+	return s, ok
+}
+
+// resolveSort validates the requested column against the resource's sortable
+// columns, taken from ResourceColumns when implemented. It returns false if
+// the column is empty or not sortable.
+func resolveSort(ctx context.Context, res Resource, column, dir string) (Sort, bool) {
+	if column == "" {
+		return Sort{}, false
+	}
+	var keys []string
+	if declared, ok := declaredColumns(ctx, res); ok {
+		keys = sortableKeys(declared)
+	} else if sortable, ok := res.(ResourceSortable); ok {
+		keys = sortable.SortableColumns()
+	}
+	for _, key := range keys {
+		if key == column {
+			if dir != "desc" {
+				dir = "asc"
+			}
+			return Sort{Column: column, Direction: dir}, true
+		}
+	}
+	return Sort{}, false
+}
+
+// ApplySort orders an Ent query by column. Column keys are converted to
+// snake_case field names ("CreatedAt" → "created_at"). An empty column
+// leaves the query unchanged. Only pass columns validated by the handler:
+//
+//	q := client.User.Query()
+//	if s, ok := engine.GetSort(ctx); ok {
+//		q = engine.ApplySort(q, s.Column, s.Direction)
+//	}
+func ApplySort[Q any, O ~func(*sql.Selector)](query interface{ Order(...O) Q }, column, dir string) Q {
+	if column == "" {
+		return query.Order()
+	}
+	var opts []sql.OrderTermOption
+	if strings.EqualFold(dir, "desc") {
+		opts = append(opts, sql.OrderDesc())
+	}
+	return query.Order(O(sql.OrderByField(sortField(column), opts...).ToFunc()))
+}
+
+// sortField converts a column key to its database field name.
+func sortField(key string) string {
+	var b strings.Builder
+	runes := []rune(key)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/a-h/templ"
+)
+
+func TestCRUDHandler_List_Sort(t *testing.T) {
+	var got Sort
+	var ok bool
+	res := NewSimpleResource("users", "User", "Users").
+		WithTable(func(ctx context.Context) templ.Component {
+			got, ok = GetSort(ctx)
+			return templ.NopComponent
+		})
+	res.SetTableColumns(
+		Column{Key: "Name", Label: "Name", Sortable: true},
+		Column{Key: "Password", Label: "Password"},
+	)
+	h := NewCRUDHandler(res)
+
+	tests := []struct {
+		query  string
+		wantOK bool
+		want   Sort
+	}{
+		{"?sort=Name&dir=desc", true, Sort{Column: "Name", Direction: "desc"}},
+		{"?sort=Name&dir=sideways", true, Sort{Column: "Name", Direction: "asc"}},
+		{"?sort=Password", false, Sort{}},
+		{"?sort=name", false, Sort{}},
+		{"", false, Sort{}},
+	}
+	for _, tt := range tests {
+		h.List(httptest.NewRecorder(), httptest.NewRequest("GET", "/users"+tt.query, nil))
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("%q: expected %+v (%v), got %+v (%v)", tt.query, tt.want, tt.wantOK, got, ok)
+		}
+	}
+}
+
+type fakeQuery struct {
+	orders []func(*sql.Selector)
+}
+
+func (q *fakeQuery) Order(o ...func(*sql.Selector)) *fakeQuery {
+	q.orders = append(q.orders, o...)
+	return q
+}
+
+func TestApplySort(t *testing.T) {
+	q := ApplySort(&fakeQuery{}, "CreatedAt", "desc")
+	if len(q.orders) != 1 {
+		t.Fatalf("expected one order term, got %d", len(q.orders))
+	}
+	sel := sql.Select("*").From(sql.Table("users"))
+	q.orders[0](sel)
+	query, _ := sel.Query()
+	if !strings.Contains(query, "ORDER BY `users`.`created_at` DESC") {
+		t.Errorf("unexpected query: %s", query)
+	}
+
+	if q := ApplySort(&fakeQuery{}, "", "asc"); len(q.orders) != 0 {
+		t.Errorf("expected no order term for empty column, got %d", len(q.orders))
+	}
+}
+
+func TestSortField(t *testing.T) {
+	for in, want := range map[string]string{
+		"Name":      "name",
+		"CreatedAt": "created_at",
+		"ID":        "id",
+		"UserID":    "user_id",
+		"HTMLTitle": "html_title",
+		"price":     "price",
+	} {
+		if got := sortField(in); got != want {
+			t.Errorf("sortField(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package engine
+
+import (
+	"context"
+	"encoding/gob"
+	"net/http"
+	"net/url"
+
+	"github.com/bozz33/sublimego/form"
+)
+
+func init() {
+	gob.Register(wizardProgress{})
+}
+
+// wizardProgress is the state of a wizard kept in the session between
+// steps.
+type wizardProgress struct {
+	Step   int
+	Values map[string][]string
+}
+
+// wizardFor returns the wizard of the create (item nil) or edit form, or nil
+// when the resource has none or the handler has no session to keep the
+// steps in.
+func (h *CRUDHandler) wizardFor(ctx context.Context, item any) *form.Wizard {
+	rw, ok := h.Resource.(ResourceWizard)
+	if !ok || h.Session == nil {
+		return nil
+	}
+	wz := rw.Wizard(ctx, item)
+	if wz == nil || len(wz.Steps) == 0 {
+		return nil
+	}
+	return wz
+}
+
+// wizardSessionKey returns the session key of the wizard creating (key "")
+// or editing record key.
+func (h *CRUDHandler) wizardSessionKey(key string) string {
+	return "wizard:" + h.Resource.Slug() + ":" + key
+}
+
+// wizardProgress returns the progress saved for the wizard of key.
+func (h *CRUDHandler) wizardProgress(ctx context.Context, key string) wizardProgress {
+	p, _ := h.Session.Get(ctx, h.wizardSessionKey(key)).(wizardProgress)
+	if p.Values == nil {
+		p.Values = make(map[string][]string)
+	}
+	return p
+}
+
+// endWizard forgets the progress of the wizard of key, once the record is
+// saved. It does nothing for resources without a wizard.
+func (h *CRUDHandler) endWizard(ctx context.Context, key string) {
+	if h.Session != nil {
+		h.Session.Remove(ctx, h.wizardSessionKey(key))
+	}
+}
+
+// wizardContext adds the state of the wizard of key to ctx, for the
+// resource's Form to render with generics.Wizard. Steps are posted to
+// action.
+func (h *CRUDHandler) wizardContext(ctx context.Context, key, action string, errs map[string][]string) context.Context {
+	p := h.wizardProgress(ctx, key)
+	return form.WithWizardState(ctx, form.WizardState{
+		Step:   p.Step,
+		Values: p.Values,
+		Errors: errs,
+		Action: action,
+	})
+}
+
+// wizardStep handles the submission of a wizard step. Back shows the
+// previous step and Next validates the step before showing the next one;
+// the values entered are kept in the session either way. Both answer the
+// request and return nil. On the last step, once valid, it returns a copy of
+// r carrying the values of every step, for the caller to save.
+func (h *CRUDHandler) wizardStep(w http.ResponseWriter, r *http.Request, wz *form.Wizard, key, page, action string, item any) *http.Request {
+	ctx := r.Context()
+	p := h.wizardProgress(ctx, key)
+	step := min(max(p.Step, 0), len(wz.Steps)-1)
+
+	// Unchecked boxes are not submitted: clear the step's fields first.
+	for _, name := range wz.StepFieldNames(step) {
+		delete(p.Values, name)
+		if v, ok := r.PostForm[name]; ok {
+			p.Values[name] = v
+		}
+	}
+
+	if r.PostFormValue("_wizard") == "back" {
+		p.Step = max(step-1, 0)
+		h.Session.Put(ctx, h.wizardSessionKey(key), p)
+		http.Redirect(w, r, page, http.StatusSeeOther)
+		return nil
+	}
+
+	values := make(map[string]any, len(p.Values))
+	for name, v := range p.Values {
+		values[name] = form.FormValue(v)
+	}
+	p.Step = step
+	if errs := wz.Validate(ctx, step, values); errs != nil {
+		h.Session.Put(ctx, h.wizardSessionKey(key), p)
+		ctx = h.wizardContext(ctx, key, action, errs)
+		render(w, r.WithContext(ctx), h.Resource.Label(), h.Resource.Form(ctx, item))
+		return nil
+	}
+	if !wz.IsLast(step) {
+		p.Step = step + 1
+		h.Session.Put(ctx, h.wizardSessionKey(key), p)
+		http.Redirect(w, r, page, http.StatusSeeOther)
+		return nil
+	}
+
+	h.Session.Put(ctx, h.wizardSessionKey(key), p)
+	req := r.Clone(ctx)
+	req.Body = http.NoBody
+	req.PostForm = url.Values(p.Values)
+	req.Form = url.Values(p.Values)
+	return req
+}
@@ -0,0 +1,99 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/alexedwards/scs/v2"
+	"github.com/bozz33/sublimego/form"
+)
+
+type onboardingResource struct {
+	*SimpleResource
+}
+
+func (onboardingResource) Wizard(context.Context, any) *form.Wizard {
+	return form.NewWizard().
+		Step("Account", form.Email("email").Required()).
+		Step("Profile", form.Text("name").Required()).
+		ValidateStep(func(_ context.Context, step int, values map[string]any) map[string][]string {
+			if step == 0 && values["email"] == "taken@example.com" {
+				return map[string][]string{"email": {"email is taken"}}
+			}
+			return nil
+		})
+}
+
+// Form renders the wizard state as text, in place of generics.Wizard.
+func (onboardingResource) Form(ctx context.Context, _ any) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		st, _ := form.WizardStateFromContext(ctx)
+		_, err := fmt.Fprintf(w, "step=%d email=%s name=%s errors=%v", st.Step, st.Values.Get("email"), st.Values.Get("name"), st.Errors)
+		return err
+	})
+}
+
+func TestCRUDHandler_Wizard(t *testing.T) {
+	var created url.Values
+	res := onboardingResource{NewSimpleResource("members", "Member", "Members").
+		WithCreate(func(_ context.Context, r *http.Request) error {
+			created = r.PostForm
+			return nil
+		})}
+	sessions := scs.New()
+	h := sessions.LoadAndSave(NewCRUDHandler(res).WithSession(sessions))
+
+	var cookie *http.Cookie
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		for _, c := range rec.Result().Cookies() {
+			if c.Name == sessions.Cookie.Name {
+				cookie = c
+			}
+		}
+		return rec
+	}
+	page := func() string { return serve(http.MethodGet, "/members/create?fragment=1", "").Body.String() }
+
+	if body := serve(http.MethodPost, "/members", "_wizard=next&email=taken@example.com").Body.String(); !strings.Contains(body, "step=0") || !strings.Contains(body, "email is taken") {
+		t.Fatalf("expected the step callback to refuse the email, got %q", body)
+	}
+	if rec := serve(http.MethodPost, "/members", "_wizard=next&email=ada@example.com"); rec.Code != http.StatusSeeOther {
+		t.Fatalf("expected a redirect to the next step, got %d", rec.Code)
+	}
+	if body := page(); !strings.Contains(body, "step=1 email=ada@example.com") {
+		t.Fatalf("expected the second step with the email kept, got %q", body)
+	}
+
+	serve(http.MethodPost, "/members", "_wizard=back&name=Ada")
+	if body := page(); !strings.Contains(body, "step=0 email=ada@example.com name=Ada") {
+		t.Fatalf("expected going back to keep the entered values, got %q", body)
+	}
+	if created != nil {
+		t.Fatal("expected nothing saved before the last step")
+	}
+
+	serve(http.MethodPost, "/members", "_wizard=next&email=ada@example.com")
+	if rec := serve(http.MethodPost, "/members", "_wizard=next&name=Ada"); rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/members" {
+		t.Fatalf("expected a redirect to the list after the last step, got %d %s", rec.Code, rec.Header().Get("Location"))
+	}
+	if created.Get("email") != "ada@example.com" || created.Get("name") != "Ada" {
+		t.Errorf("expected the values of every step to be saved, got %v", created)
+	}
+	if body := page(); !strings.Contains(body, "step=0 email= name=") {
+		t.Errorf("expected the wizard to start over, got %q", body)
+	}
+}
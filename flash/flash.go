@@ -2,6 +2,7 @@ package flash
 
 import (
 	"context"
+	"encoding/gob"
 	"net/http"
 
 	"github.com/alexedwards/scs/v2"
@@ -17,6 +18,11 @@ const (
 
 const sessionKey = "_flash_messages"
 
+// Messages are stored in the session, whose default codec is gob.
+func init() {
+	gob.Register([]*Message{})
+}
+
 // Message represents a flash message.
 type Message struct {
 	Type  string `json:"type"`
@@ -31,6 +31,9 @@ func (b *BaseField) ComponentType() string         { return "field" }
 func (b *BaseField) Attributes() template.HTMLAttr { return "" }
 func (b *BaseField) Rules() []string               { return b.fieldRules }
 
+// SetValue sets the field value, e.g. to refill a form with submitted data.
+func (b *BaseField) SetValue(v any) { b.fieldValue = v }
+
 // RulesString returns the rules as a pipe-separated string for validation.
 func (b *BaseField) RulesString() string {
 	return strings.Join(b.fieldRules, "|")
@@ -61,7 +64,8 @@ func (b *BaseField) IsChecked() bool {
 // TextInput represents a text input field.
 type TextInput struct {
 	BaseField
-	Type string
+	Type        string
+	MaskPattern string
 }
 
 // Text creates a standard text field.
@@ -131,6 +135,17 @@ func (f *TextInput) Default(val any) *TextInput {
 	return f
 }
 
+// Mask formats the input client-side with a mask pattern (see MaskedInput
+// for the tokens), e.g. form.MaskPhoneFR. Form.Validate strips the mask
+// literals from the submitted value before validating it.
+func (f *TextInput) Mask(pattern string) *TextInput {
+	f.MaskPattern = pattern
+	return f
+}
+
+// InputMask returns the mask pattern ("" when the input is not masked).
+func (f *TextInput) InputMask() string { return f.MaskPattern }
+
 // TextareaInput represents a textarea field.
 type TextareaInput struct {
 	BaseField
@@ -2,6 +2,7 @@ package form
 
 import (
 	"context"
+	"net/url"
 
 	"github.com/bozz33/sublimego/validation"
 )
@@ -40,11 +41,70 @@ func (f *Form) SaveProcessing(ctx context.Context) error {
 	return nil
 }
 
-// Validate validates the form data against all field rules.
+// flatten returns components followed by the ones nested in them, for
+// fields placed in sections, grids, tabs and other layouts.
+func flatten(components []Component) []Component {
+	var all []Component
+	for _, c := range components {
+		all = append(all, c)
+		if layout, ok := c.(Layout); ok {
+			all = append(all, flatten(layout.Schema())...)
+		}
+	}
+	return all
+}
+
+// FieldNames returns the names of the fields of the form, including the ones
+// nested in layouts.
+func (f *Form) FieldNames() []string {
+	var names []string
+	for _, c := range flatten(f.Schema) {
+		if field, ok := c.(interface{ Name() string }); ok {
+			names = append(names, field.Name())
+		}
+	}
+	return names
+}
+
+// Values returns the submitted values of the fields of the form, as
+// Validate expects them. Fields absent from submitted are left out.
+func (f *Form) Values(submitted url.Values) map[string]any {
+	values := make(map[string]any)
+	for _, name := range f.FieldNames() {
+		if v, ok := submitted[name]; ok {
+			values[name] = FormValue(v)
+		}
+	}
+	return values
+}
+
+// Normalize strips the mask literals from the string values of masked text
+// inputs in data, including the ones nested in layouts, e.g.
+// "06 12 34 56 78" becomes "0612345678".
+func (f *Form) Normalize(data map[string]any) {
+	for _, component := range flatten(f.Schema) {
+		field, ok := component.(interface {
+			Name() string
+			InputMask() string
+		})
+		if !ok || field.InputMask() == "" {
+			continue
+		}
+		if v, ok := data[field.Name()].(string); ok {
+			data[field.Name()] = Unmask(field.InputMask(), v)
+		}
+	}
+}
+
+// Validate normalizes the form data (see Normalize) and validates it against
+// all field rules, including the fields nested in layouts. Tabs are switched
+// to the tab holding their first invalid field.
 func (f *Form) Validate(data map[string]any) bool {
 	f.Errors = make(map[string][]string)
+	f.Normalize(data)
 
-	for _, component := range f.Schema {
+	var invalid []string
+	for _, component := range flatten(f.Schema) {
 		field, ok := component.(interface {
 			Name() string
 			Rules() []string
@@ -73,17 +133,36 @@ func (f *Form) Validate(data map[string]any) bool {
 		value := data[fieldName]
 		if errors := ruleSet.Validate(value); len(errors) > 0 {
 			f.Errors[fieldName] = errors
+			invalid = append(invalid, fieldName)
 		}
 	}
 
+	f.focusErrors(invalid)
 	return len(f.Errors) == 0
 }
 
+// focusErrors makes each Tabs of the form show the tab holding the first of
+// the invalid fields it contains, in schema order.
+func (f *Form) focusErrors(invalid []string) {
+	for _, component := range flatten(f.Schema) {
+		tabs, ok := component.(*Tabs)
+		if !ok {
+			continue
+		}
+		for _, name := range invalid {
+			if i := tabs.tabOf(name); i >= 0 {
+				tabs.Active = i
+				break
+			}
+		}
+	}
+}
+
 // GetValidationRules returns all validation rules as a map for use with validation.ValidateMap.
 func (f *Form) GetValidationRules() map[string]string {
 	rules := make(map[string]string)
 
-	for _, component := range f.Schema {
+	for _, component := range flatten(f.Schema) {
 		field, ok := component.(interface {
 			Name() string
 			RulesString() string
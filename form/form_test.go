@@ -1,6 +1,8 @@
 package form
 
 import (
+	"context"
+	"net/url"
 	"testing"
 )
 
@@ -189,3 +191,56 @@ func TestFieldComponentType(t *testing.T) {
 		t.Errorf("Expected component type 'field', got '%s'", field.ComponentType())
 	}
 }
+
+func TestTabs(t *testing.T) {
+	tabs := NewTabs().
+		Tab("General", Text("name").Required()).
+		Tab("Contact", NewSection("Address").SetSchema(Text("city").Required()), Email("email").Required()).WithIcon("mail")
+	f := New().SetSchema(tabs)
+
+	if tabs.ActiveTab() != 0 || tabs.TabItems[1].Icon != "mail" {
+		t.Fatalf("expected the first tab active and an icon on the second, got %+v", tabs)
+	}
+	if f.Validate(map[string]any{"name": "Ada"}) {
+		t.Fatal("expected the nested required fields to be validated")
+	}
+	if f.GetError("city") == "" || f.GetError("email") == "" {
+		t.Errorf("expected errors on city and email, got %v", f.Errors)
+	}
+	if tabs.ActiveTab() != 1 {
+		t.Errorf("expected the tab holding the first error to be active, got %d", tabs.ActiveTab())
+	}
+}
+
+func TestWizardValidate(t *testing.T) {
+	w := NewWizard().
+		Step("Account", NewSection("Login").SetSchema(Email("email").Required())).
+		Step("Profile", Text("name").Required()).
+		ValidateStep(func(_ context.Context, step int, values map[string]any) map[string][]string {
+			if values["name"] == "root" {
+				return map[string][]string{"name": {"name is reserved"}}
+			}
+			return nil
+		})
+
+	if names := w.StepFieldNames(0); len(names) != 1 || names[0] != "email" {
+		t.Errorf("expected the nested email field, got %v", names)
+	}
+	if errs := w.Validate(context.Background(), 0, map[string]any{}); len(errs["email"]) == 0 {
+		t.Errorf("expected the email to be required, got %v", errs)
+	}
+	if errs := w.Validate(context.Background(), 1, map[string]any{"name": "root"}); errs["name"][0] != "name is reserved" {
+		t.Errorf("expected the step callback error, got %v", errs)
+	}
+	if errs := w.Validate(context.Background(), 1, map[string]any{"name": "Ada"}); errs != nil {
+		t.Errorf("expected a valid step, got %v", errs)
+	}
+	if !w.IsLast(1) || w.IsLast(0) {
+		t.Error("expected step 1 to be the last")
+	}
+
+	w.Apply(WizardState{Step: 1, Values: url.Values{"name": {"Ada"}}})
+	if w.CurrentStep().Label != "Profile" || w.Steps[1].Components[0].(*TextInput).Value() != "Ada" {
+		t.Errorf("expected the state to be applied, got step %d", w.Current)
+	}
+}
@@ -0,0 +1,292 @@
+package form
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotAnImage is returned when an uploaded file is not a JPEG or PNG image.
+// The check is made on the file content, not on its extension or the
+// Content-Type header sent by the client.
+var ErrNotAnImage = errors.New("form: uploaded file is not a supported image (jpeg, png)")
+
+// ErrImageTooLarge is returned when an uploaded image has more pixels than
+// the field allows. The dimensions are read from the image header, before
+// anything is decoded.
+var ErrImageTooLarge = errors.New("form: uploaded image has too many pixels")
+
+// DefaultMaxImagePixels is the default pixel cap of ImageUpload (40 MP).
+const DefaultMaxImagePixels = 40_000_000
+
+// allowedImageTypes lists the sniffed MIME types accepted by ImageUpload.
+var allowedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+}
+
+// ImageUploadInput is a FileUpload restricted to images, with a client-side
+// preview and server-side resizing / thumbnail generation.
+type ImageUploadInput struct {
+	FileUploadInput
+	MaxWidth    int // 0 = no limit
+	MaxHeight   int // 0 = no limit
+	ThumbWidth  int // 0 = no thumbnail
+	ThumbHeight int // 0 = no thumbnail
+	MaxPixels   int // width x height cap checked before decoding, 0 = no limit
+}
+
+// ImageUpload creates an image upload field.
+func ImageUpload(name string) *ImageUploadInput {
+	return &ImageUploadInput{
+		FileUploadInput: FileUploadInput{
+			BaseField:   BaseField{fieldName: name, LabelStr: name},
+			AcceptTypes: "image/jpeg,image/png",
+		},
+		MaxPixels: DefaultMaxImagePixels,
+	}
+}
+
+// Label sets the label.
+func (i *ImageUploadInput) Label(label string) *ImageUploadInput {
+	i.LabelStr = label
+	return i
+}
+
+// MaxSize sets the maximum size in bytes.
+func (i *ImageUploadInput) MaxSize(size int64) *ImageUploadInput {
+	i.MaxFileSize = size
+	return i
+}
+
+// Required makes the field required.
+func (i *ImageUploadInput) Required() *ImageUploadInput {
+	i.BaseField.Required = true
+	i.fieldRules = append(i.fieldRules, "required")
+	return i
+}
+
+// MaxDimensions downscales the stored original so it fits within w x h.
+func (i *ImageUploadInput) MaxDimensions(w, h int) *ImageUploadInput {
+	i.MaxWidth = w
+	i.MaxHeight = h
+	return i
+}
+
+// MaxResolution rejects images with more than pixels pixels (width x
+// height), DefaultMaxImagePixels by default. A small file can declare huge
+// dimensions, so the cap bounds the memory used to decode it.
+func (i *ImageUploadInput) MaxResolution(pixels int) *ImageUploadInput {
+	i.MaxPixels = pixels
+	return i
+}
+
+// Thumbnail generates an additional thumbnail that fits within w x h.
+func (i *ImageUploadInput) Thumbnail(w, h int) *ImageUploadInput {
+	i.ThumbWidth = w
+	i.ThumbHeight = h
+	return i
+}
+
+// Default sets the current image URL (shown as preview on edit forms).
+func (i *ImageUploadInput) Default(url string) *ImageUploadInput {
+	i.fieldValue = url
+	return i
+}
+
+// ComponentType returns the component type identifier.
+func (i *ImageUploadInput) ComponentType() string { return "image_upload" }
+
+// HasThumbnail returns true if a thumbnail size is configured.
+func (i *ImageUploadInput) HasThumbnail() bool { return i.ThumbWidth > 0 && i.ThumbHeight > 0 }
+
+// UploadedImage describes an image stored by ImageUploadInput.Save.
+type UploadedImage struct {
+	URL          string
+	ThumbnailURL string // empty if no thumbnail is configured
+	Path         string
+	ThumbPath    string
+	MimeType     string
+	Width        int
+	Height       int
+}
+
+// Save reads the field's file from a multipart request, validates it is a
+// real image, resizes it and writes it (plus its thumbnail) into dir.
+// Returned URLs are built as baseURL + "/" + filename.
+// Returns (nil, http.ErrMissingFile) if no file was submitted.
+func (i *ImageUploadInput) Save(r *http.Request, dir, baseURL string) (*UploadedImage, error) {
+	file, _, err := r.FormFile(i.Name())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	return i.Process(file, dir, baseURL)
+}
+
+// Process validates, resizes and stores an image read from src.
+func (i *ImageUploadInput) Process(src io.Reader, dir, baseURL string) (*UploadedImage, error) {
+	if i.MaxFileSize > 0 {
+		src = io.LimitReader(src, i.MaxFileSize+1)
+	}
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf("form: read image: %w", err)
+	}
+	if i.MaxFileSize > 0 && int64(len(data)) > i.MaxFileSize {
+		return nil, fmt.Errorf("form: image exceeds %d bytes", i.MaxFileSize)
+	}
+
+	mimeType, err := DetectImageType(data)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("form: decode image: %w", err)
+	}
+	if i.MaxPixels > 0 && int64(cfg.Width)*int64(cfg.Height) > int64(i.MaxPixels) {
+		return nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("form: decode image: %w", err)
+	}
+
+	original := img
+	if i.MaxWidth > 0 || i.MaxHeight > 0 {
+		original = ResizeImage(img, i.MaxWidth, i.MaxHeight)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("form: create upload dir: %w", err)
+	}
+
+	ext := ".jpg"
+	if mimeType == "image/png" {
+		ext = ".png"
+	}
+	name := uuid.NewString()
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	out := &UploadedImage{
+		MimeType: mimeType,
+		Width:    original.Bounds().Dx(),
+		Height:   original.Bounds().Dy(),
+		Path:     filepath.Join(dir, name+ext),
+		URL:      baseURL + "/" + name + ext,
+	}
+	if err := writeImage(out.Path, original, mimeType); err != nil {
+		return nil, err
+	}
+
+	if i.HasThumbnail() {
+		thumb := ResizeImage(img, i.ThumbWidth, i.ThumbHeight)
+		out.ThumbPath = filepath.Join(dir, name+"_thumb"+ext)
+		out.ThumbnailURL = baseURL + "/" + name + "_thumb" + ext
+		if err := writeImage(out.ThumbPath, thumb, mimeType); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// DetectImageType sniffs the content type of data and returns it if it is
+// an accepted image type, or ErrNotAnImage otherwise.
+func DetectImageType(data []byte) (string, error) {
+	mimeType := http.DetectContentType(data)
+	if !allowedImageTypes[mimeType] {
+		return "", ErrNotAnImage
+	}
+	return mimeType, nil
+}
+
+// ResizeImage downscales img so it fits within maxW x maxH, preserving the
+// aspect ratio. A zero bound is ignored. Images already within bounds are
+// returned unchanged (no upscaling).
+func ResizeImage(img image.Image, maxW, maxH int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return img
+	}
+
+	scale := 1.0
+	if maxW > 0 && w > maxW {
+		scale = float64(maxW) / float64(w)
+	}
+	if maxH > 0 && h > maxH {
+		if s := float64(maxH) / float64(h); s < scale {
+			scale = s
+		}
+	}
+	if scale >= 1.0 {
+		return img
+	}
+
+	dw := max(1, int(float64(w)*scale+0.5))
+	dh := max(1, int(float64(h)*scale+0.5))
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	// Area averaging: each destination pixel is the mean of the source
+	// pixels it covers, which gives clean results when downscaling.
+	for y := 0; y < dh; y++ {
+		sy0 := b.Min.Y + y*h/dh
+		sy1 := max(sy0+1, b.Min.Y+(y+1)*h/dh)
+		for x := 0; x < dw; x++ {
+			sx0 := b.Min.X + x*w/dw
+			sx1 := max(sx0+1, b.Min.X+(x+1)*w/dw)
+			var r, g, bl, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					bl += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(bl / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
+
+// writeImage encodes img to path using the encoder matching mimeType.
+func writeImage(path string, img image.Image, mimeType string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("form: create image file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if mimeType == "image/png" {
+		err = png.Encode(f, img)
+	} else {
+		err = jpeg.Encode(f, img, &jpeg.Options{Quality: 85})
+	}
+	if err != nil {
+		return fmt.Errorf("form: encode image: %w", err)
+	}
+	return nil
+}
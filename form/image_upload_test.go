@@ -0,0 +1,128 @@
+package form
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strings"
+	"testing"
+)
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestImageUploadBuilder(t *testing.T) {
+	field := ImageUpload("cover").
+		Label("Cover").
+		MaxDimensions(800, 600).
+		Thumbnail(100, 100).
+		Required()
+
+	if field.ComponentType() != "image_upload" {
+		t.Errorf("Expected component type 'image_upload', got '%s'", field.ComponentType())
+	}
+	if field.MaxWidth != 800 || field.MaxHeight != 600 {
+		t.Errorf("Expected max 800x600, got %dx%d", field.MaxWidth, field.MaxHeight)
+	}
+	if !field.HasThumbnail() {
+		t.Error("Expected thumbnail to be configured")
+	}
+	if !field.BaseField.Required {
+		t.Error("Expected Required to be true")
+	}
+}
+
+func TestImageUploadProcess(t *testing.T) {
+	dir := t.TempDir()
+	field := ImageUpload("cover").MaxDimensions(200, 200).Thumbnail(50, 50)
+
+	out, err := field.Process(bytes.NewReader(testPNG(t, 400, 100)), dir, "/uploads/")
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if out.Width != 200 || out.Height != 50 {
+		t.Errorf("Expected original resized to 200x50, got %dx%d", out.Width, out.Height)
+	}
+	if !strings.HasPrefix(out.URL, "/uploads/") || !strings.HasSuffix(out.URL, ".png") {
+		t.Errorf("Unexpected URL %q", out.URL)
+	}
+	if !strings.HasSuffix(out.ThumbnailURL, "_thumb.png") {
+		t.Errorf("Unexpected thumbnail URL %q", out.ThumbnailURL)
+	}
+
+	f, err := os.Open(out.ThumbPath)
+	if err != nil {
+		t.Fatalf("Thumbnail not written: %v", err)
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != 50 || cfg.Height != 13 {
+		t.Errorf("Expected thumbnail 50x13, got %dx%d", cfg.Width, cfg.Height)
+	}
+}
+
+func TestImageUploadRejectsSpoofedFile(t *testing.T) {
+	field := ImageUpload("cover")
+
+	_, err := field.Process(strings.NewReader("<html><body>not an image</body></html>"), t.TempDir(), "/uploads")
+	if !errors.Is(err, ErrNotAnImage) {
+		t.Errorf("Expected ErrNotAnImage, got %v", err)
+	}
+}
+
+// pngHeader returns a PNG made of its signature and IHDR chunk only: it
+// declares w x h pixels without carrying any of them.
+func pngHeader(w, h uint32) []byte {
+	ihdr := []byte("IHDR")
+	ihdr = binary.BigEndian.AppendUint32(ihdr, w)
+	ihdr = binary.BigEndian.AppendUint32(ihdr, h)
+	ihdr = append(ihdr, 8, 2, 0, 0, 0) // 8-bit RGB
+	out := []byte("\x89PNG\r\n\x1a\n")
+	out = binary.BigEndian.AppendUint32(out, uint32(len(ihdr)-4))
+	out = append(out, ihdr...)
+	return binary.BigEndian.AppendUint32(out, crc32.ChecksumIEEE(ihdr))
+}
+
+func TestImageUploadRejectsTooManyPixels(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := ImageUpload("cover").Process(bytes.NewReader(pngHeader(50_000, 50_000)), dir, "/uploads")
+	if !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("Expected ErrImageTooLarge, got %v", err)
+	}
+
+	field := ImageUpload("cover").MaxResolution(100 * 100)
+	if _, err := field.Process(bytes.NewReader(testPNG(t, 200, 100)), dir, "/uploads"); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("Expected ErrImageTooLarge over a custom cap, got %v", err)
+	}
+	if _, err := field.Process(bytes.NewReader(testPNG(t, 100, 100)), dir, "/uploads"); err != nil {
+		t.Errorf("Expected an image within the cap accepted, got %v", err)
+	}
+}
+
+func TestResizeImageNoUpscale(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	if got := ResizeImage(img, 100, 100); got != image.Image(img) {
+		t.Error("Expected small image to be returned unchanged")
+	}
+}
@@ -73,9 +73,13 @@ func (g *Grid) ComponentType() string { return "layout_grid" }
 // Schema returns the grid components.
 func (g *Grid) Schema() []Component { return g.Components }
 
-// Tabs represents a tab system.
+// Tabs represents a tab system. All tabs are rendered in the form, with the
+// inactive ones hidden, so submitting includes the fields of every tab.
 type Tabs struct {
 	TabItems []Tab
+	// Active is the index of the tab shown first. Form.Validate sets it to
+	// the tab holding the first invalid field.
+	Active int
 }
 
 // Tab represents a tab.
@@ -92,6 +96,15 @@ func NewTabs() *Tabs {
 	}
 }
 
+// Tab adds a tab with the given components.
+//
+//	form.NewTabs().
+//		Tab("General", form.Text("name"), form.Email("email")).
+//		Tab("SEO", form.Text("meta_title"))
+func (t *Tabs) Tab(label string, components ...Component) *Tabs {
+	return t.AddTab(label, components...)
+}
+
 // AddTab adds a tab.
 func (t *Tabs) AddTab(label string, components ...Component) *Tabs {
 	t.TabItems = append(t.TabItems, Tab{
@@ -101,6 +114,35 @@ func (t *Tabs) AddTab(label string, components ...Component) *Tabs {
 	return t
 }
 
+// WithIcon sets the icon of the last added tab.
+func (t *Tabs) WithIcon(icon string) *Tabs {
+	if len(t.TabItems) > 0 {
+		t.TabItems[len(t.TabItems)-1].Icon = icon
+	}
+	return t
+}
+
+// ActiveTab returns the index of the tab shown first, 0 when Active is out
+// of range.
+func (t *Tabs) ActiveTab() int {
+	if t.Active < 0 || t.Active >= len(t.TabItems) {
+		return 0
+	}
+	return t.Active
+}
+
+// tabOf returns the index of the tab holding the field named name, or -1.
+func (t *Tabs) tabOf(name string) int {
+	for i, tab := range t.TabItems {
+		for _, c := range flatten(tab.Components) {
+			if field, ok := c.(interface{ Name() string }); ok && field.Name() == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
 // IsVisible returns true if the tabs are visible.
 func (t *Tabs) IsVisible() bool { return true }
 
@@ -124,9 +166,16 @@ type WizardStep struct {
 	Components  []Component
 }
 
-// Wizard represents a multi-step form wizard.
+// Wizard represents a multi-step form wizard. Each step is validated
+// before the next one is shown; see wizard.go for the step state.
 type Wizard struct {
 	Steps []*WizardStep
+	// Validator checks the values of a step on top of the field rules.
+	Validator StepValidator
+	// Current is the index of the step shown.
+	Current int
+	// Errors holds the field errors of the current step.
+	Errors map[string][]string
 }
 
 // NewWizard creates a new Wizard.
@@ -0,0 +1,153 @@
+package form
+
+import "unicode"
+
+// Mask placeholder tokens understood by MaskedInput.
+//
+//	#  any digit
+//	A  any letter
+//	*  any letter or digit
+//
+// Every other character in the pattern is a literal inserted as the user types.
+const (
+	MaskDigit    = '#'
+	MaskLetter   = 'A'
+	MaskAlphaNum = '*'
+)
+
+// Preset masks for common French fields.
+const (
+	MaskPhoneFR  = "## ## ## ## ##"
+	MaskSIRET    = "### ### ### #####"
+	MaskPostalFR = "#####"
+)
+
+// MaskedInputField is a text input formatted client-side with a mask pattern.
+type MaskedInputField struct {
+	BaseField
+	MaskPattern string
+	Unmasked    bool
+}
+
+// MaskedInput creates a masked input field.
+func MaskedInput(name string) *MaskedInputField {
+	return &MaskedInputField{
+		BaseField: BaseField{fieldName: name, LabelStr: name},
+	}
+}
+
+// Label sets the label.
+func (m *MaskedInputField) Label(label string) *MaskedInputField {
+	m.LabelStr = label
+	return m
+}
+
+// WithPlaceholder sets the placeholder. Defaults to the mask pattern.
+func (m *MaskedInputField) WithPlaceholder(p string) *MaskedInputField {
+	m.fieldPlaceholder = p
+	return m
+}
+
+// Required makes the field required.
+func (m *MaskedInputField) Required() *MaskedInputField {
+	m.BaseField.Required = true
+	m.fieldRules = append(m.fieldRules, "required")
+	return m
+}
+
+// WithRules adds validation rules (e.g. "phone_fr", "siret").
+func (m *MaskedInputField) WithRules(rules ...string) *MaskedInputField {
+	m.fieldRules = append(m.fieldRules, rules...)
+	return m
+}
+
+// Default sets the default value.
+func (m *MaskedInputField) Default(val string) *MaskedInputField {
+	m.fieldValue = val
+	return m
+}
+
+// Mask sets the mask pattern, e.g. "##/##/####" or "+33 # ## ## ## ##".
+func (m *MaskedInputField) Mask(pattern string) *MaskedInputField {
+	m.MaskPattern = pattern
+	return m
+}
+
+// StoreUnmasked strips the mask literals before submission, so only the
+// characters typed into placeholder positions are sent.
+func (m *MaskedInputField) StoreUnmasked() *MaskedInputField {
+	m.Unmasked = true
+	return m
+}
+
+// ComponentType returns the component type identifier.
+func (m *MaskedInputField) ComponentType() string { return "masked_input" }
+
+// PlaceholderText returns the placeholder, falling back to the mask pattern.
+func (m *MaskedInputField) PlaceholderText() string {
+	if m.fieldPlaceholder != "" {
+		return m.fieldPlaceholder
+	}
+	return m.MaskPattern
+}
+
+// ApplyMask formats value with pattern. It mirrors the client-side behavior
+// so values can be displayed consistently when rendered server-side.
+func ApplyMask(pattern, value string) string {
+	masked, _ := applyMask(pattern, value)
+	return masked
+}
+
+// Unmask returns only the characters of value that land on placeholder
+// positions of pattern, i.e. what the client submits with StoreUnmasked.
+func Unmask(pattern, value string) string {
+	_, raw := applyMask(pattern, value)
+	return raw
+}
+
+func applyMask(pattern, value string) (masked, raw string) {
+	src := make([]rune, 0, len(value))
+	for _, r := range value {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			src = append(src, r)
+		}
+	}
+
+	out := make([]rune, 0, len(pattern))
+	kept := make([]rune, 0, len(src))
+	j := 0
+	for _, p := range pattern {
+		if j >= len(src) {
+			break
+		}
+		switch p {
+		case MaskDigit, MaskLetter, MaskAlphaNum:
+			for j < len(src) && !maskAccepts(p, src[j]) {
+				j++
+			}
+			if j >= len(src) {
+				return string(out), string(kept)
+			}
+			out = append(out, src[j])
+			kept = append(kept, src[j])
+			j++
+		default:
+			out = append(out, p)
+			if src[j] == p {
+				j++
+			}
+		}
+	}
+	return string(out), string(kept)
+}
+
+func maskAccepts(token, r rune) bool {
+	switch token {
+	case MaskDigit:
+		return unicode.IsDigit(r)
+	case MaskLetter:
+		return unicode.IsLetter(r)
+	default:
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+}
@@ -0,0 +1,79 @@
+package form
+
+import "testing"
+
+func TestMaskedInput(t *testing.T) {
+	field := MaskedInput("phone").
+		Label("Phone").
+		Mask("+33 # ## ## ## ##").
+		StoreUnmasked().
+		WithRules("phone_fr")
+
+	if field.ComponentType() != "masked_input" {
+		t.Errorf("Expected component type 'masked_input', got '%s'", field.ComponentType())
+	}
+	if !field.Unmasked {
+		t.Error("Expected StoreUnmasked to be set")
+	}
+	if field.PlaceholderText() != "+33 # ## ## ## ##" {
+		t.Errorf("Expected placeholder to default to the mask, got '%s'", field.PlaceholderText())
+	}
+	if field.RulesString() != "phone_fr" {
+		t.Errorf("Expected rules 'phone_fr', got '%s'", field.RulesString())
+	}
+}
+
+func TestApplyMask(t *testing.T) {
+	tests := []struct {
+		pattern, value, masked, raw string
+	}{
+		{"##/##/####", "25122024", "25/12/2024", "25122024"},
+		{"##/##/####", "25/12", "25/12", "2512"},
+		{"+33 # ## ## ## ##", "612345678", "+33 6 12 34 56 78", "612345678"},
+		{"+33 # ## ## ## ##", "+33 6 12 34 56 78", "+33 6 12 34 56 78", "612345678"},
+		{"### ### ### #####", "73282932000074", "732 829 320 00074", "73282932000074"},
+		{"AA-###", "ab1x23", "ab-123", "ab123"},
+	}
+
+	for _, tt := range tests {
+		if got := ApplyMask(tt.pattern, tt.value); got != tt.masked {
+			t.Errorf("ApplyMask(%q, %q) = %q, want %q", tt.pattern, tt.value, got, tt.masked)
+		}
+		if got := Unmask(tt.pattern, tt.value); got != tt.raw {
+			t.Errorf("Unmask(%q, %q) = %q, want %q", tt.pattern, tt.value, got, tt.raw)
+		}
+	}
+}
+
+func TestTextInputMask(t *testing.T) {
+	phone := Text("phone").Mask(MaskPhoneFR).Required()
+	siret := Text("siret").Mask(MaskSIRET)
+	postal := Text("postal").Mask(MaskPostalFR)
+	f := New().SetSchema(
+		NewSection("Contact").SetSchema(NewGrid(2).SetSchema(phone, Text("city"))),
+		NewTabs().Tab("Company", siret).Tab("Address", postal),
+	)
+
+	if phone.InputMask() != "## ## ## ## ##" || Text("name").InputMask() != "" {
+		t.Errorf("Unexpected InputMask values: %q", phone.InputMask())
+	}
+	if got := ApplyMask(MaskPostalFR, "75 0012"); got != "75001" {
+		t.Errorf("Expected postal code 75001, got %q", got)
+	}
+
+	data := map[string]any{"phone": "06 12 34 56 78", "siret": "732 829 320 00074", "postal": "75 001", "city": "Paris"}
+	if !f.Validate(data) {
+		t.Fatalf("Expected masked values to validate, got %v", f.Errors)
+	}
+	if data["phone"] != "0612345678" || data["siret"] != "73282932000074" || data["postal"] != "75001" {
+		t.Errorf("Expected mask literals stripped in nested layouts, got %v", data)
+	}
+	if data["city"] != "Paris" {
+		t.Errorf("Expected unmasked field untouched, got %v", data["city"])
+	}
+
+	// Rules see the normalized value: mask literals alone are empty.
+	if f.Validate(map[string]any{"phone": "   "}) {
+		t.Error("Expected a phone made of mask literals to fail required")
+	}
+}
@@ -0,0 +1,74 @@
+package form
+
+import "net/http"
+
+// ToggleGroupLayout renders a settings-style list of labeled toggles, each with an
+// optional description. Every toggle is submitted as name=on or name=off.
+type ToggleGroupLayout struct {
+	Title       string
+	Description string
+	Toggles     []*ToggleInput
+}
+
+// ToggleGroup creates a new toggle group.
+func ToggleGroup(title string) *ToggleGroupLayout {
+	return &ToggleGroupLayout{
+		Title:   title,
+		Toggles: make([]*ToggleInput, 0),
+	}
+}
+
+// AddToggle adds a toggle to the group.
+func (g *ToggleGroupLayout) AddToggle(name, label, description string, def bool) *ToggleGroupLayout {
+	t := Toggle(name).Label(label).Default(def)
+	t.HelpText = description
+	g.Toggles = append(g.Toggles, t)
+	return g
+}
+
+// Desc sets the group description.
+func (g *ToggleGroupLayout) Desc(desc string) *ToggleGroupLayout {
+	g.Description = desc
+	return g
+}
+
+// IsVisible returns true.
+func (g *ToggleGroupLayout) IsVisible() bool { return true }
+
+// ComponentType returns the component type.
+func (g *ToggleGroupLayout) ComponentType() string { return "layout_toggle_group" }
+
+// Schema returns the group's toggles.
+func (g *ToggleGroupLayout) Schema() []Component {
+	components := make([]Component, len(g.Toggles))
+	for i, t := range g.Toggles {
+		components[i] = t
+	}
+	return components
+}
+
+// Names returns the names of all toggles in the group.
+func (g *ToggleGroupLayout) Names() []string {
+	names := make([]string, len(g.Toggles))
+	for i, t := range g.Toggles {
+		names[i] = t.Name()
+	}
+	return names
+}
+
+// ParseToggleGroup reads the submitted on/off state of the given toggles.
+// Each toggle renders a hidden "off" input followed by an "on" checkbox,
+// so the last submitted value wins. Missing toggles are reported as false.
+func ParseToggleGroup(r *http.Request, names []string) map[string]bool {
+	_ = r.ParseForm()
+	result := make(map[string]bool, len(names))
+	for _, name := range names {
+		values := r.Form[name]
+		if len(values) == 0 {
+			result[name] = false
+			continue
+		}
+		result[name] = values[len(values)-1] == "on"
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package form
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestToggleGroup(t *testing.T) {
+	g := ToggleGroup("Notifications").
+		AddToggle("email_alerts", "Email alerts", "Receive an email for each new order", true).
+		AddToggle("sms_alerts", "SMS alerts", "", false)
+
+	if g.ComponentType() != "layout_toggle_group" {
+		t.Errorf("Expected component type 'layout_toggle_group', got '%s'", g.ComponentType())
+	}
+	if len(g.Schema()) != 2 {
+		t.Fatalf("Expected 2 toggles, got %d", len(g.Schema()))
+	}
+	first := g.Toggles[0]
+	if first.LabelStr != "Email alerts" || first.Help() != "Receive an email for each new order" {
+		t.Errorf("Unexpected toggle label/description: %q / %q", first.LabelStr, first.Help())
+	}
+	if !first.IsChecked() {
+		t.Error("Expected first toggle to default to on")
+	}
+	if names := g.Names(); len(names) != 2 || names[1] != "sms_alerts" {
+		t.Errorf("Unexpected names: %v", names)
+	}
+}
+
+func TestParseToggleGroup(t *testing.T) {
+	// The view sends a hidden "off" before each checkbox; checked boxes add "on".
+	body := url.Values{
+		"email_alerts": {"off", "on"},
+		"sms_alerts":   {"off"},
+	}
+	req := httptest.NewRequest("POST", "/settings", strings.NewReader(body.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	got := ParseToggleGroup(req, []string{"email_alerts", "sms_alerts", "push_alerts"})
+
+	if !got["email_alerts"] {
+		t.Error("Expected email_alerts to be on")
+	}
+	if got["sms_alerts"] {
+		t.Error("Expected sms_alerts to be off")
+	}
+	if v, ok := got["push_alerts"]; !ok || v {
+		t.Error("Expected missing push_alerts to be reported as off")
+	}
+}
@@ -0,0 +1,127 @@
+package form
+
+import (
+	"context"
+	"net/url"
+)
+
+// StepValidator checks the values submitted for a wizard step, e.g. that a
+// slug is not taken. It returns the field errors, nil when the step is
+// valid.
+type StepValidator func(ctx context.Context, step int, values map[string]any) map[string][]string
+
+// Step adds a step to the wizard.
+//
+//	form.NewWizard().
+//		Step("Account", form.Email("email").Required()).
+//		Step("Profile", form.Text("name").Required(), form.Text("company"))
+func (w *Wizard) Step(label string, components ...Component) *Wizard {
+	return w.AddStep(label, components...)
+}
+
+// ValidateStep sets a callback run when a step is submitted, after the field
+// rules of the step passed.
+func (w *Wizard) ValidateStep(fn StepValidator) *Wizard {
+	w.Validator = fn
+	return w
+}
+
+// IsLast reports whether step is the last step.
+func (w *Wizard) IsLast(step int) bool {
+	return step >= len(w.Steps)-1
+}
+
+// CurrentStep returns the step shown, nil for a wizard without steps.
+func (w *Wizard) CurrentStep() *WizardStep {
+	if w.Current < 0 || w.Current >= len(w.Steps) {
+		return nil
+	}
+	return w.Steps[w.Current]
+}
+
+// Validate checks the values submitted for step against the rules of its
+// fields and the ValidateStep callback. It returns the field errors, nil
+// when the step is valid.
+func (w *Wizard) Validate(ctx context.Context, step int, values map[string]any) map[string][]string {
+	if step < 0 || step >= len(w.Steps) {
+		return nil
+	}
+	f := New().SetSchema(w.Steps[step].Components...)
+	f.Validate(values)
+	errs := f.Errors
+	if len(errs) == 0 && w.Validator != nil {
+		errs = w.Validator(ctx, step, values)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Fill sets the value of the fields of all steps found in values, e.g. the
+// values entered in earlier visits of a step.
+func (w *Wizard) Fill(values url.Values) *Wizard {
+	for _, c := range flatten(w.Schema()) {
+		field, ok := c.(interface {
+			Name() string
+			SetValue(v any)
+		})
+		if !ok {
+			continue
+		}
+		if v, ok := values[field.Name()]; ok && len(v) > 0 {
+			field.SetValue(FormValue(v))
+		}
+	}
+	return w
+}
+
+// FormValue returns a submitted form value as validation expects it: the
+// string for single values, the slice for repeated ones.
+func FormValue(v []string) any {
+	if len(v) == 1 {
+		return v[0]
+	}
+	return v
+}
+
+// WizardState is the progress of a wizard being filled in: the step shown,
+// the values entered so far and the errors of the last submission. Handlers
+// pass it to the rendering code with WithWizardState.
+type WizardState struct {
+	Step   int
+	Values url.Values
+	Errors map[string][]string
+	// Action is the URL the steps are posted to.
+	Action string
+}
+
+type wizardStateKey struct{}
+
+// WithWizardState returns a context carrying the state of the wizard being
+// rendered.
+func WithWizardState(ctx context.Context, st WizardState) context.Context {
+	return context.WithValue(ctx, wizardStateKey{}, st)
+}
+
+// WizardStateFromContext returns the wizard state set by WithWizardState.
+func WizardStateFromContext(ctx context.Context) (WizardState, bool) {
+	st, ok := ctx.Value(wizardStateKey{}).(WizardState)
+	return st, ok
+}
+
+// Apply sets the step, values and errors of st on the wizard.
+func (w *Wizard) Apply(st WizardState) *Wizard {
+	w.Current = st.Step
+	w.Errors = st.Errors
+	return w.Fill(st.Values)
+}
+
+// StepFieldNames returns the names of the fields of step, including the ones
+// nested in layouts.
+func (w *Wizard) StepFieldNames(step int) []string {
+	if step < 0 || step >= len(w.Steps) {
+		return nil
+	}
+	return New().SetSchema(w.Steps[step].Components...).FieldNames()
+}
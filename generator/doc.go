@@ -23,6 +23,34 @@
 //	// Generate a complete resource (resource.go, table.go, form.go, schema.go)
 //	err = generator.GenerateResource(gen, "Product", projectPath)
 //
+//	// Serve it at a slug of your own instead of the pluralized name
+//	err = generator.GenerateResourceWithSlug(gen, "Person", "staff", projectPath)
+//
+// Generate the resource of an existing Ent schema file, with form fields
+// and table columns inferred from the field types, sensitive fields kept out
+// of the table and a relation manager per edge:
+//
+//	err = generator.GenerateResourceFromSchema(gen, "internal/ent/schema/user.go", projectPath)
+//
+// Slugs are pluralized with English rules and a dictionary of irregular
+// plurals ("Person" → "people", "Status" → "statuses"), shared with the
+// scanner. Extend it with RegisterIrregular and RegisterUncountable.
+//
+// Preview a scaffold with DryRun: nothing is written, each file is printed
+// as new, modified, unchanged or skipped with a unified diff against the
+// file on disk, and Changes and Overwrites report the outcome:
+//
+//	gen, _ := generator.New(&generator.Options{DryRun: true, Force: true})
+//	err = generator.GenerateResource(gen, "Product", projectPath)
+//	if len(gen.Overwrites()) > 0 {
+//		// --force would replace existing files
+//	}
+//
+// Generate a Relation Manager for a generated resource, registered in its
+// GetRelationManagers (belongs_to, has_one, has_many or many_to_many):
+//
+//	err = generator.GenerateRelationManager(gen, "Post", "comments", "has_many")
+//
 // Generate a Custom Page:
 //
 //	// Generate a page with default options
@@ -31,6 +59,13 @@
 //	// Generate a page with custom options (group, icon, sort order)
 //	err = generator.GeneratePageWithOptions(gen, "Analytics", projectPath, "Reports", "chart", 50)
 //
+// Generate a Dashboard Widget (internal/widgets/sales/widget.go):
+//
+//	err = generator.GenerateWidget(gen, "Sales", projectPath)
+//
+// Pages and widgets are generated where the scanner looks for them, so
+// 'sublimego generate' registers them in the provider.
+//
 // Generated Page Structure:
 //
 //	internal/pages/settings/
@@ -0,0 +1,110 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/samber/lo"
+)
+
+// ChangeStatus tells what generating a file does to the disk.
+type ChangeStatus string
+
+const (
+	ChangeNew       ChangeStatus = "new"       // the file does not exist yet
+	ChangeModified  ChangeStatus = "modified"  // the file exists and is overwritten
+	ChangeUnchanged ChangeStatus = "unchanged" // the file exists with the same content
+	ChangeSkipped   ChangeStatus = "skipped"   // the file is skipped or exists without Force
+)
+
+// FileChange describes a file generated, or previewed in dry-run mode.
+type FileChange struct {
+	Path   string
+	Status ChangeStatus
+	Diff   string // unified diff against the file on disk, dry-run only
+}
+
+// Changes returns the files handled by the generator, in order.
+func (g *Generator) Changes() []FileChange {
+	return g.changes
+}
+
+// Overwrites returns the existing files whose content the generator
+// replaces, or would replace in dry-run mode.
+func (g *Generator) Overwrites() []FileChange {
+	return lo.Filter(g.changes, func(c FileChange, _ int) bool {
+		return c.Status == ChangeModified
+	})
+}
+
+// output returns the writer of the dry-run preview.
+func (g *Generator) output() io.Writer {
+	if g.options.Output != nil {
+		return g.options.Output
+	}
+	return os.Stdout
+}
+
+// skip records a file left out with the Skip option.
+func (g *Generator) skip(path string) {
+	g.changes = append(g.changes, FileChange{Path: path, Status: ChangeSkipped})
+	if g.options.DryRun {
+		fmt.Fprintf(g.output(), "skipped    %s\n", path)
+	}
+}
+
+// preview records and prints what writing content to path would do, with
+// the unified diff against the current file. Existing files are skipped
+// unless Force is set.
+func (g *Generator) preview(path string, content []byte) error {
+	change := FileChange{Path: path, Status: ChangeNew}
+	var current []byte
+	if fileExists(path) {
+		var err error
+		if current, err = os.ReadFile(path); err != nil {
+			return err
+		}
+		switch {
+		case !g.options.Force:
+			change.Status = ChangeSkipped
+		case string(current) == string(content):
+			change.Status = ChangeUnchanged
+		default:
+			change.Status = ChangeModified
+		}
+	}
+
+	w := g.output()
+	switch change.Status {
+	case ChangeSkipped:
+		fmt.Fprintf(w, "skipped    %s (exists, use --force to overwrite)\n", path)
+	case ChangeUnchanged:
+		fmt.Fprintf(w, "unchanged  %s\n", path)
+	default:
+		from := path
+		if change.Status == ChangeNew {
+			from = "/dev/null"
+		}
+		var lines []string
+		if current != nil {
+			lines = difflib.SplitLines(string(current))
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        lines,
+			B:        difflib.SplitLines(string(content)),
+			FromFile: from,
+			ToFile:   path,
+			Context:  3,
+		})
+		if err != nil {
+			return err
+		}
+		change.Diff = diff
+		fmt.Fprintf(w, "%-10s %s\n%s\n", change.Status, path, diff)
+	}
+
+	g.changes = append(g.changes, change)
+	return nil
+}
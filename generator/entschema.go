@@ -0,0 +1,410 @@
+package generator
+
+import (
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+//go:embed stubs/resource_schema.go.tmpl
+var resourceSchemaTemplate string
+
+// EntField describes a field of an Ent schema.
+type EntField struct {
+	Name      string   // status
+	Kind      string   // Ent constructor: String, Text, Enum, Bool, Time, Int...
+	Values    []string // enum values
+	Default   string   // literal default value, empty when none
+	Optional  bool
+	Sensitive bool // declared Sensitive() or named like a secret
+}
+
+// EntEdge describes an edge of an Ent schema.
+type EntEdge struct {
+	Name    string // comments
+	Target  string // Comment
+	Inverse bool   // edge.From
+	Unique  bool
+}
+
+// RelationType returns the relation type of the edge: an edge.To is a
+// has_one or has_many, an edge.From a belongs_to or, when not unique, the
+// inverse side of a many_to_many.
+func (e EntEdge) RelationType() string {
+	switch {
+	case e.Inverse && e.Unique:
+		return "belongs_to"
+	case e.Inverse:
+		return "many_to_many"
+	case e.Unique:
+		return "has_one"
+	default:
+		return "has_many"
+	}
+}
+
+// EntSchema describes an Ent schema type with its fields and edges.
+type EntSchema struct {
+	Name   string // Post
+	Fields []EntField
+	Edges  []EntEdge
+}
+
+// sensitiveNames are the field name fragments treated as secrets.
+var sensitiveNames = []string{"password", "secret", "token", "api_key"}
+
+// ParseEntSchema parses the Ent schema types declared in a file, with the
+// fields of their Fields() method and the edges of their Edges() method.
+func ParseEntSchema(path string) ([]EntSchema, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	byName := make(map[string]*EntSchema)
+	var names []string
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 || fn.Body == nil {
+			continue
+		}
+		recv, ok := fn.Recv.List[0].Type.(*ast.Ident)
+		if !ok || (fn.Name.Name != "Fields" && fn.Name.Name != "Edges") {
+			continue
+		}
+		schema, ok := byName[recv.Name]
+		if !ok {
+			schema = &EntSchema{Name: recv.Name}
+			byName[recv.Name] = schema
+			names = append(names, recv.Name)
+		}
+		for _, elt := range returnedElements(fn.Body) {
+			if fn.Name.Name == "Fields" {
+				if f, ok := parseEntField(elt); ok {
+					schema.Fields = append(schema.Fields, f)
+				}
+			} else if e, ok := parseEntEdge(elt); ok {
+				schema.Edges = append(schema.Edges, e)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no Ent schema found in %s", path)
+	}
+
+	sort.Strings(names)
+	schemas := make([]EntSchema, len(names))
+	for i, name := range names {
+		schemas[i] = *byName[name]
+	}
+	return schemas, nil
+}
+
+// returnedElements returns the elements of the slice literals returned by a
+// Fields() or Edges() body.
+func returnedElements(body *ast.BlockStmt) []ast.Expr {
+	var elts []ast.Expr
+	for _, stmt := range body.List {
+		ret, ok := stmt.(*ast.ReturnStmt)
+		if !ok || len(ret.Results) != 1 {
+			continue
+		}
+		if lit, ok := ret.Results[0].(*ast.CompositeLit); ok {
+			elts = append(elts, lit.Elts...)
+		}
+	}
+	return elts
+}
+
+// parseEntField walks a field.X("name").Modifier()... call chain.
+func parseEntField(expr ast.Expr) (EntField, bool) {
+	var f EntField
+	for {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return f, false
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return f, false
+		}
+
+		if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "field" {
+			if _, known := entFieldTypes[sel.Sel.Name]; !known || len(call.Args) == 0 {
+				return f, false
+			}
+			name, ok := stringLit(call.Args[0])
+			if !ok {
+				return f, false
+			}
+			f.Name = name
+			f.Kind = sel.Sel.Name
+			for _, s := range sensitiveNames {
+				f.Sensitive = f.Sensitive || strings.Contains(name, s)
+			}
+			return f, true
+		}
+
+		switch sel.Sel.Name {
+		case "Optional", "Nillable":
+			f.Optional = true
+		case "Sensitive":
+			f.Sensitive = true
+		case "Values":
+			// Modifiers are visited from the last one: keep the source order.
+			var values []string
+			for _, arg := range call.Args {
+				if v, ok := stringLit(arg); ok {
+					values = append(values, v)
+				}
+			}
+			f.Values = append(values, f.Values...)
+		case "Default":
+			if len(call.Args) == 1 {
+				if v, ok := stringLit(call.Args[0]); ok {
+					f.Default = v
+				} else if lit, ok := call.Args[0].(*ast.BasicLit); ok {
+					f.Default = lit.Value
+				} else if ident, ok := call.Args[0].(*ast.Ident); ok && (ident.Name == "true" || ident.Name == "false") {
+					f.Default = ident.Name
+				}
+			}
+		}
+		expr = sel.X
+	}
+}
+
+// parseEntEdge walks an edge.To("name", T.Type).Modifier()... call chain.
+func parseEntEdge(expr ast.Expr) (EntEdge, bool) {
+	var e EntEdge
+	for {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return e, false
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return e, false
+		}
+
+		if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "edge" {
+			if (sel.Sel.Name != "To" && sel.Sel.Name != "From") || len(call.Args) != 2 {
+				return e, false
+			}
+			name, ok := stringLit(call.Args[0])
+			if !ok {
+				return e, false
+			}
+			target, ok := call.Args[1].(*ast.SelectorExpr)
+			if !ok {
+				return e, false
+			}
+			typ, ok := target.X.(*ast.Ident)
+			if !ok {
+				return e, false
+			}
+			e.Name = name
+			e.Target = typ.Name
+			e.Inverse = sel.Sel.Name == "From"
+			return e, true
+		}
+
+		if sel.Sel.Name == "Unique" {
+			e.Unique = true
+		}
+		expr = sel.X
+	}
+}
+
+// SchemaResourceData contains the data to generate a resource from an Ent
+// schema.
+type SchemaResourceData struct {
+	*ResourceData
+	EntPackage string   // post
+	FormFields []string // form field expressions
+	Columns    []string // engine.Column literals
+	Setters    []string // builder setters filled from the request
+	Todo       []string // fields the setters leave to the developer
+	Relations  []string // relation manager types, one per edge
+}
+
+// NewSchemaResourceData infers the form fields, table columns and relations
+// of a resource from an Ent schema. Sensitive fields get a password input
+// and no column; JSON and Bytes fields are left out.
+func NewSchemaResourceData(schema EntSchema) *SchemaResourceData {
+	data := &SchemaResourceData{
+		ResourceData: NewResourceData(schema.Name),
+		EntPackage:   strings.ToLower(schema.Name),
+		Columns:      []string{`{Key: "ID", Label: "ID", Sortable: true}`},
+	}
+	for _, e := range schema.Edges {
+		data.Relations = append(data.Relations, ToPascalCase(ToSnakeCase(e.Name))+"RelationManager")
+	}
+
+	for _, f := range schema.Fields {
+		if expr := formField(f); expr != "" {
+			data.FormFields = append(data.FormFields, expr)
+		}
+		if col := tableColumn(f); col != "" {
+			data.Columns = append(data.Columns, col)
+		}
+		if setter := fieldSetter(data.EntPackage, f); setter != "" {
+			data.Setters = append(data.Setters, setter)
+		} else if f.Sensitive {
+			data.Todo = append(data.Todo, f.Name+" (hash it before storing)")
+		} else if f.Kind != "JSON" && f.Kind != "Bytes" {
+			data.Todo = append(data.Todo, f.Name)
+		}
+	}
+	return data
+}
+
+// formField returns the form field expression of an Ent field.
+func formField(f EntField) string {
+	label := fieldLabel(f.Name)
+	var expr string
+	switch {
+	case f.Kind == "JSON" || f.Kind == "Bytes":
+		return ""
+	case f.Sensitive:
+		// Left optional so that editing a record keeps the stored secret.
+		return fmt.Sprintf("form.Password(%q).Label(%q)", f.Name, label)
+	case f.Kind == "String" && strings.Contains(f.Name, "email"):
+		expr = fmt.Sprintf("form.Email(%q).Label(%q)", f.Name, label)
+	case f.Kind == "String" || f.Kind == "UUID":
+		expr = fmt.Sprintf("form.Text(%q).Label(%q)", f.Name, label)
+	case f.Kind == "Text":
+		expr = fmt.Sprintf("form.Textarea(%q).Label(%q)", f.Name, label)
+	case f.Kind == "Bool":
+		expr = fmt.Sprintf("form.Toggle(%q).Label(%q)", f.Name, label)
+		if f.Default == "true" {
+			expr += ".Default(true)"
+		}
+		return expr
+	case f.Kind == "Time":
+		expr = fmt.Sprintf("form.DateTime(%q).Label(%q)", f.Name, label)
+	case f.Kind == "Enum":
+		options := make([]string, len(f.Values))
+		for i, v := range f.Values {
+			options[i] = fmt.Sprintf("%q: %q", v, fieldLabel(v))
+		}
+		expr = fmt.Sprintf("form.Select(%q).Label(%q).Options(map[string]string{%s})", f.Name, label, strings.Join(options, ", "))
+		if f.Default != "" {
+			expr += fmt.Sprintf(".Default(%q)", f.Default)
+		}
+	default:
+		expr = fmt.Sprintf("form.Number(%q).Label(%q)", f.Name, label)
+	}
+	if !f.Optional && f.Default == "" {
+		expr += ".Required()"
+	}
+	return expr
+}
+
+// tableColumn returns the engine.Column literal of an Ent field, empty for
+// the fields kept out of the table.
+func tableColumn(f EntField) string {
+	if f.Sensitive || f.Kind == "Text" || f.Kind == "JSON" || f.Kind == "Bytes" {
+		return ""
+	}
+	col := fmt.Sprintf("{Key: %q, Label: %q", entGoName(f.Name), fieldLabel(f.Name))
+	switch f.Kind {
+	case "Bool":
+		col += `, Type: "boolean"`
+	case "Time":
+		col += `, Type: "date"`
+	case "Enum":
+		col += `, Type: "badge"`
+	}
+	col += ", Sortable: true"
+	if f.Kind == "String" || f.Kind == "Enum" {
+		col += ", Searchable: true"
+	}
+	return col + "}"
+}
+
+// fieldSetter returns the Ent builder setter filling a field from the
+// request, empty for the fields that need parsing or, for sensitive ones,
+// hashing first.
+func fieldSetter(entPackage string, f EntField) string {
+	name := entGoName(f.Name)
+	switch {
+	case f.Sensitive:
+		return ""
+	case f.Kind == "String" || f.Kind == "Text":
+		return fmt.Sprintf("Set%s(req.FormValue(%q))", name, f.Name)
+	case f.Kind == "Enum":
+		return fmt.Sprintf("Set%s(%s.%s(req.FormValue(%q)))", name, entPackage, name, f.Name)
+	case f.Kind == "Bool":
+		return fmt.Sprintf("Set%s(req.FormValue(%q) == \"true\")", name, f.Name)
+	default:
+		return ""
+	}
+}
+
+// entAcronyms are the words Ent writes in capitals in Go names.
+var entAcronyms = map[string]bool{
+	"id": true, "url": true, "uri": true, "ip": true, "api": true, "uuid": true,
+	"html": true, "http": true, "json": true, "sql": true, "xml": true,
+}
+
+// entGoName returns the Go name Ent generates for a field ("user_id" →
+// "UserID").
+func entGoName(name string) string {
+	words := strings.Split(name, "_")
+	for i, w := range words {
+		if entAcronyms[strings.ToLower(w)] {
+			words[i] = strings.ToUpper(w)
+		} else {
+			words[i] = ToPascalCase(w)
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// fieldLabel returns the display label of a field or enum value.
+func fieldLabel(name string) string {
+	return cases.Title(language.English).String(strings.ReplaceAll(name, "_", " "))
+}
+
+// GenerateResourceFromSchema generates the resource of each Ent schema in
+// schemaPath, with its form fields and table columns inferred from the
+// schema fields and a relation manager per edge.
+func GenerateResourceFromSchema(g *Generator, schemaPath, outputDir string) error {
+	schemas, err := ParseEntSchema(schemaPath)
+	if err != nil {
+		return err
+	}
+	if _, ok := g.templates["resource_schema"]; !ok {
+		tmpl, err := template.New("resource_schema").Parse(resourceSchemaTemplate)
+		if err != nil {
+			return fmt.Errorf("failed to parse template resource_schema: %w", err)
+		}
+		g.templates["resource_schema"] = tmpl
+	}
+
+	for _, schema := range schemas {
+		data := NewSchemaResourceData(schema)
+		outputPath := filepath.Join(outputDir, "internal", "resources", data.PackageName, "resource.go")
+		if err := g.Generate("resource_schema", outputPath, data); err != nil {
+			return fmt.Errorf("failed to generate %s resource: %w", schema.Name, err)
+		}
+
+		for _, edge := range schema.Edges {
+			if err := generateRelationManager(g, outputDir, schema.Name, edge.Name, edge.RelationType()); err != nil {
+				return fmt.Errorf("failed to generate %s relation of %s: %w", edge.Name, schema.Name, err)
+			}
+		}
+	}
+	return nil
+}
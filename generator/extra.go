@@ -58,6 +58,8 @@ func GenerateWidget(g *Generator, name, outputDir string) error {
 		return err
 	}
 	data := NewResourceData(name)
+	data.TypeName = ToPascalCase(name) + "Widget"
+	data.Slug = ToSnakeCase(name)
 	pkgDir := filepath.Join(outputDir, "internal", "widgets", data.PackageName)
 	outputPath := filepath.Join(pkgDir, "widget.go")
 	if err := g.Generate("widget", outputPath, data); err != nil {
@@ -66,7 +68,7 @@ func GenerateWidget(g *Generator, name, outputDir string) error {
 	fmt.Printf("Widget '%s' generated: %s\n", name, outputPath)
 	fmt.Println("\nNext steps:")
 	fmt.Println("  1. Edit widget.go to implement your data provider")
-	fmt.Println("  2. Register the widget in your panel: panel.WithWidgets(New" + data.TypeName + "Widget())")
+	fmt.Println("  2. Run: sublimego generate (registers it in registry.RegisterWidgets)")
 	return nil
 }
 
@@ -5,6 +5,7 @@ import (
 	_ "embed"
 	"fmt"
 	"go/format"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -38,16 +39,26 @@ var pageTemplTemplate string
 type Generator struct {
 	templates map[string]*template.Template
 	options   *Options
+	changes   []FileChange
 }
 
 // Options configures the generator behavior.
 type Options struct {
-	Force     bool
-	DryRun    bool
-	Skip      []string
+	Force bool
+	// DryRun renders the files without writing them and prints each one as
+	// new, modified, unchanged or skipped, with a unified diff against the
+	// file on disk.
+	DryRun bool
+	Skip   []string
+	// Backup copies a file overwritten with Force to {file}.bak.{timestamp}
+	// before writing, so hand-edited files can be recovered.
+	Backup bool
+	// Deprecated: backups are opt-in, set Backup instead. NoBackup is ignored.
 	NoBackup  bool
 	Verbose   bool
 	OutputDir string
+	// Output receives the dry-run preview. Defaults to os.Stdout.
+	Output io.Writer
 }
 
 // New creates a new generator with embedded templates.
@@ -100,25 +111,13 @@ func (g *Generator) HasTemplate(name string) bool {
 	return exists
 }
 
-// Generate creates a file from a template.
+// Generate creates a file from a template. In dry-run mode the file is
+// rendered and previewed instead, see Changes.
 func (g *Generator) Generate(templateName, outputPath string, data interface{}) error {
-	if fileExists(outputPath) && !g.options.Force {
+	if fileExists(outputPath) && !g.options.Force && !g.options.DryRun {
 		return fmt.Errorf("file already exists: %s (use --force to overwrite)", outputPath)
 	}
 
-	if g.options.DryRun {
-		if g.options.Verbose {
-			fmt.Printf("📄 Would generate: %s\n", outputPath)
-		}
-		return nil
-	}
-
-	if fileExists(outputPath) && !g.options.NoBackup {
-		if err := g.backup(outputPath); err != nil {
-			return fmt.Errorf("backup failed: %w", err)
-		}
-	}
-
 	tmpl, exists := g.templates[templateName]
 	if !exists {
 		return fmt.Errorf("template not found: %s", templateName)
@@ -134,6 +133,20 @@ func (g *Generator) Generate(templateName, outputPath string, data interface{})
 		formatted = buf.Bytes()
 	}
 
+	if g.options.DryRun {
+		return g.preview(outputPath, formatted)
+	}
+
+	status := ChangeNew
+	if fileExists(outputPath) {
+		status = ChangeModified
+		if g.options.Backup {
+			if err := g.backup(outputPath); err != nil {
+				return fmt.Errorf("backup failed: %w", err)
+			}
+		}
+	}
+
 	dir := filepath.Dir(outputPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
@@ -142,6 +155,7 @@ func (g *Generator) Generate(templateName, outputPath string, data interface{})
 	if err := os.WriteFile(outputPath, formatted, 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
+	g.changes = append(g.changes, FileChange{Path: outputPath, Status: status})
 
 	if g.options.Verbose {
 		fmt.Printf("Generated: %s (%d bytes)\n", outputPath, len(formatted))
@@ -152,8 +166,8 @@ func (g *Generator) Generate(templateName, outputPath string, data interface{})
 
 // backup creates a backup copy of a file.
 func (g *Generator) backup(path string) error {
-	timestamp := time.Now().Format("20060102_150405")
-	backupPath := fmt.Sprintf("%s.backup_%s", path, timestamp)
+	timestamp := time.Now().Format("20060102150405")
+	backupPath := fmt.Sprintf("%s.bak.%s", path, timestamp)
 
 	content, err := os.ReadFile(path)
 	if err != nil {
@@ -165,7 +179,7 @@ func (g *Generator) backup(path string) error {
 	}
 
 	if g.options.Verbose {
-		fmt.Printf("💾 Backup: %s\n", filepath.Base(backupPath))
+		fmt.Printf("💾 Backup: %s\n", backupPath)
 	}
 
 	return nil
@@ -222,79 +236,3 @@ func ToCamelCase(s string) string {
 	}
 	return strings.ToLower(pascal[:1]) + pascal[1:]
 }
-
-// Pluralize converts a word to its plural form.
-func Pluralize(s string) string {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return ""
-	}
-	special := map[string]string{
-		"person": "people",
-		"child":  "children",
-		"mouse":  "mice",
-		"tooth":  "teeth",
-		"foot":   "feet",
-		"man":    "men",
-		"woman":  "women",
-	}
-
-	lower := strings.ToLower(s)
-	if plural, ok := special[lower]; ok {
-		return plural
-	}
-
-	if strings.HasSuffix(lower, "y") && !isVowel(rune(lower[len(lower)-2])) {
-		return s[:len(s)-1] + "ies"
-	}
-
-	if strings.HasSuffix(lower, "s") || strings.HasSuffix(lower, "x") ||
-		strings.HasSuffix(lower, "z") || strings.HasSuffix(lower, "ch") ||
-		strings.HasSuffix(lower, "sh") {
-		return s + "es"
-	}
-
-	return s + "s"
-}
-
-// Singularize converts a word to its singular form.
-func Singularize(s string) string {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return ""
-	}
-	special := map[string]string{
-		"people":   "person",
-		"children": "child",
-		"mice":     "mouse",
-		"teeth":    "tooth",
-		"feet":     "foot",
-		"men":      "man",
-		"women":    "woman",
-	}
-
-	lower := strings.ToLower(s)
-	if singular, ok := special[lower]; ok {
-		return singular
-	}
-
-	if strings.HasSuffix(lower, "ies") {
-		return s[:len(s)-3] + "y"
-	}
-
-	if strings.HasSuffix(lower, "es") {
-		return s[:len(s)-2]
-	}
-
-	if strings.HasSuffix(lower, "s") {
-		return s[:len(s)-1]
-	}
-
-	return s
-}
-
-// isVowel checks if a character is a vowel.
-func isVowel(r rune) bool {
-	vowels := "aeiouAEIOU"
-	return strings.ContainsRune(vowels, r)
-}
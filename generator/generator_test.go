@@ -1,8 +1,12 @@
 package generator
 
 import (
+	"bytes"
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -116,7 +120,7 @@ func TestGenerateWithBackup(t *testing.T) {
 	os.WriteFile(outputPath, originalContent, 0644)
 
 	// Generate with force and backup
-	g, _ := New(&Options{Force: true, NoBackup: false, Verbose: true})
+	g, _ := New(&Options{Force: true, Backup: true, Verbose: true})
 	data := &ResourceData{
 		PackageName: "test",
 		Name:        "Test",
@@ -138,6 +142,25 @@ func TestGenerateWithBackup(t *testing.T) {
 	if len(newContent) == len(originalContent) {
 		t.Error("File should have been overwritten")
 	}
+
+	// Verify the original content was backed up
+	backups, _ := filepath.Glob(outputPath + ".bak.*")
+	if len(backups) != 1 {
+		t.Fatalf("expected one backup, got %v", backups)
+	}
+	if backup, _ := os.ReadFile(backups[0]); string(backup) != string(originalContent) {
+		t.Errorf("backup content = %q, want %q", backup, originalContent)
+	}
+
+	// Without Backup, nothing is copied
+	os.Remove(backups[0])
+	g2, _ := New(&Options{Force: true})
+	if err := g2.Generate("resource", outputPath, data); err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	if backups, _ := filepath.Glob(outputPath + ".bak.*"); len(backups) != 0 {
+		t.Errorf("expected no backup, got %v", backups)
+	}
 }
 
 func TestGenerateWithDryRun(t *testing.T) {
@@ -167,6 +190,68 @@ func TestGenerateWithDryRun(t *testing.T) {
 	}
 }
 
+func TestGenerateResourceDryRunDiff(t *testing.T) {
+	tmpDir := t.TempDir()
+	resourceDir := filepath.Join(tmpDir, "internal", "resources", "product")
+
+	g, _ := New(&Options{})
+	if err := GenerateResource(g, "Product", tmpDir); err != nil {
+		t.Fatalf("GenerateResource() failed: %v", err)
+	}
+	resourcePath := filepath.Join(resourceDir, "resource.go")
+	edited := []byte("package product\n\n// edited by hand\n")
+	if err := os.WriteFile(resourcePath, edited, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(resourceDir, "table.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	g, _ = New(&Options{DryRun: true, Force: true, Skip: []string{"schema"}, Output: &out})
+	if err := GenerateResource(g, "Product", tmpDir); err != nil {
+		t.Fatalf("GenerateResource() in dry-run failed: %v", err)
+	}
+
+	statuses := make(map[string]ChangeStatus)
+	for _, c := range g.Changes() {
+		statuses[filepath.Base(c.Path)] = c.Status
+	}
+	want := map[string]ChangeStatus{
+		"resource.go": ChangeModified,
+		"table.go":    ChangeNew,
+		"form.go":     ChangeUnchanged,
+		"product.go":  ChangeSkipped,
+	}
+	for file, status := range want {
+		if statuses[file] != status {
+			t.Errorf("%s: status = %q, want %q", file, statuses[file], status)
+		}
+	}
+	if overwrites := g.Overwrites(); len(overwrites) != 1 || overwrites[0].Path != resourcePath {
+		t.Errorf("Overwrites() = %v, want resource.go", overwrites)
+	}
+	if !strings.Contains(out.String(), "-// edited by hand") || !strings.Contains(out.String(), "+++ "+resourcePath) {
+		t.Errorf("expected a unified diff of resource.go, got:\n%s", out.String())
+	}
+
+	if content, _ := os.ReadFile(resourcePath); !bytes.Equal(content, edited) {
+		t.Error("dry-run must not write files")
+	}
+	if _, err := os.Stat(filepath.Join(resourceDir, "table.go")); !os.IsNotExist(err) {
+		t.Error("dry-run must not create files")
+	}
+
+	// Without Force, existing files are reported as skipped.
+	g, _ = New(&Options{DryRun: true, Output: &out})
+	if err := GenerateResource(g, "Product", tmpDir); err != nil {
+		t.Fatalf("GenerateResource() in dry-run failed: %v", err)
+	}
+	if len(g.Overwrites()) != 0 {
+		t.Errorf("expected no overwrite without Force, got %v", g.Overwrites())
+	}
+}
+
 func TestGenerateWithSkip(t *testing.T) {
 	g, _ := New(&Options{Skip: []string{"schema", "form"}})
 
@@ -218,6 +303,18 @@ func TestPluralize(t *testing.T) {
 		{"mouse", "mice"},
 		{"product", "products"},
 		{"box", "boxes"},
+		{"Person", "People"},
+		{"Child", "Children"},
+		{"Status", "Statuses"},
+		{"Company", "Companies"},
+		{"day", "days"},
+		{"analysis", "analyses"},
+		{"photo", "photos"},
+		{"hero", "heroes"},
+		{"news", "news"},
+		{"order_item", "order_items"},
+		{"sales_person", "sales_people"},
+		{"SalesPerson", "SalesPeople"},
 	}
 
 	for _, tt := range tests {
@@ -230,6 +327,49 @@ func TestPluralize(t *testing.T) {
 	}
 }
 
+func TestSingularize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"users", "user"},
+		{"categories", "category"},
+		{"people", "person"},
+		{"statuses", "status"},
+		{"addresses", "address"},
+		{"houses", "house"},
+		{"boxes", "box"},
+		{"analyses", "analysis"},
+		{"news", "news"},
+		{"order_items", "order_item"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := Singularize(tt.input)
+			if got != tt.want {
+				t.Errorf("Singularize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterIrregular(t *testing.T) {
+	RegisterIrregular("cactus", "cacti")
+	defer func() {
+		inflectMu.Lock()
+		delete(irregulars, "cactus")
+		inflectMu.Unlock()
+	}()
+
+	if got := Pluralize("Cactus"); got != "Cacti" {
+		t.Errorf("Pluralize(%q) = %q, want %q", "Cactus", got, "Cacti")
+	}
+	if got := Singularize("cacti"); got != "cactus" {
+		t.Errorf("Singularize(%q) = %q, want %q", "cacti", got, "cactus")
+	}
+}
+
 func TestToPascalCase(t *testing.T) {
 	tests := []struct {
 		input string
@@ -311,3 +451,126 @@ func BenchmarkGenerate(b *testing.B) {
 		_ = os.Remove(outputPath)
 	}
 }
+
+func TestGenerateRelationManager(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	g, _ := New(&Options{OutputDir: tmpDir})
+	if err := GenerateResource(g, "Post", tmpDir); err != nil {
+		t.Fatalf("GenerateResource() failed: %v", err)
+	}
+
+	relations := map[string]string{
+		"author":   "belongs_to",
+		"cover":    "has_one",
+		"comments": "has_many",
+		"tags":     "many_to_many",
+	}
+	for name, relType := range relations {
+		if err := GenerateRelationManager(g, "Post", name, relType); err != nil {
+			t.Fatalf("GenerateRelationManager(%s) failed: %v", relType, err)
+		}
+		path := filepath.Join(tmpDir, "internal", "resources", "post", name+"_relation.go")
+		if _, err := parser.ParseFile(token.NewFileSet(), path, nil, 0); err != nil {
+			t.Errorf("%s relation manager does not parse: %v", relType, err)
+		}
+	}
+
+	tags, _ := os.ReadFile(filepath.Join(tmpDir, "internal", "resources", "post", "tags_relation.go"))
+	if !strings.Contains(string(tags), "AddTagIDs(rid)") {
+		t.Error("expected the many-to-many manager to attach through the Ent edge")
+	}
+
+	resource, _ := os.ReadFile(filepath.Join(tmpDir, "internal", "resources", "post", "resource.go"))
+	for _, constructor := range []string{"NewAuthorRelationManager(r.db)", "NewCommentsRelationManager(r.db)", "NewTagsRelationManager(r.db)"} {
+		if strings.Count(string(resource), constructor) != 1 {
+			t.Errorf("expected %s once in GetRelationManagers", constructor)
+		}
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "resource.go", resource, 0); err != nil {
+		t.Errorf("resource.go does not parse: %v", err)
+	}
+
+	if err := GenerateRelationManager(g, "Post", "likes", "has_some"); err == nil {
+		t.Error("expected an error for an unknown relation type")
+	}
+}
+
+func TestGenerateResourceFromSchema(t *testing.T) {
+	tmpDir := t.TempDir()
+	schemaPath := filepath.Join(tmpDir, "user.go")
+	schema := `package schema
+
+type User struct{ ent.Schema }
+
+func (User) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("name"),
+		field.String("email").Unique(),
+		field.String("password").Sensitive(),
+		field.Bool("active").Default(true),
+		field.Time("last_login_at").Optional(),
+		field.Enum("role").Values("admin", "editor").Default("editor"),
+	}
+}
+
+func (User) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("posts", Post.Type),
+		edge.From("team", Team.Type).Ref("members").Unique(),
+	}
+}
+`
+	if err := os.WriteFile(schemaPath, []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	schemas, err := ParseEntSchema(schemaPath)
+	if err != nil || len(schemas) != 1 {
+		t.Fatalf("ParseEntSchema() = %v, %v", schemas, err)
+	}
+	if got := schemas[0].Fields[5].Values; strings.Join(got, ",") != "admin,editor" {
+		t.Errorf("enum values = %v", got)
+	}
+	if got := schemas[0].Edges[1].RelationType(); got != "belongs_to" {
+		t.Errorf("team relation = %q, want belongs_to", got)
+	}
+
+	g, _ := New(&Options{})
+	if err := GenerateResourceFromSchema(g, schemaPath, tmpDir); err != nil {
+		t.Fatalf("GenerateResourceFromSchema() failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "internal", "resources", "user", "resource.go"))
+	if err != nil {
+		t.Fatalf("resource.go not generated: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "resource.go", content, 0); err != nil {
+		t.Errorf("resource.go does not parse: %v", err)
+	}
+	src := string(content)
+	for _, want := range []string{
+		`form.Text("name").Label("Name").Required()`,
+		`form.Email("email")`,
+		`form.Password("password")`,
+		`form.Toggle("active").Label("Active").Default(true)`,
+		`form.DateTime("last_login_at")`,
+		`form.Select("role").Label("Role").Options(map[string]string{"admin": "Admin", "editor": "Editor"})`,
+		`{Key: "LastLoginAt", Label: "Last Login At", Type: "date", Sortable: true}`,
+		`SetRole(user.Role(req.FormValue("role")))`,
+		"NewPostsRelationManager(r.db)",
+		"NewTeamRelationManager(r.db)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("expected %s in the generated resource", want)
+		}
+	}
+	if strings.Contains(src, `Key: "Password"`) {
+		t.Error("expected the password to be excluded from the table")
+	}
+	for _, name := range []string{"posts", "team"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, "internal", "resources", "user", name+"_relation.go")); err != nil {
+			t.Errorf("%s relation manager not generated: %v", name, err)
+		}
+	}
+}
@@ -1,9 +1,13 @@
 package generator
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -93,7 +97,16 @@ func NewPageDataWithOptions(name, group, icon string, sort int) *PageData {
 
 // GenerateResource generates all files for a resource.
 func GenerateResource(g *Generator, name, outputDir string) error {
+	return GenerateResourceWithSlug(g, name, "", outputDir)
+}
+
+// GenerateResourceWithSlug generates all files for a resource served at slug
+// instead of its pluralized name. An empty slug keeps the pluralized name.
+func GenerateResourceWithSlug(g *Generator, name, slug, outputDir string) error {
 	data := NewResourceData(name)
+	if slug != "" {
+		data.Slug = slug
+	}
 
 	resourceDir := filepath.Join(outputDir, "internal", "resources", data.PackageName)
 
@@ -112,11 +125,13 @@ func GenerateResource(g *Generator, name, outputDir string) error {
 		Failed    int
 	}{}
 
-	for templateName, outputPath := range files {
+	for _, templateName := range slices.Sorted(maps.Keys(files)) {
+		outputPath := files[templateName]
 		if g.shouldSkip(templateName) {
 			if g.options.Verbose {
 				fmt.Printf("Skipped: %s\n", filepath.Base(outputPath))
 			}
+			g.skip(outputPath)
 			stats.Skipped++
 			continue
 		}
@@ -147,15 +162,27 @@ func GenerateResource(g *Generator, name, outputDir string) error {
 }
 
 // GenerateMigration generates a migration file.
+// The Ent schemas under outputDir/internal/ent/schema are diffed against the
+// snapshot saved by the previous migration (migrations/schema_state.json):
+// new tables produce CREATE TABLE, new fields produce ALTER TABLE ADD COLUMN.
+// Without schemas or changes, an empty migration stub is written instead.
 func GenerateMigration(name, outputDir string) error {
 	timestamp := fmt.Sprintf("%d", timeNow().Unix())
 	filename := fmt.Sprintf("%s_%s.sql", timestamp, ToSnakeCase(name))
-	outputPath := filepath.Join(outputDir, "migrations", filename)
+	migrationsDir := filepath.Join(outputDir, "migrations")
+	outputPath := filepath.Join(migrationsDir, filename)
 
-	content := fmt.Sprintf(`-- Migration: %s
--- Created at: %s
+	stmts, snapshot, err := schemaMigrationSQL(outputDir)
+	if err != nil {
+		return err
+	}
 
--- TODO: Add SQL commands here
+	header := fmt.Sprintf("-- Migration: %s\n-- Created at: %s\n\n", name, timeNow().Format("2006-01-02 15:04:05"))
+	var content string
+	if len(stmts) > 0 {
+		content = header + strings.Join(stmts, "\n\n") + "\n"
+	} else {
+		content = header + fmt.Sprintf(`-- TODO: Add SQL commands here
 
 -- Example:
 -- CREATE TABLE IF NOT EXISTS %s (
@@ -163,13 +190,24 @@ func GenerateMigration(name, outputDir string) error {
 --     name TEXT NOT NULL,
 --     created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 -- );
-`, name, timeNow().Format("2006-01-02 15:04:05"), Pluralize(ToSnakeCase(name)))
+`, Pluralize(ToSnakeCase(name)))
+	}
 
-	if err := ensureDir(filepath.Dir(outputPath)); err != nil {
+	if err := ensureDir(migrationsDir); err != nil {
+		return err
+	}
+	if err := writeFile(outputPath, []byte(content)); err != nil {
 		return err
 	}
 
-	return writeFile(outputPath, []byte(content))
+	if len(stmts) > 0 {
+		state, err := json.MarshalIndent(snapshot, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeFile(filepath.Join(migrationsDir, migrationStateFile), state)
+	}
+	return nil
 }
 
 // GeneratePage generates all files for a custom page.
@@ -194,11 +232,13 @@ func GeneratePageWithOptions(g *Generator, name, outputDir, group, icon string,
 		Failed    int
 	}{}
 
-	for templateName, outputPath := range files {
+	for _, templateName := range slices.Sorted(maps.Keys(files)) {
+		outputPath := files[templateName]
 		if g.shouldSkip(templateName) {
 			if g.options.Verbose {
 				fmt.Printf("Skipped: %s\n", filepath.Base(outputPath))
 			}
+			g.skip(outputPath)
 			stats.Skipped++
 			continue
 		}
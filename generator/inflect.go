@@ -0,0 +1,190 @@
+package generator
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// irregulars maps singular words to their plural when no rule applies.
+var irregulars = map[string]string{
+	"person":    "people",
+	"child":     "children",
+	"mouse":     "mice",
+	"tooth":     "teeth",
+	"foot":      "feet",
+	"goose":     "geese",
+	"man":       "men",
+	"woman":     "women",
+	"ox":        "oxen",
+	"datum":     "data",
+	"medium":    "media",
+	"criterion": "criteria",
+	"leaf":      "leaves",
+	"life":      "lives",
+	"knife":     "knives",
+	"wife":      "wives",
+	"half":      "halves",
+	"shelf":     "shelves",
+	"wolf":      "wolves",
+	"thief":     "thieves",
+	"hero":      "heroes",
+	"potato":    "potatoes",
+	"tomato":    "tomatoes",
+	"echo":      "echoes",
+	"veto":      "vetoes",
+	"quiz":      "quizzes",
+}
+
+// uncountables are the words whose plural is the word itself.
+var uncountables = map[string]bool{
+	"equipment":   true,
+	"information": true,
+	"money":       true,
+	"news":        true,
+	"series":      true,
+	"species":     true,
+	"sheep":       true,
+	"fish":        true,
+	"deer":        true,
+	"metadata":    true,
+	"feedback":    true,
+}
+
+// inflectMu guards irregulars and uncountables.
+var inflectMu sync.RWMutex
+
+// RegisterIrregular adds an irregular plural used by Pluralize and
+// Singularize, and so by the generated slugs and the scanner.
+//
+//	generator.RegisterIrregular("cactus", "cacti")
+func RegisterIrregular(singular, plural string) {
+	inflectMu.Lock()
+	defer inflectMu.Unlock()
+	irregulars[strings.ToLower(singular)] = strings.ToLower(plural)
+}
+
+// RegisterUncountable adds words whose plural is the word itself.
+func RegisterUncountable(words ...string) {
+	inflectMu.Lock()
+	defer inflectMu.Unlock()
+	for _, w := range words {
+		uncountables[strings.ToLower(w)] = true
+	}
+}
+
+// Pluralize converts a word to its plural form. In compound names
+// ("order_item", "SalesPerson") only the last word is pluralized.
+func Pluralize(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	head, word := splitLastWord(s)
+	return head + matchCase(word, pluralizeWord(strings.ToLower(word)))
+}
+
+// Singularize converts a word to its singular form. In compound names only
+// the last word is singularized.
+func Singularize(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	head, word := splitLastWord(s)
+	return head + matchCase(word, singularizeWord(strings.ToLower(word)))
+}
+
+// pluralizeWord pluralizes a lowercase word.
+func pluralizeWord(w string) string {
+	inflectMu.RLock()
+	plural, irregular := irregulars[w]
+	uncountable := uncountables[w]
+	inflectMu.RUnlock()
+	switch {
+	case irregular:
+		return plural
+	case uncountable:
+		return w
+	case strings.HasSuffix(w, "y") && len(w) > 1 && !isVowel(rune(w[len(w)-2])):
+		return w[:len(w)-1] + "ies"
+	case strings.HasSuffix(w, "is"):
+		// analysis → analyses
+		return w[:len(w)-2] + "es"
+	case strings.HasSuffix(w, "s") || strings.HasSuffix(w, "x") ||
+		strings.HasSuffix(w, "z") || strings.HasSuffix(w, "ch") ||
+		strings.HasSuffix(w, "sh"):
+		// status → statuses, box → boxes
+		return w + "es"
+	default:
+		// Words ending in -o take -s (photos, videos) unless irregular.
+		return w + "s"
+	}
+}
+
+// singularizeWord singularizes a lowercase word.
+func singularizeWord(w string) string {
+	inflectMu.RLock()
+	defer inflectMu.RUnlock()
+	for singular, plural := range irregulars {
+		if plural == w {
+			return singular
+		}
+	}
+	switch {
+	case uncountables[w]:
+		return w
+	case strings.HasSuffix(w, "ies") && len(w) > 3:
+		return w[:len(w)-3] + "y"
+	case strings.HasSuffix(w, "ouses"):
+		// houses → house
+		return w[:len(w)-1]
+	case strings.HasSuffix(w, "sses") || strings.HasSuffix(w, "uses"):
+		// addresses → address, statuses → status
+		return w[:len(w)-2]
+	case strings.HasSuffix(w, "yses"):
+		// analyses → analysis
+		return w[:len(w)-2] + "is"
+	case strings.HasSuffix(w, "xes") || strings.HasSuffix(w, "zes") ||
+		strings.HasSuffix(w, "ches") || strings.HasSuffix(w, "shes"):
+		return w[:len(w)-2]
+	case strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss"):
+		return w[:len(w)-1]
+	default:
+		return w
+	}
+}
+
+// splitLastWord splits s before its last word, which starts after a '_', '-'
+// or ' ' separator or at the last word boundary of a camel-case name.
+func splitLastWord(s string) (head, word string) {
+	runes := []rune(s)
+	for i := len(runes) - 1; i > 0; i-- {
+		r := runes[i]
+		if r == '_' || r == '-' || r == ' ' {
+			return string(runes[:i+1]), string(runes[i+1:])
+		}
+		if unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			return string(runes[:i]), string(runes[i:])
+		}
+	}
+	return "", s
+}
+
+// matchCase gives inflected the case of word: all caps, capitalized or as is.
+func matchCase(word, inflected string) string {
+	switch {
+	case len(word) > 1 && strings.ToUpper(word) == word:
+		return strings.ToUpper(inflected)
+	case word != "" && unicode.IsUpper([]rune(word)[0]):
+		return strings.ToUpper(inflected[:1]) + inflected[1:]
+	default:
+		return inflected
+	}
+}
+
+// isVowel checks if a character is a vowel.
+func isVowel(r rune) bool {
+	vowels := "aeiouAEIOU"
+	return strings.ContainsRune(vowels, r)
+}
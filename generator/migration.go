@@ -0,0 +1,287 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migrationStateFile stores the schema snapshot used to diff the next migration.
+const migrationStateFile = "schema_state.json"
+
+// SchemaColumn describes a column extracted from an Ent schema field.
+type SchemaColumn struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"` // SQL type
+	Optional bool   `json:"optional"`
+	Unique   bool   `json:"unique"`
+	Default  string `json:"default,omitempty"` // SQL literal
+}
+
+// SchemaTable describes a table extracted from an Ent schema.
+type SchemaTable struct {
+	Name    string         `json:"name"`
+	Columns []SchemaColumn `json:"columns"`
+}
+
+// entFieldTypes maps Ent field constructors to SQL column types.
+var entFieldTypes = map[string]string{
+	"String": "TEXT", "Text": "TEXT", "Enum": "TEXT", "UUID": "TEXT",
+	"Int": "INTEGER", "Int8": "INTEGER", "Int16": "INTEGER", "Int32": "INTEGER", "Int64": "INTEGER",
+	"Uint": "INTEGER", "Uint8": "INTEGER", "Uint16": "INTEGER", "Uint32": "INTEGER", "Uint64": "INTEGER",
+	"Float": "REAL", "Float32": "REAL",
+	"Bool":  "BOOLEAN",
+	"Time":  "DATETIME",
+	"Bytes": "BLOB",
+	"JSON":  "JSON",
+}
+
+// ParseEntSchemas parses every Ent schema in dir and returns one table per
+// schema type declaring a Fields() method.
+func ParseEntSchemas(dir string) ([]SchemaTable, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema dir: %w", err)
+	}
+
+	var tables []SchemaTable
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Name.Name != "Fields" || fn.Recv == nil || len(fn.Recv.List) != 1 || fn.Body == nil {
+					continue
+				}
+				recv, ok := fn.Recv.List[0].Type.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				tables = append(tables, SchemaTable{
+					Name:    Pluralize(ToSnakeCase(recv.Name)),
+					Columns: parseFieldsBody(fn.Body),
+				})
+			}
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Name < tables[j].Name })
+	return tables, nil
+}
+
+// parseFieldsBody extracts columns from the []ent.Field literal returned by Fields().
+func parseFieldsBody(body *ast.BlockStmt) []SchemaColumn {
+	var columns []SchemaColumn
+	for _, stmt := range body.List {
+		ret, ok := stmt.(*ast.ReturnStmt)
+		if !ok || len(ret.Results) != 1 {
+			continue
+		}
+		lit, ok := ret.Results[0].(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		for _, elt := range lit.Elts {
+			if col, ok := parseFieldChain(elt); ok {
+				columns = append(columns, col)
+			}
+		}
+	}
+	return columns
+}
+
+// parseFieldChain walks a field.X("name").Modifier()... call chain.
+func parseFieldChain(expr ast.Expr) (SchemaColumn, bool) {
+	var col SchemaColumn
+	for {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return col, false
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return col, false
+		}
+
+		if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "field" {
+			sqlType, known := entFieldTypes[sel.Sel.Name]
+			if !known || len(call.Args) == 0 {
+				return col, false
+			}
+			name, ok := stringLit(call.Args[0])
+			if !ok {
+				return col, false
+			}
+			col.Name = name
+			col.Type = sqlType
+			return col, true
+		}
+
+		switch sel.Sel.Name {
+		case "Optional", "Nillable":
+			col.Optional = true
+		case "Unique":
+			col.Unique = true
+		case "Default":
+			if len(call.Args) == 1 {
+				col.Default = sqlDefault(call.Args[0])
+			}
+		}
+		expr = sel.X
+	}
+}
+
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+// sqlDefault converts a Default(...) argument to a SQL literal.
+// Unsupported expressions (function calls, variables) yield no default.
+func sqlDefault(expr ast.Expr) string {
+	switch v := expr.(type) {
+	case *ast.BasicLit:
+		if v.Kind == token.STRING {
+			s, _ := strconv.Unquote(v.Value)
+			return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+		}
+		return v.Value
+	case *ast.Ident:
+		switch v.Name {
+		case "true":
+			return "1"
+		case "false":
+			return "0"
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := v.X.(*ast.Ident); ok && pkg.Name == "time" && v.Sel.Name == "Now" {
+			return "CURRENT_TIMESTAMP"
+		}
+	case *ast.UnaryExpr:
+		if lit, ok := v.X.(*ast.BasicLit); ok && v.Op == token.SUB {
+			return "-" + lit.Value
+		}
+	}
+	return ""
+}
+
+// columnDef renders a column definition. NOT NULL is only emitted when the
+// column is required and either new with the table or backed by a default,
+// since SQLite rejects ADD COLUMN ... NOT NULL without a default.
+func columnDef(c SchemaColumn, inCreate bool) string {
+	def := c.Name + " " + c.Type
+	if !c.Optional && (inCreate || c.Default != "") {
+		def += " NOT NULL"
+	}
+	if c.Unique && inCreate {
+		def += " UNIQUE"
+	}
+	if c.Default != "" {
+		def += " DEFAULT " + c.Default
+	}
+	return def
+}
+
+// DiffSchemas returns the SQL statements needed to go from previous to
+// current: CREATE TABLE for new tables and ALTER TABLE ADD COLUMN for new
+// columns. Removed tables and columns are reported as comments only.
+func DiffSchemas(previous, current []SchemaTable) []string {
+	prev := make(map[string]SchemaTable, len(previous))
+	for _, t := range previous {
+		prev[t.Name] = t
+	}
+
+	var stmts []string
+	for _, t := range current {
+		old, exists := prev[t.Name]
+		if !exists {
+			lines := []string{"    id INTEGER PRIMARY KEY AUTOINCREMENT"}
+			for _, c := range t.Columns {
+				lines = append(lines, "    "+columnDef(c, true))
+			}
+			stmts = append(stmts, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n%s\n);", t.Name, strings.Join(lines, ",\n")))
+			continue
+		}
+		delete(prev, t.Name)
+
+		oldCols := make(map[string]bool, len(old.Columns))
+		for _, c := range old.Columns {
+			oldCols[c.Name] = true
+		}
+		for _, c := range t.Columns {
+			if oldCols[c.Name] {
+				delete(oldCols, c.Name)
+				continue
+			}
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", t.Name, columnDef(c, false)))
+			if c.Unique {
+				stmts = append(stmts, fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s_%s_key ON %s (%s);", t.Name, c.Name, t.Name, c.Name))
+			}
+		}
+		removed := make([]string, 0, len(oldCols))
+		for name := range oldCols {
+			removed = append(removed, name)
+		}
+		sort.Strings(removed)
+		for _, name := range removed {
+			stmts = append(stmts, fmt.Sprintf("-- Column %s.%s was removed from the schema (not dropped automatically).", t.Name, name))
+		}
+	}
+
+	removed := make([]string, 0, len(prev))
+	for name := range prev {
+		removed = append(removed, name)
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		stmts = append(stmts, fmt.Sprintf("-- Table %s was removed from the schema (not dropped automatically).", name))
+	}
+	return stmts
+}
+
+// loadMigrationState reads the snapshot saved by the previous migration.
+func loadMigrationState(path string) ([]SchemaTable, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var tables []SchemaTable
+	if err := json.Unmarshal(data, &tables); err != nil {
+		return nil, fmt.Errorf("invalid migration state %s: %w", path, err)
+	}
+	return tables, nil
+}
+
+// schemaMigrationSQL diffs the Ent schemas under outputDir against the saved
+// snapshot. It returns the statements and the new snapshot, or nil
+// statements if there is no schema directory or nothing changed.
+func schemaMigrationSQL(outputDir string) ([]string, []SchemaTable, error) {
+	schemaDir := filepath.Join(outputDir, "internal", "ent", "schema")
+	if _, err := os.Stat(schemaDir); err != nil {
+		return nil, nil, nil
+	}
+	current, err := ParseEntSchemas(schemaDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	previous, err := loadMigrationState(filepath.Join(outputDir, "migrations", migrationStateFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	return DiffSchemas(previous, current), current, nil
+}
@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const productSchemaV1 = `package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+)
+
+type Product struct {
+	ent.Schema
+}
+
+func (Product) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("name").NotEmpty(),
+		field.String("sku").Unique(),
+		field.Float("price").Default(0),
+		field.Bool("active").Default(true),
+		field.Text("notes").Optional(),
+		field.Time("created_at").Default(time.Now).Immutable(),
+	}
+}
+`
+
+func writeSchema(t *testing.T, dir, content string) {
+	t.Helper()
+	schemaDir := filepath.Join(dir, "internal", "ent", "schema")
+	if err := os.MkdirAll(schemaDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(schemaDir, "product.go"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readMigration(t *testing.T, dir, suffix string) string {
+	t.Helper()
+	matches, _ := filepath.Glob(filepath.Join(dir, "migrations", "*_"+suffix+".sql"))
+	if len(matches) != 1 {
+		t.Fatalf("Expected one %s migration, got %v", suffix, matches)
+	}
+	data, err := os.ReadFile(matches[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestParseEntSchemas(t *testing.T) {
+	dir := t.TempDir()
+	writeSchema(t, dir, productSchemaV1)
+
+	tables, err := ParseEntSchemas(filepath.Join(dir, "internal", "ent", "schema"))
+	if err != nil {
+		t.Fatalf("ParseEntSchemas() failed: %v", err)
+	}
+	if len(tables) != 1 || tables[0].Name != "products" {
+		t.Fatalf("Expected table 'products', got %+v", tables)
+	}
+	cols := tables[0].Columns
+	if len(cols) != 6 {
+		t.Fatalf("Expected 6 columns, got %d", len(cols))
+	}
+	if !cols[1].Unique || cols[4].Type != "TEXT" || !cols[4].Optional {
+		t.Errorf("Unexpected column modifiers: %+v", cols)
+	}
+	if cols[3].Default != "1" || cols[5].Default != "CURRENT_TIMESTAMP" {
+		t.Errorf("Unexpected defaults: %q, %q", cols[3].Default, cols[5].Default)
+	}
+}
+
+func TestGenerateMigrationFromSchema(t *testing.T) {
+	dir := t.TempDir()
+	writeSchema(t, dir, productSchemaV1)
+
+	if err := GenerateMigration("create_products", dir); err != nil {
+		t.Fatalf("GenerateMigration() failed: %v", err)
+	}
+	sql := readMigration(t, dir, "create_products")
+	for _, want := range []string{
+		"CREATE TABLE IF NOT EXISTS products (",
+		"id INTEGER PRIMARY KEY AUTOINCREMENT",
+		"name TEXT NOT NULL",
+		"sku TEXT NOT NULL UNIQUE",
+		"active BOOLEAN NOT NULL DEFAULT 1",
+		"notes TEXT,",
+		"created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("Expected migration to contain %q, got:\n%s", want, sql)
+		}
+	}
+
+	// Second snapshot: one field added.
+	writeSchema(t, dir, strings.Replace(productSchemaV1,
+		`field.Text("notes").Optional(),`,
+		`field.Text("notes").Optional(),
+		field.Int("stock").Default(0),`, 1))
+
+	if err := GenerateMigration("add_stock", dir); err != nil {
+		t.Fatalf("GenerateMigration() failed: %v", err)
+	}
+	sql = readMigration(t, dir, "add_stock")
+	if !strings.Contains(sql, "ALTER TABLE products ADD COLUMN stock INTEGER NOT NULL DEFAULT 0;") {
+		t.Errorf("Expected ADD COLUMN for stock, got:\n%s", sql)
+	}
+	if strings.Contains(sql, "CREATE TABLE") {
+		t.Errorf("Expected no CREATE TABLE in diff migration, got:\n%s", sql)
+	}
+}
+
+func TestGenerateMigrationWithoutSchema(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := GenerateMigration("custom_change", dir); err != nil {
+		t.Fatalf("GenerateMigration() failed: %v", err)
+	}
+	if sql := readMigration(t, dir, "custom_change"); !strings.Contains(sql, "-- TODO: Add SQL commands here") {
+		t.Errorf("Expected stub migration, got:\n%s", sql)
+	}
+}
@@ -0,0 +1,173 @@
+package generator
+
+import (
+	_ "embed"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+//go:embed stubs/relation_manager.go.tmpl
+var relationManagerTemplate string
+
+// RelationManagerData contains the data of a relation manager.
+type RelationManagerData struct {
+	PackageName        string // post
+	ResourceTypeName   string // PostResource
+	EntTypeName        string // Post
+	EntPackage         string // post
+	TypeName           string // CommentsRelationManager
+	Name               string // comments
+	Label              string // Comments
+	EdgeName           string // Comments
+	EdgeSingular       string // Comment
+	RelatedEntTypeName string // Comment
+	RelationType       string // has_many
+	RelationConst      string // RelationHasMany
+	Unique             bool   // true for belongs_to and has_one edges
+	CanAttach          bool
+	CanCreate          bool
+	CanDelete          bool
+}
+
+// relationConsts maps the relation types to their engine constant.
+var relationConsts = map[string]string{
+	"belongs_to":   "RelationBelongsTo",
+	"has_one":      "RelationHasOne",
+	"has_many":     "RelationHasMany",
+	"many_to_many": "RelationManyToMany",
+}
+
+// NewRelationManagerData creates the data for the relationName relation
+// manager of a resource. relationType is one of belongs_to, has_one,
+// has_many and many_to_many.
+func NewRelationManagerData(resourceName, relationName, relationType string) (*RelationManagerData, error) {
+	relationConst, ok := relationConsts[relationType]
+	if !ok {
+		return nil, fmt.Errorf("unknown relation type %q (expected belongs_to, has_one, has_many or many_to_many)", relationType)
+	}
+	resource := NewResourceData(resourceName)
+	name := ToSnakeCase(relationName)
+	edge := ToPascalCase(name)
+	unique := relationType == "belongs_to" || relationType == "has_one"
+
+	related := Singularize(edge)
+	if unique {
+		related = edge
+	}
+
+	return &RelationManagerData{
+		PackageName:        resource.PackageName,
+		ResourceTypeName:   resource.TypeName,
+		EntTypeName:        resource.EntTypeName,
+		EntPackage:         strings.ToLower(resource.EntTypeName),
+		TypeName:           edge + "RelationManager",
+		Name:               name,
+		Label:              cases.Title(language.English).String(strings.ReplaceAll(name, "_", " ")),
+		EdgeName:           edge,
+		EdgeSingular:       Singularize(edge),
+		RelatedEntTypeName: related,
+		RelationType:       relationType,
+		RelationConst:      relationConst,
+		Unique:             unique,
+		CanAttach:          relationType != "has_many",
+		CanCreate:          relationType == "has_many" || relationType == "has_one",
+		CanDelete:          relationType == "has_many" || relationType == "has_one",
+	}, nil
+}
+
+// GenerateRelationManager generates the {relation}_relation.go relation
+// manager of a resource generated by GenerateResource, and adds it to the
+// resource's GetRelationManagers.
+func GenerateRelationManager(g *Generator, resourceName, relationName, relationType string) error {
+	return generateRelationManager(g, g.options.OutputDir, resourceName, relationName, relationType)
+}
+
+// generateRelationManager generates a relation manager of a resource
+// generated under outputDir.
+func generateRelationManager(g *Generator, outputDir, resourceName, relationName, relationType string) error {
+	data, err := NewRelationManagerData(resourceName, relationName, relationType)
+	if err != nil {
+		return err
+	}
+	if _, ok := g.templates["relation_manager"]; !ok {
+		tmpl, err := template.New("relation_manager").Parse(relationManagerTemplate)
+		if err != nil {
+			return fmt.Errorf("failed to parse template relation_manager: %w", err)
+		}
+		g.templates["relation_manager"] = tmpl
+	}
+
+	resourceDir := filepath.Join(outputDir, "internal", "resources", data.PackageName)
+	resourcePath := filepath.Join(resourceDir, "resource.go")
+	if !fileExists(resourcePath) && !g.options.DryRun {
+		return fmt.Errorf("resource not found: %s", resourcePath)
+	}
+
+	outputPath := filepath.Join(resourceDir, data.Name+"_relation.go")
+	if err := g.Generate("relation_manager", outputPath, data); err != nil {
+		return fmt.Errorf("failed to generate relation manager: %w", err)
+	}
+
+	if g.options.DryRun {
+		return nil
+	}
+	if err := wireRelationManager(resourcePath, data); err != nil {
+		return fmt.Errorf("failed to register relation manager: %w", err)
+	}
+
+	if g.options.Verbose {
+		fmt.Printf("Registered: New%s in %s.GetRelationManagers\n", data.TypeName, data.ResourceTypeName)
+	}
+	return nil
+}
+
+// wireRelationManager adds the relation manager to the GetRelationManagers
+// method of the resource file, creating the method when missing.
+func wireRelationManager(resourcePath string, data *RelationManagerData) error {
+	content, err := os.ReadFile(resourcePath)
+	if err != nil {
+		return err
+	}
+	src := string(content)
+	constructor := "New" + data.TypeName + "("
+	if strings.Contains(src, constructor) {
+		return nil
+	}
+
+	method := regexp.MustCompile(`func \((\w+) \*?` + data.ResourceTypeName + `\) GetRelationManagers\(\) \[\]engine\.RelationManager \{`)
+	loc := method.FindStringSubmatchIndex(src)
+	if loc == nil {
+		src += fmt.Sprintf(`
+// GetRelationManagers returns the relation managers shown on the edit page
+func (r *%s) GetRelationManagers() []engine.RelationManager {
+	return []engine.RelationManager{
+		%sr.db),
+	}
+}
+`, data.ResourceTypeName, constructor)
+	} else {
+		recv := src[loc[2]:loc[3]]
+		body := src[loc[1]:]
+		literal := "return []engine.RelationManager{"
+		at := strings.Index(body, literal)
+		if end := strings.Index(body, "\n}"); at < 0 || (end >= 0 && end < at) {
+			return fmt.Errorf("%s.GetRelationManagers does not return a slice literal, add New%s(db) to it", data.ResourceTypeName, data.TypeName)
+		}
+		at += loc[1] + len(literal)
+		src = src[:at] + fmt.Sprintf("\n\t\t%s%s.db),", constructor, recv) + src[at:]
+	}
+
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(resourcePath, formatted, 0644)
+}
@@ -0,0 +1,182 @@
+package importer
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// autoMapThreshold is the confidence below which AutoMap leaves a column
+// unmapped.
+const autoMapThreshold = 0.75
+
+// ColumnMatch is a column mapping guessed by AutoMap.
+type ColumnMatch struct {
+	ColumnMapping
+	// Confidence ranges from 1 for an exact, case-insensitive match down to
+	// autoMapThreshold. It is 0 for unmapped columns.
+	Confidence float64
+}
+
+// Mapped reports whether AutoMap found a field for the column.
+func (m ColumnMatch) Mapped() bool {
+	return m.TargetField != ""
+}
+
+// AutoMap guesses the mappings of header columns to the exported fields of
+// target, a struct or a pointer to one. Columns are compared to the field
+// name, its json tag and the aliases of its import tag, ignoring case,
+// accents and punctuation ("E-Mail" matches Email), then by edit distance:
+//
+//	type Customer struct {
+//		FullName string `import:"nom complet,nom"`
+//		Email    string
+//	}
+//
+// One match is returned per header column, in order. Columns without a close
+// enough field, or equally close to several fields, are left unmapped for
+// the caller to confirm, as is a column losing a field to a better match.
+func AutoMap(header []string, target any) []ColumnMatch {
+	fields := autoMapFields(target)
+
+	type candidate struct {
+		col   int
+		field string
+		score float64
+	}
+	var candidates []candidate
+	for col, name := range header {
+		best, second := candidate{col: col}, 0.0
+		for _, f := range fields {
+			score := 0.0
+			for _, alias := range f.aliases {
+				score = max(score, columnSimilarity(name, alias))
+			}
+			switch {
+			case score > best.score:
+				second = best.score
+				best = candidate{col, f.name, score}
+			case score > second:
+				second = score
+			}
+		}
+		ambiguous := best.score < 1 && best.score-second < 0.05
+		if best.score >= autoMapThreshold && !ambiguous {
+			candidates = append(candidates, best)
+		}
+	}
+
+	// Best matches claim their field first; a tie for one field maps neither.
+	sort.SliceStable(candidates, func(a, b int) bool { return candidates[a].score > candidates[b].score })
+	claimed := make(map[string]float64)
+	matches := make([]ColumnMatch, len(header))
+	for col, name := range header {
+		matches[col].SourceColumn = name
+	}
+	for _, c := range candidates {
+		if score, ok := claimed[c.field]; ok {
+			if score == c.score {
+				for col := range matches {
+					if matches[col].TargetField == c.field {
+						matches[col].TargetField, matches[col].Confidence = "", 0
+					}
+				}
+			}
+			continue
+		}
+		claimed[c.field] = c.score
+		matches[c.col].TargetField = c.field
+		matches[c.col].Confidence = c.score
+	}
+	return matches
+}
+
+type autoMapField struct {
+	name    string
+	aliases []string
+}
+
+// autoMapFields lists the exported fields of target with the names a column
+// may use for them.
+func autoMapFields(target any) []autoMapField {
+	typ := reflect.TypeOf(target)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []autoMapField
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		jsonName := strings.SplitN(sf.Tag.Get("json"), ",", 2)[0]
+		if !sf.IsExported() || jsonName == "-" || sf.Tag.Get("import") == "-" {
+			continue
+		}
+		f := autoMapField{name: sf.Name, aliases: []string{sf.Name}}
+		if jsonName != "" {
+			f.aliases = append(f.aliases, jsonName)
+		}
+		for _, alias := range strings.Split(sf.Tag.Get("import"), ",") {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				f.aliases = append(f.aliases, alias)
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// columnSimilarity scores how close a column name is to a field name, from 0
+// to 1.
+func columnSimilarity(column, field string) float64 {
+	if strings.EqualFold(strings.TrimSpace(column), field) {
+		return 1
+	}
+	a, b := foldName(column), foldName(field)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 0.95
+	}
+	ra, rb := []rune(a), []rune(b)
+	return 0.9 * (1 - float64(levenshtein(ra, rb))/float64(max(len(ra), len(rb))))
+}
+
+// foldName lowercases s and drops accents and every character that is not a
+// letter or a digit: "Prénom (usuel)" becomes "prenomusuel".
+func foldName(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -37,4 +37,106 @@
 //	})
 //
 //	fmt.Printf("Imported %d rows, %d errors\n", result.SuccessCount, result.ErrorCount)
+//
+// When the columns of an uploaded file are not known in advance, AutoMap
+// guesses mappings from the header and a struct, with a confidence score per
+// column. Ambiguous columns are left unmapped so the user can confirm them.
+// Mapped values are copied to their TargetField, which MapToStruct reads:
+//
+//	for _, m := range importer.AutoMap(header, User{}) {
+//		if m.Mapped() {
+//			config.Mappings = append(config.Mappings, m.ColumnMapping)
+//		}
+//	}
+//
+// Before any row is imported, the header must contain the SourceColumn of
+// every Required mapping; otherwise the import fails with the list of
+// missing columns (see Importer.ValidateHeader). Files without a header row
+// (SkipHeader false) key rows by column index, "0", "1" and so on.
+//
+// Setting ImportConfig.DryRun validates a whole file without calling the
+// row handler, so every failing row can be reported before importing. A
+// dry run also fails the rows whose mapping Transform returns an error; the
+// ImportError carries the column, the raw value and the transform message.
+// A real import keeps the raw value instead.
+// ValidateStruct checks rows against the `validate` tags of a struct, with
+// one ImportError per invalid field:
+//
+//	config.ValidateRow = importer.ValidateStruct[UserRow]()
+//
+// ImportResult.RowErrors lists each failed row with its line in the file,
+// counting the header and blank lines. WriteErrorCSV writes them back with an
+// extra "error" column so users can fix the rows and upload them again:
+//
+//	w.Header().Set("Content-Type", "text/csv")
+//	_ = result.WriteErrorCSV(w)
+//
+// CSV and JSON files are transcoded to UTF-8 before parsing and a leading
+// byte order mark is stripped. By default the encoding is detected: files
+// that are not valid UTF-8, like Excel's French CSV exports, are read as
+// Windows-1252. ImportConfig.Encoding forces a specific encoding:
+//
+//	config.Encoding = "iso-8859-15"
+//
+// ImportConfig.Delimiter, Comment and LazyQuotes tune CSV parsing, e.g. for
+// semicolon-separated exports:
+//
+//	config.Delimiter = ';'
+//
+// Excel files are read from the first sheet unless ImportConfig.SheetName or
+// SheetIndex selects another one. Cell values are passed as strings, with
+// numbers normalized ("42", not "42.0"), just like CSV values.
+//
+// ImportWorkbook imports several sheets of one workbook, each with its own
+// mappings and row handler:
+//
+//	results, err := imp.ImportWorkbook(ctx, file, map[string]importer.SheetImport{
+//		"Users":  {Handler: importUser},
+//		"Orders": {Mappings: orderMappings, Handler: importOrder},
+//	})
+//
+// Long imports can report progress, throttled to one call per
+// ProgressInterval (200ms by default) plus a final one. ImportFromReader
+// reads an HTTP upload stream directly:
+//
+//	config.OnProgress = func(processed, total int) {
+//		job.SetProgress(processed * 100 / total)
+//	}
+//	result, err := imp.ImportFromReader(ctx, r.Body, handler)
+//
+// All-or-nothing imports set ImportConfig.Tx. The row handler receives the
+// transaction's context, and the transaction is rolled back, with
+// ImportResult.RolledBack set, when more than AbortAfterErrors rows fail:
+//
+//	config.Tx = entImportTx{client} // BeginTx stores the tx with ent.NewTxContext
+//	config.AbortAfterErrors = 10
+//
+// Imports bound by per-row database latency can set ImportConfig.Concurrency
+// to run the row handler on several goroutines. Rows are then handled in no
+// particular order, and counts and errors stay exact. Cancelling the context
+// stops all workers.
+//
+// Re-imports into an existing table can detect records that already exist.
+// ExistsFunc is called once per DedupeKey value, and OnDuplicate decides
+// whether matching rows are skipped, rejected, or passed to the handler as
+// updates (see IsUpdate):
+//
+//	config.DedupeKey = []string{"email"}
+//	config.OnDuplicate = importer.DuplicateUpdate
+//	config.ExistsFunc = func(ctx context.Context, row map[string]any) (bool, error) {
+//		return db.User.Query().Where(user.Email(row["email"].(string))).Exist(ctx)
+//	}
+//
+// For upserts, FindExisting receives the UpsertKey values (composite keys
+// are supported) and returns the ID of the matching record, which the
+// handler reads with ExistingID:
+//
+//	config.UpsertKey = []string{"email", "tenant"}
+//	config.FindExisting = findUser
+//	handler := func(ctx context.Context, row map[string]any) error {
+//		if importer.IsUpdate(ctx) {
+//			return updateUser(ctx, importer.ExistingID(ctx), row)
+//		}
+//		return createUser(ctx, row)
+//	}
 package importer
@@ -0,0 +1,74 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// EncodingAuto detects the encoding of CSV and JSON files: a byte order mark
+// selects UTF-8 or UTF-16, otherwise valid UTF-8 is kept as is and anything
+// else is read as Windows-1252 (Excel's default for French locales).
+const EncodingAuto = "auto"
+
+// decode returns the content of reader transcoded to UTF-8, without BOM,
+// according to ImportConfig.Encoding.
+func (i *Importer) decode(reader io.Reader) (io.Reader, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	enc, data, err := resolveEncoding(i.config.Encoding, data)
+	if err != nil {
+		return nil, err
+	}
+	if enc == nil {
+		return bytes.NewReader(data), nil
+	}
+	decoded, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode input: %w", err)
+	}
+	return bytes.NewReader(decoded), nil
+}
+
+// resolveEncoding returns the encoding of data (nil for UTF-8) and data
+// without its byte order mark.
+func resolveEncoding(name string, data []byte) (encoding.Encoding, []byte, error) {
+	bom := []struct {
+		mark []byte
+		enc  encoding.Encoding
+	}{
+		{[]byte{0xEF, 0xBB, 0xBF}, nil},
+		{[]byte{0xFF, 0xFE}, unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)},
+		{[]byte{0xFE, 0xFF}, unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)},
+	}
+	for _, b := range bom {
+		if bytes.HasPrefix(data, b.mark) {
+			return b.enc, data[len(b.mark):], nil
+		}
+	}
+
+	switch strings.ToLower(name) {
+	case "", EncodingAuto:
+		if utf8.Valid(data) {
+			return nil, data, nil
+		}
+		return charmap.Windows1252, data, nil
+	case "utf-8", "utf8":
+		return nil, data, nil
+	}
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unsupported encoding %q", name)
+	}
+	return enc, data, nil
+}
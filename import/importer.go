@@ -4,12 +4,17 @@ import (
 	"context"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
 	"mime/multipart"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/xuri/excelize/v2"
@@ -32,11 +37,59 @@ type ImportResult struct {
 	SkippedCount int
 	Errors       []ImportError
 	Duration     time.Duration
+	// Timings accumulates the time spent in each stage: "parse", "transform",
+	// "validate", "lookup", "before_import" and "callback".
+	Timings map[string]time.Duration
+	// UpdatedCount is the number of successful rows that updated an existing
+	// record (OnDuplicate = DuplicateUpdate); it is included in SuccessCount.
+	UpdatedCount int
+	// DuplicateCount is the number of rows that matched an existing record,
+	// whether they were skipped, updated or rejected.
+	DuplicateCount int
+	// DryRun is true when the result comes from a validate-only run.
+	DryRun bool
+	// RolledBack is true when the import transaction was rolled back: none
+	// of the SuccessCount rows were persisted. See ImportConfig.Tx.
+	RolledBack bool
+	// RowErrors lists the failed rows with their source line, see WriteErrorCSV.
+	RowErrors []RowError
+
+	columns   []string // source header, in file order
+	delimiter rune     // CSV delimiter of the source file
 }
 
+// RowError describes a row that failed to import.
+type RowError struct {
+	Line int            // line in the file, counting the header and blank lines
+	Row  map[string]any // the row as read from the file, before transforms
+	Err  error
+}
+
+// errDuplicate rejects existing rows when OnDuplicate is DuplicateError.
+var errDuplicate = errors.New("record already exists")
+
+// DuplicateStrategy decides what happens to rows that already exist.
+type DuplicateStrategy string
+
+const (
+	DuplicateSkip   DuplicateStrategy = "skip"   // count the row as skipped (default)
+	DuplicateUpdate DuplicateStrategy = "update" // pass the row to the handler as an update
+	DuplicateError  DuplicateStrategy = "error"  // report the row as an import error
+)
+
+// Import stages reported in ImportResult.Timings.
+const (
+	StageParse        = "parse"
+	StageTransform    = "transform"
+	StageValidate     = "validate"
+	StageLookup       = "lookup"
+	StageBeforeImport = "before_import"
+	StageCallback     = "callback"
+)
+
 // ImportError represents an error during import.
 type ImportError struct {
-	Row     int
+	Row     int // source line of the row (see RowError.Line)
 	Column  string
 	Value   string
 	Message string
@@ -61,14 +114,88 @@ type ImportConfig struct {
 	MaxErrors     int
 	BatchSize     int
 	ValidateRow   func(row map[string]any) error
-	BeforeImport  func(row map[string]any) (map[string]any, error)
-	AfterImport   func(row map[string]any, result any) error
+	// BeforeImport may rewrite or reject each row before the handler. It
+	// also runs in dry runs, so it must not have side effects.
+	BeforeImport func(row map[string]any) (map[string]any, error)
+	AfterImport  func(row map[string]any, result any) error
+	// Logger receives debug logs per stage and an info summary per import.
+	// Defaults to a logger that discards everything.
+	Logger *slog.Logger
+	// Encoding is the character encoding of CSV and JSON files, e.g.
+	// "windows-1252" or "iso-8859-1". Defaults to EncodingAuto. A leading
+	// byte order mark is always stripped.
+	Encoding string
+	// Delimiter separates CSV fields. Defaults to ','; European exports
+	// often use ';'.
+	Delimiter rune
+	// Comment, if set, marks CSV lines to ignore when it is their first
+	// character.
+	Comment rune
+	// LazyQuotes accepts quotes in unquoted CSV fields and non-doubled
+	// quotes in quoted fields, as found in hand-edited files.
+	LazyQuotes bool
+	// SheetName selects the Excel sheet to read. When empty, SheetIndex
+	// (0-based, default 0 = first sheet) is used.
+	SheetName  string
+	SheetIndex int
+	// DedupeKey lists the target fields identifying a record. Rows sharing a
+	// key are looked up once per run, and a row whose key was already
+	// imported earlier in the file counts as existing.
+	DedupeKey []string
+	// ExistsFunc reports whether row already exists in the destination,
+	// e.g. by querying the database on the DedupeKey fields.
+	ExistsFunc func(ctx context.Context, row map[string]any) (bool, error)
+	// UpsertKey lists the fields, possibly several (e.g. email and tenant),
+	// whose values are passed to FindExisting. It is also the dedupe key
+	// when DedupeKey is empty.
+	UpsertKey []string
+	// FindExisting looks up the record matching the UpsertKey values of a
+	// row and returns its ID. It takes precedence over ExistsFunc; with
+	// OnDuplicate = DuplicateUpdate the ID is available to the handler
+	// through ExistingID.
+	FindExisting func(ctx context.Context, key map[string]any) (id string, found bool, err error)
+	// OnDuplicate decides what happens to existing rows (default DuplicateSkip).
+	OnDuplicate DuplicateStrategy
+	// Concurrency is the number of rows processed in parallel (default 1).
+	// Rows are then handled in no particular order; the handler and hooks
+	// must be safe for concurrent use.
+	Concurrency int
+	// DryRun validates the file without importing it: mappings, ValidateRow
+	// and BeforeImport run, but neither ExistsFunc nor the row handler is
+	// called. SuccessCount then counts the rows that would be imported. A
+	// failing mapping Transform fails the row in a dry run, while a real
+	// import keeps the raw value.
+	DryRun bool
+	// Tx, when set, runs the import in a transaction: the row handler gets
+	// the context returned by BeginTx, and the transaction is committed only
+	// if the run completes within AbortAfterErrors. Dry runs ignore it.
+	Tx Tx
+	// AbortAfterErrors stops the import once more rows than this have failed
+	// (0 = no limit) and rolls the transaction back, if any.
+	AbortAfterErrors int
+	// OnProgress is called with the number of rows processed so far and the
+	// number of rows in the file, at most once per ProgressInterval and once
+	// when the import ends, e.g. to drive a progress bar.
+	OnProgress func(processed, total int)
+	// ProgressInterval throttles OnProgress. Defaults to 200ms.
+	ProgressInterval time.Duration
+}
+
+// Tx is a caller-supplied transaction wrapping an import. BeginTx returns
+// the context the rows are imported with, e.g. carrying an ent transaction
+// client (see ent.NewTxContext). With Concurrency, that context is shared by
+// all workers.
+type Tx interface {
+	BeginTx(ctx context.Context) (context.Context, error)
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
 }
 
 // DefaultConfig returns a default import configuration.
 func DefaultConfig() *ImportConfig {
 	return &ImportConfig{
 		Format:        FormatCSV,
+		Delimiter:     ',',
 		SkipHeader:    true,
 		SkipEmptyRows: true,
 		StopOnError:   false,
@@ -90,88 +217,632 @@ func New(config *ImportConfig) *Importer {
 	return &Importer{config: config}
 }
 
+// logger returns the configured logger or a discarding one.
+func (i *Importer) logger() *slog.Logger {
+	if i.config.Logger != nil {
+		return i.config.Logger
+	}
+	return slog.New(slog.DiscardHandler)
+}
+
+// newResult creates an empty result with initialized collections.
+func newResult() *ImportResult {
+	return &ImportResult{
+		Errors:  make([]ImportError, 0),
+		Timings: make(map[string]time.Duration),
+	}
+}
+
+// track adds the time elapsed since start to the given stage.
+func (r *ImportResult) track(stage string, start time.Time) {
+	r.Timings[stage] += time.Since(start)
+}
+
+// WriteErrorCSV writes the failed rows as CSV, with the source columns
+// followed by an "error" column, so they can be fixed and imported again.
+// The source file's delimiter is kept.
+func (r *ImportResult) WriteErrorCSV(w io.Writer) error {
+	columns := r.columns
+	if len(columns) == 0 {
+		for _, re := range r.RowErrors {
+			for key := range re.Row {
+				if !slices.Contains(columns, key) {
+					columns = append(columns, key)
+				}
+			}
+		}
+		slices.Sort(columns)
+	}
+
+	cw := csv.NewWriter(w)
+	if r.delimiter != 0 {
+		cw.Comma = r.delimiter
+	}
+	if err := cw.Write(append(slices.Clone(columns), "error")); err != nil {
+		return err
+	}
+	for _, re := range r.RowErrors {
+		record := make([]string, 0, len(columns)+1)
+		for _, column := range columns {
+			value := ""
+			if v, ok := re.Row[column]; ok && v != nil {
+				value = fmt.Sprint(v)
+			}
+			record = append(record, value)
+		}
+		record = append(record, strings.ReplaceAll(re.Err.Error(), "\n", "; "))
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
 // ImportFromReader imports data from a reader.
 func (i *Importer) ImportFromReader(ctx context.Context, reader io.Reader, handler func(ctx context.Context, row map[string]any) error) (*ImportResult, error) {
 	start := time.Now()
-	result := &ImportResult{
-		Errors: make([]ImportError, 0),
-	}
+	result := newResult()
 
-	var rows []map[string]any
+	var src *sourceRows
 	var err error
 
+	parseStart := time.Now()
+	if i.config.Format == FormatCSV || i.config.Format == FormatJSON {
+		if reader, err = i.decode(reader); err != nil {
+			return nil, fmt.Errorf("failed to parse file: %w", err)
+		}
+	}
 	switch i.config.Format {
 	case FormatCSV:
-		rows, err = i.parseCSV(reader)
+		src, err = i.parseCSV(reader)
 	case FormatJSON:
-		rows, err = i.parseJSON(reader)
+		src, err = i.parseJSON(reader)
+	case FormatExcel:
+		src, err = i.parseExcel(reader)
 	default:
 		return nil, fmt.Errorf("unsupported format for reader: %s", i.config.Format)
 	}
+	result.track(StageParse, parseStart)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse file: %w", err)
 	}
+	i.logger().Debug("import: parsed rows", "format", i.config.Format, "rows", len(src.rows), "duration", result.Timings[StageParse])
 
-	result.TotalRows = len(rows)
+	err = i.processRows(ctx, src, result, handler)
+	i.finish(result, start)
+	return result, err
+}
+
+// ValidateHeader checks a file's header against the mappings before any row
+// is imported, and lists every Required SourceColumn missing from it.
+//
+// Headerless imports (SkipHeader false) key each row by column index ("0",
+// "1", ...): header is then the first record and only its column count is
+// checked against the highest Required index.
+func (i *Importer) ValidateHeader(header []string) error {
+	if !i.config.SkipHeader {
+		return i.validateColumnCount(len(header))
+	}
+	return i.validateColumns(header)
+}
+
+// validateSource validates the header of parsed rows. JSON keys are always
+// named, whatever SkipHeader says.
+func (i *Importer) validateSource(src *sourceRows) error {
+	if i.config.Format == FormatJSON {
+		return i.validateColumns(src.header)
+	}
+	return i.ValidateHeader(src.header)
+}
 
-	for idx, row := range rows {
-		if ctx.Err() != nil {
-			return result, ctx.Err()
+func (i *Importer) validateColumns(header []string) error {
+	var missing []string
+	for _, mapping := range i.config.Mappings {
+		if mapping.Required && !slices.Contains(header, mapping.SourceColumn) && !slices.Contains(missing, mapping.SourceColumn) {
+			missing = append(missing, mapping.SourceColumn)
 		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("invalid header: missing required columns: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
 
-		// Skip empty rows
-		if i.config.SkipEmptyRows && isEmptyRow(row) {
-			result.SkippedCount++
+func (i *Importer) validateColumnCount(count int) error {
+	need := 0
+	for _, mapping := range i.config.Mappings {
+		if !mapping.Required {
 			continue
 		}
+		idx, err := strconv.Atoi(mapping.SourceColumn)
+		if err != nil || idx < 0 {
+			return fmt.Errorf("invalid header: column %q must be an index for imports without header", mapping.SourceColumn)
+		}
+		need = max(need, idx+1)
+	}
+	if count < need {
+		return fmt.Errorf("invalid header: expected at least %d columns, found %d", need, count)
+	}
+	return nil
+}
 
-		// Validate row
-		if i.config.ValidateRow != nil {
-			if err := i.config.ValidateRow(row); err != nil {
-				result.ErrorCount++
-				result.Errors = append(result.Errors, ImportError{
-					Row:     idx + 1,
-					Message: err.Error(),
-				})
-				if i.config.StopOnError || len(result.Errors) >= i.config.MaxErrors {
-					break
-				}
-				continue
-			}
+// indexHeader returns the column names of a headerless file: "0", "1", ...
+func indexHeader(n int) []string {
+	header := make([]string, n)
+	for j := range header {
+		header[j] = strconv.Itoa(j)
+	}
+	return header
+}
+
+// processRows runs the transform, validate, before-import and callback stages
+// on parsed rows, across Concurrency workers. Errors report the source line
+// of each row.
+func (i *Importer) processRows(ctx context.Context, src *sourceRows, result *ImportResult, handler func(ctx context.Context, row map[string]any) error) error {
+	result.TotalRows = len(src.rows)
+	result.DryRun = i.config.DryRun
+	result.columns = src.header
+	if i.config.Format == FormatCSV {
+		result.delimiter = i.config.Delimiter
+	}
+	if err := i.validateSource(src); err != nil {
+		return err
+	}
+	run := &importRun{result: result, seen: make(map[string]*keyClaim), handler: handler}
+	defer i.reportProgress(run, true)
+	if i.config.Tx == nil || i.config.DryRun {
+		return i.runRows(ctx, src, run)
+	}
+
+	txCtx, err := i.config.Tx.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	err = i.runRows(txCtx, src, run)
+	if err != nil || i.aborted(result) {
+		if rbErr := i.config.Tx.Rollback(txCtx); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back: %w", rbErr))
 		}
+		result.RolledBack = true
+		i.logger().Warn("import: rolled back", "errors", result.ErrorCount, "abort_after", i.config.AbortAfterErrors)
+		return err
+	}
+	if err := i.config.Tx.Commit(txCtx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
 
-		// Before import hook
-		if i.config.BeforeImport != nil {
-			row, err = i.config.BeforeImport(row)
-			if err != nil {
-				result.ErrorCount++
-				result.Errors = append(result.Errors, ImportError{
-					Row:     idx + 1,
-					Message: err.Error(),
-				})
-				continue
+// runRows processes the rows of src, sequentially or across Concurrency
+// workers, until done or stopped.
+func (i *Importer) runRows(ctx context.Context, src *sourceRows, run *importRun) error {
+	if i.config.Concurrency <= 1 {
+		for idx, row := range src.rows {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			done := i.processRow(ctx, run, src.lines[idx], row)
+			i.reportProgress(run, false)
+			if done {
+				break
 			}
 		}
+		return nil
+	}
 
-		// Process row
-		if err := handler(ctx, row); err != nil {
-			result.ErrorCount++
-			result.Errors = append(result.Errors, ImportError{
-				Row:     idx + 1,
-				Message: err.Error(),
-			})
-			if i.config.StopOnError || len(result.Errors) >= i.config.MaxErrors {
-				break
+	stop := make(chan struct{})
+	var once sync.Once
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range i.config.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				done := i.processRow(ctx, run, src.lines[idx], src.rows[idx])
+				i.reportProgress(run, false)
+				if done {
+					once.Do(func() { close(stop) })
+				}
 			}
-			continue
+		}()
+	}
+
+feed:
+	for idx := range src.rows {
+		select {
+		case jobs <- idx:
+		case <-stop:
+			break feed
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return ctx.Err()
+}
+
+// importRun holds the state shared by the rows of one import. mu guards the
+// result and the dedupe cache when rows are processed concurrently.
+type importRun struct {
+	mu      sync.Mutex
+	result  *ImportResult
+	seen    map[string]*keyClaim
+	handler func(ctx context.Context, row map[string]any) error
+
+	// Progress reporting, see reportProgress.
+	processed    int
+	reported     int
+	lastProgress time.Time
+}
+
+// update applies fn to the result under the lock.
+func (r *importRun) update(fn func(result *ImportResult)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(r.result)
+}
+
+// track adds the time elapsed since start to the given stage.
+func (r *importRun) track(stage string, start time.Time) {
+	r.update(func(result *ImportResult) { result.track(stage, start) })
+}
+
+// reportProgress counts a processed row, or ends the run when final, and
+// calls OnProgress if ProgressInterval has elapsed since the last call. The
+// final report is skipped when nothing changed since the last one.
+func (i *Importer) reportProgress(run *importRun, final bool) {
+	if i.config.OnProgress == nil {
+		return
+	}
+	interval := i.config.ProgressInterval
+	if interval <= 0 {
+		interval = 200 * time.Millisecond
+	}
+
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	if !final {
+		run.processed++
+	}
+	now := time.Now()
+	if (final && run.processed == run.reported) || (!final && now.Sub(run.lastProgress) < interval) {
+		return
+	}
+	run.reported, run.lastProgress = run.processed, now
+	i.config.OnProgress(run.processed, run.result.TotalRows)
+}
+
+// processRow runs every stage on one row. It reports whether the import
+// must stop (StopOnError, MaxErrors or AbortAfterErrors reached).
+func (i *Importer) processRow(ctx context.Context, run *importRun, rowNum int, row map[string]any) bool {
+	log := i.logger()
+	raw := maps.Clone(row)
+	fail := func(err error, details ...ImportError) bool {
+		stop := false
+		run.update(func(result *ImportResult) {
+			result.fail(rowNum, raw, err, details...)
+			stop = i.stop(result)
+		})
+		return stop
+	}
+
+	stageStart := time.Now()
+	row, transformErrs := i.transformRow(row, rowNum)
+	run.track(StageTransform, stageStart)
+
+	// Skip empty rows
+	if i.config.SkipEmptyRows && isEmptyRow(row) {
+		run.update(func(result *ImportResult) { result.SkippedCount++ })
+		return false
+	}
+
+	// A dry run reports failing transforms so they can be fixed before
+	// importing; a real import keeps the raw values.
+	switch {
+	case len(transformErrs) > 0 && i.config.DryRun:
+		log.Debug("import: row failed transform", "row", rowNum, "errors", len(transformErrs))
+		errs := make([]error, len(transformErrs))
+		for n, e := range transformErrs {
+			errs[n] = fmt.Errorf("%s: %s", e.Column, e.Message)
+		}
+		return fail(errors.Join(errs...), transformErrs...)
+	case len(transformErrs) > 0:
+		log.Debug("import: transform failed, keeping raw values", "row", rowNum, "errors", len(transformErrs))
+	}
+
+	// Validate row
+	if i.config.ValidateRow != nil {
+		stageStart = time.Now()
+		err := i.config.ValidateRow(row)
+		run.track(StageValidate, stageStart)
+		if err != nil {
+			log.Debug("import: row failed validation", "row", rowNum, "error", err)
+			return fail(err, fieldErrorDetails(err, rowNum, row)...)
+		}
+	}
+
+	// Look up existing records
+	existing, claim, err := i.lookup(ctx, run, row)
+	if claim != nil {
+		defer claim.release()
+	}
+	if err != nil {
+		log.Debug("import: lookup failed", "row", rowNum, "error", err)
+		return fail(err)
+	}
+	exists := existing.found
+	rowCtx := ctx
+	if exists {
+		run.update(func(result *ImportResult) { result.DuplicateCount++ })
+	}
+	switch {
+	case exists && i.config.OnDuplicate == DuplicateError:
+		value := strings.Join(i.keyValues(row), "|")
+		log.Debug("import: duplicate row", "row", rowNum, "key", value)
+		return fail(errDuplicate, ImportError{
+			Row:     rowNum,
+			Column:  strings.Join(i.keyFields(), ","),
+			Value:   value,
+			Message: errDuplicate.Error(),
+		})
+	case exists && i.config.OnDuplicate == DuplicateUpdate:
+		rowCtx = context.WithValue(ctx, updateKey{}, existing)
+	case exists:
+		run.update(func(result *ImportResult) { result.SkippedCount++ })
+		return false
+	}
+
+	// Before import hook
+	if i.config.BeforeImport != nil {
+		stageStart = time.Now()
+		hooked, err := i.config.BeforeImport(row)
+		run.track(StageBeforeImport, stageStart)
+		if err != nil {
+			log.Debug("import: before-import hook failed", "row", rowNum, "error", err)
+			fail(err)
+			return false
 		}
+		row = hooked
+	}
+
+	// Process row
+	if !i.config.DryRun {
+		stageStart = time.Now()
+		err = run.handler(rowCtx, row)
+		run.track(StageCallback, stageStart)
+	}
+	if err != nil {
+		log.Debug("import: row failed", "row", rowNum, "error", err)
+		return fail(err)
+	}
 
+	if claim != nil && !exists {
+		claim.settle(match{found: true, inserted: true})
+	}
+	run.update(func(result *ImportResult) {
+		if exists {
+			result.UpdatedCount++
+		}
 		result.SuccessCount++
+	})
+	return false
+}
+
+// stop reports whether the import must stop after an error.
+func (i *Importer) stop(result *ImportResult) bool {
+	return i.config.StopOnError || len(result.Errors) >= i.config.MaxErrors || i.aborted(result)
+}
+
+// aborted reports whether more rows failed than AbortAfterErrors allows.
+func (i *Importer) aborted(result *ImportResult) bool {
+	return i.config.AbortAfterErrors > 0 && result.ErrorCount > i.config.AbortAfterErrors
+}
+
+// fail records a failed row. details default to a single ImportError
+// carrying err's message.
+func (r *ImportResult) fail(line int, raw map[string]any, err error, details ...ImportError) {
+	if len(details) == 0 {
+		details = []ImportError{{Row: line, Message: err.Error()}}
 	}
+	r.ErrorCount++
+	r.Errors = append(r.Errors, details...)
+	r.RowErrors = append(r.RowErrors, RowError{Line: line, Row: raw, Err: err})
+}
+
+type updateKey struct{}
+
+// match is the result of looking up an existing record.
+type match struct {
+	found    bool
+	id       string // set when found through FindExisting
+	inserted bool   // the record was created by an earlier row of the file
+}
+
+// IsUpdate reports whether the row passed to the import handler matched an
+// existing record and should update it rather than be inserted
+// (OnDuplicate = DuplicateUpdate).
+func IsUpdate(ctx context.Context) bool {
+	_, ok := ctx.Value(updateKey{}).(match)
+	return ok
+}
+
+// ExistingID returns the ID of the record an update row matched, as
+// returned by FindExisting. When the record was inserted by an earlier row
+// of the same file, FindExisting is called once more to get its ID. It is
+// empty for inserts and when the record was found with ExistsFunc.
+func ExistingID(ctx context.Context) string {
+	m, _ := ctx.Value(updateKey{}).(match)
+	return m.id
+}
+
+// keyClaim serializes the rows sharing a dedupe key: the first row claims
+// the key until it is handled, and the others wait for its outcome instead
+// of looking the record up (and inserting it) at the same time.
+type keyClaim struct {
+	run  *importRun
+	key  string
+	done chan struct{} // closed by release
+	// Set by settle once the record is known to exist; read by the waiting
+	// rows after done is closed.
+	m       match
+	settled bool
+	// resolve looks up the ID of a record inserted by the claiming row, once
+	// for all the rows waiting on the key.
+	resolve sync.Once
+	err     error
+}
+
+// settle records that the record behind the key exists, as m.
+func (c *keyClaim) settle(m match) {
+	c.m, c.settled = m, true
+}
+
+// release wakes the rows waiting for the key. An unsettled key (the row
+// failed before creating the record) is forgotten, so that the next row with
+// the key claims it again.
+func (c *keyClaim) release() {
+	if !c.settled {
+		c.run.mu.Lock()
+		delete(c.run.seen, c.key)
+		c.run.mu.Unlock()
+	}
+	close(c.done)
+}
 
+// lookup reports whether row already exists, through an earlier row of the
+// file with the same dedupe key or FindExisting/ExistsFunc, which run once
+// per key; without a key every row is looked up. When lookup returns a
+// claim, the caller must release it once the row is handled.
+func (i *Importer) lookup(ctx context.Context, run *importRun, row map[string]any) (match, *keyClaim, error) {
+	key := i.dedupeKey(row)
+	var claim *keyClaim
+	for key != "" && claim == nil {
+		run.mu.Lock()
+		c, ok := run.seen[key]
+		if !ok {
+			claim = &keyClaim{run: run, key: key, done: make(chan struct{})}
+			run.seen[key] = claim
+		}
+		run.mu.Unlock()
+		if ok {
+			select {
+			case <-c.done:
+			case <-ctx.Done():
+				return match{}, nil, ctx.Err()
+			}
+			if c.settled {
+				m, err := i.claimed(ctx, run, c, row)
+				return m, nil, err
+			}
+		}
+	}
+	if (i.config.FindExisting == nil && i.config.ExistsFunc == nil) || i.config.DryRun {
+		return match{}, claim, nil
+	}
+
+	var m match
+	var err error
+	if i.config.FindExisting != nil {
+		m.id, m.found, err = i.findExisting(ctx, run, row)
+	} else {
+		start := time.Now()
+		m.found, err = i.config.ExistsFunc(ctx, row)
+		run.track(StageLookup, start)
+	}
+	if err != nil {
+		return match{}, claim, fmt.Errorf("lookup failed: %w", err)
+	}
+	if claim != nil && m.found {
+		claim.settle(m)
+	}
+	return m, claim, nil
+}
+
+// claimed returns the match settled on c for row. When the claiming row
+// inserted the record and rows are updated, its ID is looked up with
+// FindExisting so that the handler gets it through ExistingID.
+func (i *Importer) claimed(ctx context.Context, run *importRun, c *keyClaim, row map[string]any) (match, error) {
+	if !c.m.inserted || i.config.FindExisting == nil || i.config.OnDuplicate != DuplicateUpdate || i.config.DryRun {
+		return c.m, nil
+	}
+	c.resolve.Do(func() {
+		var err error
+		c.m.id, _, err = i.findExisting(ctx, run, row)
+		if err != nil {
+			c.err = fmt.Errorf("lookup failed: %w", err)
+		}
+	})
+	return c.m, c.err
+}
+
+// findExisting calls FindExisting with the UpsertKey values of row.
+func (i *Importer) findExisting(ctx context.Context, run *importRun, row map[string]any) (string, bool, error) {
+	values := make(map[string]any, len(i.config.UpsertKey))
+	for _, field := range i.config.UpsertKey {
+		values[field] = row[field]
+	}
+	start := time.Now()
+	defer run.track(StageLookup, start)
+	return i.config.FindExisting(ctx, values)
+}
+
+// keyFields returns the fields identifying a record: DedupeKey, or
+// UpsertKey when DedupeKey is empty.
+func (i *Importer) keyFields() []string {
+	if len(i.config.DedupeKey) > 0 {
+		return i.config.DedupeKey
+	}
+	return i.config.UpsertKey
+}
+
+// keyValues returns the key field values of row.
+func (i *Importer) keyValues(row map[string]any) []string {
+	fields := i.keyFields()
+	values := make([]string, len(fields))
+	for n, field := range fields {
+		values[n] = fmt.Sprint(row[field])
+	}
+	return values
+}
+
+// dedupeKey joins the quoted key field values of row, so that values
+// containing the separator cannot collide.
+func (i *Importer) dedupeKey(row map[string]any) string {
+	values := i.keyValues(row)
+	for n, value := range values {
+		values[n] = strconv.Quote(value)
+	}
+	return strings.Join(values, ",")
+}
+
+// finish sets the total duration and logs the import summary.
+func (i *Importer) finish(result *ImportResult, start time.Time) {
 	result.Duration = time.Since(start)
-	return result, nil
+	attrs := []any{
+		"format", i.config.Format,
+		"total", result.TotalRows,
+		"success", result.SuccessCount,
+		"errors", result.ErrorCount,
+		"skipped", result.SkippedCount,
+		"updated", result.UpdatedCount,
+		"duplicates", result.DuplicateCount,
+		"dry_run", result.DryRun,
+		"rolled_back", result.RolledBack,
+		"duration", result.Duration,
+	}
+	for _, stage := range []string{StageParse, StageTransform, StageValidate, StageLookup, StageBeforeImport, StageCallback} {
+		if d, ok := result.Timings[stage]; ok {
+			attrs = append(attrs, stage, d)
+		}
+	}
+	i.logger().Info("import completed", attrs...)
 }
 
 // ImportFromFile imports data from a multipart file.
@@ -196,46 +867,64 @@ func (i *Importer) ImportFromFile(ctx context.Context, file multipart.File, head
 	return i.ImportFromReader(ctx, file, handler)
 }
 
-// parseCSV parses CSV data.
-func (i *Importer) parseCSV(reader io.Reader) ([]map[string]any, error) {
+// sourceRows holds parsed rows with the source line of each row.
+type sourceRows struct {
+	header []string // column names, in file order
+	rows   []map[string]any
+	lines  []int
+}
+
+// parseCSV parses CSV data. Blank lines are ignored but still counted in
+// the line numbers.
+func (i *Importer) parseCSV(reader io.Reader) (*sourceRows, error) {
 	csvReader := csv.NewReader(reader)
 	csvReader.FieldsPerRecord = -1 // Allow variable number of fields
-
-	records, err := csvReader.ReadAll()
-	if err != nil {
-		return nil, err
-	}
-
-	if len(records) == 0 {
-		return []map[string]any{}, nil
-	}
-
-	// Get headers
-	headers := records[0]
-	startRow := 0
-	if i.config.SkipHeader {
-		startRow = 1
+	if i.config.Delimiter != 0 {
+		csvReader.Comma = i.config.Delimiter
 	}
+	csvReader.Comment = i.config.Comment
+	csvReader.LazyQuotes = i.config.LazyQuotes
+
+	src := &sourceRows{}
+	for n := 0; ; n++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			if !i.config.SkipHeader {
+				src.header = indexHeader(len(record))
+			} else {
+				src.header = record
+				continue
+			}
+		}
 
-	rows := make([]map[string]any, 0, len(records)-startRow)
-	for idx := startRow; idx < len(records); idx++ {
-		record := records[idx]
 		row := make(map[string]any)
-
-		for j, header := range headers {
-			if j < len(record) {
-				row[header] = i.transformValue(header, record[j])
+		if !i.config.SkipHeader {
+			for j, value := range record {
+				row[strconv.Itoa(j)] = value
+			}
+		} else {
+			for j, header := range src.header {
+				if j < len(record) {
+					row[header] = record[j]
+				}
 			}
 		}
-
-		rows = append(rows, row)
+		line, _ := csvReader.FieldPos(0)
+		src.rows = append(src.rows, row)
+		src.lines = append(src.lines, line)
 	}
 
-	return rows, nil
+	return src, nil
 }
 
-// parseJSON parses JSON data.
-func (i *Importer) parseJSON(reader io.Reader) ([]map[string]any, error) {
+// parseJSON parses JSON data. The line of a row is its position in the array.
+func (i *Importer) parseJSON(reader io.Reader) (*sourceRows, error) {
 	var rows []map[string]any
 	if err := json.NewDecoder(reader).Decode(&rows); err != nil {
 		// Try single object
@@ -245,125 +934,245 @@ func (i *Importer) parseJSON(reader io.Reader) ([]map[string]any, error) {
 		}
 		rows = []map[string]any{single}
 	}
-	return rows, nil
+
+	src := &sourceRows{rows: rows, lines: make([]int, len(rows))}
+	keys := make(map[string]bool)
+	for n, row := range rows {
+		src.lines[n] = n + 1
+		for key := range row {
+			if !keys[key] {
+				keys[key] = true
+				src.header = append(src.header, key)
+			}
+		}
+	}
+	slices.Sort(src.header)
+	return src, nil
 }
 
 // importExcel imports from an Excel file.
 func (i *Importer) importExcel(ctx context.Context, file io.Reader, handler func(ctx context.Context, row map[string]any) error) (*ImportResult, error) {
 	start := time.Now()
-	result := &ImportResult{
-		Errors: make([]ImportError, 0),
+	result := newResult()
+
+	parseStart := time.Now()
+	src, err := i.parseExcel(file)
+	if err != nil {
+		return nil, err
 	}
+	result.track(StageParse, parseStart)
+	i.logger().Debug("import: parsed rows", "format", FormatExcel, "rows", len(src.rows), "duration", result.Timings[StageParse])
 
-	f, err := excelize.OpenReader(file)
+	err = i.processRows(ctx, src, result, handler)
+	i.finish(result, start)
+	return result, err
+}
+
+// parseExcel parses the configured sheet of an XLSX file. Cell values are
+// returned as strings, like CSV values, so column transforms apply unchanged.
+func (i *Importer) parseExcel(reader io.Reader) (*sourceRows, error) {
+	f, err := excelize.OpenReader(reader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open Excel file: %w", err)
 	}
 	defer func() { _ = f.Close() }()
 
-	// Get the first sheet
-	sheets := f.GetSheetList()
-	if len(sheets) == 0 {
-		return nil, fmt.Errorf("no sheets found in Excel file")
+	sheet, err := i.excelSheet(f)
+	if err != nil {
+		return nil, err
 	}
+	return i.sheetRows(f, sheet)
+}
 
-	rows, err := f.GetRows(sheets[0])
+// sheetRows reads the rows of a sheet, keyed by the header row. The line of
+// a row is its row number in the sheet.
+func (i *Importer) sheetRows(f *excelize.File, sheet string) (*sourceRows, error) {
+	records, err := f.GetRows(sheet)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read rows: %w", err)
 	}
-
-	if len(rows) == 0 {
-		return result, nil
+	if len(records) == 0 {
+		return &sourceRows{}, nil
 	}
 
-	// Get headers
-	headers := rows[0]
+	// Get headers, ignoring empty trailing columns.
+	headers := records[0]
+	for len(headers) > 0 && strings.TrimSpace(headers[len(headers)-1]) == "" {
+		headers = headers[:len(headers)-1]
+	}
 	startRow := 0
 	if i.config.SkipHeader {
 		startRow = 1
+	} else {
+		headers = indexHeader(len(headers))
 	}
 
-	result.TotalRows = len(rows) - startRow
-
-	for idx := startRow; idx < len(rows); idx++ {
-		if ctx.Err() != nil {
-			return result, ctx.Err()
+	src := &sourceRows{}
+	for _, header := range headers {
+		if header != "" {
+			src.header = append(src.header, header)
 		}
-
-		record := rows[idx]
-		row := make(map[string]any)
-
+	}
+	for idx := startRow; idx < len(records); idx++ {
+		record := records[idx]
+		row := make(map[string]any, len(headers))
 		for j, header := range headers {
+			if header == "" {
+				continue
+			}
+			// Trailing empty cells are not returned by GetRows.
+			value := ""
 			if j < len(record) {
-				row[header] = i.transformValue(header, record[j])
+				value = excelValue(f, sheet, j, idx, record[j])
 			}
+			row[header] = value
 		}
+		src.rows = append(src.rows, row)
+		src.lines = append(src.lines, idx+1)
+	}
+	return src, nil
+}
+
+// SheetImport configures the import of one workbook sheet.
+type SheetImport struct {
+	Mappings []ColumnMapping // replaces ImportConfig.Mappings for this sheet
+	Handler  func(ctx context.Context, row map[string]any) error
+}
+
+// ImportWorkbook imports every sheet of an XLSX workbook that has an entry
+// in sheets (keyed by sheet name, case-insensitive), each with its own
+// mappings and row handler. Other sheets are skipped. The other settings of
+// the importer's config apply to all sheets. Results are keyed by the
+// sheet name found in the workbook.
+func (i *Importer) ImportWorkbook(ctx context.Context, reader io.Reader, sheets map[string]SheetImport) (map[string]*ImportResult, error) {
+	f, err := excelize.OpenReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Excel file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	configured := make(map[string]SheetImport, len(sheets))
+	for name, sheet := range sheets {
+		configured[strings.ToLower(name)] = sheet
+	}
 
-		// Skip empty rows
-		if i.config.SkipEmptyRows && isEmptyRow(row) {
-			result.SkippedCount++
+	results := make(map[string]*ImportResult)
+	for _, name := range f.GetSheetList() {
+		sheet, ok := configured[strings.ToLower(name)]
+		if !ok || sheet.Handler == nil {
+			i.logger().Debug("import: sheet skipped", "sheet", name)
 			continue
 		}
 
-		// Validate row
-		if i.config.ValidateRow != nil {
-			if err := i.config.ValidateRow(row); err != nil {
-				result.ErrorCount++
-				result.Errors = append(result.Errors, ImportError{
-					Row:     idx + 1,
-					Message: err.Error(),
-				})
-				if i.config.StopOnError || len(result.Errors) >= i.config.MaxErrors {
-					break
-				}
-				continue
-			}
+		cfg := *i.config
+		cfg.Format = FormatExcel
+		cfg.Mappings = sheet.Mappings
+		sub := &Importer{config: &cfg}
+
+		start := time.Now()
+		result := newResult()
+		src, err := sub.sheetRows(f, name)
+		if err != nil {
+			return results, fmt.Errorf("sheet %q: %w", name, err)
 		}
+		result.track(StageParse, start)
 
-		// Before import hook
-		if i.config.BeforeImport != nil {
-			row, err = i.config.BeforeImport(row)
-			if err != nil {
-				result.ErrorCount++
-				result.Errors = append(result.Errors, ImportError{
-					Row:     idx + 1,
-					Message: err.Error(),
-				})
-				continue
-			}
+		err = sub.processRows(ctx, src, result, sheet.Handler)
+		sub.finish(result, start)
+		results[name] = result
+		if err != nil {
+			return results, err
 		}
+	}
+	return results, nil
+}
 
-		// Process row
-		if err := handler(ctx, row); err != nil {
-			result.ErrorCount++
-			result.Errors = append(result.Errors, ImportError{
-				Row:     idx + 1,
-				Message: err.Error(),
-			})
-			if i.config.StopOnError || len(result.Errors) >= i.config.MaxErrors {
-				break
+// excelSheet returns the sheet selected by SheetName or SheetIndex.
+func (i *Importer) excelSheet(f *excelize.File) (string, error) {
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return "", fmt.Errorf("no sheets found in Excel file")
+	}
+	if name := i.config.SheetName; name != "" {
+		for _, sheet := range sheets {
+			if strings.EqualFold(sheet, name) {
+				return sheet, nil
 			}
-			continue
 		}
-
-		result.SuccessCount++
+		return "", fmt.Errorf("sheet %q not found in Excel file", name)
 	}
+	if i.config.SheetIndex < 0 || i.config.SheetIndex >= len(sheets) {
+		return "", fmt.Errorf("sheet index %d out of range (file has %d sheets)", i.config.SheetIndex, len(sheets))
+	}
+	return sheets[i.config.SheetIndex], nil
+}
 
-	result.Duration = time.Since(start)
-	return result, nil
+// excelValue normalizes numbers stored as floats, so that 42 reads "42"
+// rather than "42.0" and 0.1 rather than "0.10000000000000001". Text cells
+// are returned as is.
+func excelValue(f *excelize.File, sheet string, col, row int, value string) string {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	normalized := strconv.FormatFloat(n, 'g', 15, 64)
+	if strings.ContainsAny(normalized, "e") {
+		// Keep plain notation for large or small numbers.
+		normalized = strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	if normalized == value {
+		return value
+	}
+	cell, err := excelize.CoordinatesToCellName(col+1, row+1)
+	if err != nil {
+		return value
+	}
+	switch typ, _ := f.GetCellType(sheet, cell); typ {
+	case excelize.CellTypeSharedString, excelize.CellTypeInlineString:
+		return value
+	}
+	return normalized
 }
 
-// transformValue transforms a value based on column mappings.
-func (i *Importer) transformValue(column, value string) any {
+// transformRow applies the column mapping transforms to the string values of
+// a row, then copies each mapped value to its TargetField. Failing transforms
+// leave the raw value in place and are returned as errors for rowNum.
+func (i *Importer) transformRow(row map[string]any, rowNum int) (map[string]any, []ImportError) {
+	var errs []ImportError
+	done := make(map[string]bool)
 	for _, mapping := range i.config.Mappings {
-		if mapping.SourceColumn == column && mapping.Transform != nil {
-			transformed, err := mapping.Transform(value)
-			if err == nil {
-				return transformed
-			}
+		if mapping.Transform == nil || done[mapping.SourceColumn] {
+			continue
+		}
+		str, ok := row[mapping.SourceColumn].(string)
+		if !ok {
+			continue
+		}
+		done[mapping.SourceColumn] = true
+		transformed, err := mapping.Transform(str)
+		if err != nil {
+			errs = append(errs, ImportError{
+				Row:     rowNum,
+				Column:  mapping.SourceColumn,
+				Value:   str,
+				Message: err.Error(),
+			})
+			continue
 		}
+		row[mapping.SourceColumn] = transformed
 	}
-	return value
+	for _, mapping := range i.config.Mappings {
+		if mapping.TargetField == "" || mapping.TargetField == mapping.SourceColumn {
+			continue
+		}
+		if _, taken := row[mapping.TargetField]; taken {
+			continue
+		}
+		if v, ok := row[mapping.SourceColumn]; ok {
+			row[mapping.TargetField] = v
+		}
+	}
+	return row, errs
 }
 
 // isEmptyRow checks if a row is empty.
@@ -401,11 +1210,14 @@ func MapToStruct(row map[string]any, dest any) error {
 			fieldName = fieldType.Name
 		}
 
-		// Also try lowercase
+		// Also try lowercase, then the Go field name (ColumnMapping.TargetField)
 		value, ok := row[fieldName]
 		if !ok {
 			value, ok = row[strings.ToLower(fieldName)]
 		}
+		if !ok {
+			value, ok = row[fieldType.Name]
+		}
 		if !ok {
 			continue
 		}
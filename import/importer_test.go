@@ -0,0 +1,810 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime/multipart"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// buildWorkbook returns an XLSX file with a "Products" sheet and an
+// "Archive" sheet.
+func buildWorkbook(t *testing.T) *bytes.Reader {
+	t.Helper()
+	f := excelize.NewFile()
+	defer func() { _ = f.Close() }()
+
+	require.NoError(t, f.SetSheetName("Sheet1", "Products"))
+	rows := [][]any{
+		{"name", "qty", "price", "code", ""}, // empty trailing header
+		{"Widget", 42.0, 0.1 + 0.2, "1.10"},
+		{"Gadget", 7, 19.99}, // trailing cells left empty
+	}
+	for r, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, r+1)
+		require.NoError(t, f.SetSheetRow("Products", cell, &row))
+	}
+	_, err := f.NewSheet("Archive")
+	require.NoError(t, err)
+	require.NoError(t, f.SetSheetRow("Archive", "A1", &[]any{"name"}))
+	require.NoError(t, f.SetSheetRow("Archive", "A2", &[]any{"Old"}))
+
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+	return bytes.NewReader(buf.Bytes())
+}
+
+func collect(rows *[]map[string]any) func(context.Context, map[string]any) error {
+	return func(_ context.Context, row map[string]any) error {
+		*rows = append(*rows, row)
+		return nil
+	}
+}
+
+func TestImportExcel_FirstSheet(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Format = FormatExcel
+	cfg.Mappings = []ColumnMapping{{SourceColumn: "qty", Transform: func(v string) (any, error) { return strconv.Atoi(v) }}}
+
+	var rows []map[string]any
+	result, err := New(cfg).ImportFromReader(context.Background(), buildWorkbook(t), collect(&rows))
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.SuccessCount)
+
+	require.Len(t, rows, 2)
+	assert.Equal(t, map[string]any{"name": "Widget", "qty": 42, "price": "0.3", "code": "1.10"}, rows[0])
+	assert.Equal(t, map[string]any{"name": "Gadget", "qty": 7, "price": "19.99", "code": ""}, rows[1])
+}
+
+func TestImportExcel_SheetSelection(t *testing.T) {
+	for name, cfg := range map[string]*ImportConfig{
+		"by name":  {Format: FormatExcel, SkipHeader: true, SheetName: "archive", MaxErrors: 10},
+		"by index": {Format: FormatExcel, SkipHeader: true, SheetIndex: 1, MaxErrors: 10},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var rows []map[string]any
+			_, err := New(cfg).ImportFromReader(context.Background(), buildWorkbook(t), collect(&rows))
+			require.NoError(t, err)
+			assert.Equal(t, []map[string]any{{"name": "Old"}}, rows)
+		})
+	}
+
+	cfg := &ImportConfig{Format: FormatExcel, SheetName: "Missing"}
+	_, err := New(cfg).ImportFromReader(context.Background(), buildWorkbook(t), collect(new([]map[string]any)))
+	assert.ErrorContains(t, err, `sheet "Missing" not found`)
+}
+
+func TestImportFromFile_DetectsExcel(t *testing.T) {
+	file := buildWorkbook(t)
+	var rows []map[string]any
+	result, err := New(nil).ImportFromFile(context.Background(), readerFile{file}, &multipart.FileHeader{Filename: "products.XLSX"}, collect(&rows))
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.TotalRows)
+	assert.Equal(t, "42", rows[0]["qty"])
+}
+
+// readerFile adapts a bytes.Reader to multipart.File.
+type readerFile struct{ *bytes.Reader }
+
+func (readerFile) Close() error { return nil }
+
+func TestImportWorkbook_PerSheetMappings(t *testing.T) {
+	var products, archived []map[string]any
+	cfg := DefaultConfig()
+	results, err := New(cfg).ImportWorkbook(context.Background(), buildWorkbook(t), map[string]SheetImport{
+		"products": {
+			Mappings: []ColumnMapping{{SourceColumn: "qty", Transform: func(v string) (any, error) { return strconv.Atoi(v) }}},
+			Handler:  collect(&products),
+		},
+		"Archive": {Handler: collect(&archived)},
+		"Missing": {Handler: collect(new([]map[string]any))},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+	assert.Equal(t, 2, results["Products"].SuccessCount)
+	assert.Equal(t, 1, results["Archive"].SuccessCount)
+	assert.Equal(t, 42, products[0]["qty"])
+	assert.Equal(t, []map[string]any{{"name": "Old"}}, archived)
+	// Mappings of one sheet do not leak into the importer config.
+	assert.Empty(t, cfg.Mappings)
+}
+
+func TestImportWorkbook_SkipsUnconfiguredSheets(t *testing.T) {
+	var rows []map[string]any
+	results, err := New(nil).ImportWorkbook(context.Background(), buildWorkbook(t), map[string]SheetImport{
+		"Archive": {Handler: collect(&rows)},
+	})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.NotContains(t, results, "Products")
+	assert.Len(t, rows, 1)
+}
+
+const customersCSV = "email,name\na@example.com,Ann\nb@example.com,Bob\na@example.com,Ann again\nc@example.com,Cid\n"
+
+// existing returns an ExistsFunc backed by a set of emails and counts its calls.
+func existing(calls *int, emails ...string) func(context.Context, map[string]any) (bool, error) {
+	return func(_ context.Context, row map[string]any) (bool, error) {
+		*calls++
+		return slices.Contains(emails, row["email"].(string)), nil
+	}
+}
+
+func TestImportFromReader_Dedupe(t *testing.T) {
+	t.Run("skip", func(t *testing.T) {
+		calls := 0
+		cfg := DefaultConfig()
+		cfg.DedupeKey = []string{"email"}
+		cfg.ExistsFunc = existing(&calls, "b@example.com")
+
+		var rows []map[string]any
+		result, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader(customersCSV), collect(&rows))
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls, "in-file duplicate must hit the cache")
+		assert.Equal(t, 2, result.SuccessCount)
+		assert.Equal(t, 2, result.SkippedCount)
+		assert.Equal(t, 2, result.DuplicateCount)
+		assert.Equal(t, 0, result.UpdatedCount)
+		require.Len(t, rows, 2)
+		assert.Equal(t, "Cid", rows[1]["name"])
+	})
+
+	t.Run("update", func(t *testing.T) {
+		calls := 0
+		cfg := DefaultConfig()
+		cfg.DedupeKey = []string{"email"}
+		cfg.ExistsFunc = existing(&calls, "b@example.com")
+		cfg.OnDuplicate = DuplicateUpdate
+
+		var updated []string
+		result, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader(customersCSV), func(ctx context.Context, row map[string]any) error {
+			if IsUpdate(ctx) {
+				updated = append(updated, row["name"].(string))
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Bob", "Ann again"}, updated)
+		assert.Equal(t, 4, result.SuccessCount)
+		assert.Equal(t, 2, result.UpdatedCount)
+		assert.Equal(t, 0, result.SkippedCount)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		calls := 0
+		cfg := DefaultConfig()
+		cfg.DedupeKey = []string{"email"}
+		cfg.ExistsFunc = existing(&calls, "b@example.com")
+		cfg.OnDuplicate = DuplicateError
+
+		result, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader(customersCSV), collect(new([]map[string]any)))
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.SuccessCount)
+		require.Len(t, result.Errors, 2)
+		assert.Equal(t, ImportError{Row: 3, Column: "email", Value: "b@example.com", Message: "record already exists"}, result.Errors[0])
+		assert.Equal(t, 4, result.Errors[1].Row)
+	})
+}
+
+func TestImportFromReader_LookupError(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ExistsFunc = func(context.Context, map[string]any) (bool, error) {
+		return false, errors.New("db down")
+	}
+
+	result, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader(customersCSV), collect(new([]map[string]any)))
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.SuccessCount)
+	assert.Equal(t, 4, result.ErrorCount)
+	assert.Equal(t, "lookup failed: db down", result.Errors[0].Message)
+}
+
+func TestImportFromReader_DryRun(t *testing.T) {
+	csv := "name,age\nAnn,31\nBob,old\n,\nCid,-4\n"
+	cfg := DefaultConfig()
+	cfg.DryRun = true
+	cfg.Mappings = []ColumnMapping{{SourceColumn: "age", Transform: func(v string) (any, error) { return strconv.Atoi(v) }}}
+	cfg.ValidateRow = func(row map[string]any) error {
+		if row["age"].(int) < 0 {
+			return errors.New("age must be positive")
+		}
+		return nil
+	}
+
+	called := false
+	result, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader(csv), func(context.Context, map[string]any) error {
+		called = true
+		return nil
+	})
+	require.NoError(t, err)
+	assert.False(t, called, "dry run must not call the handler")
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 1, result.SuccessCount)
+	assert.Equal(t, 1, result.SkippedCount)
+	assert.Equal(t, 2, result.ErrorCount)
+	require.Len(t, result.Errors, 2)
+	assert.Equal(t, ImportError{Row: 3, Column: "age", Value: "old", Message: `strconv.Atoi: parsing "old": invalid syntax`}, result.Errors[0])
+	assert.Equal(t, ImportError{Row: 5, Message: "age must be positive"}, result.Errors[1])
+}
+
+type contactRow struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email" validate:"required,email"`
+}
+
+func TestImportFromReader_ValidateStruct(t *testing.T) {
+	csv := "name,email\nAnn,ann@example.com\n,bob\n"
+	cfg := DefaultConfig()
+	cfg.DryRun = true
+	cfg.ValidateRow = ValidateStruct[contactRow]()
+
+	result, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader(csv), func(context.Context, map[string]any) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.SuccessCount)
+	assert.Equal(t, 1, result.ErrorCount)
+	assert.Equal(t, []ImportError{
+		{Row: 3, Column: "email", Value: "bob", Message: "The email field must be a valid email address"},
+		{Row: 3, Column: "name", Value: "", Message: "The name field is required"},
+	}, result.Errors)
+}
+
+// numeric rejects the rows whose column a Transform did not turn into a
+// number.
+func numeric(column string) func(map[string]any) error {
+	return func(row map[string]any) error {
+		switch row[column].(type) {
+		case int, float64:
+			return nil
+		}
+		return fmt.Errorf("%s: %v is not a number", column, row[column])
+	}
+}
+
+func TestImportResult_RowErrors(t *testing.T) {
+	csv := "sku,name,qty\nA1,Apple,3\n\nB2,\"Banana\nsplit\",x\nC3,Cherry,9\n"
+	cfg := DefaultConfig()
+	cfg.Mappings = []ColumnMapping{{SourceColumn: "qty", Transform: func(v string) (any, error) { return strconv.Atoi(v) }}}
+	cfg.ValidateRow = numeric("qty")
+
+	result, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader(csv), func(_ context.Context, row map[string]any) error {
+		if row["sku"] == "C3" {
+			return errors.New("out of stock")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, result.RowErrors, 2)
+
+	// The header, the blank line and the multi-line field are counted.
+	assert.Equal(t, 4, result.RowErrors[0].Line)
+	assert.Equal(t, map[string]any{"sku": "B2", "name": "Banana\nsplit", "qty": "x"}, result.RowErrors[0].Row)
+	assert.ErrorContains(t, result.RowErrors[0].Err, "qty: ")
+	assert.Equal(t, 6, result.RowErrors[1].Line)
+	assert.EqualError(t, result.RowErrors[1].Err, "out of stock")
+
+	var buf bytes.Buffer
+	require.NoError(t, result.WriteErrorCSV(&buf))
+	assert.Equal(t, "sku,name,qty,error\n"+
+		"B2,\"Banana\nsplit\",x,qty: x is not a number\n"+
+		"C3,Cherry,9,out of stock\n", buf.String())
+}
+
+func TestImportFromReader_Concurrency(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("n\n")
+	for n := range 40 {
+		fmt.Fprintf(&b, "%d\n", n)
+	}
+	csv := b.String()
+
+	run := func(concurrency int, wait func()) *ImportResult {
+		cfg := DefaultConfig()
+		cfg.Concurrency = concurrency
+		result, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader(csv), func(_ context.Context, row map[string]any) error {
+			wait()
+			if n, _ := strconv.Atoi(row["n"].(string)); n%10 == 0 {
+				return errors.New("rejected")
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	// With 8 workers, the first 8 rows are in the handler at the same time:
+	// each waits until all of them arrived.
+	var arrived atomic.Int32
+	barrier := make(chan struct{})
+	var overlapped atomic.Bool
+	overlapped.Store(true)
+	barrierWait := func() {
+		n := arrived.Add(1)
+		if n == 8 {
+			close(barrier)
+		}
+		if n > 8 {
+			return
+		}
+		select {
+		case <-barrier:
+		case <-time.After(5 * time.Second):
+			overlapped.Store(false)
+		}
+	}
+
+	sequential := run(1, func() {})
+	concurrent := run(8, barrierWait)
+	assert.True(t, overlapped.Load(), "concurrency=8 should handle rows at the same time")
+	for _, result := range []*ImportResult{sequential, concurrent} {
+		assert.Equal(t, 36, result.SuccessCount)
+		assert.Equal(t, 4, result.ErrorCount)
+		assert.Len(t, result.RowErrors, 4)
+	}
+}
+
+func TestImportFromReader_ConcurrentDedupe(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("email\n")
+	for n := range 40 {
+		fmt.Fprintf(&b, "user%d@example.com\n", n%5)
+	}
+
+	var mu sync.Mutex
+	stored := make(map[string]int)
+	lookups := 0
+	cfg := DefaultConfig()
+	cfg.Concurrency = 8
+	cfg.DedupeKey = []string{"email"}
+	cfg.ExistsFunc = func(_ context.Context, row map[string]any) (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		lookups++
+		return stored[row["email"].(string)] > 0, nil
+	}
+
+	result, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader(b.String()), func(_ context.Context, row map[string]any) error {
+		time.Sleep(5 * time.Millisecond) // leave time for the other workers to race
+		mu.Lock()
+		defer mu.Unlock()
+		stored[row["email"].(string)]++
+		return nil
+	})
+	require.NoError(t, err)
+	for email, n := range stored {
+		assert.Equal(t, 1, n, "%s inserted more than once", email)
+	}
+	assert.Len(t, stored, 5)
+	assert.Equal(t, 5, lookups, "rows sharing a key must wait for the first one")
+	assert.Equal(t, 5, result.SuccessCount)
+	assert.Equal(t, 35, result.SkippedCount)
+	assert.Equal(t, 35, result.DuplicateCount)
+}
+
+func TestImportFromReader_ConcurrencyCancel(t *testing.T) {
+	csv := "n\n" + strings.Repeat("x\n", 100)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	handled := 0
+	cfg := DefaultConfig()
+	cfg.Concurrency = 4
+	_, err := New(cfg).ImportFromReader(ctx, strings.NewReader(csv), func(context.Context, map[string]any) error {
+		mu.Lock()
+		handled++
+		if handled == 10 {
+			cancel()
+		}
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, handled, 20, "workers must stop after cancellation")
+}
+
+func TestImportFromReader_Upsert(t *testing.T) {
+	csv := "email,tenant,name\na@example.com,acme,Ann\na@example.com,globex,Ann G\nb@example.com,acme,Bob\na@example.com,acme,Ann again\n"
+	existing := map[string]string{"a@example.com/acme": "17"}
+
+	var keys []map[string]any
+	cfg := DefaultConfig()
+	cfg.UpsertKey = []string{"email", "tenant"}
+	cfg.OnDuplicate = DuplicateUpdate
+	cfg.FindExisting = func(_ context.Context, key map[string]any) (string, bool, error) {
+		keys = append(keys, key)
+		id, ok := existing[fmt.Sprintf("%s/%s", key["email"], key["tenant"])]
+		return id, ok, nil
+	}
+
+	var ops []string
+	result, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader(csv), func(ctx context.Context, row map[string]any) error {
+		if IsUpdate(ctx) {
+			ops = append(ops, "update "+ExistingID(ctx)+" "+row["name"].(string))
+		} else {
+			ops = append(ops, "insert "+row["name"].(string))
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"update 17 Ann", "insert Ann G", "insert Bob", "update 17 Ann again"}, ops)
+	assert.Len(t, keys, 3, "the repeated composite key must hit the cache")
+	assert.Equal(t, map[string]any{"email": "a@example.com", "tenant": "acme"}, keys[0])
+	assert.Equal(t, 2, result.UpdatedCount)
+	assert.Equal(t, 4, result.SuccessCount)
+}
+
+func TestImportFromReader_UpsertInsertedInFile(t *testing.T) {
+	csv := "email,name\na@example.com,Ann\na@example.com,Ann again\na@example.com,Ann once more\n"
+	var mu sync.Mutex
+	stored := map[string]string{}
+	lookups := 0
+
+	cfg := DefaultConfig()
+	cfg.UpsertKey = []string{"email"}
+	cfg.OnDuplicate = DuplicateUpdate
+	cfg.FindExisting = func(_ context.Context, key map[string]any) (string, bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		lookups++
+		id, ok := stored[key["email"].(string)]
+		return id, ok, nil
+	}
+
+	var ops []string
+	result, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader(csv), func(ctx context.Context, row map[string]any) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if IsUpdate(ctx) {
+			ops = append(ops, "update "+ExistingID(ctx)+" "+row["name"].(string))
+			return nil
+		}
+		stored[row["email"].(string)] = "42"
+		ops = append(ops, "insert "+row["name"].(string))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"insert Ann", "update 42 Ann again", "update 42 Ann once more"}, ops)
+	assert.Equal(t, 2, lookups, "the inserted ID must be looked up once")
+	assert.Equal(t, 2, result.UpdatedCount)
+}
+
+func TestImportFromReader_DedupeKeySeparator(t *testing.T) {
+	csv := "a,b\nx|y,z\nx,y|z\n"
+	cfg := DefaultConfig()
+	cfg.DedupeKey = []string{"a", "b"}
+	cfg.ExistsFunc = func(context.Context, map[string]any) (bool, error) { return false, nil }
+
+	result, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader(csv), collect(new([]map[string]any)))
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.SuccessCount)
+	assert.Equal(t, 0, result.DuplicateCount)
+}
+
+func TestImportFromReader_Encoding(t *testing.T) {
+	collect := func(cfg *ImportConfig, data []byte) []string {
+		t.Helper()
+		var names []string
+		_, err := New(cfg).ImportFromReader(context.Background(), bytes.NewReader(data), func(_ context.Context, row map[string]any) error {
+			names = append(names, row["nom"].(string))
+			return nil
+		})
+		require.NoError(t, err)
+		return names
+	}
+
+	utf8BOM := append([]byte{0xEF, 0xBB, 0xBF}, "nom\nHélène\n"...)
+	assert.Equal(t, []string{"Hélène"}, collect(DefaultConfig(), utf8BOM))
+
+	// "Hélène" and "Zoë" in Windows-1252, as exported by Excel.
+	cp1252 := []byte("nom\nH\xe9l\xe8ne\nZo\xeb\n")
+	assert.Equal(t, []string{"Hélène", "Zoë"}, collect(DefaultConfig(), cp1252))
+
+	cfg := DefaultConfig()
+	cfg.Encoding = "iso-8859-15"
+	assert.Equal(t, []string{"€uro"}, collect(cfg, []byte("nom\n\xa4uro\n")))
+
+	cfg.Encoding = "klingon"
+	_, err := New(cfg).ImportFromReader(context.Background(), bytes.NewReader(cp1252), func(context.Context, map[string]any) error { return nil })
+	assert.ErrorContains(t, err, `unsupported encoding "klingon"`)
+}
+
+func TestImportFromReader_TransformErrorKeepsRawValue(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Mappings = []ColumnMapping{{SourceColumn: "qty", Transform: func(v string) (any, error) { return strconv.Atoi(v) }}}
+
+	var rows []map[string]any
+	result, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader("sku,qty\nA,1\nB,x\n"), func(_ context.Context, row map[string]any) error {
+		rows = append(rows, row)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.SuccessCount)
+	assert.Zero(t, result.ErrorCount)
+	assert.Equal(t, []map[string]any{{"sku": "A", "qty": 1}, {"sku": "B", "qty": "x"}}, rows)
+}
+
+func TestImportFromReader_Delimiter(t *testing.T) {
+	csv := "# export du 01/03\nnom;ville;montant\nDupont;Paris;12,50\nMartin;Le \"Vieux\" Lyon;abc\n"
+	cfg := DefaultConfig()
+	cfg.Delimiter = ';'
+	cfg.Comment = '#'
+	cfg.LazyQuotes = true
+	cfg.Mappings = []ColumnMapping{{SourceColumn: "montant", Transform: func(v string) (any, error) {
+		return strconv.ParseFloat(strings.Replace(v, ",", ".", 1), 64)
+	}}}
+	cfg.ValidateRow = numeric("montant")
+
+	var rows []map[string]any
+	result, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader(csv), func(_ context.Context, row map[string]any) error {
+		rows = append(rows, row)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, map[string]any{"nom": "Dupont", "ville": "Paris", "montant": 12.5}, rows[0])
+	require.Len(t, result.RowErrors, 1)
+	assert.Equal(t, "Le \"Vieux\" Lyon", result.RowErrors[0].Row["ville"])
+
+	var buf bytes.Buffer
+	require.NoError(t, result.WriteErrorCSV(&buf))
+	assert.True(t, strings.HasPrefix(buf.String(), "nom;ville;montant;error\nMartin;\"Le \"\"Vieux\"\" Lyon\";abc;"), buf.String())
+
+	// Without LazyQuotes the stray quotes are a parse error.
+	cfg.LazyQuotes = false
+	_, err = New(cfg).ImportFromReader(context.Background(), strings.NewReader(csv), func(context.Context, map[string]any) error { return nil })
+	assert.Error(t, err)
+}
+
+// recordHandler is a slog.Handler keeping the records it receives.
+type recordHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *recordHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *recordHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *recordHandler) WithGroup(string) slog.Handler            { return h }
+
+func (h *recordHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r)
+	return nil
+}
+
+func TestImportFromReader_TimingsAndSummary(t *testing.T) {
+	logs := &recordHandler{}
+	cfg := DefaultConfig()
+	cfg.Logger = slog.New(logs)
+	cfg.Mappings = []ColumnMapping{{SourceColumn: "qty", Transform: func(v string) (any, error) { return strconv.Atoi(v) }}}
+	cfg.ValidateRow = numeric("qty")
+	cfg.ExistsFunc = func(context.Context, map[string]any) (bool, error) { return false, nil }
+	cfg.BeforeImport = func(row map[string]any) (map[string]any, error) { return row, nil }
+
+	result, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader("name,qty\nA,1\nB,x\nC,3\n"),
+		func(context.Context, map[string]any) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.SuccessCount)
+	assert.Equal(t, 1, result.ErrorCount)
+
+	for _, stage := range []string{StageParse, StageTransform, StageValidate, StageLookup, StageBeforeImport, StageCallback} {
+		assert.Contains(t, result.Timings, stage)
+	}
+
+	var summary *slog.Record
+	for n, r := range logs.records {
+		if r.Message == "import completed" {
+			summary = &logs.records[n]
+		}
+	}
+	require.NotNil(t, summary, "expected the summary log line")
+	assert.Equal(t, slog.LevelInfo, summary.Level)
+	attrs := make(map[string]slog.Value)
+	summary.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value
+		return true
+	})
+	assert.Equal(t, int64(3), attrs["total"].Int64())
+	assert.Equal(t, int64(2), attrs["success"].Int64())
+	assert.Equal(t, int64(1), attrs["errors"].Int64())
+	assert.Contains(t, attrs, StageCallback)
+}
+
+func TestImportFromReader_JSONTransform(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Format = FormatJSON
+	cfg.Mappings = []ColumnMapping{
+		{SourceColumn: "price", Transform: func(v string) (any, error) {
+			return strconv.ParseFloat(strings.Replace(v, ",", ".", 1), 64)
+		}},
+		{SourceColumn: "name", Transform: func(v string) (any, error) { return strings.ToUpper(v), nil }},
+	}
+
+	var rows []map[string]any
+	_, err := New(cfg).ImportFromReader(context.Background(),
+		strings.NewReader(`[{"name": "widget", "price": "12,50"}, {"name": "gadget", "price": 7}]`), collect(&rows))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, map[string]any{"name": "WIDGET", "price": 12.5}, rows[0])
+	// Values that are not strings are left to the handler.
+	assert.Equal(t, map[string]any{"name": "GADGET", "price": 7.0}, rows[1])
+}
+
+func TestImporter_ValidateHeader(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Mappings = []ColumnMapping{
+		{SourceColumn: "email", Required: true},
+		{SourceColumn: "name", Required: true},
+		{SourceColumn: "age"},
+	}
+	imp := New(cfg)
+	assert.NoError(t, imp.ValidateHeader([]string{"name", "email"}))
+	assert.EqualError(t, imp.ValidateHeader([]string{"mail", "age"}), "invalid header: missing required columns: email, name")
+
+	// The check runs before any row reaches the handler.
+	called := false
+	_, err := imp.ImportFromReader(context.Background(), strings.NewReader("name,age\nAnn,31\n"), func(context.Context, map[string]any) error {
+		called = true
+		return nil
+	})
+	assert.EqualError(t, err, "invalid header: missing required columns: email")
+	assert.False(t, called)
+
+	// Headerless imports map column indexes and check the column count.
+	cfg = DefaultConfig()
+	cfg.SkipHeader = false
+	cfg.Mappings = []ColumnMapping{{SourceColumn: "0", Required: true}, {SourceColumn: "2", Required: true}}
+	imp = New(cfg)
+	assert.EqualError(t, imp.ValidateHeader([]string{"Ann", "31"}), "invalid header: expected at least 3 columns, found 2")
+
+	var rows []map[string]any
+	_, err = imp.ImportFromReader(context.Background(), strings.NewReader("Ann,31,ann@example.com\n"), func(_ context.Context, row map[string]any) error {
+		rows = append(rows, row)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"0": "Ann", "1": "31", "2": "ann@example.com"}}, rows)
+
+	cfg.Mappings = []ColumnMapping{{SourceColumn: "email", Required: true}}
+	assert.EqualError(t, New(cfg).ValidateHeader([]string{"Ann"}), `invalid header: column "email" must be an index for imports without header`)
+}
+
+func TestAutoMap(t *testing.T) {
+	type customer struct {
+		FullName string `import:"nom complet"`
+		Email    string `json:"email"`
+		Phone    string
+		Address1 string
+		Address2 string
+		internal string
+	}
+
+	matches := AutoMap([]string{"Nom complet", "E-Mail", "Téléphone", "Phone", "Address", "Notes"}, &customer{})
+	require.Len(t, matches, 6)
+
+	mapped := make(map[string]string)
+	for _, m := range matches {
+		if m.Mapped() {
+			mapped[m.SourceColumn] = m.TargetField
+		} else {
+			assert.Zero(t, m.Confidence, m.SourceColumn)
+		}
+	}
+	assert.Equal(t, map[string]string{"Nom complet": "FullName", "E-Mail": "Email", "Phone": "Phone"}, mapped)
+	assert.Equal(t, 1.0, matches[0].Confidence)
+	assert.Equal(t, 0.95, matches[1].Confidence)
+
+	// Misspellings within the threshold are mapped with a lower confidence.
+	m := AutoMap([]string{"Fulname"}, customer{})[0]
+	assert.Equal(t, "FullName", m.TargetField)
+	assert.Less(t, m.Confidence, 0.95)
+
+	// Mapped values reach the struct through TargetField.
+	cfg := DefaultConfig()
+	for _, m := range AutoMap([]string{"Nom complet", "E-Mail"}, customer{}) {
+		cfg.Mappings = append(cfg.Mappings, m.ColumnMapping)
+	}
+	var got customer
+	_, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader("Nom complet,E-Mail\nAnn Lee,ann@example.com\n"), func(_ context.Context, row map[string]any) error {
+		return MapToStruct(row, &got)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, customer{FullName: "Ann Lee", Email: "ann@example.com"}, got)
+}
+
+type txKey struct{}
+
+// fakeTx records the rows written inside the transaction and keeps them only
+// on commit.
+type fakeTx struct {
+	pending, committed []string
+	rolledBack         bool
+}
+
+func (tx *fakeTx) BeginTx(ctx context.Context) (context.Context, error) {
+	return context.WithValue(ctx, txKey{}, tx), nil
+}
+
+func (tx *fakeTx) Commit(context.Context) error {
+	tx.committed = append(tx.committed, tx.pending...)
+	return nil
+}
+
+func (tx *fakeTx) Rollback(context.Context) error {
+	tx.rolledBack = true
+	return nil
+}
+
+func TestImportFromReader_AbortAfterErrors(t *testing.T) {
+	csv := "sku,qty\nA,1\nB,x\nC,3\nD,y\nE,z\nF,6\n"
+	run := func(abortAfter int) (*ImportResult, *fakeTx) {
+		t.Helper()
+		tx := &fakeTx{}
+		cfg := DefaultConfig()
+		cfg.Tx = tx
+		cfg.AbortAfterErrors = abortAfter
+		cfg.Mappings = []ColumnMapping{{SourceColumn: "qty", Transform: func(v string) (any, error) { return strconv.Atoi(v) }}}
+		cfg.ValidateRow = numeric("qty")
+		result, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader(csv), func(ctx context.Context, row map[string]any) error {
+			ctx.Value(txKey{}).(*fakeTx).pending = append(tx.pending, row["sku"].(string))
+			return nil
+		})
+		require.NoError(t, err)
+		return result, tx
+	}
+
+	result, tx := run(3)
+	assert.False(t, result.RolledBack)
+	assert.False(t, tx.rolledBack)
+	assert.Equal(t, []string{"A", "C", "F"}, tx.committed)
+	assert.Equal(t, 3, result.ErrorCount)
+
+	// The third failure exceeds the threshold: the run stops and nothing is kept.
+	result, tx = run(2)
+	assert.True(t, result.RolledBack)
+	assert.True(t, tx.rolledBack)
+	assert.Empty(t, tx.committed)
+	assert.Equal(t, 3, result.ErrorCount)
+	assert.Equal(t, 2, result.SuccessCount, "F is never reached")
+}
+
+func TestImportFromReader_OnProgress(t *testing.T) {
+	csv := "n\n1\n2\n3\n4\n5\n"
+	run := func(interval time.Duration, concurrency int) [][2]int {
+		t.Helper()
+		var mu sync.Mutex
+		var calls [][2]int
+		cfg := DefaultConfig()
+		cfg.Concurrency = concurrency
+		cfg.ProgressInterval = interval
+		cfg.OnProgress = func(processed, total int) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, [2]int{processed, total})
+		}
+		_, err := New(cfg).ImportFromReader(context.Background(), strings.NewReader(csv), func(context.Context, map[string]any) error { return nil })
+		require.NoError(t, err)
+		return calls
+	}
+
+	// Throttled: the first row, then the final count.
+	assert.Equal(t, [][2]int{{1, 5}, {5, 5}}, run(time.Hour, 1))
+	assert.Equal(t, [][2]int{{1, 5}, {5, 5}}, run(time.Hour, 3))
+	// Unthrottled: every row, without repeating the final count.
+	assert.Equal(t, [][2]int{{1, 5}, {2, 5}, {3, 5}, {4, 5}, {5, 5}}, run(time.Nanosecond, 1))
+}
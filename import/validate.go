@@ -0,0 +1,61 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/bozz33/sublimego/validation"
+)
+
+// FieldErrors reports the invalid fields of a row, keyed by field name. When
+// ValidateRow returns it, each field becomes an ImportError of the row.
+type FieldErrors map[string]string
+
+func (e FieldErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, field := range slices.Sorted(maps.Keys(e)) {
+		msgs = append(msgs, field+": "+e[field])
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ValidateStruct returns a ValidateRow function that copies each row onto a
+// new T with MapToStruct and checks it with validation.ValidateStruct, so
+// the `validate` tags of T apply to imported rows. With DryRun a whole file
+// is validated this way before anything is imported:
+//
+//	config.ValidateRow = importer.ValidateStruct[UserRow]()
+//	config.DryRun = true
+func ValidateStruct[T any]() func(row map[string]any) error {
+	return func(row map[string]any) error {
+		var item T
+		if err := MapToStruct(row, &item); err != nil {
+			return err
+		}
+		if errs := validation.ValidateStruct(&item); errs != nil {
+			return FieldErrors(errs)
+		}
+		return nil
+	}
+}
+
+// fieldErrorDetails returns the ImportErrors of a FieldErrors err, nil for
+// other errors.
+func fieldErrorDetails(err error, line int, row map[string]any) []ImportError {
+	var fe FieldErrors
+	if !errors.As(err, &fe) {
+		return nil
+	}
+	details := make([]ImportError, 0, len(fe))
+	for _, field := range slices.Sorted(maps.Keys(fe)) {
+		value := ""
+		if v, ok := row[field]; ok && v != nil {
+			value = fmt.Sprint(v)
+		}
+		details = append(details, ImportError{Row: line, Column: field, Value: value, Message: fe[field]})
+	}
+	return details
+}
@@ -0,0 +1,220 @@
+package infolist
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowedTags maps the tags kept by SanitizeHTML to their allowed attributes.
+var allowedTags = map[string][]string{
+	"a": {"href", "title"}, "img": {"src", "alt", "title"},
+	"p": nil, "br": nil, "hr": nil, "span": nil, "div": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"strong": nil, "b": nil, "em": nil, "i": nil, "u": nil, "s": nil, "del": nil,
+	"ul": nil, "ol": nil, "li": nil, "blockquote": nil, "code": nil, "pre": nil,
+	"table": nil, "thead": nil, "tbody": nil, "tr": nil, "th": nil, "td": nil,
+}
+
+// droppedTags are removed along with their content.
+var droppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"template": true, "noscript": true, "textarea": true, "select": true,
+}
+
+// voidTags are the allowed tags without an end tag.
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+// SanitizeHTML keeps the formatting tags of s and drops everything else:
+// scripts and styles with their content, event handlers, style attributes
+// and links to other schemes than http, https and mailto. Text is escaped.
+// s is parsed as an HTML fragment, so the output is always well nested:
+// unclosed tags are closed and stray end tags dropped.
+func SanitizeHTML(s string) string {
+	body := &xhtml.Node{Type: xhtml.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := xhtml.ParseFragment(strings.NewReader(s), body)
+	if err != nil {
+		return html.EscapeString(s)
+	}
+	var b strings.Builder
+	for _, n := range nodes {
+		writeSanitized(&b, n)
+	}
+	return b.String()
+}
+
+// writeSanitized writes the allowed parts of n and its descendants to b.
+// Disallowed elements are unwrapped, keeping their content.
+func writeSanitized(b *strings.Builder, n *xhtml.Node) {
+	switch n.Type {
+	case xhtml.TextNode:
+		b.WriteString(html.EscapeString(n.Data))
+		return
+	case xhtml.ElementNode:
+	default:
+		return
+	}
+	if droppedTags[n.Data] {
+		return
+	}
+	attrs, allowed := allowedTags[n.Data]
+	if allowed {
+		b.WriteString("<" + n.Data)
+		for _, a := range n.Attr {
+			if a.Namespace != "" || !contains(attrs, a.Key) || ((a.Key == "href" || a.Key == "src") && !safeURL(a.Val)) {
+				continue
+			}
+			b.WriteString(" " + a.Key + `="` + html.EscapeString(a.Val) + `"`)
+		}
+		if n.Data == "a" {
+			b.WriteString(` rel="noopener noreferrer"`)
+		}
+		b.WriteString(">")
+		if voidTags[n.Data] {
+			return
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeSanitized(b, c)
+	}
+	if allowed {
+		b.WriteString("</" + n.Data + ">")
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// safeURL reports whether a link target is relative or uses the http,
+// https or mailto scheme.
+func safeURL(u string) bool {
+	u = strings.ToLower(strings.TrimSpace(u))
+	scheme, _, found := strings.Cut(u, ":")
+	if !found || strings.ContainsAny(scheme, "/?#") {
+		return true
+	}
+	return scheme == "http" || scheme == "https" || scheme == "mailto"
+}
+
+var (
+	mdHeading = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	mdOrdered = regexp.MustCompile(`^\d+[.)]\s+(.*)$`)
+	mdCode    = regexp.MustCompile("`([^`]+)`")
+	mdLink    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdBold    = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdItalic  = regexp.MustCompile(`\*([^*]+)\*|\b_([^_]+)_\b`)
+)
+
+// RenderMarkdown converts Markdown to HTML: headings, paragraphs, lists,
+// block quotes, fenced code, inline code, emphasis and links. Raw HTML in
+// the source is escaped, and the result goes through SanitizeHTML.
+func RenderMarkdown(src string) string {
+	var b strings.Builder
+	var para []string
+	list := "" // "ul" or "ol" while inside a list
+	inCode := false
+
+	flushPara := func() {
+		if len(para) > 0 {
+			b.WriteString("<p>" + mdInline(strings.Join(para, " ")) + "</p>")
+			para = nil
+		}
+	}
+	closeList := func() {
+		if list != "" {
+			b.WriteString("</" + list + ">")
+			list = ""
+		}
+	}
+	openList := func(tag string) {
+		if list != tag {
+			closeList()
+			b.WriteString("<" + tag + ">")
+			list = tag
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			flushPara()
+			closeList()
+			if inCode {
+				b.WriteString("</code></pre>")
+			} else {
+				b.WriteString("<pre><code>")
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			b.WriteString(html.EscapeString(line) + "\n")
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flushPara()
+			closeList()
+		case mdHeading.MatchString(trimmed):
+			flushPara()
+			closeList()
+			m := mdHeading.FindStringSubmatch(trimmed)
+			tag := "h" + strconv.Itoa(len(m[1]))
+			b.WriteString("<" + tag + ">" + mdInline(m[2]) + "</" + tag + ">")
+		case trimmed == "---" || trimmed == "***":
+			flushPara()
+			closeList()
+			b.WriteString("<hr>")
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") || strings.HasPrefix(trimmed, "+ "):
+			flushPara()
+			openList("ul")
+			b.WriteString("<li>" + mdInline(trimmed[2:]) + "</li>")
+		case mdOrdered.MatchString(trimmed):
+			flushPara()
+			openList("ol")
+			b.WriteString("<li>" + mdInline(mdOrdered.FindStringSubmatch(trimmed)[1]) + "</li>")
+		case strings.HasPrefix(trimmed, ">"):
+			flushPara()
+			closeList()
+			b.WriteString("<blockquote>" + mdInline(strings.TrimSpace(trimmed[1:])) + "</blockquote>")
+		default:
+			closeList()
+			para = append(para, trimmed)
+		}
+	}
+	flushPara()
+	closeList()
+	if inCode {
+		b.WriteString("</code></pre>")
+	}
+	return SanitizeHTML(b.String())
+}
+
+// mdInline renders the inline Markdown of a line of text.
+func mdInline(s string) string {
+	s = html.EscapeString(s)
+	// Keep code spans out of the emphasis and link rules.
+	var spans []string
+	s = mdCode.ReplaceAllStringFunc(s, func(m string) string {
+		spans = append(spans, "<code>"+m[1:len(m)-1]+"</code>")
+		return "\x00" + strconv.Itoa(len(spans)-1) + "\x00"
+	})
+	s = mdLink.ReplaceAllString(s, `<a href="$2">$1</a>`)
+	s = mdBold.ReplaceAllString(s, "<strong>$1$2</strong>")
+	s = mdItalic.ReplaceAllString(s, "<em>$1$2</em>")
+	for i, span := range spans {
+		s = strings.Replace(s, "\x00"+strconv.Itoa(i)+"\x00", span, 1)
+	}
+	return s
+}
@@ -16,6 +16,12 @@ const (
 	EntryTypeIcon     EntryType = "icon"
 	EntryTypeList     EntryType = "list"
 	EntryTypeLink     EntryType = "link"
+	EntryTypeURL      EntryType = "url"
+	EntryTypeMarkdown EntryType = "markdown"
+	EntryTypeHTML     EntryType = "html"
+	// EntryTypeRepeatable renders a list of related records, one block of
+	// entries per record (see RepeatableEntry).
+	EntryTypeRepeatable EntryType = "repeatable"
 )
 
 // Entry is a single read-only field in an Infolist.
@@ -29,10 +35,17 @@ type Entry struct {
 	IconColor  string   // for EntryTypeIcon (Tailwind color name)
 	ListItems  []string // for EntryTypeList
 	LinkURL    string   // for EntryTypeLink
-	LinkTarget string   // for EntryTypeLink ("_blank" etc.)
+	LinkTarget string   // for EntryTypeLink and EntryTypeURL ("_blank" etc.)
 	IsCopyable bool
 	Hidden     bool
 	HelpText   string
+	// ValueFunc derives the value from the record at render time (see ComputedEntry).
+	ValueFunc func(item any) any
+	// Items and ItemEntries are the records and the entry builder of
+	// EntryTypeRepeatable; EmptyText is shown when there are no items.
+	Items       []any
+	ItemEntries func(item any) []*Entry
+	EmptyText   string
 }
 
 // Label returns the display label.
@@ -46,15 +59,63 @@ func (e *Entry) ValueStr() string {
 	return fmt.Sprintf("%v", e.Value)
 }
 
+// HTML returns the formatted content of a Markdown or HTML entry as
+// sanitized HTML, safe to render unescaped.
+func (e *Entry) HTML() string {
+	switch e.Type {
+	case EntryTypeMarkdown:
+		return RenderMarkdown(e.ValueStr())
+	case EntryTypeHTML:
+		return SanitizeHTML(e.ValueStr())
+	default:
+		return ""
+	}
+}
+
 // IsVisible returns true if the entry should be displayed.
 func (e *Entry) IsVisible() bool { return !e.Hidden }
 
+// IsComputed returns true if the value is derived from the record.
+func (e *Entry) IsComputed() bool { return e.ValueFunc != nil }
+
+// ForRecord returns the entry to render for record. Computed entries return
+// a copy holding the derived value; static entries are returned unchanged.
+func (e *Entry) ForRecord(record any) *Entry {
+	if e.ValueFunc == nil {
+		return e
+	}
+	resolved := *e
+	resolved.Value = e.ValueFunc(record)
+	return &resolved
+}
+
+// RepeatedEntries returns the visible entries of each item of a repeatable
+// entry, computed entries being resolved against their item.
+func (e *Entry) RepeatedEntries() [][]*Entry {
+	if e.ItemEntries == nil {
+		return nil
+	}
+	blocks := make([][]*Entry, 0, len(e.Items))
+	for _, item := range e.Items {
+		var block []*Entry
+		for _, entry := range e.ItemEntries(item) {
+			if entry != nil && entry.IsVisible() {
+				block = append(block, entry.ForRecord(item))
+			}
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
 // Section groups entries under a heading.
 type Section struct {
 	Heading     string
 	Description string
 	Columns     int // 1, 2, or 3 — default 2
 	Entries     []*Entry
+	CanCollapse bool
+	Collapsed   bool // initial state of a collapsible section
 }
 
 // NewSection creates a new section with a heading.
@@ -74,6 +135,20 @@ func (s *Section) WithColumns(n int) *Section {
 	return s
 }
 
+// Collapsible lets the section be expanded and collapsed. It renders as a
+// <details> element, so toggling works without JavaScript.
+func (s *Section) Collapsible() *Section {
+	s.CanCollapse = true
+	return s
+}
+
+// Collapse makes the section collapsible and collapsed at first.
+func (s *Section) Collapse() *Section {
+	s.CanCollapse = true
+	s.Collapsed = true
+	return s
+}
+
 // Add appends entries to the section.
 func (s *Section) Add(entries ...*Entry) *Section {
 	s.Entries = append(s.Entries, entries...)
@@ -83,6 +158,11 @@ func (s *Section) Add(entries ...*Entry) *Section {
 // Infolist is the top-level container for a read-only detail view.
 type Infolist struct {
 	Sections []*Section
+	Record   any // the record being displayed, passed to computed entries
+	// StatRows are rows of stat cards shown above the sections (see StatGroup).
+	StatRows []*StatRow
+	// Tabs are groups of sections shown as tabs below the sections.
+	Tabs []*InfolistTabs
 }
 
 // New creates an empty Infolist.
@@ -90,6 +170,12 @@ func New() *Infolist {
 	return &Infolist{}
 }
 
+// WithRecord sets the record computed entries derive their values from.
+func (il *Infolist) WithRecord(record any) *Infolist {
+	il.Record = record
+	return il
+}
+
 // AddSection appends a section and returns the Infolist for chaining.
 func (il *Infolist) AddSection(s *Section) *Infolist {
 	if s.Columns == 0 {
@@ -99,6 +185,69 @@ func (il *Infolist) AddSection(s *Section) *Infolist {
 	return il
 }
 
+// AddTabs appends a group of tabbed sections.
+func (il *Infolist) AddTabs(t *InfolistTabs) *Infolist {
+	il.Tabs = append(il.Tabs, t)
+	return il
+}
+
+// InfolistTabs groups sections into tabs. Without JavaScript the tabs are
+// rendered one after the other under their label.
+type InfolistTabs struct {
+	Items  []InfolistTab
+	Active int // index of the tab shown first
+}
+
+// InfolistTab is a tab of sections.
+type InfolistTab struct {
+	Label    string
+	Icon     string
+	Sections []*Section
+}
+
+// NewTabs creates an empty group of tabs.
+//
+//	infolist.NewTabs().
+//		Tab("Profile", profile, contact).
+//		Tab("Billing", billing).WithIcon("payments")
+func NewTabs() *InfolistTabs {
+	return &InfolistTabs{}
+}
+
+// Tab adds a tab holding sections.
+func (t *InfolistTabs) Tab(label string, sections ...*Section) *InfolistTabs {
+	for _, s := range sections {
+		if s.Columns == 0 {
+			s.Columns = 2
+		}
+	}
+	t.Items = append(t.Items, InfolistTab{Label: label, Sections: sections})
+	return t
+}
+
+// WithIcon sets the icon of the last added tab.
+func (t *InfolistTabs) WithIcon(icon string) *InfolistTabs {
+	if len(t.Items) > 0 {
+		t.Items[len(t.Items)-1].Icon = icon
+	}
+	return t
+}
+
+// WithActive sets the tab shown first.
+func (t *InfolistTabs) WithActive(index int) *InfolistTabs {
+	t.Active = index
+	return t
+}
+
+// ActiveTab returns the index of the tab shown first, 0 when Active is out
+// of range.
+func (t *InfolistTabs) ActiveTab() int {
+	if t.Active < 0 || t.Active >= len(t.Items) {
+		return 0
+	}
+	return t.Active
+}
+
 // --- Fluent entry builders ---
 
 // TextEntry creates a plain text entry.
@@ -149,7 +298,70 @@ func LinkEntry(name, label string, url string, displayText string) *Entry {
 	return &Entry{Name: name, LabelStr: label, Value: displayText, Type: EntryTypeLink, LinkURL: url}
 }
 
-// OpenInNewTab makes a LinkEntry open in a new tab.
+// URLEntry creates an entry showing its value as a clickable link to itself.
+// Links to other schemes than http, https and mailto are not followed.
+func URLEntry(name, label string, value any) *Entry {
+	return &Entry{Name: name, LabelStr: label, Value: value, Type: EntryTypeURL}
+}
+
+// MarkdownEntry creates an entry rendering its Markdown value as formatted
+// text. Raw HTML in the value is escaped.
+func MarkdownEntry(name, label string, value any) *Entry {
+	return &Entry{Name: name, LabelStr: label, Value: value, Type: EntryTypeMarkdown}
+}
+
+// HTMLEntry creates an entry rendering its HTML value, sanitized to keep
+// formatting tags only (see SanitizeHTML).
+func HTMLEntry(name, label string, value any) *Entry {
+	return &Entry{Name: name, LabelStr: label, Value: value, Type: EntryTypeHTML}
+}
+
+// ComputedEntry creates an entry whose value is derived from the record at
+// render time, e.g. a full name or an age computed from a birth date:
+//
+//	infolist.ComputedEntry("Age", func(item any) any {
+//		return age(item.(*User).BirthDate)
+//	}, infolist.EntryTypeText)
+func ComputedEntry(label string, fn func(item any) any, entryType EntryType) *Entry {
+	if entryType == "" {
+		entryType = EntryTypeText
+	}
+	e := &Entry{Name: label, LabelStr: label, Type: entryType, ValueFunc: fn}
+	if entryType == EntryTypeDate {
+		e.Format = "2006-01-02"
+	}
+	return e
+}
+
+// RepeatableEntry creates an entry listing related records, such as the
+// orders of a user. Each item is rendered as its own grid of the entries
+// built by entryBuilder, laid out with the columns of the section:
+//
+//	infolist.RepeatableEntry("orders", "Orders", orders, func(item any) []*infolist.Entry {
+//		o := item.(*ent.Order)
+//		return []*infolist.Entry{
+//			infolist.TextEntry("number", "Number", o.Number),
+//			infolist.BadgeEntry("status", "Status", o.Status, "blue"),
+//		}
+//	})
+func RepeatableEntry(name, label string, items []any, entryBuilder func(item any) []*Entry) *Entry {
+	return &Entry{
+		Name:        name,
+		LabelStr:    label,
+		Type:        EntryTypeRepeatable,
+		Items:       items,
+		ItemEntries: entryBuilder,
+		EmptyText:   "No items",
+	}
+}
+
+// WithEmptyText sets the placeholder of a RepeatableEntry without items.
+func (e *Entry) WithEmptyText(text string) *Entry {
+	e.EmptyText = text
+	return e
+}
+
+// OpenInNewTab makes a LinkEntry or URLEntry open in a new tab.
 func (e *Entry) OpenInNewTab() *Entry {
 	e.LinkTarget = "_blank"
 	return e
@@ -101,3 +101,170 @@ func TestEntryHelp(t *testing.T) {
 	e := TextEntry("x", "X", "val").Help("Some help text")
 	assert.Equal(t, "Some help text", e.HelpText)
 }
+
+func TestComputedEntry(t *testing.T) {
+	type person struct{ First, Last string }
+	e := ComputedEntry("Full Name", func(item any) any {
+		p := item.(person)
+		return p.First + " " + p.Last
+	}, EntryTypeText)
+
+	assert.True(t, e.IsComputed())
+	assert.Equal(t, "Full Name", e.Label())
+	assert.Nil(t, e.Value)
+
+	resolved := e.ForRecord(person{First: "Ada", Last: "Lovelace"})
+	assert.Equal(t, "Ada Lovelace", resolved.ValueStr())
+	assert.Nil(t, e.Value, "ForRecord must not mutate the shared entry")
+}
+
+func TestComputedEntryDefaults(t *testing.T) {
+	assert.Equal(t, EntryTypeText, ComputedEntry("X", func(any) any { return 1 }, "").Type)
+	assert.Equal(t, "2006-01-02", ComputedEntry("Born", func(any) any { return nil }, EntryTypeDate).Format)
+}
+
+func TestStaticEntryForRecord(t *testing.T) {
+	e := TextEntry("name", "Name", "John")
+	assert.False(t, e.IsComputed())
+	assert.Same(t, e, e.ForRecord("ignored"))
+}
+
+func TestWithRecord(t *testing.T) {
+	il := New().WithRecord(42)
+	assert.Equal(t, 42, il.Record)
+}
+
+func TestStatGroup(t *testing.T) {
+	type order struct {
+		Total    float64
+		Refunded bool
+	}
+	row := StatGroup(
+		NewStat("Items", 3).WithIcon("inventory_2").WithColor("primary"),
+		ComputedStat("Total", func(r any) any { return r.(order).Total }),
+		NewStat("Refund", "yes").VisibleWhen(func(r any) bool { return r.(order).Refunded }),
+		NewStat("Internal", 1).Hide(true),
+	)
+	il := New().WithRecord(order{Total: 42.5}).WithStats(row)
+	assert.Len(t, il.StatRows, 1)
+
+	stats := row.Visible(il.Record)
+	assert.Len(t, stats, 2)
+	assert.Equal(t, "Items", stats[0].Label())
+	assert.Equal(t, "inventory_2", stats[0].Icon)
+	assert.Equal(t, "primary", stats[0].Color)
+	assert.Equal(t, "42.5", stats[1].ValueStr())
+
+	assert.Len(t, row.Visible(order{Refunded: true}), 3)
+}
+
+func TestRepeatableEntry(t *testing.T) {
+	type order struct {
+		Number string
+		Total  int
+	}
+	items := []any{order{"A-1", 10}, order{"A-2", 25}}
+	e := RepeatableEntry("orders", "Orders", items, func(item any) []*Entry {
+		o := item.(order)
+		return []*Entry{
+			TextEntry("number", "Number", o.Number),
+			ComputedEntry("Total", func(item any) any { return item.(order).Total * 2 }, EntryTypeText),
+			TextEntry("internal", "Internal", "x").Hide(true),
+		}
+	})
+	assert.Equal(t, EntryTypeRepeatable, e.Type)
+	assert.Equal(t, "No items", e.EmptyText)
+
+	blocks := e.RepeatedEntries()
+	assert.Len(t, blocks, 2)
+	assert.Len(t, blocks[0], 2)
+	assert.Equal(t, "A-1", blocks[0][0].ValueStr())
+	assert.Equal(t, "50", blocks[1][1].ValueStr())
+}
+
+func TestRepeatableEntryEmpty(t *testing.T) {
+	e := RepeatableEntry("orders", "Orders", nil, func(item any) []*Entry { return nil }).
+		WithEmptyText("No orders yet")
+	assert.Empty(t, e.RepeatedEntries())
+	assert.Equal(t, "No orders yet", e.EmptyText)
+}
+
+func TestSectionCollapsible(t *testing.T) {
+	s := NewSection("Details").Collapsible()
+	assert.True(t, s.CanCollapse)
+	assert.False(t, s.Collapsed)
+
+	s = NewSection("Audit").Collapse()
+	assert.True(t, s.CanCollapse)
+	assert.True(t, s.Collapsed)
+}
+
+func TestTabs(t *testing.T) {
+	tabs := NewTabs().
+		Tab("Profile", NewSection("Identity"), &Section{Heading: "Contact"}).
+		Tab("Billing", NewSection("Invoices")).WithIcon("payments").
+		WithActive(1)
+	il := New().AddTabs(tabs)
+
+	assert.Len(t, il.Tabs, 1)
+	assert.Len(t, tabs.Items, 2)
+	assert.Equal(t, "payments", tabs.Items[1].Icon)
+	assert.Empty(t, tabs.Items[0].Icon)
+	assert.Equal(t, 2, tabs.Items[0].Sections[1].Columns)
+	assert.Equal(t, 1, tabs.ActiveTab())
+
+	tabs.WithActive(5)
+	assert.Equal(t, 0, tabs.ActiveTab())
+}
+
+func TestURLEntry(t *testing.T) {
+	e := URLEntry("website", "Website", "https://example.com").OpenInNewTab()
+	assert.Equal(t, EntryTypeURL, e.Type)
+	assert.Equal(t, "https://example.com", e.ValueStr())
+	assert.Equal(t, "_blank", e.LinkTarget)
+}
+
+func TestMarkdownEntry(t *testing.T) {
+	e := MarkdownEntry("bio", "Bio", "# Hello\n\nSome **bold** and `code` with a [link](https://example.com).\n\n- one\n- two\n\n<script>alert(1)</script>")
+	assert.Equal(t, EntryTypeMarkdown, e.Type)
+
+	html := e.HTML()
+	assert.Contains(t, html, "<h1>Hello</h1>")
+	assert.Contains(t, html, "<strong>bold</strong>")
+	assert.Contains(t, html, "<code>code</code>")
+	assert.Contains(t, html, `<a href="https://example.com" rel="noopener noreferrer">link</a>`)
+	assert.Contains(t, html, "<ul><li>one</li><li>two</li></ul>")
+	assert.NotContains(t, html, "<script>")
+	assert.Contains(t, html, "&lt;script&gt;")
+}
+
+func TestMarkdownEntryUnsafeLink(t *testing.T) {
+	html := MarkdownEntry("bio", "Bio", "[click](javascript:alert(1))").HTML()
+	assert.NotContains(t, html, "javascript:")
+}
+
+func TestHTMLEntrySanitizes(t *testing.T) {
+	e := HTMLEntry("body", "Body", `<p onclick="steal()" style="color:red">Hi <b>there</b></p><script>alert(1)</script><a href="javascript:alert(1)">x</a><img src="/logo.png" onerror="x()">`)
+	assert.Equal(t, EntryTypeHTML, e.Type)
+	assert.Equal(t, `<p>Hi <b>there</b></p><a rel="noopener noreferrer">x</a><img src="/logo.png">`, e.HTML())
+}
+
+func TestHTMLEntryBalancesTags(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"stray end tags", `</div></p>text</b>`, `<p></p>text`},
+		{"unclosed tags", `<div><b>bold <em>both`, `<div><b>bold <em>both</em></b></div>`},
+		{"misnested tags", `<b><i>x</b>y</i>`, `<b><i>x</i></b><i>y</i>`},
+		{"end tag of a dropped tag", `<p>a</script>b</p>`, `<p>ab</p>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SanitizeHTML(tt.in))
+		})
+	}
+}
+
+func TestHTMLOfOtherEntries(t *testing.T) {
+	assert.Empty(t, TextEntry("name", "Name", "<b>x</b>").HTML())
+}
@@ -0,0 +1,112 @@
+package infolist
+
+import "fmt"
+
+// Stat is a headline metric shown as a card above the sections of a detail
+// view, e.g. an order total or an item count.
+type Stat struct {
+	LabelStr    string
+	Value       any
+	Icon        string // Material Icons Outlined name
+	Color       string // primary, success, danger, warning, info...
+	Description string
+	Hidden      bool
+	// ValueFunc derives the value from the record at render time.
+	ValueFunc func(record any) any
+	// VisibleFunc hides the stat for records it returns false for.
+	VisibleFunc func(record any) bool
+}
+
+// NewStat creates a stat card with a static value.
+func NewStat(label string, value any) Stat {
+	return Stat{LabelStr: label, Value: value}
+}
+
+// ComputedStat creates a stat card whose value is derived from the record.
+func ComputedStat(label string, fn func(record any) any) Stat {
+	return Stat{LabelStr: label, ValueFunc: fn}
+}
+
+// Label returns the display label.
+func (s Stat) Label() string { return s.LabelStr }
+
+// ValueStr returns the value as a string.
+func (s Stat) ValueStr() string {
+	if s.Value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", s.Value)
+}
+
+// WithIcon sets the card icon.
+func (s Stat) WithIcon(icon string) Stat {
+	s.Icon = icon
+	return s
+}
+
+// WithColor sets the icon color.
+func (s Stat) WithColor(color string) Stat {
+	s.Color = color
+	return s
+}
+
+// WithDescription adds a line of text below the value.
+func (s Stat) WithDescription(desc string) Stat {
+	s.Description = desc
+	return s
+}
+
+// Hide hides the stat conditionally.
+func (s Stat) Hide(hidden bool) Stat {
+	s.Hidden = hidden
+	return s
+}
+
+// VisibleWhen shows the stat only for records fn returns true for.
+func (s Stat) VisibleWhen(fn func(record any) bool) Stat {
+	s.VisibleFunc = fn
+	return s
+}
+
+// IsVisible returns true if the stat should be displayed for record.
+func (s Stat) IsVisible(record any) bool {
+	if s.Hidden {
+		return false
+	}
+	return s.VisibleFunc == nil || s.VisibleFunc(record)
+}
+
+// ForRecord returns the stat with its value resolved for record.
+func (s Stat) ForRecord(record any) Stat {
+	if s.ValueFunc != nil {
+		s.Value = s.ValueFunc(record)
+	}
+	return s
+}
+
+// StatRow is a row of stat cards rendered above the sections.
+type StatRow struct {
+	Stats []Stat
+}
+
+// StatGroup creates a row of stat cards. Add it with Infolist.WithStats.
+func StatGroup(stats ...Stat) *StatRow {
+	return &StatRow{Stats: stats}
+}
+
+// Visible returns the stats to render for record, with values resolved.
+func (r *StatRow) Visible(record any) []Stat {
+	out := make([]Stat, 0, len(r.Stats))
+	for _, s := range r.Stats {
+		if s.IsVisible(record) {
+			out = append(out, s.ForRecord(record))
+		}
+	}
+	return out
+}
+
+// WithStats appends a row of stat cards, rendered above the sections.
+func (il *Infolist) WithStats(row *StatRow) *Infolist {
+	il.StatRows = append(il.StatRows, row)
+	return il
+}
@@ -0,0 +1,453 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/bozz33/sublimego/internal/ent/auditlog"
+	"github.com/bozz33/sublimego/internal/ent/predicate"
+)
+
+// AuditLogUpdate is the builder for updating AuditLog entities.
+type AuditLogUpdate struct {
+	config
+	hooks    []Hook
+	mutation *AuditLogMutation
+}
+
+// Where appends a list predicates to the AuditLogUpdate builder.
+func (_u *AuditLogUpdate) Where(ps ...predicate.AuditLog) *AuditLogUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetActorID sets the "actor_id" field.
+func (_u *AuditLogUpdate) SetActorID(v int) *AuditLogUpdate {
+	_u.mutation.ResetActorID()
+	_u.mutation.SetActorID(v)
+	return _u
+}
+
+// SetNillableActorID sets the "actor_id" field if the given value is not nil.
+func (_u *AuditLogUpdate) SetNillableActorID(v *int) *AuditLogUpdate {
+	if v != nil {
+		_u.SetActorID(*v)
+	}
+	return _u
+}
+
+// AddActorID adds value to the "actor_id" field.
+func (_u *AuditLogUpdate) AddActorID(v int) *AuditLogUpdate {
+	_u.mutation.AddActorID(v)
+	return _u
+}
+
+// ClearActorID clears the value of the "actor_id" field.
+func (_u *AuditLogUpdate) ClearActorID() *AuditLogUpdate {
+	_u.mutation.ClearActorID()
+	return _u
+}
+
+// SetActorName sets the "actor_name" field.
+func (_u *AuditLogUpdate) SetActorName(v string) *AuditLogUpdate {
+	_u.mutation.SetActorName(v)
+	return _u
+}
+
+// SetNillableActorName sets the "actor_name" field if the given value is not nil.
+func (_u *AuditLogUpdate) SetNillableActorName(v *string) *AuditLogUpdate {
+	if v != nil {
+		_u.SetActorName(*v)
+	}
+	return _u
+}
+
+// ClearActorName clears the value of the "actor_name" field.
+func (_u *AuditLogUpdate) ClearActorName() *AuditLogUpdate {
+	_u.mutation.ClearActorName()
+	return _u
+}
+
+// SetResource sets the "resource" field.
+func (_u *AuditLogUpdate) SetResource(v string) *AuditLogUpdate {
+	_u.mutation.SetResource(v)
+	return _u
+}
+
+// SetNillableResource sets the "resource" field if the given value is not nil.
+func (_u *AuditLogUpdate) SetNillableResource(v *string) *AuditLogUpdate {
+	if v != nil {
+		_u.SetResource(*v)
+	}
+	return _u
+}
+
+// SetRecordID sets the "record_id" field.
+func (_u *AuditLogUpdate) SetRecordID(v string) *AuditLogUpdate {
+	_u.mutation.SetRecordID(v)
+	return _u
+}
+
+// SetNillableRecordID sets the "record_id" field if the given value is not nil.
+func (_u *AuditLogUpdate) SetNillableRecordID(v *string) *AuditLogUpdate {
+	if v != nil {
+		_u.SetRecordID(*v)
+	}
+	return _u
+}
+
+// SetAction sets the "action" field.
+func (_u *AuditLogUpdate) SetAction(v string) *AuditLogUpdate {
+	_u.mutation.SetAction(v)
+	return _u
+}
+
+// SetNillableAction sets the "action" field if the given value is not nil.
+func (_u *AuditLogUpdate) SetNillableAction(v *string) *AuditLogUpdate {
+	if v != nil {
+		_u.SetAction(*v)
+	}
+	return _u
+}
+
+// SetChanges sets the "changes" field.
+func (_u *AuditLogUpdate) SetChanges(v string) *AuditLogUpdate {
+	_u.mutation.SetChanges(v)
+	return _u
+}
+
+// SetNillableChanges sets the "changes" field if the given value is not nil.
+func (_u *AuditLogUpdate) SetNillableChanges(v *string) *AuditLogUpdate {
+	if v != nil {
+		_u.SetChanges(*v)
+	}
+	return _u
+}
+
+// ClearChanges clears the value of the "changes" field.
+func (_u *AuditLogUpdate) ClearChanges() *AuditLogUpdate {
+	_u.mutation.ClearChanges()
+	return _u
+}
+
+// Mutation returns the AuditLogMutation object of the builder.
+func (_u *AuditLogUpdate) Mutation() *AuditLogMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *AuditLogUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *AuditLogUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *AuditLogUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *AuditLogUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (_u *AuditLogUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	_spec := sqlgraph.NewUpdateSpec(auditlog.Table, auditlog.Columns, sqlgraph.NewFieldSpec(auditlog.FieldID, field.TypeInt))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.ActorID(); ok {
+		_spec.SetField(auditlog.FieldActorID, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedActorID(); ok {
+		_spec.AddField(auditlog.FieldActorID, field.TypeInt, value)
+	}
+	if _u.mutation.ActorIDCleared() {
+		_spec.ClearField(auditlog.FieldActorID, field.TypeInt)
+	}
+	if value, ok := _u.mutation.ActorName(); ok {
+		_spec.SetField(auditlog.FieldActorName, field.TypeString, value)
+	}
+	if _u.mutation.ActorNameCleared() {
+		_spec.ClearField(auditlog.FieldActorName, field.TypeString)
+	}
+	if value, ok := _u.mutation.Resource(); ok {
+		_spec.SetField(auditlog.FieldResource, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.RecordID(); ok {
+		_spec.SetField(auditlog.FieldRecordID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Action(); ok {
+		_spec.SetField(auditlog.FieldAction, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Changes(); ok {
+		_spec.SetField(auditlog.FieldChanges, field.TypeString, value)
+	}
+	if _u.mutation.ChangesCleared() {
+		_spec.ClearField(auditlog.FieldChanges, field.TypeString)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{auditlog.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// AuditLogUpdateOne is the builder for updating a single AuditLog entity.
+type AuditLogUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *AuditLogMutation
+}
+
+// SetActorID sets the "actor_id" field.
+func (_u *AuditLogUpdateOne) SetActorID(v int) *AuditLogUpdateOne {
+	_u.mutation.ResetActorID()
+	_u.mutation.SetActorID(v)
+	return _u
+}
+
+// SetNillableActorID sets the "actor_id" field if the given value is not nil.
+func (_u *AuditLogUpdateOne) SetNillableActorID(v *int) *AuditLogUpdateOne {
+	if v != nil {
+		_u.SetActorID(*v)
+	}
+	return _u
+}
+
+// AddActorID adds value to the "actor_id" field.
+func (_u *AuditLogUpdateOne) AddActorID(v int) *AuditLogUpdateOne {
+	_u.mutation.AddActorID(v)
+	return _u
+}
+
+// ClearActorID clears the value of the "actor_id" field.
+func (_u *AuditLogUpdateOne) ClearActorID() *AuditLogUpdateOne {
+	_u.mutation.ClearActorID()
+	return _u
+}
+
+// SetActorName sets the "actor_name" field.
+func (_u *AuditLogUpdateOne) SetActorName(v string) *AuditLogUpdateOne {
+	_u.mutation.SetActorName(v)
+	return _u
+}
+
+// SetNillableActorName sets the "actor_name" field if the given value is not nil.
+func (_u *AuditLogUpdateOne) SetNillableActorName(v *string) *AuditLogUpdateOne {
+	if v != nil {
+		_u.SetActorName(*v)
+	}
+	return _u
+}
+
+// ClearActorName clears the value of the "actor_name" field.
+func (_u *AuditLogUpdateOne) ClearActorName() *AuditLogUpdateOne {
+	_u.mutation.ClearActorName()
+	return _u
+}
+
+// SetResource sets the "resource" field.
+func (_u *AuditLogUpdateOne) SetResource(v string) *AuditLogUpdateOne {
+	_u.mutation.SetResource(v)
+	return _u
+}
+
+// SetNillableResource sets the "resource" field if the given value is not nil.
+func (_u *AuditLogUpdateOne) SetNillableResource(v *string) *AuditLogUpdateOne {
+	if v != nil {
+		_u.SetResource(*v)
+	}
+	return _u
+}
+
+// SetRecordID sets the "record_id" field.
+func (_u *AuditLogUpdateOne) SetRecordID(v string) *AuditLogUpdateOne {
+	_u.mutation.SetRecordID(v)
+	return _u
+}
+
+// SetNillableRecordID sets the "record_id" field if the given value is not nil.
+func (_u *AuditLogUpdateOne) SetNillableRecordID(v *string) *AuditLogUpdateOne {
+	if v != nil {
+		_u.SetRecordID(*v)
+	}
+	return _u
+}
+
+// SetAction sets the "action" field.
+func (_u *AuditLogUpdateOne) SetAction(v string) *AuditLogUpdateOne {
+	_u.mutation.SetAction(v)
+	return _u
+}
+
+// SetNillableAction sets the "action" field if the given value is not nil.
+func (_u *AuditLogUpdateOne) SetNillableAction(v *string) *AuditLogUpdateOne {
+	if v != nil {
+		_u.SetAction(*v)
+	}
+	return _u
+}
+
+// SetChanges sets the "changes" field.
+func (_u *AuditLogUpdateOne) SetChanges(v string) *AuditLogUpdateOne {
+	_u.mutation.SetChanges(v)
+	return _u
+}
+
+// SetNillableChanges sets the "changes" field if the given value is not nil.
+func (_u *AuditLogUpdateOne) SetNillableChanges(v *string) *AuditLogUpdateOne {
+	if v != nil {
+		_u.SetChanges(*v)
+	}
+	return _u
+}
+
+// ClearChanges clears the value of the "changes" field.
+func (_u *AuditLogUpdateOne) ClearChanges() *AuditLogUpdateOne {
+	_u.mutation.ClearChanges()
+	return _u
+}
+
+// Mutation returns the AuditLogMutation object of the builder.
+func (_u *AuditLogUpdateOne) Mutation() *AuditLogMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the AuditLogUpdate builder.
+func (_u *AuditLogUpdateOne) Where(ps ...predicate.AuditLog) *AuditLogUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *AuditLogUpdateOne) Select(field string, fields ...string) *AuditLogUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated AuditLog entity.
+func (_u *AuditLogUpdateOne) Save(ctx context.Context) (*AuditLog, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *AuditLogUpdateOne) SaveX(ctx context.Context) *AuditLog {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *AuditLogUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *AuditLogUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (_u *AuditLogUpdateOne) sqlSave(ctx context.Context) (_node *AuditLog, err error) {
+	_spec := sqlgraph.NewUpdateSpec(auditlog.Table, auditlog.Columns, sqlgraph.NewFieldSpec(auditlog.FieldID, field.TypeInt))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "AuditLog.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, auditlog.FieldID)
+		for _, f := range fields {
+			if !auditlog.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != auditlog.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.ActorID(); ok {
+		_spec.SetField(auditlog.FieldActorID, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedActorID(); ok {
+		_spec.AddField(auditlog.FieldActorID, field.TypeInt, value)
+	}
+	if _u.mutation.ActorIDCleared() {
+		_spec.ClearField(auditlog.FieldActorID, field.TypeInt)
+	}
+	if value, ok := _u.mutation.ActorName(); ok {
+		_spec.SetField(auditlog.FieldActorName, field.TypeString, value)
+	}
+	if _u.mutation.ActorNameCleared() {
+		_spec.ClearField(auditlog.FieldActorName, field.TypeString)
+	}
+	if value, ok := _u.mutation.Resource(); ok {
+		_spec.SetField(auditlog.FieldResource, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.RecordID(); ok {
+		_spec.SetField(auditlog.FieldRecordID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Action(); ok {
+		_spec.SetField(auditlog.FieldAction, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Changes(); ok {
+		_spec.SetField(auditlog.FieldChanges, field.TypeString, value)
+	}
+	if _u.mutation.ChangesCleared() {
+		_spec.ClearField(auditlog.FieldChanges, field.TypeString)
+	}
+	_node = &AuditLog{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{auditlog.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
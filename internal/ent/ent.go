@@ -12,6 +12,7 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/bozz33/sublimego/internal/ent/auditlog"
 	"github.com/bozz33/sublimego/internal/ent/notification"
 	"github.com/bozz33/sublimego/internal/ent/user"
 )
@@ -74,6 +75,7 @@ var (
 func checkColumn(t, c string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
+			auditlog.Table:     auditlog.ValidColumn,
 			notification.Table: notification.ValidColumn,
 			user.Table:         user.ValidColumn,
 		})
@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// AuditLog schema: one row per create, update or delete made through a
+// resource.
+type AuditLog struct {
+	ent.Schema
+}
+
+func (AuditLog) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("actor_id").Optional(),
+		field.String("actor_name").Optional(),
+		field.String("resource"),
+		field.String("record_id"),
+		field.String("action"),
+		// JSON object of field => {"old": ..., "new": ...}
+		field.Text("changes").Optional(),
+		field.Time("created_at").Default(time.Now).Immutable(),
+	}
+}
+
+func (AuditLog) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("resource", "record_id"),
+	}
+}
+
+func (AuditLog) Edges() []ent.Edge {
+	return nil
+}
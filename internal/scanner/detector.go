@@ -21,6 +21,7 @@ const (
 	ConflictGenericName
 	ConflictNamingConvention
 	ConflictPackageConflict
+	ConflictDuplicateSlug
 )
 
 // Conflict detects a conflict between resources.
@@ -31,6 +32,7 @@ type Conflict struct {
 	Suggestion string
 	DocsURL    string
 	Resources  []ResourceMetadata
+	Pages      []PageMetadata
 	AutoFix    bool
 }
 
@@ -178,9 +180,40 @@ func (d *Detector) detectPackageConflicts() []Conflict {
 	return conflicts
 }
 
+// DetectPages analyzes page conflicts: two pages sharing a slug would be
+// served at the same URL, so only one of them would be reachable.
+func (d *Detector) DetectPages(pages []PageMetadata) []Conflict {
+	var conflicts []Conflict
+
+	grouped := lo.GroupBy(pages, func(p PageMetadata) string {
+		return p.Slug
+	})
+
+	for _, slug := range lo.Uniq(lo.Map(pages, func(p PageMetadata, _ int) string { return p.Slug })) {
+		dups := grouped[slug]
+		if len(dups) < 2 {
+			continue
+		}
+		names := lo.Map(dups, func(p PageMetadata, _ int) string {
+			return fmt.Sprintf("%s.%s", p.PackageName, p.TypeName)
+		})
+		conflicts = append(conflicts, Conflict{
+			Type:       ConflictDuplicateSlug,
+			Severity:   "error",
+			Message:    fmt.Sprintf("Duplicate page slug '%s' used by %s", slug, strings.Join(names, ", ")),
+			Suggestion: "Give each page a unique slug",
+			DocsURL:    "https://docs.sublimego.dev/pages",
+			Pages:      dups,
+			AutoFix:    false,
+		})
+	}
+
+	return conflicts
+}
+
 // generateAlias generates a unique alias for a resource.
 func (d *Detector) generateAlias(resource ResourceMetadata) string {
-	alias := fmt.Sprintf("%s_%s", resource.PackageName, strings.ToLower(resource.TypeName))
+	alias := strings.ToLower(fmt.Sprintf("%s_%s", resource.PackageName, resource.TypeName))
 
 	if d.isAliasUnique(alias, resource) {
 		return alias
@@ -202,7 +235,7 @@ func (d *Detector) isAliasUnique(alias string, exclude ResourceMetadata) bool {
 		if r.PackageName == exclude.PackageName && r.TypeName == exclude.TypeName {
 			continue
 		}
-		if fmt.Sprintf("%s_%s", r.PackageName, strings.ToLower(r.TypeName)) == alias {
+		if strings.ToLower(fmt.Sprintf("%s_%s", r.PackageName, r.TypeName)) == alias {
 			return false
 		}
 	}
@@ -1,13 +1,15 @@
 // Package scanner provides automatic resource discovery and code generation.
 //
-// It scans the project directory for resource definitions and generates
-// the provider registration code. This enables automatic resource discovery
-// without manual registration.
+// It scans the project directory for resource and custom page definitions
+// and generates the provider registration code. This enables automatic
+// discovery without manual registration.
 //
 // Features:
 //   - Automatic resource scanning
+//   - Automatic page scanning (types named *Page or implementing engine.Page)
+//   - Automatic widget provider scanning (types named *Widget with a New<Type> constructor)
 //   - Provider code generation
-//   - Conflict detection (duplicate names, etc.)
+//   - Conflict detection (duplicate names, duplicate page slugs, etc.)
 //   - Import management
 //   - Template-based generation
 //
@@ -35,10 +35,10 @@ func NewGeneratorWithConfig(config ScannerConfig) *Generator {
 func (g *Generator) Generate(result ScanResult) GenerationResult {
 	start := time.Now()
 
-	if len(result.Resources) == 0 {
+	if len(result.Resources) == 0 && len(result.Pages) == 0 && len(result.Widgets) == 0 {
 		return GenerationResult{
 			Success:  false,
-			Message:  "no resources found to generate",
+			Message:  "no resources, pages or widgets found to generate",
 			Duration: time.Since(start),
 		}
 	}
@@ -119,8 +119,8 @@ func (g *Generator) Generate(result ScanResult) GenerationResult {
 		}
 	}
 
-	message := fmt.Sprintf("generated %s (%d bytes, %d resources)",
-		g.config.OutputPath, bytesWritten, len(result.Resources))
+	message := fmt.Sprintf("generated %s (%d bytes, %d resources, %d pages, %d widgets)",
+		g.config.OutputPath, bytesWritten, len(result.Resources), len(result.Pages), len(result.Widgets))
 	if len(templateData.Warnings) > 0 {
 		message += fmt.Sprintf(" with %d warnings", len(templateData.Warnings))
 	}
@@ -143,14 +143,23 @@ func (g *Generator) Generate(result ScanResult) GenerationResult {
 const providerTemplate = `// Code generated by SublimeGo Scanner. DO NOT EDIT.
 // Generated at: {{.Timestamp}}
 // Resources found: {{.Count}}
+// Pages found: {{.PageCount}}
+// Widgets found: {{.WidgetCount}}
 
 package registry
 
 import (
 {{range .Imports}}
 	{{if .NeedsAlias}}{{.Alias}} {{end}}"{{.Path}}"
+{{end}}
+{{range .PageImports}}
+	{{if .NeedsAlias}}{{.Alias}} {{end}}"{{.Path}}"
+{{end}}
+{{range .WidgetImports}}
+	{{if .NeedsAlias}}{{.Alias}} {{end}}"{{.Path}}"
 {{end}}
 	"github.com/bozz33/sublimego/engine"
+	"github.com/bozz33/sublimego/widget"
 )
 
 {{if .Warnings}}
@@ -176,6 +185,79 @@ var AllResources = []engine.Resource{
 {{end}}
 }
 
+// AllPages contains all discovered custom pages.
+var AllPages = []engine.Page{
+{{range .Pages}}
+	{{if .Constructor}}{{.Constructor}}(){{else}}&{{.Reference}}{}{{end}}, // {{.Source}}{{if .Conflict}} (conflict resolved with alias){{end}}
+{{end}}
+}
+
+// AllWidgets contains all discovered dashboard widget providers.
+var AllWidgets = []widget.Provider{
+{{range .Widgets}}
+	{{.Constructor}}(), // {{.Source}}
+{{end}}
+}
+
+// RegisterWidgets registers the discovered widget providers on the dashboard.
+func RegisterWidgets() {
+	for _, p := range AllWidgets {
+		widget.Register(p)
+	}
+}
+
+// PageCount returns the number of registered pages.
+func PageCount() int {
+	return {{.PageCount}}
+}
+
+// GetPageBySlug returns a page by its slug.
+func GetPageBySlug(slug string) (engine.Page, bool) {
+	for _, page := range AllPages {
+		if page.Slug() == slug {
+			return page, true
+		}
+	}
+	return nil, false
+}
+
+// HasPage checks if a page with the given slug exists.
+func HasPage(slug string) bool {
+	_, exists := GetPageBySlug(slug)
+	return exists
+}
+
+// GetAllPageSlugs returns all page slugs.
+func GetAllPageSlugs() []string {
+	slugs := make([]string, 0, len(AllPages))
+	for _, page := range AllPages {
+		slugs = append(slugs, page.Slug())
+	}
+	return slugs
+}
+
+// PageInfo contains metadata about a page.
+type PageInfo struct {
+	Slug  string
+	Label string
+	Icon  string
+	Group string
+}
+
+// GetPageInfo returns metadata for all pages.
+func GetPageInfo() []PageInfo {
+	info := make([]PageInfo, 0, len(AllPages))
+	for _, page := range AllPages {
+		info = append(info, PageInfo{
+			Slug:  page.Slug(),
+			Label: page.Label(),
+			Icon:  page.Icon(),
+			Group: page.Group(),
+		})
+	}
+	return info
+}
+
 // ResourceCount returns the number of registered resources.
 func ResourceCount() int {
 	return {{.Count}}
@@ -257,6 +339,8 @@ func GetConflicts() []ConflictInfo {
 // RegistryStats contains statistics about the registry.
 type RegistryStats struct {
 	TotalResources int
+	TotalPages     int
+	TotalWidgets   int
 	TotalConflicts int
 	TotalWarnings  int
 	GeneratedAt    string
@@ -266,6 +350,8 @@ type RegistryStats struct {
 func GetStats() RegistryStats {
 	return RegistryStats{
 		TotalResources: {{.Count}},
+		TotalPages:     {{.PageCount}},
+		TotalWidgets:   {{.WidgetCount}},
 		TotalConflicts: {{len .Conflicts}},
 		TotalWarnings:  {{len .Warnings}},
 		GeneratedAt:    "{{.Generated.Format "2006-01-02 15:04:05"}}",
@@ -0,0 +1,277 @@
+package scanner
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// pageMethods is the method set of the engine.Page interface.
+var pageMethods = []string{"Slug", "Label", "Icon", "Group", "Sort", "Render", "CanAccess"}
+
+// typeCandidate is a struct type found while scanning a directory.
+type typeCandidate struct {
+	TypeName    string
+	PackageName string
+	FilePath    string
+	key         string // directory and type name, the receiver key of its methods
+}
+
+// declarations holds the struct types and methods of the packages under a
+// directory, gathered across all their files.
+type declarations struct {
+	types        []typeCandidate
+	methods      map[string][]string // type key → method names
+	slugs        map[string]string   // type key → literal returned by Slug()
+	constructors map[string]string   // type key → New<Type> function without parameters
+}
+
+// scanDeclarations analyzes the Go files under root.
+func (s *Scanner) scanDeclarations(root string) (*declarations, error) {
+	decls := &declarations{
+		methods:      make(map[string][]string),
+		slugs:        make(map[string]string),
+		constructors: make(map[string]string),
+	}
+
+	err := s.walkGoFiles(root, func(path string) error {
+		node, err := parser.ParseFile(s.fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return fmt.Errorf("failed to scan %s: failed to parse file: %w", path, err)
+		}
+		dir := filepath.Dir(path)
+
+		for _, decl := range node.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				if decl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					if _, ok := typeSpec.Type.(*ast.StructType); !ok {
+						continue
+					}
+					decls.types = append(decls.types, typeCandidate{
+						TypeName:    typeSpec.Name.Name,
+						PackageName: node.Name.Name,
+						FilePath:    path,
+						key:         dir + "." + typeSpec.Name.Name,
+					})
+				}
+			case *ast.FuncDecl:
+				recv := receiverName(decl)
+				if recv == "" {
+					if typeName, ok := strings.CutPrefix(decl.Name.Name, "New"); ok && decl.Type.Params.NumFields() == 0 && decl.Type.Results.NumFields() > 0 {
+						decls.constructors[dir+"."+typeName] = decl.Name.Name
+					}
+					continue
+				}
+				key := dir + "." + recv
+				decls.methods[key] = append(decls.methods[key], decl.Name.Name)
+				if decl.Name.Name == "Slug" {
+					if slug, ok := literalReturn(decl); ok {
+						decls.slugs[key] = slug
+					}
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decls, nil
+}
+
+// scanPages analyzes the Go files under root to find pages. A struct type is
+// a page if its name ends with "Page" or if the methods declared on it in its
+// package cover the engine.Page interface. Methods are gathered across all
+// the files of a package before deciding.
+func (s *Scanner) scanPages(root string) ([]PageMetadata, error) {
+	decls, err := s.scanDeclarations(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var pages []PageMetadata
+	for _, c := range decls.types {
+		if !strings.HasSuffix(c.TypeName, "Page") && len(lo.Without(pageMethods, decls.methods[c.key]...)) > 0 {
+			continue
+		}
+		page := PageMetadata{
+			TypeName:    c.TypeName,
+			PackageName: c.PackageName,
+			FilePath:    c.FilePath,
+			Slug:        decls.slugs[c.key],
+			Constructor: decls.constructors[c.key],
+		}
+		if page.Slug == "" {
+			page.Slug = s.extractPageSlug(page.TypeName)
+		}
+		pages = append(pages, page)
+	}
+
+	return pages, nil
+}
+
+// scanWidgets analyzes the Go files under root to find widget providers:
+// struct types named *Widget with a New<Type> constructor taking no
+// arguments, as the generator creates them.
+func (s *Scanner) scanWidgets(root string) ([]WidgetMetadata, error) {
+	decls, err := s.scanDeclarations(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var widgets []WidgetMetadata
+	for _, c := range decls.types {
+		constructor, ok := decls.constructors[c.key]
+		if !ok || !strings.HasSuffix(c.TypeName, "Widget") {
+			continue
+		}
+		widgets = append(widgets, WidgetMetadata{
+			TypeName:    c.TypeName,
+			PackageName: c.PackageName,
+			FilePath:    c.FilePath,
+			Constructor: constructor,
+		})
+	}
+
+	return widgets, nil
+}
+
+// receiverName returns the type name of a method's receiver, empty for
+// functions.
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// literalReturn returns the string a function returns when its body is a
+// single return of a string literal, as in func (p *X) Slug() string { return "x" }.
+func literalReturn(fn *ast.FuncDecl) (string, bool) {
+	if fn.Body == nil || len(fn.Body.List) != 1 {
+		return "", false
+	}
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", false
+	}
+	lit, ok := ret.Results[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+// extractPageSlug derives a page slug from its type name when the page does
+// not declare it literally ("SalesReportPage" → "sales-report"). Unlike
+// resource slugs, page slugs are not pluralized.
+func (s *Scanner) extractPageSlug(typeName string) string {
+	name := strings.TrimSuffix(typeName, "Page")
+	if name == "" {
+		name = typeName
+	}
+
+	return strings.ToLower(strings.Join(splitWords(name), "-"))
+}
+
+// buildPackageImports builds the imports of the packages under
+// internal/<dir>. A package named like one of taken is imported under an
+// alias suffixed with suffix.
+func (s *Scanner) buildPackageImports(dir, suffix string, packages []string, taken ...[]ImportInfo) []ImportInfo {
+	names := make(map[string]bool)
+	for _, imports := range taken {
+		for _, i := range imports {
+			names[i.Package] = true
+		}
+	}
+
+	var imports []ImportInfo
+	for _, pkg := range lo.Uniq(packages) {
+		info := ImportInfo{
+			Path:    fmt.Sprintf("github.com/bozz33/sublimego/internal/%s/%s", dir, pkg),
+			Package: pkg,
+		}
+		if names[pkg] {
+			info.Alias = pkg + suffix
+			info.NeedsAlias = true
+		}
+		imports = append(imports, info)
+	}
+
+	return imports
+}
+
+// qualifier returns the name a package is referenced by in the generated
+// code, its alias when imported under one.
+func qualifier(imports []ImportInfo, pkg string) (string, bool) {
+	for _, i := range imports {
+		if i.Package == pkg && i.NeedsAlias {
+			return i.Alias, true
+		}
+	}
+	return pkg, false
+}
+
+// buildPages builds the page list, referencing pages through the aliases of
+// their imports. Pages with a constructor are built with it, so the pages
+// embedding *engine.BasePage are initialized.
+func (s *Scanner) buildPages(pages []PageMetadata, imports []ImportInfo) []PageInfo {
+	var result []PageInfo
+	for _, page := range pages {
+		name, hasConflict := qualifier(imports, page.PackageName)
+		info := PageInfo{
+			Reference: fmt.Sprintf("%s.%s", name, page.TypeName),
+			Source:    page.FilePath,
+			Conflict:  hasConflict,
+		}
+		if hasConflict {
+			info.Alias = name
+		}
+		if page.Constructor != "" {
+			info.Constructor = fmt.Sprintf("%s.%s", name, page.Constructor)
+		}
+		result = append(result, info)
+	}
+
+	return result
+}
+
+// buildWidgets builds the widget provider list.
+func (s *Scanner) buildWidgets(widgets []WidgetMetadata, imports []ImportInfo) []WidgetInfo {
+	var result []WidgetInfo
+	for _, w := range widgets {
+		name, _ := qualifier(imports, w.PackageName)
+		result = append(result, WidgetInfo{
+			Constructor: fmt.Sprintf("%s.%s", name, w.Constructor),
+			Source:      w.FilePath,
+		})
+	}
+
+	return result
+}
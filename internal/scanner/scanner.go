@@ -9,7 +9,9 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+	"unicode"
 
+	"github.com/bozz33/sublimego/generator"
 	"github.com/samber/lo"
 )
 
@@ -19,12 +21,16 @@ type ResourceMetadata struct {
 	PackageName string
 	FilePath    string
 	Slug        string
+	Label       string
+	Group       string
+	Icon        string
 }
 
 // Scanner analyzes source code to discover resources.
 type Scanner struct {
-	config ScannerConfig
-	fset   *token.FileSet
+	config  ScannerConfig
+	fset    *token.FileSet
+	aliases map[string]string // resource key → assigned import alias
 }
 
 // New creates a new scanner with default configuration.
@@ -32,16 +38,18 @@ func New(resourcesPath string) *Scanner {
 	config := DefaultConfig()
 	config.ResourcesPath = resourcesPath
 	return &Scanner{
-		config: config,
-		fset:   token.NewFileSet(),
+		config:  config,
+		fset:    token.NewFileSet(),
+		aliases: make(map[string]string),
 	}
 }
 
 // NewWithConfig creates a new scanner with custom configuration.
 func NewWithConfig(config ScannerConfig) *Scanner {
 	return &Scanner{
-		config: config,
-		fset:   token.NewFileSet(),
+		config:  config,
+		fset:    token.NewFileSet(),
+		aliases: make(map[string]string),
 	}
 }
 
@@ -49,8 +57,12 @@ func NewWithConfig(config ScannerConfig) *Scanner {
 func (s *Scanner) Scan() ScanResult {
 	start := time.Now()
 
-	// If resources directory doesn't exist, return success with zero resources.
-	if _, err := os.Stat(s.config.ResourcesPath); os.IsNotExist(err) {
+	_, resErr := os.Stat(s.config.ResourcesPath)
+	_, pageErr := os.Stat(s.config.PagesPath)
+	_, widgetErr := os.Stat(s.config.WidgetsPath)
+
+	// If no directory exists, return success with nothing found.
+	if os.IsNotExist(resErr) && os.IsNotExist(pageErr) && os.IsNotExist(widgetErr) {
 		return ScanResult{
 			Success:  true,
 			Message:  fmt.Sprintf("Resources directory %s not found, nothing to scan", s.config.ResourcesPath),
@@ -59,32 +71,28 @@ func (s *Scanner) Scan() ScanResult {
 	}
 
 	var allMetadata []ResourceMetadata
+	var err error
 
-	err := filepath.Walk(s.config.ResourcesPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		for _, pattern := range s.config.ExcludePatterns {
-			matched, err := filepath.Match(pattern, filepath.Base(path))
-			if err != nil {
-				continue
-			}
-			if matched {
-				return nil
-			}
-		}
-
-		if !info.IsDir() && strings.HasSuffix(path, ".go") {
+	if !os.IsNotExist(resErr) {
+		err = s.walkGoFiles(s.config.ResourcesPath, func(path string) error {
 			metadata, err := s.scanFile(path)
 			if err != nil {
 				return fmt.Errorf("failed to scan %s: %w", path, err)
 			}
 			allMetadata = append(allMetadata, metadata...)
-		}
+			return nil
+		})
+	}
 
-		return nil
-	})
+	var pages []PageMetadata
+	if err == nil && !os.IsNotExist(pageErr) {
+		pages, err = s.scanPages(s.config.PagesPath)
+	}
+
+	var widgets []WidgetMetadata
+	if err == nil && !os.IsNotExist(widgetErr) {
+		widgets, err = s.scanWidgets(s.config.WidgetsPath)
+	}
 
 	if err != nil {
 		return ScanResult{
@@ -96,6 +104,7 @@ func (s *Scanner) Scan() ScanResult {
 
 	detector := NewDetector(allMetadata)
 	conflicts := detector.Detect()
+	conflicts = append(conflicts, detector.DetectPages(pages)...)
 
 	hasErrors := detector.HasErrors(conflicts)
 	if hasErrors && s.config.StrictMode {
@@ -103,12 +112,14 @@ func (s *Scanner) Scan() ScanResult {
 			Success:   false,
 			Message:   "Strict mode: blocking errors detected",
 			Resources: allMetadata,
+			Pages:     pages,
+			Widgets:   widgets,
 			Conflicts: conflicts,
 			Duration:  time.Since(start),
 		}
 	}
 
-	message := fmt.Sprintf("Scanned %d resources", len(allMetadata))
+	message := fmt.Sprintf("Scanned %d resources, %d pages and %d widgets", len(allMetadata), len(pages), len(widgets))
 	if len(conflicts) > 0 {
 		message += fmt.Sprintf(" (%d conflicts detected)", len(conflicts))
 	}
@@ -117,12 +128,54 @@ func (s *Scanner) Scan() ScanResult {
 		Success:   true,
 		Message:   message,
 		Resources: allMetadata,
+		Pages:     pages,
+		Widgets:   widgets,
 		Conflicts: conflicts,
 		Duration:  time.Since(start),
 	}
 }
 
+// walkGoFiles calls fn for every Go file under root that no exclude
+// pattern matches.
+func (s *Scanner) walkGoFiles(root string, fn func(path string) error) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		for _, pattern := range s.config.ExcludePatterns {
+			matched, err := filepath.Match(pattern, filepath.Base(path))
+			if err != nil {
+				continue
+			}
+			if matched {
+				return nil
+			}
+		}
+
+		if !info.IsDir() && strings.HasSuffix(path, ".go") {
+			return fn(path)
+		}
+
+		return nil
+	})
+}
+
 // scanFile analyzes a Go file to find resources.
+//
+// Slug, label, group and icon are read from the source when they are static:
+// first from a method of the file returning a string literal
+// (func (r *UserResource) Label() string { return "User" }), then from a
+// directive in the type's doc comment:
+//
+//	//sublimego:slug people
+//	//sublimego:label Person
+//	//sublimego:group Administration
+//	//sublimego:icon users
+//	type PersonResource struct{}
+//
+// Otherwise the slug and label are derived from the type name, and the group
+// and icon are left empty.
 func (s *Scanner) scanFile(filePath string) ([]ResourceMetadata, error) {
 	node, err := parser.ParseFile(s.fset, filePath, nil, parser.ParseComments)
 	if err != nil {
@@ -132,6 +185,21 @@ func (s *Scanner) scanFile(filePath string) ([]ResourceMetadata, error) {
 	var metadata []ResourceMetadata
 	packageName := node.Name.Name
 
+	literals := make(map[string]map[string]string)
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || !lo.Contains(staticMethods, fn.Name.Name) {
+			continue
+		}
+		recv := receiverName(fn)
+		if value, ok := literalReturn(fn); ok && recv != "" {
+			if literals[recv] == nil {
+				literals[recv] = make(map[string]string)
+			}
+			literals[recv][fn.Name.Name] = value
+		}
+	}
+
 	for _, decl := range node.Decls {
 		genDecl, ok := decl.(*ast.GenDecl)
 		if !ok || genDecl.Tok != token.TYPE {
@@ -147,14 +215,32 @@ func (s *Scanner) scanFile(filePath string) ([]ResourceMetadata, error) {
 			typeName := typeSpec.Name.Name
 
 			if s.isPotentialResource(typeName) {
-				slug := s.extractSlug(typeName)
-
-				metadata = append(metadata, ResourceMetadata{
+				doc := typeSpec.Doc
+				if doc == nil && len(genDecl.Specs) == 1 {
+					doc = genDecl.Doc
+				}
+				values := docDirectives(doc)
+				for method, value := range literals[typeName] {
+					values[strings.ToLower(method)] = value
+				}
+
+				m := ResourceMetadata{
 					TypeName:    typeName,
 					PackageName: packageName,
 					FilePath:    filePath,
-					Slug:        slug,
-				})
+					Slug:        values["slug"],
+					Label:       values["label"],
+					Group:       values["group"],
+					Icon:        values["icon"],
+				}
+				if m.Slug == "" {
+					m.Slug = s.extractSlug(typeName)
+				}
+				if m.Label == "" {
+					m.Label = s.extractLabel(typeName)
+				}
+
+				metadata = append(metadata, m)
 			}
 		}
 	}
@@ -162,6 +248,27 @@ func (s *Scanner) scanFile(filePath string) ([]ResourceMetadata, error) {
 	return metadata, nil
 }
 
+// staticMethods are the resource methods whose literal results are recorded.
+var staticMethods = []string{"Slug", "Label", "Group", "Icon"}
+
+// docDirectives returns the values of the //sublimego:<key> <value>
+// directives of a doc comment, keyed by key.
+func docDirectives(doc *ast.CommentGroup) map[string]string {
+	values := make(map[string]string)
+	if doc == nil {
+		return values
+	}
+	for _, c := range doc.List {
+		directive, ok := strings.CutPrefix(c.Text, "//sublimego:")
+		if !ok {
+			continue
+		}
+		key, value, _ := strings.Cut(directive, " ")
+		values[key] = strings.TrimSpace(value)
+	}
+	return values
+}
+
 // isPotentialResource detects if a type could be a resource.
 func (s *Scanner) isPotentialResource(typeName string) bool {
 	if strings.HasSuffix(typeName, "Resource") {
@@ -177,39 +284,71 @@ func (s *Scanner) isPotentialResource(typeName string) bool {
 	return true
 }
 
-// extractSlug extracts the slug from the type name.
+// extractSlug extracts the slug from the type name, the way the generator
+// derives the slug of the resources it creates ("OrderItemResource" →
+// "order_items"). Resources declare another slug in their Slug method or
+// with a //sublimego:slug directive.
 func (s *Scanner) extractSlug(typeName string) string {
 	name := strings.TrimSuffix(typeName, "Resource")
-	slug := strings.ToLower(name)
-
-	switch {
-	case strings.HasSuffix(slug, "y"):
-		slug = slug[:len(slug)-1] + "ies"
-	case strings.HasSuffix(slug, "s"):
-		// Already plural
-	case strings.HasSuffix(slug, "x") || strings.HasSuffix(slug, "ch") || strings.HasSuffix(slug, "sh"):
-		slug += "es"
-	default:
-		slug += "s"
+	return generator.Pluralize(generator.ToSnakeCase(name))
+}
+
+// extractLabel extracts the label from the type name ("OrderItemResource" →
+// "Order Item").
+func (s *Scanner) extractLabel(typeName string) string {
+	name := strings.TrimSuffix(typeName, "Resource")
+	if name == "" {
+		name = typeName
 	}
+	return strings.Join(splitWords(name), " ")
+}
 
-	return slug
+// splitWords splits a Go identifier into words, keeping acronyms together
+// ("HTTPLogEntry" → "HTTP", "Log", "Entry").
+func splitWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if !unicode.IsUpper(runes[i]) {
+			continue
+		}
+		if !unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
 }
 
 // BuildTemplateData builds data for the template.
 func (s *Scanner) BuildTemplateData(result ScanResult) TemplateData {
 	imports := s.buildImports(result.Resources, result.Conflicts)
 	resources := s.buildResources(result.Resources, result.Conflicts)
+	pageImports := s.buildPackageImports("pages", "_page", lo.Map(result.Pages, func(p PageMetadata, _ int) string {
+		return p.PackageName
+	}), imports)
+	pages := s.buildPages(result.Pages, pageImports)
+	widgetImports := s.buildPackageImports("widgets", "_widget", lo.Map(result.Widgets, func(w WidgetMetadata, _ int) string {
+		return w.PackageName
+	}), imports, pageImports)
+	widgets := s.buildWidgets(result.Widgets, widgetImports)
 	warnings := s.extractWarnings(result.Conflicts)
 
 	return TemplateData{
-		Timestamp: time.Now().Format("2006-01-02 15:04:05"),
-		Count:     len(result.Resources),
-		Imports:   imports,
-		Resources: resources,
-		Warnings:  warnings,
-		Conflicts: result.Conflicts,
-		Generated: time.Now(),
+		Timestamp:     time.Now().Format("2006-01-02 15:04:05"),
+		Count:         len(result.Resources),
+		PageCount:     len(result.Pages),
+		WidgetCount:   len(result.Widgets),
+		Imports:       imports,
+		PageImports:   pageImports,
+		WidgetImports: widgetImports,
+		Resources:     resources,
+		Pages:         pages,
+		Widgets:       widgets,
+		Warnings:      warnings,
+		Conflicts:     result.Conflicts,
+		Generated:     time.Now(),
 	}
 }
 
@@ -251,14 +390,14 @@ func (s *Scanner) buildResources(resources []ResourceMetadata, conflicts []Confl
 		if conflict.Type == ConflictDuplicateName && conflict.AutoFix {
 			for _, resource := range conflict.Resources {
 				alias := s.generateAlias(resource)
-				key := fmt.Sprintf("%s.%s", resource.PackageName, resource.TypeName)
+				key := resourceKey(resource)
 				aliasMap[key] = alias
 			}
 		}
 	}
 
 	for _, resource := range resources {
-		key := fmt.Sprintf("%s.%s", resource.PackageName, resource.TypeName)
+		key := resourceKey(resource)
 		alias, hasConflict := aliasMap[key]
 
 		reference := fmt.Sprintf("%s.%s", resource.PackageName, resource.TypeName)
@@ -290,28 +429,39 @@ func (s *Scanner) extractWarnings(conflicts []Conflict) []string {
 	return warnings
 }
 
-// generateAlias generates a unique alias for a resource.
+// generateAlias generates a unique alias for a resource. A resource keeps
+// the alias first assigned to it, so imports and references agree.
 func (s *Scanner) generateAlias(resource ResourceMetadata) string {
-	alias := fmt.Sprintf("%s_%s", resource.PackageName, strings.ToLower(resource.TypeName))
-	if s.isAliasUnique(alias, resource) {
+	if alias, ok := s.aliases[resourceKey(resource)]; ok {
 		return alias
 	}
 
-	counter := 1
-	for {
-		candidate := fmt.Sprintf("%s_%d", alias, counter)
-		if s.isAliasUnique(candidate, resource) {
-			return candidate
-		}
-		counter++
+	alias := strings.ToLower(fmt.Sprintf("%s_%s", resource.PackageName, resource.TypeName))
+	candidate := alias
+	for counter := 1; !s.isAliasUnique(candidate, resource); counter++ {
+		candidate = fmt.Sprintf("%s_%d", alias, counter)
 	}
+
+	s.aliases[resourceKey(resource)] = candidate
+	return candidate
 }
 
-// isAliasUnique checks if an alias is unique.
+// isAliasUnique checks if an alias is not assigned to another resource.
 func (s *Scanner) isAliasUnique(alias string, exclude ResourceMetadata) bool {
+	key := resourceKey(exclude)
+	for k, a := range s.aliases {
+		if k != key && a == alias {
+			return false
+		}
+	}
 	return true
 }
 
+// resourceKey identifies a resource by its package and type.
+func resourceKey(resource ResourceMetadata) string {
+	return fmt.Sprintf("%s.%s", resource.PackageName, resource.TypeName)
+}
+
 // GroupByPackage groups metadata by package.
 func GroupByPackage(metadata []ResourceMetadata) map[string][]ResourceMetadata {
 	return lo.GroupBy(metadata, func(m ResourceMetadata) string {
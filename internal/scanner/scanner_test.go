@@ -0,0 +1,129 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bozz33/sublimego/generator"
+)
+
+func TestGenerateAliasUnique(t *testing.T) {
+	// "Admin" and "admin" give the same alias once lowercased.
+	upper := ResourceMetadata{TypeName: "UserResource", PackageName: "Admin"}
+	lower := ResourceMetadata{TypeName: "UserResource", PackageName: "admin"}
+	result := ScanResult{
+		Resources: []ResourceMetadata{upper, lower},
+		Conflicts: []Conflict{{
+			Type:      ConflictDuplicateName,
+			Severity:  "error",
+			Resources: []ResourceMetadata{upper, lower},
+			AutoFix:   true,
+		}},
+	}
+
+	data := NewWithConfig(DefaultConfig()).BuildTemplateData(result)
+	if len(data.Imports) != 2 {
+		t.Fatalf("expected 2 imports, got %d", len(data.Imports))
+	}
+	if a, b := data.Imports[0].Alias, data.Imports[1].Alias; a == b {
+		t.Errorf("expected distinct import aliases, got %q twice", a)
+	}
+	for i, res := range data.Resources {
+		want := data.Imports[i].Alias + ".UserResource"
+		if res.Reference != want {
+			t.Errorf("resource %d: expected reference %q, got %q", i, want, res.Reference)
+		}
+	}
+	if got := data.Imports[1].Alias; got != "admin_userresource_1" {
+		t.Errorf("expected the second alias to be numbered, got %q", got)
+	}
+}
+
+func TestScanFileMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "person.go")
+	src := `package person
+
+//sublimego:slug people
+//sublimego:group Administration
+type PersonResource struct{}
+
+func (r *PersonResource) Icon() string { return "users" }
+
+func (r *PersonResource) Group() string { return "Staff" }
+
+type AuditLogResource struct{}
+
+func (r *AuditLogResource) Slug() string { return slugPrefix + "logs" }
+`
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, err := New("").scanFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metadata) != 2 {
+		t.Fatalf("expected 2 resources, got %d", len(metadata))
+	}
+
+	person := metadata[0]
+	if person.Slug != "people" || person.Icon != "users" || person.Label != "Person" {
+		t.Errorf("unexpected metadata %+v", person)
+	}
+	if person.Group != "Staff" {
+		t.Errorf("expected the method to win over the directive, got group %q", person.Group)
+	}
+
+	// A computed slug falls back to the name heuristics.
+	log := metadata[1]
+	if log.Slug != "audit_logs" || log.Label != "Audit Log" || log.Icon != "" {
+		t.Errorf("unexpected metadata %+v", log)
+	}
+}
+
+func TestScanGeneratedPagesAndWidgets(t *testing.T) {
+	dir := t.TempDir()
+	g, err := generator.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := generator.GeneratePage(g, "Settings", dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := generator.GenerateWidget(g, "Sales", dir); err != nil {
+		t.Fatal(err)
+	}
+
+	config := DefaultConfig()
+	config.ResourcesPath = filepath.Join(dir, "internal", "resources")
+	config.PagesPath = filepath.Join(dir, "internal", "pages")
+	config.WidgetsPath = filepath.Join(dir, "internal", "widgets")
+	config.OutputPath = filepath.Join(dir, "internal", "registry", "provider_gen.go")
+
+	result := NewWithConfig(config).Scan()
+	if !result.Success {
+		t.Fatal(result.Message)
+	}
+	if len(result.Pages) != 1 || result.Pages[0].Constructor != "NewSettingsPage" {
+		t.Fatalf("unexpected pages %+v", result.Pages)
+	}
+	if len(result.Widgets) != 1 || result.Widgets[0].Constructor != "NewSalesWidget" {
+		t.Fatalf("unexpected widgets %+v", result.Widgets)
+	}
+
+	if gen := NewGeneratorWithConfig(config).Generate(result); !gen.Success {
+		t.Fatal(gen.Message)
+	}
+	code, err := os.ReadFile(config.OutputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"settings.NewSettingsPage(),", "sales.NewSalesWidget(),"} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("expected %q in the generated provider", want)
+		}
+	}
+}
@@ -20,10 +20,11 @@ type ResourceInfo struct {
 
 // PageInfo represents a page for generation.
 type PageInfo struct {
-	Reference string // "settings.SettingsPage"
-	Source    string // "internal/pages/settings/page.go"
-	Alias     string // Alias used if needed
-	Conflict  bool   // True if this page has a conflict
+	Reference   string // "settings.SettingsPage"
+	Constructor string // "settings.NewSettingsPage" (empty if none)
+	Source      string // "internal/pages/settings/page.go"
+	Alias       string // Alias used if needed
+	Conflict    bool   // True if this page has a conflict
 }
 
 // PageMetadata contains metadata for a discovered page.
@@ -32,26 +33,45 @@ type PageMetadata struct {
 	PackageName string
 	FilePath    string
 	Slug        string
+	Constructor string // "NewSettingsPage" (empty if none)
+}
+
+// WidgetInfo represents a widget provider for generation.
+type WidgetInfo struct {
+	Constructor string // "sales.NewSalesWidget"
+	Source      string // "internal/widgets/sales/widget.go"
+}
+
+// WidgetMetadata contains metadata for a discovered widget provider.
+type WidgetMetadata struct {
+	TypeName    string
+	PackageName string
+	FilePath    string
+	Constructor string // "NewSalesWidget"
 }
 
 // TemplateData contains all data for the template.
 type TemplateData struct {
-	Timestamp   string         // "2024-01-30 11:53:00"
-	Count       int            // Number of resources
-	PageCount   int            // Number of pages
-	Imports     []ImportInfo   // Required imports
-	PageImports []ImportInfo   // Page imports
-	Resources   []ResourceInfo // Resources to generate
-	Pages       []PageInfo     // Pages to generate
-	Warnings    []string       // Educational warnings
-	Conflicts   []Conflict     // Detected conflicts
-	Generated   time.Time      // Generation date
+	Timestamp     string         // "2024-01-30 11:53:00"
+	Count         int            // Number of resources
+	PageCount     int            // Number of pages
+	WidgetCount   int            // Number of widget providers
+	Imports       []ImportInfo   // Required imports
+	PageImports   []ImportInfo   // Page imports
+	WidgetImports []ImportInfo   // Widget imports
+	Resources     []ResourceInfo // Resources to generate
+	Pages         []PageInfo     // Pages to generate
+	Widgets       []WidgetInfo   // Widget providers to generate
+	Warnings      []string       // Educational warnings
+	Conflicts     []Conflict     // Detected conflicts
+	Generated     time.Time      // Generation date
 }
 
 // ScannerConfig contains the scanner configuration.
 type ScannerConfig struct {
 	ResourcesPath   string   // Path to resources
 	PagesPath       string   // Path to pages
+	WidgetsPath     string   // Path to widgets
 	OutputPath      string   // Path to generated file
 	TemplatePath    string   // Path to template
 	StrictMode      bool     // Strict mode (error on warnings)
@@ -66,6 +86,7 @@ func DefaultConfig() ScannerConfig {
 	return ScannerConfig{
 		ResourcesPath:   "internal/resources",
 		PagesPath:       "internal/pages",
+		WidgetsPath:     "internal/widgets",
 		OutputPath:      "internal/registry/provider_gen.go",
 		TemplatePath:    "templates/provider.go.tmpl",
 		StrictMode:      false,
@@ -80,6 +101,7 @@ func DefaultConfig() ScannerConfig {
 type ScanResult struct {
 	Resources []ResourceMetadata
 	Pages     []PageMetadata
+	Widgets   []WidgetMetadata
 	Conflicts []Conflict
 	Success   bool
 	Message   string
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/bozz33/sublimego/auth"
+)
+
+// APITokenConfig configures the API token middleware.
+type APITokenConfig struct {
+	// Validator resolves a bearer token to the ID of the user it belongs to.
+	Validator func(token string) (userID int, ok bool)
+	// LoadUser builds the user of a valid token. Defaults to a bare
+	// auth.NewUser carrying only the ID.
+	LoadUser func(ctx context.Context, userID int) (*auth.User, error)
+	// Methods lists the methods allowed with a token. Defaults to GET and
+	// HEAD, i.e. read-only access.
+	Methods []string
+}
+
+type apiTokenKey struct{}
+
+// APIToken returns a middleware authenticating "Authorization: Bearer"
+// requests with validator, for read-only (GET/HEAD) access.
+//
+// Requests without a bearer token pass through untouched, so APIToken is
+// placed in front of the session-based RequireAuth: token requests skip the
+// session check, the others still need a session.
+//
+//	h = middleware.RequireAuth(manager)(h)
+//	h = middleware.APIToken(validateToken)(h)
+func APIToken(validator func(token string) (userID int, ok bool)) Middleware {
+	return APITokenWithConfig(&APITokenConfig{Validator: validator})
+}
+
+// APITokenWithConfig returns an API token middleware with custom config.
+func APITokenWithConfig(config *APITokenConfig) Middleware {
+	if config == nil || config.Validator == nil {
+		panic("APITokenConfig and Validator are required")
+	}
+	methods := config.Methods
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet, http.MethodHead}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			userID, valid := config.Validator(token)
+			if !valid {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			if !slices.Contains(methods, r.Method) {
+				w.Header().Set("Allow", strings.Join(methods, ", "))
+				http.Error(w, "Method not allowed with an API token", http.StatusMethodNotAllowed)
+				return
+			}
+
+			user := auth.NewUser(userID, "", "")
+			if config.LoadUser != nil {
+				loaded, err := config.LoadUser(r.Context(), userID)
+				if err != nil || loaded == nil {
+					http.Error(w, "Unauthorized", http.StatusUnauthorized)
+					return
+				}
+				user = loaded
+			}
+
+			ctx := auth.WithUser(r.Context(), user)
+			ctx = context.WithValue(ctx, apiTokenKey{}, true)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// TokenAuthenticated reports whether the request was authenticated by
+// APIToken rather than by the session.
+func TokenAuthenticated(ctx context.Context) bool {
+	ok, _ := ctx.Value(apiTokenKey{}).(bool)
+	return ok
+}
+
+// bearerToken extracts the token of an "Authorization: Bearer" header.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
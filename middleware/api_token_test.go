@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/bozz33/sublimego/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIToken(t *testing.T) {
+	validator := func(token string) (int, bool) { return 42, token == "secret" }
+	var userID int
+	handler := APIToken(validator)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID = auth.UserFromContext(r.Context()).ID
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		header string
+		want   int
+		userID int
+	}{
+		{"valid token", http.MethodGet, "Bearer secret", http.StatusOK, 42},
+		{"lowercase scheme", http.MethodGet, "bearer secret", http.StatusOK, 42},
+		{"invalid token", http.MethodGet, "Bearer nope", http.StatusUnauthorized, 0},
+		{"write with token", http.MethodPost, "Bearer secret", http.StatusMethodNotAllowed, 0},
+		{"no token", http.MethodPost, "", http.StatusOK, 0},
+		{"basic auth", http.MethodGet, "Basic dXNlcjpwYXNz", http.StatusOK, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID = 0
+			req := httptest.NewRequest(tt.method, "/admin/posts", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Code)
+			assert.Equal(t, tt.userID, userID)
+		})
+	}
+}
+
+func TestAPIToken_ComposesWithRequireAuth(t *testing.T) {
+	sessions := scs.New()
+	manager := auth.NewManager(sessions)
+	var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, TokenAuthenticated(r.Context()))
+		w.WriteHeader(http.StatusOK)
+	})
+	h = RequireAuth(manager)(h)
+	h = APIToken(func(token string) (int, bool) { return 1, token == "secret" })(h)
+	h = sessions.LoadAndSave(h)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/posts", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Without a token the session is still required.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/posts", nil))
+	assert.Equal(t, http.StatusFound, rec.Code)
+}
@@ -31,6 +31,9 @@ func RequireAuth(manager *auth.Manager) Middleware {
 }
 
 // RequireAuthWithConfig returns an auth middleware with custom config.
+// Requests authenticated by APIToken are let through without a session.
+// Unauthenticated requests are redirected to RedirectURL, or answered with
+// a 401 when it is empty (JSON APIs).
 func RequireAuthWithConfig(config *AuthConfig) Middleware {
 	if config == nil || config.Manager == nil {
 		panic("AuthConfig and Manager are required")
@@ -38,7 +41,17 @@ func RequireAuthWithConfig(config *AuthConfig) Middleware {
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Already authenticated by APIToken.
+			if TokenAuthenticated(r.Context()) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			if !config.Manager.IsAuthenticatedFromRequest(r) {
+				if config.RedirectURL == "" {
+					http.Error(w, "Unauthorized", http.StatusUnauthorized)
+					return
+				}
 				if config.SaveIntendedURL && r.Method == "GET" {
 					config.Manager.SetIntendedURLFromRequest(r)
 				}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"net/http"
@@ -95,7 +96,10 @@ func (m *CSRFManager) CSRF(next http.Handler) http.Handler {
 						HttpOnly: false,
 						SameSite: m.config.SameSite,
 					})
+					r = r.WithContext(WithCSRFToken(r.Context(), token))
 				}
+			} else {
+				r = r.WithContext(WithCSRFToken(r.Context(), cookie.Value))
 			}
 			next.ServeHTTP(w, r)
 			return
@@ -134,6 +138,20 @@ func GetCSRFToken(r *http.Request, cookieName ...string) string {
 	return cookie.Value
 }
 
+type csrfTokenKey struct{}
+
+// WithCSRFToken stores the request's CSRF token in the context so templates
+// can render it in forms.
+func WithCSRFToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, csrfTokenKey{}, token)
+}
+
+// CSRFTokenFromContext returns the token stored by WithCSRFToken, or "".
+func CSRFTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(csrfTokenKey{}).(string)
+	return token
+}
+
 // CSRFField returns the HTML for a hidden CSRF input field.
 func CSRFField(token string, fieldName ...string) string {
 	name := "_token"
@@ -7,7 +7,7 @@
 // Features:
 //   - CORS with configurable origins and methods
 //   - Rate limiting with token bucket algorithm
-//   - Authentication middleware
+//   - Authentication middleware (session, or read-only API tokens)
 //   - Middleware stack composition
 //   - Conditional middleware execution
 //   - Path-based middleware filtering
@@ -2,10 +2,12 @@ package middleware
 
 import (
 	"fmt"
+	"math"
 	"net"
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bozz33/sublimego/auth"
@@ -23,6 +25,10 @@ type RateLimitConfig struct {
 	WhitelistIPs      []string
 	CleanupInterval   time.Duration
 	OnLimitExceeded   func(r *http.Request, key string)
+
+	// RequestsPerSecond overrides RequestsPerMinute when positive, for
+	// rates that are not a whole number of requests per minute.
+	RequestsPerSecond float64
 }
 
 // RateLimiter manages rate limiting using the Token Bucket algorithm.
@@ -37,7 +43,7 @@ type RateLimiter struct {
 // limiterEntry contains a rate limiter and its last access time.
 type limiterEntry struct {
 	limiter  *rate.Limiter
-	lastSeen time.Time
+	lastSeen atomic.Int64 // UnixNano
 }
 
 // NewRateLimiter creates a new rate limiter.
@@ -79,6 +85,29 @@ func DefaultRateLimitConfig() *RateLimitConfig {
 	}
 }
 
+// RateLimit returns a middleware allowing rps requests per second per
+// client, with bursts of up to burst requests. Clients are identified by
+// KeyByUser: the authenticated user, or the IP address for guests. The user
+// is only known behind the auth middleware, hence Panel.WithRateLimit.
+// Rejected requests get a 429 with a Retry-After header.
+//
+//	panel.WithRateLimit(middleware.RateLimit(10, 20))
+func RateLimit(rps float64, burst int) Middleware {
+	return NewRateLimiter(&RateLimitConfig{
+		RequestsPerSecond: rps,
+		Burst:             burst,
+		KeyFunc:           KeyByUser,
+	}).Middleware()
+}
+
+// limit returns the configured rate.
+func (rl *RateLimiter) limit() rate.Limit {
+	if rl.config.RequestsPerSecond > 0 {
+		return rate.Limit(rl.config.RequestsPerSecond)
+	}
+	return rate.Limit(float64(rl.config.RequestsPerMinute) / 60.0)
+}
+
 // Middleware returns the rate limiting middleware.
 func (rl *RateLimiter) Middleware() Middleware {
 	return func(next http.Handler) http.Handler {
@@ -97,7 +126,7 @@ func (rl *RateLimiter) Middleware() Middleware {
 					rl.config.OnLimitExceeded(r, key)
 				}
 
-				rl.handleRateLimitExceeded(w, r, key)
+				rl.handleRateLimitExceeded(w, r, limiter)
 				return
 			}
 
@@ -109,23 +138,16 @@ func (rl *RateLimiter) Middleware() Middleware {
 
 // getLimiter retrieves or creates a limiter for a given key.
 func (rl *RateLimiter) getLimiter(key string) *rate.Limiter {
-	if entry, ok := rl.limiters.Load(key); ok {
-		if e, ok2 := entry.(*limiterEntry); ok2 {
-			e.lastSeen = time.Now()
-			return e.limiter
-		}
-	}
-
-	limit := rate.Limit(float64(rl.config.RequestsPerMinute) / 60.0)
-	limiter := rate.NewLimiter(limit, rl.config.Burst)
-
-	entry := &limiterEntry{
-		limiter:  limiter,
-		lastSeen: time.Now(),
+	entry, ok := rl.limiters.Load(key)
+	if !ok {
+		// Concurrent first requests of a client must share one limiter.
+		entry, _ = rl.limiters.LoadOrStore(key, &limiterEntry{
+			limiter: rate.NewLimiter(rl.limit(), rl.config.Burst),
+		})
 	}
-
-	rl.limiters.Store(key, entry)
-	return limiter
+	e := entry.(*limiterEntry)
+	e.lastSeen.Store(time.Now().UnixNano())
+	return e.limiter
 }
 
 // isWhitelisted checks if a key or IP is in the whitelist.
@@ -158,9 +180,14 @@ func (rl *RateLimiter) isWhitelisted(key string, r *http.Request) bool {
 	return false
 }
 
+// requestsPerMinute returns the limit advertised in X-RateLimit-Limit.
+func (rl *RateLimiter) requestsPerMinute() int {
+	return int(math.Round(float64(rl.limit()) * 60))
+}
+
 // setRateLimitHeaders adds informative rate limiting headers.
 func (rl *RateLimiter) setRateLimitHeaders(w http.ResponseWriter, limiter *rate.Limiter) {
-	w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", rl.config.RequestsPerMinute))
+	w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", rl.requestsPerMinute()))
 
 	tokens := int(limiter.Tokens())
 	if tokens < 0 {
@@ -173,9 +200,14 @@ func (rl *RateLimiter) setRateLimitHeaders(w http.ResponseWriter, limiter *rate.
 }
 
 // handleRateLimitExceeded handles the case when the limit is exceeded.
-func (rl *RateLimiter) handleRateLimitExceeded(w http.ResponseWriter, r *http.Request, key string) {
+// Retry-After is the time until the client's bucket holds a token again.
+func (rl *RateLimiter) handleRateLimitExceeded(w http.ResponseWriter, r *http.Request, limiter *rate.Limiter) {
 	retryAfter := 60
-	w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", rl.config.RequestsPerMinute))
+	if res := limiter.Reserve(); res.OK() {
+		retryAfter = max(1, int(math.Ceil(res.Delay().Seconds())))
+		res.Cancel()
+	}
+	w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", rl.requestsPerMinute()))
 	w.Header().Set("X-RateLimit-Remaining", "0")
 	w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(time.Minute).Unix()))
 	w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
@@ -209,7 +241,7 @@ func (rl *RateLimiter) cleanup() {
 		if !ok {
 			return true
 		}
-		if entry.lastSeen.Before(threshold) {
+		if entry.lastSeen.Load() < threshold.UnixNano() {
 			rl.limiters.Delete(key)
 		}
 		return true
@@ -221,7 +253,8 @@ func (rl *RateLimiter) Stop() {
 	close(rl.stopClean)
 }
 
-// KeyByIP extracts the client IP.
+// KeyByIP extracts the client IP. Forwarding headers are only honoured for
+// requests coming from a proxy trusted with TrustProxies.
 func KeyByIP(r *http.Request) string {
 	return getClientIPFromRequest(r)
 }
@@ -246,22 +279,86 @@ func KeyByHeader(header string) KeyFunc {
 	}
 }
 
-// getClientIPFromRequest extracts the client IP considering proxies.
-func getClientIPFromRequest(r *http.Request) string {
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		ips := strings.Split(xff, ",")
-		if len(ips) > 0 {
-			return strings.TrimSpace(ips[0])
+// trustedProxies are the networks whose forwarding headers are believed,
+// guarded by trustedMu.
+var (
+	trustedMu      sync.RWMutex
+	trustedProxies []*net.IPNet
+)
+
+// TrustProxies sets the reverse proxies, as IP addresses or CIDR ranges,
+// allowed to report the client IP in X-Forwarded-For or X-Real-IP. Headers
+// from any other peer are ignored, since clients can set them freely. It
+// panics on an invalid address; call it again without arguments to trust
+// no proxy.
+//
+//	middleware.TrustProxies("10.0.0.0/8", "127.0.0.1")
+func TrustProxies(proxies ...string) {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				panic(fmt.Sprintf("middleware: invalid trusted proxy %q", proxy))
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			panic(fmt.Sprintf("middleware: invalid trusted proxy %q", proxy))
 		}
+		nets = append(nets, ipNet)
 	}
+	trustedMu.Lock()
+	defer trustedMu.Unlock()
+	trustedProxies = nets
+}
 
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return xri
+// isTrustedProxy reports whether ipStr belongs to a trusted proxy.
+func isTrustedProxy(ipStr string) bool {
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return false
 	}
+	trustedMu.RLock()
+	defer trustedMu.RUnlock()
+	for _, ipNet := range trustedProxies {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
 
+// getClientIPFromRequest extracts the client IP. When the peer is a trusted
+// proxy, it is the rightmost X-Forwarded-For address not belonging to one,
+// or X-Real-IP.
+func getClientIPFromRequest(r *http.Request) string {
 	ip, _, err := net.SplitHostPort(r.RemoteAddr)
 	if err != nil {
-		return r.RemoteAddr
+		ip = r.RemoteAddr
+	}
+	if !isTrustedProxy(ip) {
+		return ip
+	}
+
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		hops := strings.Split(xff, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if hop != "" && (i == 0 || !isTrustedProxy(hop)) {
+				return hop
+			}
+		}
+	}
+
+	if xri := strings.TrimSpace(r.Header.Get("X-Real-IP")); xri != "" {
+		return xri
 	}
 	return ip
 }
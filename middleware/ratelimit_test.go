@@ -368,6 +368,8 @@ func TestRateLimiter_Cleanup(t *testing.T) {
 }
 
 func TestGetClientIP_XForwardedFor(t *testing.T) {
+	TrustProxies("192.168.1.0/24", "198.51.100.1")
+	t.Cleanup(func() { TrustProxies() })
 	req := httptest.NewRequest("GET", "/test", nil)
 	req.Header.Set("X-Forwarded-For", "203.0.113.1, 198.51.100.1")
 	req.RemoteAddr = "192.168.1.1:1234"
@@ -377,6 +379,8 @@ func TestGetClientIP_XForwardedFor(t *testing.T) {
 }
 
 func TestGetClientIP_XRealIP(t *testing.T) {
+	TrustProxies("192.168.1.1")
+	t.Cleanup(func() { TrustProxies() })
 	req := httptest.NewRequest("GET", "/test", nil)
 	req.Header.Set("X-Real-IP", "203.0.113.1")
 	req.RemoteAddr = "192.168.1.1:1234"
@@ -385,6 +389,42 @@ func TestGetClientIP_XRealIP(t *testing.T) {
 	assert.Equal(t, "203.0.113.1", ip)
 }
 
+func TestGetClientIP_UntrustedHeaders(t *testing.T) {
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("X-Forwarded-For", "203.0.113.1")
+	req.Header.Set("X-Real-IP", "203.0.113.2")
+	req.RemoteAddr = "192.168.1.1:1234"
+	assert.Equal(t, "192.168.1.1", getClientIPFromRequest(req))
+
+	// A trusted proxy appends the peer to the header the client sent.
+	TrustProxies("192.168.1.1")
+	t.Cleanup(func() { TrustProxies() })
+	req.Header.Set("X-Forwarded-For", "203.0.113.1, 198.51.100.7")
+	assert.Equal(t, "198.51.100.7", getClientIPFromRequest(req))
+}
+
+func TestTrustProxies_Invalid(t *testing.T) {
+	assert.Panics(t, func() { TrustProxies("not-an-ip") })
+	assert.Panics(t, func() { TrustProxies("10.0.0.0/33") })
+}
+
+func TestRateLimiter_SpoofedForwardedFor(t *testing.T) {
+	wrapped := WithRateLimitConfig(60, 2, KeyByIP).Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	codes := make([]int, 3)
+	for i := range codes {
+		req := httptest.NewRequest("POST", "/login", nil)
+		req.RemoteAddr = "192.0.2.10:1234"
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("203.0.113.%d", i))
+		rec := httptest.NewRecorder()
+		wrapped.ServeHTTP(rec, req)
+		codes[i] = rec.Code
+	}
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+}
+
 func TestGetClientIP_RemoteAddr(t *testing.T) {
 	req := httptest.NewRequest("GET", "/test", nil)
 	req.RemoteAddr = "192.168.1.1:1234"
@@ -472,3 +512,24 @@ func BenchmarkRateLimiter_Concurrent(b *testing.B) {
 		}
 	})
 }
+
+func TestRateLimit_FractionalRate(t *testing.T) {
+	wrapped := RateLimit(0.5, 1)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/login", nil)
+		req.RemoteAddr = ip + ":1234"
+		rec := httptest.NewRecorder()
+		wrapped.ServeHTTP(rec, req)
+		return rec
+	}
+
+	require.Equal(t, http.StatusOK, serve("10.0.0.1").Code)
+	assert.Equal(t, "30", serve("10.0.0.2").Header().Get("X-RateLimit-Limit"))
+
+	rec := serve("10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+}
@@ -33,10 +33,15 @@ func NewDatabaseStore(db *ent.Client, maxPerUser int) *DatabaseStore {
 }
 
 // Send persists a notification and broadcasts it to SSE subscribers.
+// Errors are dropped; use SendContext to handle them.
 func (s *DatabaseStore) Send(userID string, n *Notification) {
-	if n.ID == "" {
-		n.ID = fmt.Sprintf("%d", time.Now().UnixNano())
-	}
+	_ = s.SendContext(context.Background(), userID, n)
+}
+
+// SendContext persists a notification and broadcasts it to SSE subscribers.
+// n.ID is set to the ID of the stored row, so streamed notifications can be
+// marked read.
+func (s *DatabaseStore) SendContext(ctx context.Context, userID string, n *Notification) error {
 	if n.CreatedAt.IsZero() {
 		n.CreatedAt = time.Now()
 	}
@@ -45,8 +50,7 @@ func (s *DatabaseStore) Send(userID string, n *Notification) {
 	}
 	n.UserID = userID
 
-	ctx := context.Background()
-	_, _ = s.db.Notification.Create().
+	row, err := s.db.Notification.Create().
 		SetUserID(userID).
 		SetTitle(n.Title).
 		SetBody(n.Body).
@@ -57,6 +61,10 @@ func (s *DatabaseStore) Send(userID string, n *Notification) {
 		SetRead(false).
 		SetCreatedAt(n.CreatedAt).
 		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("notifications: failed to save notification: %w", err)
+	}
+	n.ID = fmt.Sprintf("%d", row.ID)
 
 	s.mu.RLock()
 	subs := s.subscribers[userID]
@@ -68,6 +76,7 @@ func (s *DatabaseStore) Send(userID string, n *Notification) {
 		default:
 		}
 	}
+	return nil
 }
 
 // GetAll returns all notifications for a user (newest first).
@@ -116,7 +125,8 @@ func (s *DatabaseStore) MarkAllRead(userID string) {
 		Exec(ctx)
 }
 
-// UnreadCount returns the number of unread notifications for a user.
+// UnreadCount returns the number of unread notifications for a user. The
+// query is served by the (user_id, read) index.
 func (s *DatabaseStore) UnreadCount(userID string) int {
 	ctx := context.Background()
 	count, err := s.db.Notification.Query().
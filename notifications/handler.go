@@ -5,8 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
 )
 
+// DefaultMaxStreams is the default number of concurrent SSE streams per user.
+const DefaultMaxStreams = 5
+
+// streamHeartbeat is how often an idle stream sends a comment line, so
+// proxies keep the connection open and dead clients are detected.
+var streamHeartbeat = 30 * time.Second
+
 // NotificationStore is the interface that both Store (in-memory) and
 // DatabaseStore (Ent-backed) implement. The Handler depends only on
 // this interface, making the persistence backend swappable.
@@ -26,12 +37,22 @@ type NotificationStore interface {
 //
 //	GET  /notifications          -> list all notifications (JSON)
 //	GET  /notifications/unread   -> list unread notifications (JSON)
+//	GET  /notifications/unread-count -> number of unread notifications (JSON)
 //	GET  /notifications/stream   -> SSE stream of live notifications
 //	POST /notifications/{id}/read -> mark one as read
 //	POST /notifications/read-all  -> mark all as read
 type Handler struct {
 	store      NotificationStore
 	userIDFunc func(r *http.Request) string
+	maxStreams int
+
+	mu      sync.Mutex
+	streams map[string][]*stream // userID -> open streams, oldest first
+}
+
+// stream is an open SSE connection; cancel ends it.
+type stream struct {
+	cancel context.CancelFunc
 }
 
 // NewHandler creates a notification HTTP handler.
@@ -41,7 +62,53 @@ func NewHandler(store NotificationStore, userIDFunc func(r *http.Request) string
 	if store == nil {
 		store = globalStore
 	}
-	return &Handler{store: store, userIDFunc: userIDFunc}
+	return &Handler{
+		store:      store,
+		userIDFunc: userIDFunc,
+		maxStreams: DefaultMaxStreams,
+		streams:    make(map[string][]*stream),
+	}
+}
+
+// WithMaxStreams caps the concurrent SSE streams per user. When a user opens
+// one more, their oldest stream is closed. Zero or less disables the cap.
+func (h *Handler) WithMaxStreams(n int) *Handler {
+	h.maxStreams = n
+	return h
+}
+
+// openStream registers a stream for userID, closing the oldest ones beyond
+// the cap. The returned func unregisters it.
+func (h *Handler) openStream(userID string, cancel context.CancelFunc) func() {
+	st := &stream{cancel: cancel}
+
+	h.mu.Lock()
+	list := append(h.streams[userID], st)
+	if h.maxStreams > 0 {
+		for len(list) > h.maxStreams {
+			list[0].cancel()
+			list = list[1:]
+		}
+	}
+	h.streams[userID] = list
+	h.mu.Unlock()
+
+	return func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		list := h.streams[userID]
+		for i, s := range list {
+			if s == st {
+				list = append(list[:i:i], list[i+1:]...)
+				break
+			}
+		}
+		if len(list) == 0 {
+			delete(h.streams, userID)
+		} else {
+			h.streams[userID] = list
+		}
+	}
 }
 
 // Register mounts all notification routes on the given mux.
@@ -51,6 +118,7 @@ func (h *Handler) Register(mux *http.ServeMux, prefix string) {
 	}
 	mux.HandleFunc(prefix, h.handleList)
 	mux.HandleFunc(prefix+"/unread", h.handleUnread)
+	mux.HandleFunc(prefix+"/unread-count", h.handleUnreadCount)
 	mux.HandleFunc(prefix+"/stream", h.handleStream)
 	mux.HandleFunc(prefix+"/read-all", h.handleReadAll)
 	// /notifications/{id}/read — handled via prefix match
@@ -91,7 +159,22 @@ func (h *Handler) handleUnread(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// handleStream streams live notifications via Server-Sent Events.
+func (h *Handler) handleUnreadCount(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	userID := h.userIDFunc(r)
+	if userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	writeJSON(w, map[string]any{"unread_count": h.store.UnreadCount(userID)})
+}
+
+// handleStream streams live notifications via Server-Sent Events. The
+// stream ends when the client disconnects, a write fails, or the user opens
+// more than maxStreams streams.
 func (h *Handler) handleStream(w http.ResponseWriter, r *http.Request) {
 	userID := h.userIDFunc(r)
 	if userID == "" {
@@ -110,17 +193,29 @@ func (h *Handler) handleStream(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("X-Accel-Buffering", "no")
 
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	defer h.openStream(userID, cancel)()
+
+	// Subscribe first so nothing sent after the unread count is missed.
+	ch := h.store.Subscribe(ctx, userID)
+
 	// Send current unread count as first event
 	unread := h.store.UnreadCount(userID)
 	_, _ = fmt.Fprintf(w, "event: connected\ndata: {\"unread_count\": %d}\n\n", unread)
 	flusher.Flush()
-
-	ch := h.store.Subscribe(r.Context(), userID)
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
 
 	for {
 		select {
-		case <-r.Context().Done():
+		case <-ctx.Done():
 			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
 		case n, ok := <-ch:
 			if !ok {
 				return
@@ -129,7 +224,9 @@ func (h *Handler) handleStream(w http.ResponseWriter, r *http.Request) {
 			if err != nil {
 				continue
 			}
-			_, _ = w.Write(msg)
+			if _, err := w.Write(msg); err != nil {
+				return
+			}
 			flusher.Flush()
 		}
 	}
@@ -157,14 +254,10 @@ func (h *Handler) handleByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Extract ID from path: /notifications/{id}/read
-	path := r.URL.Path
-	// strip leading /notifications/
-	rest := path[len("/notifications/"):]
-	// expect: {id}/read
+	// Extract ID from path: {prefix}/{id}/read
 	var notifID string
-	if len(rest) > 5 && rest[len(rest)-5:] == "/read" {
-		notifID = rest[:len(rest)-5]
+	if rest, ok := strings.CutSuffix(r.URL.Path, "/read"); ok {
+		notifID = path.Base(rest)
 	}
 
 	if notifID == "" || r.Method != http.MethodPost {
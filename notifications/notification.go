@@ -41,7 +41,7 @@ type Store struct {
 	maxPerUser    int
 }
 
-var globalStore = &Store{
+var globalStore NotificationStore = &Store{
 	notifications: make(map[string][]*Notification),
 	subscribers:   make(map[string][]chan *Notification),
 	maxPerUser:    100,
@@ -59,11 +59,44 @@ func NewStore(maxPerUser int) *Store {
 	}
 }
 
-// SetGlobalStore replaces the global store (useful for testing or custom config).
-func SetGlobalStore(s *Store) {
+// SetGlobalStore replaces the global store (useful for testing or custom
+// config), e.g. with a DatabaseStore to persist notifications.
+func SetGlobalStore(s NotificationStore) {
 	globalStore = s
 }
 
+// contextSender is implemented by stores whose Send can fail, such as
+// DatabaseStore.
+type contextSender interface {
+	SendContext(ctx context.Context, userID string, n *Notification) error
+}
+
+// Notify sends a notification to a user via the global store, for app code
+// reporting events such as a finished export. Level picks the default icon.
+func Notify(ctx context.Context, userID, title, body string, level Level) error {
+	var n *Notification
+	switch level {
+	case LevelSuccess:
+		n = Success(title)
+	case LevelWarning:
+		n = Warning(title)
+	case LevelDanger:
+		n = Danger(title)
+	default:
+		n = Info(title)
+		if level != "" {
+			n.Level = level
+		}
+	}
+	n.WithBody(body)
+
+	if s, ok := globalStore.(contextSender); ok {
+		return s.SendContext(ctx, userID, n)
+	}
+	globalStore.Send(userID, n)
+	return nil
+}
+
 // Send sends a notification to a user via the global store.
 func Send(userID string, n *Notification) {
 	globalStore.Send(userID, n)
@@ -289,9 +322,12 @@ func (n *Notification) SendToAll(userIDs []string) {
 	}
 }
 
-// Broadcast sends a notification to all users currently tracked in the global store.
+// Broadcast sends a notification to all users currently tracked in the global
+// store, if the store tracks users (as the in-memory Store does).
 func Broadcast(n *Notification) {
-	globalStore.Broadcast(n)
+	if b, ok := globalStore.(interface{ Broadcast(n *Notification) }); ok {
+		b.Broadcast(n)
+	}
 }
 
 // Broadcast sends a notification to all users currently tracked in this store.
@@ -1,9 +1,20 @@
 package notifications_test
 
 import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/bozz33/sublimego/internal/ent"
 	"github.com/bozz33/sublimego/notifications"
+	_ "modernc.org/sqlite"
 )
 
 func TestInfoBuilder(t *testing.T) {
@@ -110,3 +121,102 @@ func TestStoreGetUnread(t *testing.T) {
 		}
 	}
 }
+
+func newTestClient(t *testing.T) *ent.Client {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:"+t.Name()+"?mode=memory&cache=shared&_pragma=foreign_keys(1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := ent.NewClient(ent.Driver(entsql.OpenDB("sqlite3", db)))
+	t.Cleanup(func() { _ = client.Close() })
+	if err := client.Schema.Create(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestNotify_DatabaseStore(t *testing.T) {
+	store := notifications.NewDatabaseStore(newTestClient(t), 0)
+	notifications.SetGlobalStore(store)
+	t.Cleanup(func() { notifications.SetGlobalStore(notifications.NewStore(100)) })
+
+	ctx := context.Background()
+	if err := notifications.Notify(ctx, "7", "Export ready", "orders.csv", notifications.LevelSuccess); err != nil {
+		t.Fatal(err)
+	}
+	if err := notifications.Notify(ctx, "7", "Disk almost full", "", notifications.LevelWarning); err != nil {
+		t.Fatal(err)
+	}
+
+	items := store.GetAll("7")
+	if len(items) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(items))
+	}
+	if items[1].Level != notifications.LevelSuccess || items[1].Body != "orders.csv" {
+		t.Errorf("unexpected notification %+v", items[1])
+	}
+
+	mux := http.NewServeMux()
+	notifications.NewHandler(store, func(r *http.Request) string { return "7" }).
+		Register(mux, "/admin/api/notifications")
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/api/notifications/"+items[0].ID+"/read", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("mark read: expected 204, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/api/notifications/unread-count", nil))
+	var body struct {
+		UnreadCount int `json:"unread_count"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.UnreadCount != 1 {
+		t.Errorf("expected 1 unread, got %d", body.UnreadCount)
+	}
+}
+
+func TestHandler_StreamCapsSubscribers(t *testing.T) {
+	store := notifications.NewStore(50)
+	mux := http.NewServeMux()
+	notifications.NewHandler(store, func(r *http.Request) string { return "7" }).
+		WithMaxStreams(1).
+		Register(mux, "/api/notifications")
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	open := func() *bufio.Reader {
+		resp, err := http.Get(srv.URL + "/api/notifications/stream")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		r := bufio.NewReader(resp.Body)
+		if line, _ := r.ReadString('\n'); line != "event: connected\n" {
+			t.Fatalf("expected connected event, got %q", line)
+		}
+		return r
+	}
+	first := open()
+	second := open()
+
+	// The first stream is closed once the second one exceeds the cap.
+	if _, err := io.ReadAll(first); err != nil {
+		t.Fatal(err)
+	}
+
+	store.Send("7", notifications.Info("Export ready"))
+	for {
+		line, err := second.ReadString('\n')
+		if err != nil {
+			t.Fatalf("stream ended before the notification: %v", err)
+		}
+		if strings.HasPrefix(line, "data: ") && strings.Contains(line, "Export ready") {
+			break
+		}
+	}
+}
@@ -40,6 +40,17 @@
 //			}),
 //	)
 //
-//	// Perform a global search
+//	// Or register a plain search function under a resource slug
+//	search.RegisterFunc("orders", func(ctx context.Context, query string) []search.Result {
+//		return findOrders(ctx, query)
+//	})
+//
+// Panel resources opt in by listing their searchable fields; the panel
+// registers them under their slug, with links to their records:
+//
+//	func (r *UserResource) Searchable() []string { return []string{"name", "email"} }
+//
+//	// Perform a global search: every resource is searched in parallel and
+//	// contributes at most its share of the limit
 //	results, err := search.QuickSearch(ctx, "john")
 package search
@@ -17,6 +17,7 @@ type Result struct {
 	URL          string  `json:"url"`
 	Icon         string  `json:"icon,omitempty"`
 	ResourceType string  `json:"resource_type"`
+	Slug         string  `json:"slug,omitempty"` // slug of the resource, see RegisterFunc
 	Score        float64 `json:"score"`
 }
 
@@ -38,6 +39,7 @@ type Searchable interface {
 
 // BaseSearchable provides default implementations for Searchable.
 type BaseSearchable struct {
+	slug     string
 	label    string
 	icon     string
 	priority int
@@ -63,6 +65,9 @@ func (s *BaseSearchable) GetSearchPriority() int        { return s.priority }
 func (s *BaseSearchable) IsSearchEnabled() bool         { return s.enabled }
 func (s *BaseSearchable) GetSearchableFields() []string { return s.fields }
 
+// GetSearchSlug returns the slug of the resource searched, "" if not set.
+func (s *BaseSearchable) GetSearchSlug() string { return s.slug }
+
 func (s *BaseSearchable) Search(ctx context.Context, query string, limit int) ([]Result, error) {
 	if s.searcher != nil {
 		return s.searcher(ctx, query, limit)
@@ -70,6 +75,13 @@ func (s *BaseSearchable) Search(ctx context.Context, query string, limit int) ([
 	return []Result{}, nil
 }
 
+// SetSlug sets the slug of the resource searched, copied to the results
+// that have none.
+func (s *BaseSearchable) SetSlug(slug string) *BaseSearchable {
+	s.slug = slug
+	return s
+}
+
 // SetIcon sets the search icon.
 func (s *BaseSearchable) SetIcon(icon string) *BaseSearchable {
 	s.icon = icon
@@ -117,6 +129,37 @@ func Register(s Searchable) {
 	globalRegistry.searchables = append(globalRegistry.searchables, s)
 }
 
+// RegisterFunc registers searchFn as the global search of the resource
+// slug, replacing a previous registration of the same slug. GlobalSearch
+// caps the results of each resource, so searchFn need not limit them.
+// The returned searchable sets the label, icon and priority:
+//
+//	search.RegisterFunc("users", searchUsers).SetIcon("person").SetPriority(1)
+func RegisterFunc(slug string, searchFn func(ctx context.Context, query string) []Result) *BaseSearchable {
+	s := NewSearchable(slug).SetSlug(slug).WithSearcher(func(ctx context.Context, query string, _ int) ([]Result, error) {
+		return searchFn(ctx, query), nil
+	})
+
+	globalRegistry.mu.Lock()
+	defer globalRegistry.mu.Unlock()
+	filtered := make([]Searchable, 0, len(globalRegistry.searchables)+1)
+	for _, existing := range globalRegistry.searchables {
+		if slugOf(existing) != slug {
+			filtered = append(filtered, existing)
+		}
+	}
+	globalRegistry.searchables = append(filtered, s)
+	return s
+}
+
+// slugOf returns the resource slug of a searchable, "" if it has none.
+func slugOf(s Searchable) string {
+	if sl, ok := s.(interface{ GetSearchSlug() string }); ok {
+		return sl.GetSearchSlug()
+	}
+	return ""
+}
+
 // Unregister removes a searchable by label.
 func Unregister(label string) {
 	globalRegistry.mu.Lock()
@@ -164,7 +207,9 @@ func DefaultSearchOptions(query string) *SearchOptions {
 	}
 }
 
-// GlobalSearch performs a search across all registered searchables.
+// GlobalSearch performs a search across all registered searchables, in
+// parallel. Each searchable contributes its best results, up to an equal
+// share of opts.Limit (at least 3); the merged results are sorted by score.
 func GlobalSearch(ctx context.Context, opts *SearchOptions) ([]Result, error) {
 	searchables := GetSearchables()
 
@@ -211,6 +256,19 @@ func GlobalSearch(ctx context.Context, opts *SearchOptions) ([]Result, error) {
 			if err != nil {
 				return
 			}
+			if len(results) > perResourceLimit {
+				sort.SliceStable(results, func(i, j int) bool {
+					return results[i].Score > results[j].Score
+				})
+				results = results[:perResourceLimit]
+			}
+			if slug := slugOf(searchable); slug != "" {
+				for i := range results {
+					if results[i].Slug == "" {
+						results[i].Slug = slug
+					}
+				}
+			}
 
 			mu.Lock()
 			allResults = append(allResults, results...)
@@ -2,6 +2,7 @@ package search_test
 
 import (
 	"context"
+	"strconv"
 	"testing"
 
 	"github.com/bozz33/sublimego/search"
@@ -101,3 +102,45 @@ func TestCalculateScore(t *testing.T) {
 		t.Errorf("expected 'golang' to score higher than 'python' for query 'go', got %f vs %f", score, noScore)
 	}
 }
+
+func TestRegisterFunc(t *testing.T) {
+	search.Clear()
+	defer search.Clear()
+
+	many := func(_ context.Context, query string) []search.Result {
+		out := make([]search.Result, 30)
+		for i := range out {
+			out[i] = search.Result{ID: strconv.Itoa(i), Title: query, Score: float64(i) / 100}
+		}
+		return out
+	}
+	search.RegisterFunc("orders", func(context.Context, string) []search.Result { return nil })
+	search.RegisterFunc("orders", many) // replaces the first one
+	search.RegisterFunc("users", func(_ context.Context, query string) []search.Result {
+		return []search.Result{{ID: "7", Title: "Ada", URL: "/admin/users/7", Slug: "people", Score: 1}}
+	}).SetPriority(1)
+
+	if search.Count() != 2 {
+		t.Fatalf("expected 2 searchables, got %d", search.Count())
+	}
+
+	results, err := search.QuickSearch(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Default limit 20 over 2 resources: at most 10 each.
+	if len(results) != 11 {
+		t.Fatalf("expected 1 user and 10 orders, got %d results", len(results))
+	}
+	if results[0].ID != "7" || results[0].Slug != "people" {
+		t.Errorf("expected the user first with its own slug, got %+v", results[0])
+	}
+	for _, r := range results[1:] {
+		if r.Slug != "orders" {
+			t.Errorf("expected the slug of the resource, got %q", r.Slug)
+		}
+	}
+	if results[1].ID != "29" {
+		t.Errorf("expected the best orders kept, got %q first", results[1].ID)
+	}
+}
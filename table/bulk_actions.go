@@ -80,3 +80,16 @@ func (b *BulkAction) GetIcon() string { return b.IconStr }
 
 // GetColor returns the action color variant.
 func (b *BulkAction) GetColor() string { return b.ColorStr }
+
+// VisibleBulkActions returns the bulk actions to offer in the bulk-action
+// bar, i.e. those whose visibility condition holds. (The full list is the
+// BulkActions field.)
+func (t *Table) VisibleBulkActions(ctx context.Context) []*BulkAction {
+	visible := make([]*BulkAction, 0, len(t.BulkActions))
+	for _, ba := range t.BulkActions {
+		if ba.IsVisible(ctx) {
+			visible = append(visible, ba)
+		}
+	}
+	return visible
+}
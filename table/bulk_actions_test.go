@@ -84,3 +84,14 @@ func TestTableWithBulkActions(t *testing.T) {
 
 	assert.Len(t, tbl.BulkActions, 2)
 }
+
+func TestTableVisibleBulkActions(t *testing.T) {
+	hidden := NewBulkAction("Archive", "archive", "warning").
+		VisibleWhen(func(ctx context.Context) bool { return false })
+	tbl := New([]any{}).WithBulkActions(BulkDelete(), hidden, BulkExport())
+
+	visible := tbl.VisibleBulkActions(context.Background())
+	assert.Len(t, visible, 2)
+	assert.Equal(t, "Delete selected", visible[0].GetLabel())
+	assert.Equal(t, "Export selected", visible[1].GetLabel())
+}
@@ -3,6 +3,9 @@ package table
 import (
 	"fmt"
 	"reflect"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -13,7 +16,10 @@ type TextColumn struct {
 	SortableFlag bool
 	SearchFlag   bool
 	CopyFlag     bool
-	ValueFunc    func(item any) string // optional: replaces reflect-based lookup
+	ValueFunc    func(item any) string            // optional: replaces reflect-based lookup
+	FormatFunc   func(value any, item any) string // optional: formats the raw value for display
+
+	surfaces
 }
 
 // Text creates a new text column.
@@ -30,6 +36,14 @@ func (c *TextColumn) Using(fn func(item any) string) *TextColumn {
 	return c
 }
 
+// FormatUsing sets a display formatter. It receives the raw field value
+// (nil if the field does not exist) and the whole item, which allows derived
+// text such as a full name or a currency amount without a dedicated field.
+func (c *TextColumn) FormatUsing(fn func(value any, item any) string) *TextColumn {
+	c.FormatFunc = fn
+	return c
+}
+
 // WithLabel sets the column label.
 func (c *TextColumn) WithLabel(label string) *TextColumn {
 	c.LabelStr = label
@@ -61,8 +75,12 @@ func (c *TextColumn) Type() string       { return "text" }
 func (c *TextColumn) IsSortable() bool   { return c.SortableFlag }
 func (c *TextColumn) IsSearchable() bool { return c.SearchFlag }
 func (c *TextColumn) IsCopyable() bool   { return c.CopyFlag }
+func (c *TextColumn) GetAlign() string   { return "left" }
 func (c *TextColumn) Value(item any) string {
 	if c.ValueFunc != nil {
+		if c.FormatFunc != nil {
+			return c.FormatFunc(c.ValueFunc(item), item)
+		}
 		return c.ValueFunc(item)
 	}
 	v := reflect.ValueOf(item)
@@ -70,6 +88,13 @@ func (c *TextColumn) Value(item any) string {
 		v = v.Elem()
 	}
 	field := v.FieldByName(c.colKey)
+	if c.FormatFunc != nil {
+		var raw any
+		if field.IsValid() {
+			raw = field.Interface()
+		}
+		return c.FormatFunc(raw, item)
+	}
 	if field.IsValid() {
 		return fmt.Sprintf("%v", field.Interface())
 	}
@@ -83,6 +108,8 @@ type BadgeColumn struct {
 	SortableFlag bool
 	ColorMap     map[string]string
 	ValueFunc    func(item any) string // optional: replaces reflect-based lookup
+
+	surfaces
 }
 
 // Badge creates a new badge column.
@@ -133,6 +160,7 @@ func (c *BadgeColumn) Type() string       { return "badge" }
 func (c *BadgeColumn) IsSortable() bool   { return c.SortableFlag }
 func (c *BadgeColumn) IsSearchable() bool { return false }
 func (c *BadgeColumn) IsCopyable() bool   { return false }
+func (c *BadgeColumn) GetAlign() string   { return "left" }
 func (c *BadgeColumn) Value(item any) string {
 	if c.ValueFunc != nil {
 		return c.ValueFunc(item)
@@ -154,6 +182,8 @@ type ImageColumn struct {
 	LabelStr  string
 	Rounded   bool
 	ValueFunc func(item any) string // optional: replaces reflect-based lookup
+
+	surfaces
 }
 
 // Image creates a new image column.
@@ -189,6 +219,7 @@ func (c *ImageColumn) Type() string       { return "image" }
 func (c *ImageColumn) IsSortable() bool   { return false }
 func (c *ImageColumn) IsSearchable() bool { return false }
 func (c *ImageColumn) IsCopyable() bool   { return false }
+func (c *ImageColumn) GetAlign() string   { return "left" }
 func (c *ImageColumn) Value(item any) string {
 	if c.ValueFunc != nil {
 		return c.ValueFunc(item)
@@ -204,6 +235,110 @@ func (c *ImageColumn) Value(item any) string {
 	return ""
 }
 
+// IconColumn maps field values to named (Material) icons.
+type IconColumn struct {
+	colKey       string
+	LabelStr     string
+	SortableFlag bool
+	IconMap      map[string]string
+	FallbackIcon string
+	TooltipFunc  func(value string) string // optional: tooltip from the raw value
+	ValueFunc    func(item any) string     // optional: replaces reflect-based lookup
+
+	surfaces
+}
+
+// Icon creates a new icon column.
+func Icon(key string) *IconColumn {
+	return &IconColumn{
+		colKey:       key,
+		LabelStr:     key,
+		IconMap:      make(map[string]string),
+		FallbackIcon: "help_outline",
+	}
+}
+
+// WithLabel sets the column label.
+func (c *IconColumn) WithLabel(label string) *IconColumn {
+	c.LabelStr = label
+	return c
+}
+
+// Sortable makes the column sortable.
+func (c *IconColumn) Sortable() *IconColumn {
+	c.SortableFlag = true
+	return c
+}
+
+// Icons sets the icon names by value.
+func (c *IconColumn) Icons(icons map[string]string) *IconColumn {
+	c.IconMap = icons
+	return c
+}
+
+// Fallback sets the icon used for unmapped values.
+func (c *IconColumn) Fallback(icon string) *IconColumn {
+	c.FallbackIcon = icon
+	return c
+}
+
+// WithTooltip shows the raw value as a tooltip.
+func (c *IconColumn) WithTooltip() *IconColumn {
+	c.TooltipFunc = func(value string) string { return value }
+	return c
+}
+
+// TooltipUsing derives the tooltip text from the raw value.
+func (c *IconColumn) TooltipUsing(fn func(value string) string) *IconColumn {
+	c.TooltipFunc = fn
+	return c
+}
+
+// Using sets a custom accessor function, bypassing reflection.
+func (c *IconColumn) Using(fn func(item any) string) *IconColumn {
+	c.ValueFunc = fn
+	return c
+}
+
+// GetIcon returns the icon name for a value.
+func (c *IconColumn) GetIcon(value string) string {
+	if icon, ok := c.IconMap[value]; ok {
+		return icon
+	}
+	return c.FallbackIcon
+}
+
+// GetTooltip returns the tooltip for a value ("" if none is configured).
+func (c *IconColumn) GetTooltip(value string) string {
+	if c.TooltipFunc == nil {
+		return ""
+	}
+	return c.TooltipFunc(value)
+}
+
+// Column interface implementation
+func (c *IconColumn) Key() string        { return c.colKey }
+func (c *IconColumn) Label() string      { return c.LabelStr }
+func (c *IconColumn) Type() string       { return "icon" }
+func (c *IconColumn) IsSortable() bool   { return c.SortableFlag }
+func (c *IconColumn) IsSearchable() bool { return false }
+func (c *IconColumn) IsCopyable() bool   { return false }
+func (c *IconColumn) GetAlign() string   { return "left" }
+func (c *IconColumn) Value(item any) string {
+	if c.ValueFunc != nil {
+		return c.ValueFunc(item)
+	}
+	v := reflect.ValueOf(item)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	field := v.FieldByName(c.colKey)
+	if field.IsValid() {
+		return fmt.Sprintf("%v", field.Interface())
+	}
+	return ""
+}
+
 // BooleanColumn displays a boolean value as a ✓ or ✗ icon.
 type BooleanColumn struct {
 	colKey       string
@@ -212,6 +347,8 @@ type BooleanColumn struct {
 	TrueLabel    string
 	FalseLabel   string
 	ValueFunc    func(item any) string // optional: replaces reflect-based lookup
+
+	surfaces
 }
 
 // BoolCol creates a new boolean column.
@@ -256,6 +393,7 @@ func (c *BooleanColumn) Type() string       { return "boolean" }
 func (c *BooleanColumn) IsSortable() bool   { return c.SortableFlag }
 func (c *BooleanColumn) IsSearchable() bool { return false }
 func (c *BooleanColumn) IsCopyable() bool   { return false }
+func (c *BooleanColumn) GetAlign() string   { return "left" }
 func (c *BooleanColumn) Value(item any) string {
 	if c.ValueFunc != nil {
 		return c.ValueFunc(item)
@@ -291,6 +429,8 @@ type DateColumn struct {
 	Format       string           // Go time format string, default "2006-01-02"
 	Relative     bool             // Show relative time ("2 hours ago")
 	ValueFunc    func(any) string // optional: replaces reflect-based lookup
+
+	surfaces
 }
 
 // DateCol creates a new date column.
@@ -339,6 +479,7 @@ func (c *DateColumn) Type() string       { return "date" }
 func (c *DateColumn) IsSortable() bool   { return c.SortableFlag }
 func (c *DateColumn) IsSearchable() bool { return false }
 func (c *DateColumn) IsCopyable() bool   { return false }
+func (c *DateColumn) GetAlign() string   { return "left" }
 func (c *DateColumn) Value(item any) string {
 	if c.ValueFunc != nil {
 		return c.ValueFunc(item)
@@ -403,3 +544,361 @@ func relativeTime(t time.Time) string {
 		return t.Format("2006-01-02")
 	}
 }
+
+// SelectColumn renders an inline-editable dropdown. Changing the value posts
+// to {BaseURL}/{id}/inline with the form fields "column" and "value".
+type SelectColumn struct {
+	colKey       string
+	LabelStr     string
+	SortableFlag bool
+	OptionsMap   map[string]string     // value -> label
+	ValueFunc    func(item any) string // optional: replaces reflect-based lookup
+
+	surfaces
+}
+
+// SelectCol creates a new select column.
+func SelectCol(key string) *SelectColumn {
+	return &SelectColumn{
+		colKey:     key,
+		LabelStr:   key,
+		OptionsMap: make(map[string]string),
+	}
+}
+
+// WithLabel sets the column label.
+func (c *SelectColumn) WithLabel(label string) *SelectColumn {
+	c.LabelStr = label
+	return c
+}
+
+// Sortable makes the column sortable.
+func (c *SelectColumn) Sortable() *SelectColumn {
+	c.SortableFlag = true
+	return c
+}
+
+// Options sets the selectable values (value -> label).
+func (c *SelectColumn) Options(options map[string]string) *SelectColumn {
+	c.OptionsMap = options
+	return c
+}
+
+// Using sets a custom accessor function, bypassing reflection.
+func (c *SelectColumn) Using(fn func(item any) string) *SelectColumn {
+	c.ValueFunc = fn
+	return c
+}
+
+// OptionValues returns the option values sorted by label.
+func (c *SelectColumn) OptionValues() []string {
+	values := make([]string, 0, len(c.OptionsMap))
+	for v := range c.OptionsMap {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return c.OptionsMap[values[i]] < c.OptionsMap[values[j]]
+	})
+	return values
+}
+
+// InlineURL returns the inline update endpoint for a row.
+func (c *SelectColumn) InlineURL(baseURL, id string) string {
+	return baseURL + "/" + id + "/inline"
+}
+
+// Column interface implementation
+func (c *SelectColumn) Key() string        { return c.colKey }
+func (c *SelectColumn) Label() string      { return c.LabelStr }
+func (c *SelectColumn) Type() string       { return "select" }
+func (c *SelectColumn) IsSortable() bool   { return c.SortableFlag }
+func (c *SelectColumn) IsSearchable() bool { return false }
+func (c *SelectColumn) IsCopyable() bool   { return false }
+func (c *SelectColumn) GetAlign() string   { return "left" }
+func (c *SelectColumn) Value(item any) string {
+	if c.ValueFunc != nil {
+		return c.ValueFunc(item)
+	}
+	v := reflect.ValueOf(item)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	field := v.FieldByName(c.colKey)
+	if field.IsValid() {
+		return fmt.Sprintf("%v", field.Interface())
+	}
+	return ""
+}
+
+// ToggleColumn renders an inline switch that flips a boolean field. It posts
+// to the same {BaseURL}/{id}/inline endpoint as SelectColumn, with "value"
+// set to "true" or "false", and reflects the value returned by the server.
+type ToggleColumn struct {
+	colKey       string
+	LabelStr     string
+	SortableFlag bool
+	ValueFunc    func(item any) bool // optional: replaces reflect-based lookup
+
+	surfaces
+}
+
+// Toggle creates a new toggle column.
+func Toggle(key string) *ToggleColumn {
+	return &ToggleColumn{
+		colKey:   key,
+		LabelStr: key,
+	}
+}
+
+// WithLabel sets the column label.
+func (c *ToggleColumn) WithLabel(label string) *ToggleColumn {
+	c.LabelStr = label
+	return c
+}
+
+// Sortable makes the column sortable.
+func (c *ToggleColumn) Sortable() *ToggleColumn {
+	c.SortableFlag = true
+	return c
+}
+
+// Using sets a custom accessor function, bypassing reflection.
+func (c *ToggleColumn) Using(fn func(item any) bool) *ToggleColumn {
+	c.ValueFunc = fn
+	return c
+}
+
+// IsOn returns the boolean state of the field for an item.
+func (c *ToggleColumn) IsOn(item any) bool {
+	if c.ValueFunc != nil {
+		return c.ValueFunc(item)
+	}
+	v := reflect.ValueOf(item)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	field := v.FieldByName(c.colKey)
+	if !field.IsValid() {
+		return false
+	}
+	switch field.Kind() {
+	case reflect.Bool:
+		return field.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.Int() != 0
+	}
+	return false
+}
+
+// InlineURL returns the inline update endpoint for a row.
+func (c *ToggleColumn) InlineURL(baseURL, id string) string {
+	return baseURL + "/" + id + "/inline"
+}
+
+// Column interface implementation
+func (c *ToggleColumn) Key() string        { return c.colKey }
+func (c *ToggleColumn) Label() string      { return c.LabelStr }
+func (c *ToggleColumn) Type() string       { return "toggle" }
+func (c *ToggleColumn) IsSortable() bool   { return c.SortableFlag }
+func (c *ToggleColumn) IsSearchable() bool { return false }
+func (c *ToggleColumn) IsCopyable() bool   { return false }
+func (c *ToggleColumn) GetAlign() string   { return "left" }
+func (c *ToggleColumn) Value(item any) string {
+	if c.IsOn(item) {
+		return "true"
+	}
+	return "false"
+}
+
+// BadgesColumn renders one badge per value of a slice or comma-separated field.
+type BadgesColumn struct {
+	colKey       string
+	LabelStr     string
+	SortableFlag bool
+	ColorMap     map[string]string
+	ValuesFunc   func(item any) []string // optional: replaces reflect-based lookup
+
+	surfaces
+}
+
+// Badges creates a new multi-badge column.
+func Badges(key string) *BadgesColumn {
+	return &BadgesColumn{
+		colKey:   key,
+		LabelStr: key,
+		ColorMap: make(map[string]string),
+	}
+}
+
+// WithLabel sets the column label.
+func (c *BadgesColumn) WithLabel(label string) *BadgesColumn {
+	c.LabelStr = label
+	return c
+}
+
+// Sortable makes the column sortable.
+func (c *BadgesColumn) Sortable() *BadgesColumn {
+	c.SortableFlag = true
+	return c
+}
+
+// Using sets a custom accessor function, bypassing reflection.
+func (c *BadgesColumn) Using(fn func(item any) []string) *BadgesColumn {
+	c.ValuesFunc = fn
+	return c
+}
+
+// Colors sets the colors by value, shared by all badges.
+func (c *BadgesColumn) Colors(colors map[string]string) *BadgesColumn {
+	c.ColorMap = colors
+	return c
+}
+
+// GetColor returns the color for a value.
+func (c *BadgesColumn) GetColor(value string) string {
+	if color, ok := c.ColorMap[value]; ok {
+		return color
+	}
+	return "primary"
+}
+
+// GetValues returns the individual badge values for an item.
+// Supports slice/array fields and comma-separated strings.
+func (c *BadgesColumn) GetValues(item any) []string {
+	if c.ValuesFunc != nil {
+		return c.ValuesFunc(item)
+	}
+	v := reflect.ValueOf(item)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	field := v.FieldByName(c.colKey)
+	if !field.IsValid() {
+		return nil
+	}
+	if field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+		if field.IsNil() {
+			return nil
+		}
+		field = field.Elem()
+	}
+
+	switch field.Kind() {
+	case reflect.Slice, reflect.Array:
+		values := make([]string, 0, field.Len())
+		for i := 0; i < field.Len(); i++ {
+			if s := fmt.Sprintf("%v", field.Index(i).Interface()); s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	case reflect.String:
+		var values []string
+		for _, part := range strings.Split(field.String(), ",") {
+			if s := strings.TrimSpace(part); s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return []string{fmt.Sprintf("%v", field.Interface())}
+	}
+}
+
+// Column interface implementation
+func (c *BadgesColumn) Key() string        { return c.colKey }
+func (c *BadgesColumn) Label() string      { return c.LabelStr }
+func (c *BadgesColumn) Type() string       { return "badges" }
+func (c *BadgesColumn) IsSortable() bool   { return c.SortableFlag }
+func (c *BadgesColumn) IsSearchable() bool { return false }
+func (c *BadgesColumn) IsCopyable() bool   { return false }
+func (c *BadgesColumn) GetAlign() string   { return "left" }
+func (c *BadgesColumn) Value(item any) string {
+	return strings.Join(c.GetValues(item), ", ")
+}
+
+// StepperColumn renders a sequential status (e.g. pending → paid → shipped →
+// delivered) as an ordered progression: prior steps are marked done and the
+// current one is highlighted.
+type StepperColumn struct {
+	colKey       string
+	LabelStr     string
+	SortableFlag bool
+	StepValues   []string
+	ValueFunc    func(item any) string // optional: replaces reflect-based lookup
+
+	surfaces
+}
+
+// Stepper creates a new stepper column.
+func Stepper(key string) *StepperColumn {
+	return &StepperColumn{
+		colKey:   key,
+		LabelStr: key,
+	}
+}
+
+// WithLabel sets the column label.
+func (c *StepperColumn) WithLabel(label string) *StepperColumn {
+	c.LabelStr = label
+	return c
+}
+
+// Sortable makes the column sortable.
+func (c *StepperColumn) Sortable() *StepperColumn {
+	c.SortableFlag = true
+	return c
+}
+
+// Steps sets the ordered list of step values.
+func (c *StepperColumn) Steps(steps []string) *StepperColumn {
+	c.StepValues = steps
+	return c
+}
+
+// Using sets a custom accessor function, bypassing reflection.
+func (c *StepperColumn) Using(fn func(item any) string) *StepperColumn {
+	c.ValueFunc = fn
+	return c
+}
+
+// StepIndex returns the index of the item's current step, or -1 if the
+// value is not one of the steps.
+func (c *StepperColumn) StepIndex(item any) int {
+	return slices.Index(c.StepValues, c.Value(item))
+}
+
+// StepState returns "done", "current" or "upcoming" for step i of an item.
+func (c *StepperColumn) StepState(item any, i int) string {
+	current := c.StepIndex(item)
+	switch {
+	case current < 0 || i > current:
+		return "upcoming"
+	case i < current:
+		return "done"
+	}
+	return "current"
+}
+
+// Column interface implementation
+func (c *StepperColumn) Key() string        { return c.colKey }
+func (c *StepperColumn) Label() string      { return c.LabelStr }
+func (c *StepperColumn) Type() string       { return "stepper" }
+func (c *StepperColumn) IsSortable() bool   { return c.SortableFlag }
+func (c *StepperColumn) IsSearchable() bool { return false }
+func (c *StepperColumn) IsCopyable() bool   { return false }
+func (c *StepperColumn) GetAlign() string   { return "left" }
+func (c *StepperColumn) Value(item any) string {
+	if c.ValueFunc != nil {
+		return c.ValueFunc(item)
+	}
+	v := reflect.ValueOf(item)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	field := v.FieldByName(c.colKey)
+	if field.IsValid() {
+		return fmt.Sprintf("%v", field.Interface())
+	}
+	return ""
+}
@@ -30,4 +30,16 @@
 //
 //	// Render table
 //	table.Render(ctx)
+//
+// Relation columns show a field of a related record. With Modal, clicking the
+// cell loads the related record's page with ?fragment=1 into a slideover, and
+// the panel answers with the record's view without the page layout:
+//
+//	table.Relation("Edges.Customer.Name").WithLabel("Customer").Modal("/customers/{id}")
+//
+// Resources declaring their columns with engine.ResourceColumns share them
+// between the list view, exports and the JSON API. ExcludeFromExport and
+// ExcludeFromAPI leave a column out of one surface:
+//
+//	table.ExcludeFromExport(table.Image("Avatar").WithLabel("Avatar"))
 package table
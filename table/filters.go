@@ -5,6 +5,7 @@ type SelectFilter struct {
 	filterKey string
 	LabelStr  string
 	Options   []FilterOption
+	Multiple  bool
 }
 
 // Select creates a new select filter
@@ -28,6 +29,16 @@ func (f *SelectFilter) WithOptions(options []FilterOption) *SelectFilter {
 	return f
 }
 
+// WithMultiple allows selecting several options at once. The values are
+// submitted as repeated filter_{key} params.
+func (f *SelectFilter) WithMultiple() *SelectFilter {
+	f.Multiple = true
+	return f
+}
+
+// IsMultiple reports whether several options can be selected.
+func (f *SelectFilter) IsMultiple() bool { return f.Multiple }
+
 // Implementation of the Filter interface
 func (f *SelectFilter) Key() string                   { return f.filterKey }
 func (f *SelectFilter) Label() string                 { return f.LabelStr }
@@ -64,3 +75,145 @@ func (f *BooleanFilter) FilterOptions() []FilterOption {
 		{Value: "false", Label: "No"},
 	}
 }
+
+// TernaryFilter is a three-state filter: All, Yes or No. Unlike
+// BooleanFilter, its blank option submits an empty value, which leaves the
+// filter out of the active filters so the query is not constrained.
+type TernaryFilter struct {
+	filterKey string
+	LabelStr  string
+	TrueStr   string
+	FalseStr  string
+	BlankStr  string
+}
+
+// Ternary creates a new ternary filter (All / Yes / No).
+func Ternary(key string) *TernaryFilter {
+	return &TernaryFilter{
+		filterKey: key,
+		LabelStr:  key,
+		TrueStr:   "Yes",
+		FalseStr:  "No",
+		BlankStr:  "All",
+	}
+}
+
+// WithLabel sets the filter label
+func (f *TernaryFilter) WithLabel(label string) *TernaryFilter {
+	f.LabelStr = label
+	return f
+}
+
+// TrueLabel sets the label of the "true" option (default "Yes").
+func (f *TernaryFilter) TrueLabel(label string) *TernaryFilter {
+	f.TrueStr = label
+	return f
+}
+
+// FalseLabel sets the label of the "false" option (default "No").
+func (f *TernaryFilter) FalseLabel(label string) *TernaryFilter {
+	f.FalseStr = label
+	return f
+}
+
+// BlankLabel sets the label of the unfiltered option (default "All").
+func (f *TernaryFilter) BlankLabel(label string) *TernaryFilter {
+	f.BlankStr = label
+	return f
+}
+
+// Implementation of the Filter interface
+func (f *TernaryFilter) Key() string   { return f.filterKey }
+func (f *TernaryFilter) Label() string { return f.LabelStr }
+func (f *TernaryFilter) Type() string  { return "ternary" }
+func (f *TernaryFilter) FilterOptions() []FilterOption {
+	return []FilterOption{
+		{Value: "", Label: f.BlankStr},
+		{Value: "true", Label: f.TrueStr},
+		{Value: "false", Label: f.FalseStr},
+	}
+}
+
+// RangeFilter filters a numeric column between a minimum and a maximum.
+// It submits filter_{key}_min and filter_{key}_max; either bound may be
+// left empty for an open-ended range.
+type RangeFilter struct {
+	filterKey string
+	LabelStr  string
+	MinValue  *float64 // optional lower limit of the inputs
+	MaxValue  *float64 // optional upper limit of the inputs
+	StepValue string   // input step ("any" by default)
+}
+
+// Range creates a new numeric range filter.
+func Range(key string) *RangeFilter {
+	return &RangeFilter{
+		filterKey: key,
+		LabelStr:  key,
+		StepValue: "any",
+	}
+}
+
+// WithLabel sets the filter label
+func (f *RangeFilter) WithLabel(label string) *RangeFilter {
+	f.LabelStr = label
+	return f
+}
+
+// Bounds sets the lower and upper limits accepted by the inputs.
+func (f *RangeFilter) Bounds(lo, hi float64) *RangeFilter {
+	f.MinValue, f.MaxValue = &lo, &hi
+	return f
+}
+
+// Step sets the input step (e.g. "0.01" or "1").
+func (f *RangeFilter) Step(step string) *RangeFilter {
+	f.StepValue = step
+	return f
+}
+
+// MinParam returns the query parameter of the lower bound.
+func (f *RangeFilter) MinParam() string { return "filter_" + f.filterKey + "_min" }
+
+// MaxParam returns the query parameter of the upper bound.
+func (f *RangeFilter) MaxParam() string { return "filter_" + f.filterKey + "_max" }
+
+// Implementation of the Filter interface
+func (f *RangeFilter) Key() string                   { return f.filterKey }
+func (f *RangeFilter) Label() string                 { return f.LabelStr }
+func (f *RangeFilter) Type() string                  { return "range" }
+func (f *RangeFilter) FilterOptions() []FilterOption { return nil }
+
+// DateRangeFilter filters a date column (e.g. created_at) between two days.
+// It submits filter_{key}_from and filter_{key}_to as YYYY-MM-DD; setting
+// only "from" means "everything since", only "to" "everything until".
+type DateRangeFilter struct {
+	filterKey string
+	LabelStr  string
+}
+
+// DateRange creates a new date range filter.
+func DateRange(key string) *DateRangeFilter {
+	return &DateRangeFilter{
+		filterKey: key,
+		LabelStr:  key,
+	}
+}
+
+// WithLabel sets the filter label.
+func (f *DateRangeFilter) WithLabel(label string) *DateRangeFilter {
+	f.LabelStr = label
+	return f
+}
+
+// FromParam returns the query parameter of the start date.
+func (f *DateRangeFilter) FromParam() string { return "filter_" + f.filterKey + "_from" }
+
+// ToParam returns the query parameter of the end date.
+func (f *DateRangeFilter) ToParam() string { return "filter_" + f.filterKey + "_to" }
+
+// Implementation of the Filter interface
+func (f *DateRangeFilter) Key() string                   { return f.filterKey }
+func (f *DateRangeFilter) Label() string                 { return f.LabelStr }
+func (f *DateRangeFilter) Type() string                  { return "date_range" }
+func (f *DateRangeFilter) FilterOptions() []FilterOption { return nil }
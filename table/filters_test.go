@@ -27,3 +27,48 @@ func TestTableWithFiltersIntegration(t *testing.T) {
 	assert.Equal(t, "select", tbl.Filters[0].Type())
 	assert.Equal(t, "boolean", tbl.Filters[1].Type())
 }
+
+func TestRangeFilter(t *testing.T) {
+	f := Range("price").WithLabel("Price").Bounds(0, 1000).Step("0.01")
+
+	assert.Equal(t, "range", f.Type())
+	assert.Equal(t, "Price", f.Label())
+	assert.Equal(t, "filter_price_min", f.MinParam())
+	assert.Equal(t, "filter_price_max", f.MaxParam())
+	assert.Equal(t, 0.0, *f.MinValue)
+	assert.Equal(t, 1000.0, *f.MaxValue)
+	assert.Equal(t, "0.01", f.StepValue)
+	assert.Nil(t, f.FilterOptions())
+}
+
+func TestDateRangeFilter(t *testing.T) {
+	f := DateRange("created_at").WithLabel("Created")
+
+	assert.Equal(t, "date_range", f.Type())
+	assert.Equal(t, "Created", f.Label())
+	assert.Equal(t, "filter_created_at_from", f.FromParam())
+	assert.Equal(t, "filter_created_at_to", f.ToParam())
+}
+
+func TestTernaryFilter(t *testing.T) {
+	f := Ternary("verified").WithLabel("Verified")
+	assert.Equal(t, "ternary", f.Type())
+	assert.Equal(t, []FilterOption{
+		{Value: "", Label: "All"},
+		{Value: "true", Label: "Yes"},
+		{Value: "false", Label: "No"},
+	}, f.FilterOptions())
+
+	f.TrueLabel("Verified only").FalseLabel("Unverified").BlankLabel("Any")
+	opts := f.FilterOptions()
+	assert.Equal(t, "Any", opts[0].Label)
+	assert.Equal(t, "Verified only", opts[1].Label)
+	assert.Equal(t, "Unverified", opts[2].Label)
+}
+
+func TestSelectFilterWithMultiple(t *testing.T) {
+	f := Select("status")
+	assert.False(t, f.IsMultiple())
+	assert.True(t, f.WithMultiple().IsMultiple())
+	assert.Equal(t, "select", f.Type())
+}
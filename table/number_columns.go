@@ -0,0 +1,357 @@
+package table
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// numberLocale describes how numbers are written in a given locale.
+type numberLocale struct {
+	thousands    string
+	decimal      string
+	symbolBefore bool
+}
+
+// numberLocales lists the supported locale tags. Unknown tags fall back to "fr".
+var numberLocales = map[string]numberLocale{
+	"fr": {thousands: "\u00a0", decimal: ","}, // non-breaking space
+	"de": {thousands: ".", decimal: ","},
+	"es": {thousands: ".", decimal: ","},
+	"it": {thousands: ".", decimal: ","},
+	"en": {thousands: ",", decimal: ".", symbolBefore: true},
+}
+
+// currencySymbols maps ISO 4217 codes to display symbols.
+var currencySymbols = map[string]string{
+	"EUR": "€",
+	"USD": "$",
+	"GBP": "£",
+	"JPY": "¥",
+	"CHF": "CHF",
+}
+
+// NumberColumn displays a numeric field with locale-aware thousands and
+// decimal separators, and an optional currency symbol.
+type NumberColumn struct {
+	colKey       string
+	LabelStr     string
+	SortableFlag bool
+	DecimalCount int
+	CurrencyCode string
+	LocaleTag    string
+	colType      string
+	ValueFunc    func(item any) float64 // optional: replaces reflect-based lookup
+
+	// NegativeClass styles negative values, see NegativeColor.
+	NegativeClass string
+	// ParenNegative writes negative values in parentheses, as in accounting.
+	ParenNegative bool
+
+	surfaces
+}
+
+// Number creates a new number column (0 decimals, French formatting).
+func Number(key string) *NumberColumn {
+	return &NumberColumn{
+		colKey:    key,
+		LabelStr:  key,
+		LocaleTag: "fr",
+		colType:   "number",
+	}
+}
+
+// Money creates a new currency column (2 decimals, EUR, French formatting).
+func Money(key string) *NumberColumn {
+	c := Number(key)
+	c.DecimalCount = 2
+	c.CurrencyCode = "EUR"
+	c.colType = "money"
+	return c
+}
+
+// WithLabel sets the column label.
+func (c *NumberColumn) WithLabel(label string) *NumberColumn {
+	c.LabelStr = label
+	return c
+}
+
+// Sortable makes the column sortable.
+func (c *NumberColumn) Sortable() *NumberColumn {
+	c.SortableFlag = true
+	return c
+}
+
+// Decimals sets the number of decimals displayed.
+func (c *NumberColumn) Decimals(n int) *NumberColumn {
+	if n < 0 {
+		n = 0
+	}
+	c.DecimalCount = n
+	return c
+}
+
+// Currency sets the ISO 4217 currency code (e.g. "EUR", "USD").
+func (c *NumberColumn) Currency(code string) *NumberColumn {
+	c.CurrencyCode = strings.ToUpper(code)
+	return c
+}
+
+// Locale sets the formatting locale (e.g. "fr", "en-US", "de").
+func (c *NumberColumn) Locale(tag string) *NumberColumn {
+	c.LocaleTag = tag
+	return c
+}
+
+// NegativeColor styles negative values with the given CSS classes, or in red
+// when class is empty.
+func (c *NumberColumn) NegativeColor(class string) *NumberColumn {
+	if class == "" {
+		class = "text-red-600 dark:text-red-400"
+	}
+	c.NegativeClass = class
+	return c
+}
+
+// Parentheses writes negative values in parentheses instead of with a minus
+// sign, e.g. "(1 234,50 €)".
+func (c *NumberColumn) Parentheses() *NumberColumn {
+	c.ParenNegative = true
+	return c
+}
+
+// Using sets a custom accessor function, bypassing reflection.
+func (c *NumberColumn) Using(fn func(item any) float64) *NumberColumn {
+	c.ValueFunc = fn
+	return c
+}
+
+// Column interface implementation
+func (c *NumberColumn) Key() string        { return c.colKey }
+func (c *NumberColumn) Label() string      { return c.LabelStr }
+func (c *NumberColumn) Type() string       { return c.colType }
+func (c *NumberColumn) IsSortable() bool   { return c.SortableFlag }
+func (c *NumberColumn) IsSearchable() bool { return false }
+func (c *NumberColumn) IsCopyable() bool   { return false }
+func (c *NumberColumn) GetAlign() string   { return "right" }
+func (c *NumberColumn) Value(item any) string {
+	n, ok := c.number(item)
+	if !ok {
+		return ""
+	}
+	return c.Format(n)
+}
+
+// Format formats n using the column's decimals, locale and currency.
+func (c *NumberColumn) Format(n float64) string {
+	loc := lookupLocale(c.LocaleTag)
+	s := formatNumber(n, c.DecimalCount, loc)
+	negative := strings.HasPrefix(s, "-")
+	if negative {
+		s = s[1:]
+	}
+	if c.CurrencyCode != "" {
+		symbol, ok := currencySymbols[c.CurrencyCode]
+		if !ok {
+			symbol = c.CurrencyCode
+		}
+		if loc.symbolBefore {
+			s = symbol + s
+		} else {
+			s += "\u00a0" + symbol
+		}
+	}
+	switch {
+	case negative && c.ParenNegative:
+		return "(" + s + ")"
+	case negative:
+		return "-" + s
+	}
+	return s
+}
+
+// IsNegative reports whether the item's value is displayed as negative, i.e.
+// is below zero once rounded to the column's decimals.
+func (c *NumberColumn) IsNegative(item any) bool {
+	n, ok := c.number(item)
+	return ok && strings.HasPrefix(formatNumber(n, c.DecimalCount, lookupLocale(c.LocaleTag)), "-")
+}
+
+// CellClass returns the CSS classes of the item's cell: NegativeClass for
+// negative values, "" otherwise.
+func (c *NumberColumn) CellClass(item any) string {
+	if c.IsNegative(item) {
+		return c.NegativeClass
+	}
+	return ""
+}
+
+// number extracts the numeric value of the field.
+func (c *NumberColumn) number(item any) (float64, bool) {
+	if c.ValueFunc != nil {
+		return c.ValueFunc(item), true
+	}
+	return numericField(item, c.colKey)
+}
+
+// numericField reads a numeric (or numeric string) struct field by name.
+func numericField(item any, key string) (float64, bool) {
+	v := reflect.ValueOf(item)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	field := v.FieldByName(key)
+	if !field.IsValid() {
+		return 0, false
+	}
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return 0, false
+		}
+		field = field.Elem()
+	}
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(field.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(field.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), true
+	case reflect.String:
+		f, err := strconv.ParseFloat(field.String(), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// lookupLocale resolves a tag such as "en-US" or "fr_FR" to its base language.
+func lookupLocale(tag string) numberLocale {
+	lang := strings.ToLower(tag)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	if loc, ok := numberLocales[lang]; ok {
+		return loc
+	}
+	return numberLocales["fr"]
+}
+
+// formatNumber renders n with grouped thousands and a fixed number of decimals.
+func formatNumber(n float64, decimals int, loc numberLocale) string {
+	s := strconv.FormatFloat(math.Abs(n), 'f', decimals, 64)
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+
+	var b strings.Builder
+	if n < 0 && strings.Trim(s, "0.") != "" {
+		b.WriteByte('-')
+	}
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(loc.thousands)
+		}
+		b.WriteRune(r)
+	}
+	if fracPart != "" {
+		b.WriteString(loc.decimal)
+		b.WriteString(fracPart)
+	}
+	return b.String()
+}
+
+// ProgressColumn renders a numeric field as a horizontal progress bar.
+type ProgressColumn struct {
+	colKey       string
+	LabelStr     string
+	SortableFlag bool
+	MaxValue     float64
+	Thresholds   map[float64]string     // lower bound -> color
+	ValueFunc    func(item any) float64 // optional: replaces reflect-based lookup
+
+	surfaces
+}
+
+// Progress creates a new progress column for values between 0 and 100.
+func Progress(key string) *ProgressColumn {
+	return &ProgressColumn{
+		colKey:   key,
+		LabelStr: key,
+		MaxValue: 100,
+	}
+}
+
+// WithLabel sets the column label.
+func (c *ProgressColumn) WithLabel(label string) *ProgressColumn {
+	c.LabelStr = label
+	return c
+}
+
+// Sortable makes the column sortable.
+func (c *ProgressColumn) Sortable() *ProgressColumn {
+	c.SortableFlag = true
+	return c
+}
+
+// Max sets the value that corresponds to a full bar (default 100).
+func (c *ProgressColumn) Max(n float64) *ProgressColumn {
+	if n > 0 {
+		c.MaxValue = n
+	}
+	return c
+}
+
+// ColorThresholds colors the bar by value. Each key is a lower bound: the
+// color of the highest bound not greater than the value is used, e.g.
+// {0: "danger", 30: "warning", 70: "success"}.
+func (c *ProgressColumn) ColorThresholds(thresholds map[float64]string) *ProgressColumn {
+	c.Thresholds = thresholds
+	return c
+}
+
+// Using sets a custom accessor function, bypassing reflection.
+func (c *ProgressColumn) Using(fn func(item any) float64) *ProgressColumn {
+	c.ValueFunc = fn
+	return c
+}
+
+// Raw returns the raw numeric value of the field.
+func (c *ProgressColumn) Raw(item any) float64 {
+	if c.ValueFunc != nil {
+		return c.ValueFunc(item)
+	}
+	n, _ := numericField(item, c.colKey)
+	return n
+}
+
+// Percent returns the bar width in percent, clamped to 0–100.
+func (c *ProgressColumn) Percent(item any) float64 {
+	p := c.Raw(item) / c.MaxValue * 100
+	return math.Max(0, math.Min(100, p))
+}
+
+// GetColor returns the bar color for an item ("primary" if no threshold matches).
+func (c *ProgressColumn) GetColor(item any) string {
+	value := c.Raw(item)
+	color, best, found := "primary", 0.0, false
+	for bound, col := range c.Thresholds {
+		if value >= bound && (!found || bound > best) {
+			color, best, found = col, bound, true
+		}
+	}
+	return color
+}
+
+// Column interface implementation
+func (c *ProgressColumn) Key() string        { return c.colKey }
+func (c *ProgressColumn) Label() string      { return c.LabelStr }
+func (c *ProgressColumn) Type() string       { return "progress" }
+func (c *ProgressColumn) IsSortable() bool   { return c.SortableFlag }
+func (c *ProgressColumn) IsSearchable() bool { return false }
+func (c *ProgressColumn) IsCopyable() bool   { return false }
+func (c *ProgressColumn) GetAlign() string   { return "left" }
+func (c *ProgressColumn) Value(item any) string {
+	return strconv.FormatFloat(c.Raw(item), 'f', -1, 64)
+}
@@ -0,0 +1,231 @@
+package table
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+)
+
+// PerPageOptions are the page sizes offered by the per-page selector.
+var PerPageOptions = []int{10, 25, 50, 100}
+
+// maxPerPage caps ?per_page= to keep a single request bounded.
+const maxPerPage = 200
+
+type pageParamsKey struct{}
+
+// PageParams holds the pagination parameters of a request.
+type PageParams struct {
+	Page    int        // ?page=N (1-indexed)
+	PerPage int        // ?per_page=N (0 = table default)
+	Query   url.Values // the full query, kept so page links preserve filters/sort
+}
+
+// ParsePageParams reads ?page= and ?per_page= from the request.
+// Invalid or missing values yield page 1 and PerPage 0 (table default).
+func ParsePageParams(r *http.Request) PageParams {
+	q := r.URL.Query()
+	p := PageParams{Page: 1, Query: q}
+	if n, err := strconv.Atoi(q.Get("page")); err == nil && n > 0 {
+		p.Page = n
+	}
+	if n, err := strconv.Atoi(q.Get("per_page")); err == nil && n > 0 && n <= maxPerPage {
+		p.PerPage = n
+	}
+	return p
+}
+
+// WithPageParams stores the pagination parameters in the context.
+func WithPageParams(ctx context.Context, p PageParams) context.Context {
+	return context.WithValue(ctx, pageParamsKey{}, p)
+}
+
+// PageParamsFromContext retrieves the pagination parameters from the context.
+func PageParamsFromContext(ctx context.Context) (PageParams, bool) {
+	p, ok := ctx.Value(pageParamsKey{}).(PageParams)
+	return p, ok
+}
+
+// PageLink is a link to one page of the table, or an ellipsis standing for
+// the pages left out between two links.
+type PageLink struct {
+	Page     int
+	URL      string
+	Active   bool
+	Ellipsis bool // no Page nor URL
+}
+
+// pageWindow is the number of page links shown on each side of the current
+// page, besides the first and last pages.
+const pageWindow = 2
+
+// TableState is the paginated view of a table for one request.
+type TableState struct {
+	Rows           []any // rows of the current page
+	Total          int
+	CurrentPage    int
+	PerPage        int
+	LastPage       int
+	Links          []PageLink
+	PrevURL        string // empty on the first page
+	NextURL        string // empty on the last page
+	PerPageOptions []int
+
+	query url.Values
+}
+
+// From returns the 1-based index of the first row of the page (0 if empty).
+func (s TableState) From() int {
+	if s.Total == 0 {
+		return 0
+	}
+	return (s.CurrentPage-1)*s.PerPage + 1
+}
+
+// To returns the 1-based index of the last row of the page.
+func (s TableState) To() int {
+	return min(s.CurrentPage*s.PerPage, s.Total)
+}
+
+// PerPageURL returns the URL showing n rows per page, starting from page 1.
+func (s TableState) PerPageURL(n int) string {
+	return pageURL(s.query, 1, n)
+}
+
+// SortURL returns the URL sorting by column key, toggling the direction if
+// the table is already sorted by it. Sorting goes back to the first page.
+func (s TableState) SortURL(key string) string {
+	dir := "asc"
+	if s.query.Get("sort") == key && s.query.Get("dir") != "desc" {
+		dir = "desc"
+	}
+	q := url.Values{}
+	for k, v := range s.query {
+		q[k] = v
+	}
+	q.Set("sort", key)
+	q.Set("dir", dir)
+	q.Del("page")
+	return "?" + q.Encode()
+}
+
+// SortIcon returns the Material icon reflecting the sort state of a column.
+func (s TableState) SortIcon(key string) string {
+	switch {
+	case s.query.Get("sort") != key:
+		return "unfold_more"
+	case s.query.Get("dir") == "desc":
+		return "arrow_downward"
+	}
+	return "arrow_upward"
+}
+
+// Paginate enables pagination with perPage rows per page.
+// A non-positive value disables pagination.
+func (t *Table) Paginate(perPage int) *Table {
+	t.Pagination = perPage > 0
+	if perPage > 0 {
+		t.PerPage = perPage
+	}
+	return t
+}
+
+// WithTotal declares that Rows already hold the current page only (the query
+// was paginated server-side) and sets the total row count across all pages.
+func (t *Table) WithTotal(total int) *Table {
+	t.Total = total
+	return t
+}
+
+// BuildTableState computes the pagination state of rows for the request whose
+// parameters are stored in ctx (see WithPageParams). A page beyond the last
+// one is clamped to the last page.
+func (t *Table) BuildTableState(ctx context.Context, rows []any) TableState {
+	params, _ := PageParamsFromContext(ctx)
+
+	perPage := t.PerPage
+	if params.PerPage > 0 {
+		perPage = params.PerPage
+	}
+	if !t.Pagination || perPage <= 0 {
+		return TableState{Rows: rows, Total: len(rows), CurrentPage: 1, PerPage: len(rows), LastPage: 1, query: params.Query}
+	}
+
+	serverSide := t.Total > 0
+	total := len(rows)
+	if serverSide {
+		total = t.Total
+	}
+
+	lastPage := max(1, (total+perPage-1)/perPage)
+	page := min(max(params.Page, 1), lastPage)
+
+	if !serverSide {
+		start := min((page-1)*perPage, total)
+		end := min(start+perPage, total)
+		rows = rows[start:end]
+	}
+
+	state := TableState{
+		Rows:           rows,
+		Total:          total,
+		CurrentPage:    page,
+		PerPage:        perPage,
+		LastPage:       lastPage,
+		PerPageOptions: PerPageOptions,
+		query:          params.Query,
+	}
+	if !slices.Contains(PerPageOptions, perPage) {
+		state.PerPageOptions = append(slices.Clone(PerPageOptions), perPage)
+		slices.Sort(state.PerPageOptions)
+	}
+	state.Links = PageLinks(params.Query, page, lastPage, perPage)
+	if page > 1 {
+		state.PrevURL = pageURL(params.Query, page-1, perPage)
+	}
+	if page < lastPage {
+		state.NextURL = pageURL(params.Query, page+1, perPage)
+	}
+	return state
+}
+
+// PageLinks returns the links to the first and last pages and to the pages
+// within two pages of the current one, with an ellipsis for each gap. A gap
+// of a single page shows that page instead. The URLs keep the params of
+// query.
+func PageLinks(query url.Values, page, lastPage, perPage int) []PageLink {
+	pages := []int{1}
+	for p := max(2, page-pageWindow); p <= min(lastPage-1, page+pageWindow); p++ {
+		pages = append(pages, p)
+	}
+	if lastPage > 1 {
+		pages = append(pages, lastPage)
+	}
+
+	var links []PageLink
+	prev := 0
+	for _, p := range pages {
+		switch {
+		case p-prev == 2:
+			links = append(links, PageLink{Page: prev + 1, URL: pageURL(query, prev+1, perPage)})
+		case p-prev > 2:
+			links = append(links, PageLink{Ellipsis: true})
+		}
+		links = append(links, PageLink{Page: p, URL: pageURL(query, p, perPage), Active: p == page})
+		prev = p
+	}
+	return links
+}
+
+// pageURL returns a relative URL ("?...") for page, keeping the other params.
+func pageURL(query url.Values, page, perPage int) string {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("page", strconv.Itoa(page))
+	q.Set("per_page", strconv.Itoa(perPage))
+	return "?" + q.Encode()
+}
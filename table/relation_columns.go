@@ -0,0 +1,150 @@
+package table
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// RelationColumn displays a field of a related record, e.g. the name of an
+// order's customer. Keys may be dotted paths ("Edges.Customer.Name"); a nil
+// pointer along the path yields an empty cell.
+//
+// With Modal, clicking the cell previews the related record in a slideover
+// instead of navigating away.
+type RelationColumn struct {
+	colKey       string
+	LabelStr     string
+	SortableFlag bool
+	SearchFlag   bool
+	IDKey        string                // field holding the related record's ID
+	ModalRoute   string                // e.g. "/customers/{id}"
+	ValueFunc    func(item any) string // optional: replaces reflect-based lookup
+	IDFunc       func(item any) string // optional: replaces reflect-based ID lookup
+
+	surfaces
+}
+
+// Relation creates a new relation column. For a dotted key the related ID
+// defaults to the ID field next to the displayed one ("Edges.Customer.ID").
+func Relation(key string) *RelationColumn {
+	c := &RelationColumn{
+		colKey:   key,
+		LabelStr: key,
+	}
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		c.IDKey = key[:i] + ".ID"
+	}
+	return c
+}
+
+// WithLabel sets the column label.
+func (c *RelationColumn) WithLabel(label string) *RelationColumn {
+	c.LabelStr = label
+	return c
+}
+
+// Sortable makes the column sortable.
+func (c *RelationColumn) Sortable() *RelationColumn {
+	c.SortableFlag = true
+	return c
+}
+
+// Searchable makes the column searchable.
+func (c *RelationColumn) Searchable() *RelationColumn {
+	c.SearchFlag = true
+	return c
+}
+
+// Using sets a custom accessor function, bypassing reflection.
+func (c *RelationColumn) Using(fn func(item any) string) *RelationColumn {
+	c.ValueFunc = fn
+	return c
+}
+
+// RelatedID sets the field holding the related record's ID (e.g. "CustomerID").
+func (c *RelationColumn) RelatedID(key string) *RelationColumn {
+	c.IDKey = key
+	return c
+}
+
+// RelatedIDUsing sets a custom accessor for the related record's ID.
+func (c *RelationColumn) RelatedIDUsing(fn func(item any) string) *RelationColumn {
+	c.IDFunc = fn
+	return c
+}
+
+// Modal opens the related record in a slideover when the cell is clicked.
+// routeTemplate is the record's URL with an {id} placeholder, e.g.
+// "/customers/{id}"; it is loaded with ?fragment=1 so that the panel answers
+// with the record's view alone, without the page layout.
+func (c *RelationColumn) Modal(routeTemplate string) *RelationColumn {
+	c.ModalRoute = routeTemplate
+	return c
+}
+
+// RelatedKey returns the ID of the item's related record, or "" if unknown.
+func (c *RelationColumn) RelatedKey(item any) string {
+	if c.IDFunc != nil {
+		return c.IDFunc(item)
+	}
+	if c.IDKey == "" {
+		return ""
+	}
+	return fieldPathValue(item, c.IDKey)
+}
+
+// ModalURL returns the URL loaded into the slideover for item, or "" when
+// the column has no modal or the item has no related record.
+func (c *RelationColumn) ModalURL(item any) string {
+	id := c.RelatedKey(item)
+	if c.ModalRoute == "" || id == "" {
+		return ""
+	}
+	u := strings.ReplaceAll(c.ModalRoute, "{id}", url.PathEscape(id))
+	if strings.Contains(u, "?") {
+		return u + "&fragment=1"
+	}
+	return u + "?fragment=1"
+}
+
+// Column interface implementation
+func (c *RelationColumn) Key() string        { return c.colKey }
+func (c *RelationColumn) Label() string      { return c.LabelStr }
+func (c *RelationColumn) Type() string       { return "relation" }
+func (c *RelationColumn) IsSortable() bool   { return c.SortableFlag }
+func (c *RelationColumn) IsSearchable() bool { return c.SearchFlag }
+func (c *RelationColumn) IsCopyable() bool   { return false }
+func (c *RelationColumn) GetAlign() string   { return "left" }
+func (c *RelationColumn) Value(item any) string {
+	if c.ValueFunc != nil {
+		return c.ValueFunc(item)
+	}
+	return fieldPathValue(item, c.colKey)
+}
+
+// fieldPathValue follows a dotted field path through structs and pointers
+// and formats the value found, or returns "" if the path is broken.
+func fieldPathValue(item any, path string) string {
+	v := reflect.ValueOf(item)
+	for _, name := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return ""
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return ""
+		}
+		v = v.FieldByName(name)
+		if !v.IsValid() {
+			return ""
+		}
+	}
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return ""
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
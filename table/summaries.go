@@ -0,0 +1,119 @@
+package table
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Summarizer computes a footer value for a column from the table rows.
+type Summarizer interface {
+	ColumnKey() string // column the value is shown under ("" = first column)
+	Label() string
+	Compute(rows []any) string
+}
+
+// summaryLabels are the default labels of the built-in aggregations.
+var summaryLabels = map[SummaryType]string{
+	SummarySum:     "Sum",
+	SummaryAverage: "Average",
+	SummaryMin:     "Min",
+	SummaryMax:     "Max",
+	SummaryCount:   "Count",
+}
+
+// Sum creates a summary adding up a numeric column.
+func Sum(key string) *SummaryDef { return NewSummary(key, SummarySum) }
+
+// Avg creates a summary averaging a numeric column.
+func Avg(key string) *SummaryDef { return NewSummary(key, SummaryAverage) }
+
+// Min creates a summary showing the smallest value of a numeric column.
+func Min(key string) *SummaryDef { return NewSummary(key, SummaryMin) }
+
+// Max creates a summary showing the largest value of a numeric column.
+func Max(key string) *SummaryDef { return NewSummary(key, SummaryMax) }
+
+// Count creates a summary counting the rows, shown under the first column.
+// Use On to show it under another column.
+func Count() *SummaryDef { return NewSummary("", SummaryCount) }
+
+// On sets the column the summary is shown under.
+func (s *SummaryDef) On(key string) *SummaryDef {
+	s.colKey = key
+	return s
+}
+
+// Summarize adds footer summaries to the table, e.g.
+//
+//	tbl.Summarize(table.Sum("Amount"), table.Avg("Rating"), table.Count())
+func (t *Table) Summarize(summaries ...Summarizer) *Table {
+	t.Summaries = append(t.Summaries, summaries...)
+	return t
+}
+
+// SummarizeAllRows also shows the summaries computed over all rows, not just
+// the current page, when the table is paginated in memory.
+func (t *Table) SummarizeAllRows() *Table {
+	t.SummaryAllRows = true
+	return t
+}
+
+// SummariesFor returns the summaries shown under the column at index i.
+func (t *Table) SummariesFor(i int) []Summarizer {
+	if i < 0 || i >= len(t.Columns) {
+		return nil
+	}
+	key := t.Columns[i].Key()
+	var out []Summarizer
+	for _, s := range t.Summaries {
+		if s.ColumnKey() == key || (s.ColumnKey() == "" && i == 0) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// aggregate computes a built-in aggregation. Non-numeric values are skipped.
+func (s *SummaryDef) aggregate(rows []any) string {
+	if s.sumType == SummaryCount {
+		return s.format(float64(len(rows)))
+	}
+
+	var sum float64
+	count := 0
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, row := range rows {
+		n, ok := numericField(row, s.colKey)
+		if !ok {
+			continue
+		}
+		sum += n
+		count++
+		lo, hi = math.Min(lo, n), math.Max(hi, n)
+	}
+	if count == 0 {
+		return ""
+	}
+
+	switch s.sumType {
+	case SummarySum:
+		return s.format(sum)
+	case SummaryAverage:
+		return s.format(sum / float64(count))
+	case SummaryMin:
+		return s.format(lo)
+	case SummaryMax:
+		return s.format(hi)
+	}
+	return ""
+}
+
+// format renders n with the summary's format string, or with at most two
+// decimals by default.
+func (s *SummaryDef) format(n float64) string {
+	if s.formatStr != "" {
+		return fmt.Sprintf(s.formatStr, n)
+	}
+	return strconv.FormatFloat(math.Round(n*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,64 @@
+package table
+
+// surfaces records the surfaces other than the list view a column is left
+// out of. It is embedded in the built-in column types.
+type surfaces struct {
+	noExport bool
+	noAPI    bool
+}
+
+func (s *surfaces) columnSurfaces() *surfaces { return s }
+
+// surfaceColumn is a column carrying surface flags.
+type surfaceColumn interface {
+	Column
+	columnSurfaces() *surfaces
+}
+
+// ExcludeFromExport leaves col out of CSV/Excel exports while keeping it in
+// the list view and the API:
+//
+//	table.ExcludeFromExport(table.Text("avatar").Label("Avatar"))
+func ExcludeFromExport[C surfaceColumn](col C) C {
+	col.columnSurfaces().noExport = true
+	return col
+}
+
+// ExcludeFromAPI leaves col out of JSON responses while keeping it in the
+// list view and exports.
+func ExcludeFromAPI[C surfaceColumn](col C) C {
+	col.columnSurfaces().noAPI = true
+	return col
+}
+
+// InExport reports whether col is included in exports.
+func InExport(col Column) bool {
+	s, ok := col.(surfaceColumn)
+	return !ok || !s.columnSurfaces().noExport
+}
+
+// InAPI reports whether col is included in JSON responses.
+func InAPI(col Column) bool {
+	s, ok := col.(surfaceColumn)
+	return !ok || !s.columnSurfaces().noAPI
+}
+
+// ExportColumns returns the columns of cols included in exports.
+func ExportColumns(cols []Column) []Column {
+	return filterColumns(cols, InExport)
+}
+
+// APIColumns returns the columns of cols included in JSON responses.
+func APIColumns(cols []Column) []Column {
+	return filterColumns(cols, InAPI)
+}
+
+func filterColumns(cols []Column, keep func(Column) bool) []Column {
+	out := make([]Column, 0, len(cols))
+	for _, col := range cols {
+		if keep(col) {
+			out = append(out, col)
+		}
+	}
+	return out
+}
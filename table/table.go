@@ -13,12 +13,17 @@ type Table struct {
 	Actions     []*actions.Action
 	BulkActions []*BulkAction
 	Filters     []Filter
-	Summaries   []Summary
+	Summaries   []Summarizer
 	Groups      []Grouping
 	Searchable  bool
 	Pagination  bool
 	PerPage     int
+	Total       int // total row count when Rows is already paginated (see WithTotal)
 	BaseURL     string
+	// RowClassFunc returns extra CSS classes for a row's <tr> (optional).
+	RowClassFunc func(item any) string
+	// SummaryAllRows adds a footer row summarizing all rows, not only the page.
+	SummaryAllRows bool
 }
 
 // New creates a new Table instance.
@@ -53,6 +58,52 @@ func (t *Table) WithBaseURL(url string) *Table {
 	return t
 }
 
+type resourceURLKey struct{}
+
+// WithResourceURL stores the URL of the resource listed by the request, the
+// default base URL of its tables (see URL).
+func WithResourceURL(ctx context.Context, url string) context.Context {
+	return context.WithValue(ctx, resourceURLKey{}, url)
+}
+
+// URL returns the base URL of the table: BaseURL, or the URL of the resource
+// listed by the request (see WithResourceURL). Inline cells post to
+// {URL}/{id}/inline.
+func (t *Table) URL(ctx context.Context) string {
+	if t.BaseURL != "" {
+		return t.BaseURL
+	}
+	url, _ := ctx.Value(resourceURLKey{}).(string)
+	return url
+}
+
+// RowClass sets a function returning extra CSS classes for each row, e.g. to
+// grey out or strike through inactive or soft-deleted records.
+func (t *Table) RowClass(fn func(item any) string) *Table {
+	t.RowClassFunc = fn
+	return t
+}
+
+// GetRowClass returns the extra CSS classes for a row ("" by default).
+func (t *Table) GetRowClass(item any) string {
+	if t.RowClassFunc == nil {
+		return ""
+	}
+	return t.RowClassFunc(item)
+}
+
+// SortableColumns returns the keys of the columns whose IsSortable is true.
+// Resources built on a Table can return it from engine.ResourceSortable.
+func (t *Table) SortableColumns() []string {
+	keys := make([]string, 0, len(t.Columns))
+	for _, col := range t.Columns {
+		if col.IsSortable() {
+			keys = append(keys, col.Key())
+		}
+	}
+	return keys
+}
+
 // AddColumn adds a simple column.
 func (t *Table) AddColumn(key, label string) *Table {
 	col := Text(key).WithLabel(label)
@@ -74,7 +125,9 @@ func (t *Table) WithFilters(filters ...Filter) *Table {
 
 // WithSummaries sets the column summaries shown in the table footer.
 func (t *Table) WithSummaries(summaries ...Summary) *Table {
-	t.Summaries = append(t.Summaries, summaries...)
+	for _, s := range summaries {
+		t.Summaries = append(t.Summaries, s)
+	}
 	return t
 }
 
@@ -90,12 +143,6 @@ func (t *Table) Search(enabled bool) *Table {
 	return t
 }
 
-// Paginate enables/disables pagination.
-func (t *Table) Paginate(enabled bool) *Table {
-	t.Pagination = enabled
-	return t
-}
-
 // Column is the common interface for all columns.
 type Column interface {
 	Key() string
@@ -104,6 +151,7 @@ type Column interface {
 	IsSortable() bool
 	IsSearchable() bool
 	IsCopyable() bool
+	GetAlign() string // "left", "center" or "right"
 	Value(item any) string
 }
 
@@ -143,11 +191,9 @@ const (
 
 // Summary defines a column-level aggregation shown in the table footer.
 type Summary interface {
-	ColumnKey() string
-	Label() string
+	Summarizer
 	Type() SummaryType
 	Format() string
-	Compute(rows []any) string
 }
 
 // SummaryDef is the concrete implementation of Summary.
@@ -186,14 +232,24 @@ func (s *SummaryDef) WithCompute(fn func(rows []any) string) *SummaryDef {
 }
 
 func (s *SummaryDef) ColumnKey() string { return s.colKey }
-func (s *SummaryDef) Label() string     { return s.labelStr }
 func (s *SummaryDef) Type() SummaryType { return s.sumType }
 func (s *SummaryDef) Format() string    { return s.formatStr }
+
+// Label returns the summary label, defaulting to the aggregation name.
+func (s *SummaryDef) Label() string {
+	if s.labelStr != "" {
+		return s.labelStr
+	}
+	return summaryLabels[s.sumType]
+}
+
+// Compute aggregates the column over rows, using the custom compute function
+// if one is set.
 func (s *SummaryDef) Compute(rows []any) string {
 	if s.computeFn != nil {
 		return s.computeFn(rows)
 	}
-	return ""
+	return s.aggregate(rows)
 }
 
 // ---------------------------------------------------------------------------
@@ -1,6 +1,11 @@
 package table
 
 import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -63,11 +68,16 @@ func TestTableSearch(t *testing.T) {
 }
 
 func TestTablePaginate(t *testing.T) {
-	tbl := New(nil).Paginate(false)
+	tbl := New(nil).Paginate(0)
 
 	if tbl.Pagination {
 		t.Error("Expected Pagination to be false")
 	}
+
+	tbl = New(nil).Paginate(25)
+	if !tbl.Pagination || tbl.PerPage != 25 {
+		t.Errorf("Expected pagination with 25 per page, got %v/%d", tbl.Pagination, tbl.PerPage)
+	}
 }
 
 func TestTextColumn(t *testing.T) {
@@ -105,6 +115,44 @@ func TestTextColumnInterface(t *testing.T) {
 	}
 }
 
+func TestTextColumnFormatUsing(t *testing.T) {
+	type person struct {
+		FirstName string
+		LastName  string
+		Cents     int
+	}
+	p := &person{FirstName: "Ada", LastName: "Lovelace", Cents: 12345}
+
+	fullName := Text("FirstName").FormatUsing(func(value any, item any) string {
+		return value.(string) + " " + item.(*person).LastName
+	})
+	if got := fullName.Value(p); got != "Ada Lovelace" {
+		t.Errorf("Expected 'Ada Lovelace', got '%s'", got)
+	}
+
+	price := Text("Cents").FormatUsing(func(value any, _ any) string {
+		c := value.(int)
+		return fmt.Sprintf("%d.%02d €", c/100, c%100)
+	})
+	if got := price.Value(p); got != "123.45 €" {
+		t.Errorf("Expected '123.45 €', got '%s'", got)
+	}
+
+	missing := Text("Unknown").FormatUsing(func(value any, _ any) string {
+		if value == nil {
+			return "-"
+		}
+		return "set"
+	})
+	if got := missing.Value(p); got != "-" {
+		t.Errorf("Expected nil value for missing field, got '%s'", got)
+	}
+
+	if got := Text("Cents").Value(p); got != "12345" {
+		t.Errorf("Expected unformatted '12345', got '%s'", got)
+	}
+}
+
 func TestBadgeColumn(t *testing.T) {
 	col := Badge("status").
 		WithLabel("Status").
@@ -124,6 +172,90 @@ func TestBadgeColumn(t *testing.T) {
 	}
 }
 
+func TestBadgesColumn(t *testing.T) {
+	type post struct {
+		Tags     []string
+		Keywords string
+	}
+	item := &post{Tags: []string{"go", "", "web"}, Keywords: "a, b ,,c"}
+
+	col := Badges("Tags").Colors(map[string]string{"go": "success"})
+	if col.Type() != "badges" {
+		t.Errorf("Expected type 'badges', got '%s'", col.Type())
+	}
+	values := col.GetValues(item)
+	if len(values) != 2 || values[0] != "go" || values[1] != "web" {
+		t.Errorf("Expected [go web], got %v", values)
+	}
+	if col.Value(item) != "go, web" {
+		t.Errorf("Expected 'go, web', got '%s'", col.Value(item))
+	}
+	if col.GetColor("go") != "success" || col.GetColor("web") != "primary" {
+		t.Error("Expected shared color map with primary fallback")
+	}
+
+	csv := Badges("Keywords").GetValues(item)
+	if len(csv) != 3 || csv[2] != "c" {
+		t.Errorf("Expected [a b c], got %v", csv)
+	}
+}
+
+func TestIconColumn(t *testing.T) {
+	type server struct{ Status string }
+	col := Icon("Status").
+		Icons(map[string]string{"up": "check_circle", "down": "error"}).
+		Fallback("help").
+		TooltipUsing(func(v string) string { return "Status: " + v })
+
+	if col.Type() != "icon" {
+		t.Errorf("Expected type 'icon', got '%s'", col.Type())
+	}
+	raw := col.Value(server{Status: "down"})
+	if col.GetIcon(raw) != "error" {
+		t.Errorf("Expected icon 'error', got '%s'", col.GetIcon(raw))
+	}
+	if col.GetIcon("maintenance") != "help" {
+		t.Errorf("Expected fallback icon 'help', got '%s'", col.GetIcon("maintenance"))
+	}
+	if col.GetTooltip(raw) != "Status: down" {
+		t.Errorf("Expected tooltip 'Status: down', got '%s'", col.GetTooltip(raw))
+	}
+	if Icon("Status").GetTooltip("up") != "" {
+		t.Error("Expected no tooltip by default")
+	}
+	if Icon("Status").WithTooltip().GetTooltip("up") != "up" {
+		t.Error("Expected WithTooltip to use the raw value")
+	}
+}
+
+func TestStepperColumn(t *testing.T) {
+	type order struct{ Status string }
+	col := Stepper("Status").Steps([]string{"pending", "paid", "shipped", "delivered"})
+
+	if col.Type() != "stepper" {
+		t.Errorf("Expected type 'stepper', got '%s'", col.Type())
+	}
+	item := order{Status: "shipped"}
+	if col.Value(item) != "shipped" {
+		t.Errorf("Expected value 'shipped', got '%s'", col.Value(item))
+	}
+	if col.StepIndex(item) != 2 {
+		t.Errorf("Expected step index 2, got %d", col.StepIndex(item))
+	}
+	want := []string{"done", "done", "current", "upcoming"}
+	for i, w := range want {
+		if got := col.StepState(item, i); got != w {
+			t.Errorf("Step %d: expected '%s', got '%s'", i, w, got)
+		}
+	}
+	if col.StepIndex(order{Status: "cancelled"}) != -1 {
+		t.Error("Expected -1 for a value outside the steps")
+	}
+	if col.StepState(order{Status: "cancelled"}, 0) != "upcoming" {
+		t.Error("Expected all steps upcoming for an unknown value")
+	}
+}
+
 func TestImageColumn(t *testing.T) {
 	col := Image("avatar").
 		WithLabel("Profile Picture").
@@ -140,6 +272,65 @@ func TestImageColumn(t *testing.T) {
 	}
 }
 
+func TestSelectColumn(t *testing.T) {
+	type order struct{ Status string }
+	col := SelectCol("Status").
+		WithLabel("Status").
+		Options(map[string]string{
+			"shipped": "Shipped",
+			"draft":   "Draft",
+			"paid":    "Paid",
+		})
+
+	if col.Type() != "select" {
+		t.Errorf("Expected type 'select', got '%s'", col.Type())
+	}
+	if got := col.Value(order{Status: "paid"}); got != "paid" {
+		t.Errorf("Expected value 'paid', got '%s'", got)
+	}
+	values := col.OptionValues()
+	if len(values) != 3 || values[0] != "draft" || values[2] != "shipped" {
+		t.Errorf("Expected options sorted by label, got %v", values)
+	}
+	if url := col.InlineURL("/admin/orders", "7"); url != "/admin/orders/7/inline" {
+		t.Errorf("Expected inline URL '/admin/orders/7/inline', got '%s'", url)
+	}
+}
+
+func TestToggleColumn(t *testing.T) {
+	type user struct {
+		Active  bool
+		Visible int
+	}
+	col := Toggle("Active").WithLabel("Active")
+
+	if col.Type() != "toggle" {
+		t.Errorf("Expected type 'toggle', got '%s'", col.Type())
+	}
+	if !col.IsOn(&user{Active: true}) || col.Value(&user{Active: true}) != "true" {
+		t.Error("Expected toggle to be on")
+	}
+	if col.IsOn(user{}) || col.Value(user{}) != "false" {
+		t.Error("Expected toggle to be off")
+	}
+	if !Toggle("Visible").IsOn(user{Visible: 1}) {
+		t.Error("Expected non-zero int to be on")
+	}
+	if url := col.InlineURL("/users", "3"); url != "/users/3/inline" {
+		t.Errorf("Expected inline URL '/users/3/inline', got '%s'", url)
+	}
+}
+
+func TestTableURL(t *testing.T) {
+	ctx := WithResourceURL(context.Background(), "/admin/users")
+	if url := New(nil).URL(ctx); url != "/admin/users" {
+		t.Errorf("Expected the resource URL by default, got '%s'", url)
+	}
+	if url := New(nil).WithBaseURL("/team").URL(ctx); url != "/team" {
+		t.Errorf("Expected BaseURL to win, got '%s'", url)
+	}
+}
+
 func TestSelectFilter(t *testing.T) {
 	filter := Select("status").
 		WithLabel("Status").
@@ -174,3 +365,303 @@ func TestBooleanFilter(t *testing.T) {
 		t.Errorf("Expected 2 options (Yes/No), got %d", len(opts))
 	}
 }
+
+func TestNumberColumns(t *testing.T) {
+	type invoice struct {
+		Total    float64
+		Quantity int
+		Refund   float64
+		Missing  *float64
+	}
+	inv := invoice{Total: 1234567.891, Quantity: 12500, Refund: -42.5}
+
+	if got := Number("Quantity").Value(inv); got != "12\u00a0500" {
+		t.Errorf("Expected '12 500', got %q", got)
+	}
+	if got := Money("Total").Value(inv); got != "1\u00a0234\u00a0567,89\u00a0€" {
+		t.Errorf("Expected '1 234 567,89 €', got %q", got)
+	}
+	if got := Money("Total").Currency("usd").Locale("en-US").Value(inv); got != "$1,234,567.89" {
+		t.Errorf("Expected '$1,234,567.89', got '%s'", got)
+	}
+	if got := Money("Refund").Locale("en").Currency("USD").Value(inv); got != "-$42.50" {
+		t.Errorf("Expected '-$42.50', got '%s'", got)
+	}
+	if got := Number("Total").Decimals(1).Locale("de").Value(inv); got != "1.234.567,9" {
+		t.Errorf("Expected '1.234.567,9', got '%s'", got)
+	}
+	if got := Money("Missing").Value(inv); got != "" {
+		t.Errorf("Expected empty value for nil pointer, got '%s'", got)
+	}
+
+	col := Money("Total").Sortable()
+	if col.Type() != "money" || Number("Total").Type() != "number" {
+		t.Errorf("Unexpected column types: %s / %s", col.Type(), Number("Total").Type())
+	}
+	if !col.IsSortable() {
+		t.Error("Expected column to be sortable")
+	}
+}
+
+func TestProgressColumn(t *testing.T) {
+	type task struct {
+		Done  float64
+		Score int
+	}
+	col := Progress("Done").ColorThresholds(map[float64]string{
+		0:  "danger",
+		30: "warning",
+		70: "success",
+	})
+
+	if col.Type() != "progress" {
+		t.Errorf("Expected type 'progress', got '%s'", col.Type())
+	}
+	if p := col.Percent(task{Done: 45}); p != 45 {
+		t.Errorf("Expected 45%%, got %v", p)
+	}
+	if p := col.Percent(task{Done: 150}); p != 100 {
+		t.Errorf("Expected values above max to be clamped to 100%%, got %v", p)
+	}
+	if p := col.Percent(task{Done: -5}); p != 0 {
+		t.Errorf("Expected negative values to be clamped to 0%%, got %v", p)
+	}
+
+	for value, want := range map[float64]string{10: "danger", 30: "warning", 69.9: "warning", 95: "success"} {
+		if got := col.GetColor(task{Done: value}); got != want {
+			t.Errorf("GetColor(%v) = %s, want %s", value, got, want)
+		}
+	}
+	if got := Progress("Done").GetColor(task{Done: 50}); got != "primary" {
+		t.Errorf("Expected default color 'primary', got '%s'", got)
+	}
+
+	scaled := Progress("Score").Max(5)
+	if p := scaled.Percent(task{Score: 4}); p != 80 {
+		t.Errorf("Expected 80%% on a 0-5 scale, got %v", p)
+	}
+	if scaled.Value(task{Score: 4}) != "4" {
+		t.Errorf("Expected raw value '4', got '%s'", scaled.Value(task{Score: 4}))
+	}
+}
+
+func TestBuildTableState(t *testing.T) {
+	rows := make([]any, 23)
+	for i := range rows {
+		rows[i] = i + 1
+	}
+	tbl := New(rows).Paginate(10)
+
+	r := httptest.NewRequest("GET", "/users?page=2&search=bob", nil)
+	state := tbl.BuildTableState(WithPageParams(context.Background(), ParsePageParams(r)), rows)
+	if state.Total != 23 || state.CurrentPage != 2 || state.LastPage != 3 {
+		t.Fatalf("Unexpected state: total=%d page=%d last=%d", state.Total, state.CurrentPage, state.LastPage)
+	}
+	if len(state.Rows) != 10 || state.Rows[0] != 11 {
+		t.Errorf("Expected rows 11-20, got %v", state.Rows)
+	}
+	if len(state.Links) != 3 || !state.Links[1].Active {
+		t.Errorf("Expected 3 links with page 2 active, got %+v", state.Links)
+	}
+	if state.NextURL != "?page=3&per_page=10&search=bob" {
+		t.Errorf("Expected next URL to keep the search, got %q", state.NextURL)
+	}
+
+	// A page beyond the range is clamped to the last page.
+	r = httptest.NewRequest("GET", "/users?page=99&per_page=25", nil)
+	state = tbl.BuildTableState(WithPageParams(context.Background(), ParsePageParams(r)), rows)
+	if state.CurrentPage != 1 || state.PerPage != 25 || len(state.Rows) != 23 {
+		t.Errorf("Expected single page of 23 rows, got page=%d perPage=%d rows=%d", state.CurrentPage, state.PerPage, len(state.Rows))
+	}
+	r = httptest.NewRequest("GET", "/users?page=99", nil)
+	state = tbl.BuildTableState(WithPageParams(context.Background(), ParsePageParams(r)), rows)
+	if state.CurrentPage != 3 || len(state.Rows) != 3 || state.NextURL != "" {
+		t.Errorf("Expected clamp to last page with 3 rows, got page=%d rows=%d", state.CurrentPage, len(state.Rows))
+	}
+
+	// Server-side pagination: rows are the current page only.
+	state = New(rows[:10]).Paginate(10).WithTotal(95).BuildTableState(context.Background(), rows[:10])
+	if state.LastPage != 10 || len(state.Rows) != 10 {
+		t.Errorf("Expected 10 pages of server-side rows, got last=%d rows=%d", state.LastPage, len(state.Rows))
+	}
+}
+
+func TestBuildTableState_WindowedLinks(t *testing.T) {
+	tbl := New(nil).Paginate(10).WithTotal(5000)
+
+	pages := func(state TableState) string {
+		var out []string
+		for _, link := range state.Links {
+			switch {
+			case link.Ellipsis:
+				out = append(out, "…")
+			case link.Active:
+				out = append(out, fmt.Sprintf("[%d]", link.Page))
+			default:
+				out = append(out, strconv.Itoa(link.Page))
+			}
+		}
+		return strings.Join(out, " ")
+	}
+
+	tests := []struct {
+		page int
+		want string
+	}{
+		{1, "[1] 2 3 … 500"},
+		{4, "1 2 3 [4] 5 6 … 500"},
+		{250, "1 … 248 249 [250] 251 252 … 500"},
+		{497, "1 … 495 496 [497] 498 499 500"},
+		{500, "1 … 498 499 [500]"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", fmt.Sprintf("/users?page=%d", tt.page), nil)
+		state := tbl.BuildTableState(WithPageParams(context.Background(), ParsePageParams(r)), nil)
+		if state.LastPage != 500 {
+			t.Fatalf("Expected 500 pages, got %d", state.LastPage)
+		}
+		if got := pages(state); got != tt.want {
+			t.Errorf("page %d: expected links %q, got %q", tt.page, tt.want, got)
+		}
+	}
+
+	r := httptest.NewRequest("GET", "/users?page=250", nil)
+	state := tbl.BuildTableState(WithPageParams(context.Background(), ParsePageParams(r)), nil)
+	if last := state.Links[len(state.Links)-1]; last.URL != "?page=500&per_page=10" {
+		t.Errorf("Expected a link to the last page, got %+v", last)
+	}
+}
+
+func TestSummaries(t *testing.T) {
+	type order struct {
+		Amount float64
+		Rating int
+		Note   string
+	}
+	rows := []any{
+		order{Amount: 10.5, Rating: 4},
+		order{Amount: 20, Rating: 5},
+		&order{Amount: 4.25, Rating: 2},
+	}
+
+	tests := []struct {
+		name string
+		s    Summarizer
+		want string
+	}{
+		{"sum float", Sum("Amount"), "34.75"},
+		{"avg int", Avg("Rating"), "3.67"},
+		{"min", Min("Amount"), "4.25"},
+		{"max", Max("Rating"), "5"},
+		{"count", Count(), "3"},
+		{"formatted", Sum("Amount").WithFormat("%.1f €"), "34.8 €"},
+		{"non-numeric", Sum("Note"), ""},
+	}
+	for _, tt := range tests {
+		if got := tt.s.Compute(rows); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+	if Avg("Rating").Label() != "Average" || Sum("Amount").WithLabel("Total").Label() != "Total" {
+		t.Error("Unexpected summary labels")
+	}
+}
+
+func TestTableSummariesFor(t *testing.T) {
+	tbl := New(nil).
+		WithColumns(Text("Name"), Number("Amount")).
+		Summarize(Sum("Amount"), Count(), Avg("Amount"))
+
+	if got := tbl.SummariesFor(0); len(got) != 1 || got[0].Label() != "Count" {
+		t.Errorf("Expected Count under the first column, got %v", got)
+	}
+	if got := tbl.SummariesFor(1); len(got) != 2 {
+		t.Errorf("Expected 2 summaries under Amount, got %d", len(got))
+	}
+}
+
+func TestRelationColumn(t *testing.T) {
+	type customer struct {
+		ID   int
+		Name string
+	}
+	type order struct {
+		Edges struct{ Customer *customer }
+	}
+	col := Relation("Edges.Customer.Name").WithLabel("Customer").Modal("/customers/{id}")
+
+	if col.Type() != "relation" {
+		t.Errorf("Expected type 'relation', got '%s'", col.Type())
+	}
+	var item order
+	item.Edges.Customer = &customer{ID: 7, Name: "Hélène"}
+	if col.Value(item) != "Hélène" {
+		t.Errorf("Expected value 'Hélène', got '%s'", col.Value(item))
+	}
+	if got := col.ModalURL(&item); got != "/customers/7?fragment=1" {
+		t.Errorf("Expected modal URL '/customers/7?fragment=1', got '%s'", got)
+	}
+	if got := col.ModalURL(order{}); got != "" {
+		t.Errorf("Expected no modal URL without a related record, got '%s'", got)
+	}
+
+	type invoice struct{ CustomerID string }
+	byID := Relation("CustomerName").RelatedID("CustomerID").Modal("/customers/{id}?tab=billing")
+	if got := byID.ModalURL(invoice{CustomerID: "a/b"}); got != "/customers/a%2Fb?tab=billing&fragment=1" {
+		t.Errorf("Unexpected modal URL '%s'", got)
+	}
+	if Relation("Edges.Customer.Name").ModalURL(&item) != "" {
+		t.Error("Expected no modal URL without Modal")
+	}
+}
+
+func TestNumberColumnNegatives(t *testing.T) {
+	type entry struct{ Amount float64 }
+	debit, credit, rounding := entry{Amount: -1234.5}, entry{Amount: 80}, entry{Amount: -0.001}
+
+	col := Money("Amount").NegativeColor("")
+	if got := col.Value(debit); got != "-1\u00a0234,50\u00a0€" {
+		t.Errorf("Expected '-1 234,50 €', got %q", got)
+	}
+	if col.CellClass(debit) != "text-red-600 dark:text-red-400" || col.CellClass(credit) != "" {
+		t.Errorf("Unexpected cell classes %q / %q", col.CellClass(debit), col.CellClass(credit))
+	}
+	if col.IsNegative(rounding) {
+		t.Error("Expected a value rounding to zero not to be negative")
+	}
+
+	paren := Money("Amount").Parentheses().NegativeColor("text-rose-700")
+	if got := paren.Value(debit); got != "(1\u00a0234,50\u00a0€)" {
+		t.Errorf("Expected '(1 234,50 €)', got %q", got)
+	}
+	if got := paren.Locale("en").Currency("USD").Value(debit); got != "($1,234.50)" {
+		t.Errorf("Expected '($1,234.50)', got %q", got)
+	}
+	if got := Number("Amount").Parentheses().Value(credit); got != "80" {
+		t.Errorf("Expected '80', got %q", got)
+	}
+	if paren.CellClass(debit) != "text-rose-700" {
+		t.Errorf("Expected custom negative class, got %q", paren.CellClass(debit))
+	}
+
+	if Number("Amount").GetAlign() != "right" || col.GetAlign() != "right" || Text("Amount").GetAlign() != "left" {
+		t.Error("Expected numbers aligned right and text aligned left")
+	}
+}
+
+func TestColumnSurfaces(t *testing.T) {
+	name := Text("name")
+	avatar := ExcludeFromExport(Image("avatar"))
+	total := ExcludeFromAPI(Money("total"))
+	cols := []Column{name, avatar, total}
+
+	if !InExport(name) || !InAPI(name) {
+		t.Error("expected plain column on every surface")
+	}
+	if got := ExportColumns(cols); len(got) != 2 || got[1] != total {
+		t.Errorf("unexpected export columns %v", got)
+	}
+	if got := APIColumns(cols); len(got) != 2 || got[1] != avatar {
+		t.Errorf("unexpected API columns %v", got)
+	}
+}
@@ -1,6 +1,13 @@
 package components
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bozz33/sublimego/color"
+	"github.com/bozz33/sublimego/table"
+)
 
 // extractRowID extracts a string ID from an item using the Identifiable interface,
 // or falls back to fmt.Sprintf for map[string]any and raw values.
@@ -23,9 +30,33 @@ func extractRowID(item any) string {
 	return fmt.Sprintf("%v", item)
 }
 
+// hasModalColumn reports whether a column of t opens records in a slideover.
+func hasModalColumn(t *table.Table) bool {
+	for _, col := range t.Columns {
+		if rel, ok := col.(*table.RelationColumn); ok && rel.ModalRoute != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// alignClass returns the text alignment class of a column's cells.
+func alignClass(col table.Column) string {
+	switch col.GetAlign() {
+	case "right":
+		return "text-right"
+	case "center":
+		return "text-center"
+	}
+	return ""
+}
+
 // getBadgeClasses returns the CSS classes for a badge based on its color
 func getBadgeClasses(color string) string {
 	classes := "px-2.5 py-0.5 text-xs font-medium rounded-full "
+	if getBadgeStyle(color) != "" {
+		return classes
+	}
 	switch color {
 	case "primary":
 		return classes + "bg-primary-100 text-primary-800 dark:bg-primary-900 dark:text-primary-300"
@@ -40,6 +71,19 @@ func getBadgeClasses(color string) string {
 	}
 }
 
+// getBadgeStyle returns the inline style of a badge colored with a hex value
+// ("#0ea5e9"), with black or white text for contrast, or "" for named colors.
+func getBadgeStyle(hex string) string {
+	if !strings.HasPrefix(hex, "#") {
+		return ""
+	}
+	fg := color.Shade{Hex: hex}.BestTextColor()
+	if fg == "" {
+		return ""
+	}
+	return fmt.Sprintf("background-color: %s; color: %s", hex, fg)
+}
+
 // getActionClasses returns the CSS classes for an action based on its color
 func getActionClasses(color string) string {
 	base := "inline-flex items-center justify-center w-8 h-8 rounded-lg transition-colors "
@@ -67,3 +111,8 @@ func getBulkActionClasses(color string) string {
 		return base + "bg-gray-100 text-gray-700 hover:bg-gray-200 dark:bg-gray-700 dark:text-gray-300 dark:hover:bg-gray-600"
 	}
 }
+
+// formatBound formats a range filter bound for a number input attribute.
+func formatBound(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
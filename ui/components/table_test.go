@@ -0,0 +1,145 @@
+package components
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bozz33/sublimego/table"
+)
+
+type rowItem struct {
+	ID     int
+	Name   string
+	Active bool
+}
+
+func (r rowItem) GetID() int { return r.ID }
+
+func TestTable_RowClass(t *testing.T) {
+	data := []any{
+		rowItem{ID: 1, Name: "Alice", Active: true},
+		rowItem{ID: 2, Name: "Bob", Active: false},
+		rowItem{ID: 3, Name: "Carol", Active: true},
+	}
+
+	calls := 0
+	tbl := table.New(data).
+		WithColumns(table.Text("Name")).
+		RowClass(func(item any) string {
+			calls++
+			if !item.(rowItem).Active {
+				return "opacity-50 line-through"
+			}
+			return ""
+		})
+
+	var buf bytes.Buffer
+	if err := Table(context.Background(), tbl, data).Render(context.Background(), &buf); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	if calls != len(data) {
+		t.Errorf("expected RowClass to be invoked once per row (%d), got %d", len(data), calls)
+	}
+	if n := strings.Count(buf.String(), "opacity-50 line-through"); n != 1 {
+		t.Errorf("expected the inactive row class once, got %d", n)
+	}
+}
+
+func TestTable_GetRowClass_Default(t *testing.T) {
+	if got := table.New(nil).GetRowClass(rowItem{}); got != "" {
+		t.Errorf("expected no row class by default, got %q", got)
+	}
+}
+
+func TestTable_SummaryFooter(t *testing.T) {
+	data := []any{rowItem{ID: 1}, rowItem{ID: 2}, rowItem{ID: 3}}
+	tbl := table.New(data).
+		WithColumns(table.Text("Name"), table.Number("ID")).
+		Paginate(2).
+		Summarize(table.Sum("ID"), table.Count()).
+		SummarizeAllRows()
+
+	var buf bytes.Buffer
+	if err := Table(context.Background(), tbl, data).Render(context.Background(), &buf); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	html := buf.String()
+	if !strings.Contains(html, "<tfoot") {
+		t.Fatal("expected a footer row")
+	}
+	// Page 1 holds IDs 1 and 2; all rows add up to 6.
+	for _, want := range []string{"<span>3</span>", "<span>2</span>", "<span>6</span>", "All rows"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected footer to contain %q", want)
+		}
+	}
+}
+
+func TestTable_BulkActionBar(t *testing.T) {
+	data := []any{rowItem{ID: 1, Name: "Alice"}}
+
+	render := func(tbl *table.Table) string {
+		var buf bytes.Buffer
+		if err := Table(context.Background(), tbl, data).Render(context.Background(), &buf); err != nil {
+			t.Fatalf("render failed: %v", err)
+		}
+		return buf.String()
+	}
+
+	html := render(table.New(data).WithColumns(table.Text("Name")).WithBulkActions(table.BulkDelete()))
+	if !strings.Contains(html, `role="toolbar"`) || !strings.Contains(html, `x-show="selectedIds.length > 0"`) {
+		t.Error("expected a bulk-action bar hidden until rows are selected")
+	}
+	if !strings.Contains(html, "Delete selected") || !strings.Contains(html, `x-text="selectedIds.length"`) {
+		t.Error("expected the bulk actions and the selected-count placeholder")
+	}
+
+	if html := render(table.New(data).WithColumns(table.Text("Name"))); strings.Contains(html, `role="toolbar"`) {
+		t.Error("expected no bulk-action bar without bulk actions")
+	}
+}
+
+func TestTable_StepperColumn(t *testing.T) {
+	type order struct {
+		ID     int
+		Status string
+	}
+	data := []any{order{ID: 1, Status: "paid"}}
+	tbl := table.New(data).
+		WithColumns(table.Stepper("Status").Steps([]string{"pending", "paid", "shipped"}))
+
+	var buf bytes.Buffer
+	if err := Table(context.Background(), tbl, data).Render(context.Background(), &buf); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	html := buf.String()
+	if strings.Count(html, `aria-current="step"`) != 1 {
+		t.Errorf("expected exactly one current step, got:\n%s", html)
+	}
+	if strings.Count(html, "check_circle") != 1 || strings.Count(html, "radio_button_unchecked") != 1 {
+		t.Errorf("expected one done and one upcoming step, got:\n%s", html)
+	}
+}
+
+func TestRenderBadge_HexColor(t *testing.T) {
+	render := func(color string) string {
+		var buf bytes.Buffer
+		if err := RenderBadge("Paid", color).Render(context.Background(), &buf); err != nil {
+			t.Fatalf("render failed: %v", err)
+		}
+		return buf.String()
+	}
+
+	if html := render("#1e3a8a"); !strings.Contains(html, "background-color: #1e3a8a; color: #ffffff") {
+		t.Errorf("expected white text on a dark hex badge, got:\n%s", html)
+	}
+	if html := render("#fde68a"); !strings.Contains(html, "color: #000000") {
+		t.Errorf("expected black text on a light hex badge, got:\n%s", html)
+	}
+	if html := render("success"); strings.Contains(html, "style=") || !strings.Contains(html, "bg-green-100") {
+		t.Errorf("expected named colors to keep their classes, got:\n%s", html)
+	}
+}
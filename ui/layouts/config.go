@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"strings"
+
+	"github.com/bozz33/sublimego/color"
 )
 
 // FooterLink represents a link in the footer
@@ -141,10 +143,33 @@ func primaryColorPalette(color string) string {
 	return palettes["green"]
 }
 
+// PrimaryHex returns the 500 shade of a primary color name or hex color,
+// e.g. for the theme color of the web app manifest. Unknown names fall back
+// to green.
+func PrimaryHex(name string) string {
+	shades := map[string]string{
+		"green":  "#22c55e",
+		"blue":   "#3b82f6",
+		"red":    "#ef4444",
+		"purple": "#a855f7",
+		"orange": "#f97316",
+		"pink":   "#ec4899",
+		"indigo": "#6366f1",
+	}
+	if hex, ok := shades[name]; ok {
+		return hex
+	}
+	if p := color.GeneratePalette("primary", name); p != nil {
+		return p.Hex(500)
+	}
+	return shades["green"]
+}
+
 // primaryCSSVars generates a CSS :root block with CSS custom properties for the primary color.
 // This is the Filament-style approach: inject color variables into <head> so custom.css
 // can use var(--primary-500) instead of hardcoded hex values.
-func primaryCSSVars(color string) string {
+// A hex color gets a palette generated around it (see color.GeneratePalette).
+func primaryCSSVars(name string) string {
 	type shade struct {
 		num int
 		hex string
@@ -189,8 +214,13 @@ func primaryCSSVars(color string) string {
 			{800, "#3730a3"}, {900, "#312e81"},
 		}},
 	}
-	p, ok := palettes[color]
-	if !ok {
+	p, ok := palettes[name]
+	if generated := color.GeneratePalette("primary", name); !ok && generated != nil {
+		p = palette{}
+		for _, s := range generated.Shades {
+			p.shades = append(p.shades, shade{s.Number, s.Hex})
+		}
+	} else if !ok {
 		p = palettes["green"]
 	}
 	css := ":root {"
@@ -59,9 +59,9 @@ func Topbar(ctx context.Context) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var2 string
-		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs("{ unread: 0, open: false, init() { const es = new EventSource('" + assetPath(cfg.Path, "/api/notifications/stream") + "'); es.onmessage = e => { const d = JSON.parse(e.data); if(d.unread_count !== undefined) this.unread = d.unread_count; }; } }")
+		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs("{ unread: 0, open: false, init() { fetch('" + assetPath(cfg.Path, "/api/notifications/unread-count") + "').then(r => r.ok ? r.json() : {}).then(d => { if(d.unread_count !== undefined) this.unread = d.unread_count; }); const es = new EventSource('" + assetPath(cfg.Path, "/api/notifications/stream") + "'); es.addEventListener('connected', e => { this.unread = JSON.parse(e.data).unread_count; }); es.addEventListener('notification', () => { this.unread++; }); } }")
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/layouts/topbar.templ`, Line: 68, Col: 260}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/layouts/topbar.templ`, Line: 68, Col: 479}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
 		if templ_7745c5c3_Err != nil {
@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+)
+
+// ContextCheck validates a field against something struct tags cannot
+// reach, such as the database. Run checks with ValidateStructWithContext.
+type ContextCheck struct {
+	// Field is the key of the error, the json name of the field.
+	Field string
+	// Tag selects the message, as for struct tags (e.g. "unique_record").
+	Tag string
+	// Param replaces {param} in the message.
+	Param string
+	// Valid reports whether the value is valid.
+	Valid func(ctx context.Context) (bool, error)
+}
+
+// Unique checks that no other record holds the value of field. exists
+// reports whether one does; when updating, it must leave out the record being
+// updated:
+//
+//	errs, err := validation.ValidateStructWithContext(ctx, input,
+//		validation.Unique("email", func(ctx context.Context) (bool, error) {
+//			return client.User.Query().
+//				Where(user.Email(input.Email), user.IDNEQ(id)).
+//				Exist(ctx)
+//		}))
+func Unique(field string, exists func(ctx context.Context) (bool, error)) ContextCheck {
+	return ContextCheck{
+		Field: field,
+		Tag:   "unique_record",
+		Valid: func(ctx context.Context) (bool, error) {
+			taken, err := exists(ctx)
+			return !taken, err
+		},
+	}
+}
+
+// runChecks runs the checks of the fields without error in result and adds
+// the errors of the failed ones.
+func runChecks(ctx context.Context, checks []ContextCheck, messages, result map[string]string) error {
+	for _, c := range checks {
+		if _, failed := result[c.Field]; failed {
+			continue
+		}
+		ok, err := c.Valid(ctx)
+		if err != nil {
+			return fmt.Errorf("validation: %s check of %s: %w", c.Tag, c.Field, err)
+		}
+		if ok {
+			continue
+		}
+		message, exists := lookupFieldMessage(c.Field + "." + c.Tag)
+		if !exists {
+			message, exists = messages[c.Tag]
+		}
+		if !exists {
+			message = fmt.Sprintf("Field %s is invalid", c.Field)
+		}
+		result[c.Field] = formatMessage(message, c.Field, c.Param, "")
+	}
+	return nil
+}
@@ -14,9 +14,6 @@ var (
 	reSlug         = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 	reSIRET        = regexp.MustCompile(`^\d{14}$`)
 	reSIREN        = regexp.MustCompile(`^\d{9}$`)
-	reDigit        = regexp.MustCompile(`\d`)
-	reUpper        = regexp.MustCompile(`[A-Z]`)
-	reLower        = regexp.MustCompile(`[a-z]`)
 )
 
 // registerCustomValidators registers all custom validators.
@@ -27,6 +24,15 @@ func (v *Validator) registerCustomValidators() {
 	_ = v.validate.RegisterValidation("siret", validateSIRET)
 	_ = v.validate.RegisterValidation("siren", validateSIREN)
 	_ = v.validate.RegisterValidation("strong_password", validateStrongPassword)
+	_ = v.validate.RegisterValidation("password_confirmation", validatePasswordConfirmation)
+}
+
+// isEmpty reports whether the field holds an empty (or blank) string.
+// The custom validators below accept empty values so they can be used on
+// optional fields, like built-ins tagged omitempty; fields that must be
+// filled also carry the required tag, which runs first and rejects them.
+func isEmpty(fl validator.FieldLevel) bool {
+	return strings.TrimSpace(fl.Field().String()) == ""
 }
 
 // validatePhoneFR validates a French phone number.
@@ -37,38 +43,52 @@ func (v *Validator) registerCustomValidators() {
 // - 06-12-34-56-78
 // - +33612345678
 // - +33 6 12 34 56 78
+// - +33 (0)6 12 34 56 78
+// - 0033612345678
 func validatePhoneFR(fl validator.FieldLevel) bool {
-	phone := fl.Field().String()
-
-	phone = strings.ReplaceAll(phone, " ", "")
-	phone = strings.ReplaceAll(phone, ".", "")
-	phone = strings.ReplaceAll(phone, "-", "")
-	phone = strings.ReplaceAll(phone, "(", "")
-	phone = strings.ReplaceAll(phone, ")", "")
-
-	if strings.HasPrefix(phone, "+33") {
-		phone = phone[3:]
-		if len(phone) != 9 {
-			return false
-		}
-		// Must start with 6, 7 or 0 (for mobile)
-		return strings.HasPrefix(phone, "6") || strings.HasPrefix(phone, "7") || strings.HasPrefix(phone, "0")
-	}
-
-	if len(phone) == 10 {
-		return strings.HasPrefix(phone, "0")
+	if isEmpty(fl) {
+		return true
 	}
+	return NormalizePhoneFR(fl.Field().String()) != ""
+}
 
-	if len(phone) == 9 {
-		return strings.HasPrefix(phone, "6") || strings.HasPrefix(phone, "7")
+// NormalizePhoneFR returns the E.164 form (+33XXXXXXXXX) of a French phone
+// number, or an empty string if it is not a valid French number.
+// Spaces, dots, dashes and parentheses are ignored.
+func NormalizePhoneFR(phone string) string {
+	phone = strings.NewReplacer(" ", "", ".", "", "-", "", "(", "", ")", "").Replace(phone)
+
+	var national string
+	switch {
+	case strings.HasPrefix(phone, "+33"):
+		national = strings.TrimPrefix(phone[3:], "0") // +33 (0)6...
+	case strings.HasPrefix(phone, "0033"):
+		national = strings.TrimPrefix(phone[4:], "0")
+	case len(phone) == 10 && strings.HasPrefix(phone, "0"):
+		national = phone[1:]
+	case len(phone) == 9 && (strings.HasPrefix(phone, "6") || strings.HasPrefix(phone, "7")):
+		national = phone
+	default:
+		return ""
+	}
+
+	if len(national) != 9 || national[0] == '0' {
+		return ""
+	}
+	for _, r := range national {
+		if r < '0' || r > '9' {
+			return ""
+		}
 	}
-
-	return false
+	return "+33" + national
 }
 
 // validatePostalCodeFR validates a French postal code.
 // Accepted formats: 75001, 13000, 69001, 2A000, 2B000
 func validatePostalCodeFR(fl validator.FieldLevel) bool {
+	if isEmpty(fl) {
+		return true
+	}
 	postalCode := fl.Field().String()
 
 	if len(postalCode) != 5 {
@@ -85,11 +105,10 @@ func validatePostalCodeFR(fl validator.FieldLevel) bool {
 // Accepts: mon-article, article-123, mon_article_123
 // Rejects: Mon Article, -article, article-
 func validateSlug(fl validator.FieldLevel) bool {
-	slug := fl.Field().String()
-
-	if len(slug) == 0 {
-		return false
+	if isEmpty(fl) {
+		return true
 	}
+	slug := fl.Field().String()
 
 	if strings.HasPrefix(slug, "-") || strings.HasPrefix(slug, "_") ||
 		strings.HasSuffix(slug, "-") || strings.HasSuffix(slug, "_") {
@@ -102,6 +121,9 @@ func validateSlug(fl validator.FieldLevel) bool {
 // validateSIRET validates a SIRET number (14 digits).
 // Uses the Luhn algorithm for validation.
 func validateSIRET(fl validator.FieldLevel) bool {
+	if isEmpty(fl) {
+		return true
+	}
 	siret := fl.Field().String()
 
 	siret = strings.ReplaceAll(siret, " ", "")
@@ -137,6 +159,9 @@ func validateSIRET(fl validator.FieldLevel) bool {
 // validateSIREN validates a SIREN number (9 digits).
 // Uses the Luhn algorithm for validation.
 func validateSIREN(fl validator.FieldLevel) bool {
+	if isEmpty(fl) {
+		return true
+	}
 	siren := fl.Field().String()
 
 	siren = strings.ReplaceAll(siren, " ", "")
@@ -169,54 +194,37 @@ func validateSIREN(fl validator.FieldLevel) bool {
 	return sum%10 == 0
 }
 
-// validateStrongPassword validates a strong password.
-// Rules: 8+ characters, 1 uppercase, 1 lowercase, 1 digit
-func validateStrongPassword(fl validator.FieldLevel) bool {
-	password := fl.Field().String()
-
-	if len(password) < 8 {
-		return false
-	}
-
-	if !reUpper.MatchString(password) {
-		return false
-	}
-	if !reLower.MatchString(password) {
-		return false
-	}
-	return reDigit.MatchString(password)
-}
-
-// Standalone helpers for quick validation
+// Standalone helpers for quick validation. Empty values are invalid here,
+// as the helpers check a value that must be present.
 
 // IsValidPhoneFR checks if a French phone number is valid.
 func IsValidPhoneFR(phone string) bool {
 	v := New()
-	return v.validate.Var(phone, "phone_fr") == nil
+	return v.validate.Var(phone, "required,phone_fr") == nil
 }
 
 // IsValidPostalCodeFR checks if a French postal code is valid.
 func IsValidPostalCodeFR(postalCode string) bool {
 	v := New()
-	return v.validate.Var(postalCode, "postal_code_fr") == nil
+	return v.validate.Var(postalCode, "required,postal_code_fr") == nil
 }
 
 // IsValidSlug checks if a slug is valid.
 func IsValidSlug(slug string) bool {
 	v := New()
-	return v.validate.Var(slug, "slug") == nil
+	return v.validate.Var(slug, "required,slug") == nil
 }
 
 // IsValidSIRET checks if a SIRET is valid.
 func IsValidSIRET(siret string) bool {
 	v := New()
-	return v.validate.Var(siret, "siret") == nil
+	return v.validate.Var(siret, "required,siret") == nil
 }
 
 // IsValidSIREN checks if a SIREN is valid.
 func IsValidSIREN(siren string) bool {
 	v := New()
-	return v.validate.Var(siren, "siren") == nil
+	return v.validate.Var(siren, "required,siren") == nil
 }
 
 // IsStrongPassword checks if a password is strong.
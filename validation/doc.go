@@ -6,10 +6,17 @@
 //
 // Features:
 //   - Struct validation with tags
-//   - English error messages (French messages available)
-//   - Custom validators (phone_fr, postal_code_fr, siret, siren, slug)
-//   - Strong password validation
+//   - English error messages; French available with SetLocale("fr"), other
+//     languages with RegisterLocale, per request with WithLocale
+//   - Custom validators (phone_fr, postal_code_fr, siret, siren, slug); they
+//     accept empty values, so combine them with required when mandatory
+//   - Application validators added with RegisterValidator
+//   - Checks needing a context, such as Unique for database uniqueness,
+//     run by ValidateStructWithContext
+//   - Strong password validation with a configurable policy
+//     (SetPasswordPolicy) and password_confirmation for confirm fields
 //   - Form and JSON validation helpers
+//   - ValidateSlice for batches, with errors keyed by index ("2.email")
 //   - Error message helpers
 //
 // Basic usage:
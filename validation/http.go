@@ -0,0 +1,40 @@
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// WriteErrors answers a request whose input is invalid with a 422 and the
+// errors as JSON, keyed by field:
+//
+//	{"errors": {"email": "The email field is required"}}
+func WriteErrors(w http.ResponseWriter, errs map[string]string) {
+	if errs == nil {
+		errs = map[string]string{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	_ = json.NewEncoder(w).Encode(map[string]any{"errors": errs})
+}
+
+type errorsKey struct{}
+
+// WithErrors returns a context carrying errs, for an HTML form rendered with
+// it: each field shows the message of its name under the input.
+//
+//	if errs := validation.ValidateForm(r, &input); errs != nil {
+//		w.WriteHeader(http.StatusUnprocessableEntity)
+//		page.Render(validation.WithErrors(r.Context(), errs), w)
+//		return
+//	}
+func WithErrors(ctx context.Context, errs map[string]string) context.Context {
+	return context.WithValue(ctx, errorsKey{}, errs)
+}
+
+// ErrorsFromContext returns the errors set with WithErrors, nil if none.
+func ErrorsFromContext(ctx context.Context) map[string]string {
+	errs, _ := ctx.Value(errorsKey{}).(map[string]string)
+	return errs
+}
@@ -0,0 +1,85 @@
+package validation
+
+import (
+	"context"
+	"maps"
+)
+
+// DefaultLocale is the locale of the built-in English messages.
+const DefaultLocale = "en"
+
+// locales holds the messages of each locale on top of the English ones,
+// guarded by registryMu. The English entry only holds the messages
+// registered with RegisterLocale.
+var (
+	locales = map[string]map[string]string{
+		DefaultLocale: {},
+		"fr":          frenchMessages(),
+	}
+	currentLocale = DefaultLocale
+)
+
+// RegisterLocale adds messages to a locale, creating it if needed. Messages
+// are keyed by tag and support the {field}, {param} and {value}
+// placeholders; tags the locale has no message for use the English one.
+//
+//	validation.RegisterLocale("de", map[string]string{
+//		"required": "Das Feld {field} ist erforderlich",
+//	})
+func RegisterLocale(tag string, messages map[string]string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if locales[tag] == nil {
+		locales[tag] = make(map[string]string, len(messages))
+	}
+	maps.Copy(locales[tag], messages)
+}
+
+// SetLocale sets the locale of validation messages, "en" or "fr" out of the
+// box. An unknown locale falls back to English. A locale set on the context
+// with WithLocale takes precedence.
+func SetLocale(tag string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	currentLocale = tag
+}
+
+// Locale returns the locale set with SetLocale.
+func Locale() string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return currentLocale
+}
+
+type localeKey struct{}
+
+// WithLocale returns a context validating with the messages of locale, e.g.
+// the language of the request's user, for ValidateStructWithContext.
+func WithLocale(ctx context.Context, tag string) context.Context {
+	return context.WithValue(ctx, localeKey{}, tag)
+}
+
+// LocaleFromContext returns the locale set with WithLocale, or the one set
+// with SetLocale.
+func LocaleFromContext(ctx context.Context) string {
+	if tag, ok := ctx.Value(localeKey{}).(string); ok && tag != "" {
+		return tag
+	}
+	return Locale()
+}
+
+// messagesFor returns the messages of locale: the English ones, then the
+// messages registered with RegisterValidator or RegisterCustomMessage, then
+// the locale's. The strong_password message describes the password policy.
+func messagesFor(tag string) map[string]string {
+	messages := englishMessages()
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	messages["strong_password"], _ = passwordPolicy.message(DefaultLocale)
+	maps.Copy(messages, customMessages)
+	if msg, ok := passwordPolicy.message(tag); ok && tag != DefaultLocale {
+		messages["strong_password"] = msg
+	}
+	maps.Copy(messages, locales[tag])
+	return messages
+}
@@ -1,7 +1,8 @@
 package validation
 
-// defaultMessages returns validation messages in English (default)
-func defaultMessages() map[string]string {
+// englishMessages returns validation messages in English, the default
+// locale and the fallback of the others.
+func englishMessages() map[string]string {
 	return map[string]string{
 		// Required & Presence
 		"required":         "The {field} field is required",
@@ -85,18 +86,20 @@ func defaultMessages() map[string]string {
 		"dive":   "Each element of {field}",
 
 		// Custom Validators
-		"phone_fr":        "The {field} field must be a valid French phone number",
-		"postal_code_fr":  "The {field} field must be a valid French postal code (e.g., 75001)",
-		"slug":            "The {field} field must be a valid slug (e.g., my-article-123)",
-		"siret":           "The {field} field must be a valid SIRET number (14 digits)",
-		"siren":           "The {field} field must be a valid SIREN number (9 digits)",
-		"strong_password": "The {field} field must contain at least 8 characters with uppercase, lowercase and number",
+		"phone_fr":       "The {field} field must be a valid French phone number",
+		"postal_code_fr": "The {field} field must be a valid French postal code (e.g., 75001)",
+		"slug":           "The {field} field must be a valid slug (e.g., my-article-123)",
+		"siret":          "The {field} field must be a valid SIRET number (14 digits)",
+		"siren":          "The {field} field must be a valid SIREN number (9 digits)",
+		// strong_password describes the password policy, see messagesFor.
+		"password_confirmation": "The {field} field does not match the password",
+
+		// Context checks
+		"unique_record": "The {field} has already been taken",
 	}
 }
 
 // frenchMessages returns validation messages in French.
-//
-//nolint:unused
 func frenchMessages() map[string]string {
 	return map[string]string{
 		// Required & Presence
@@ -181,11 +184,14 @@ func frenchMessages() map[string]string {
 		"dive":   "Chaque élément de {field}",
 
 		// Custom French Validators
-		"phone_fr":        "Le champ {field} doit être un numéro de téléphone français valide",
-		"postal_code_fr":  "Le champ {field} doit être un code postal français valide (ex: 75001)",
-		"slug":            "Le champ {field} doit être un slug valide (ex: mon-article-123)",
-		"siret":           "Le champ {field} doit être un numéro SIRET valide (14 chiffres)",
-		"siren":           "Le champ {field} doit être un numéro SIREN valide (9 chiffres)",
-		"strong_password": "Le champ {field} doit contenir au moins 8 caractères avec majuscule, minuscule et chiffre",
+		"phone_fr":              "Le champ {field} doit être un numéro de téléphone français valide",
+		"postal_code_fr":        "Le champ {field} doit être un code postal français valide (ex: 75001)",
+		"slug":                  "Le champ {field} doit être un slug valide (ex: mon-article-123)",
+		"siret":                 "Le champ {field} doit être un numéro SIRET valide (14 chiffres)",
+		"siren":                 "Le champ {field} doit être un numéro SIREN valide (9 chiffres)",
+		"password_confirmation": "Le champ {field} ne correspond pas au mot de passe",
+
+		// Vérifications avec contexte
+		"unique_record": "La valeur du champ {field} est déjà utilisée",
 	}
 }
@@ -0,0 +1,138 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// PasswordPolicy is the rule set of the strong_password tag.
+type PasswordPolicy struct {
+	MinLength        int  // in characters
+	MaxLength        int  // in characters, 0 for no limit
+	RequireDigit     bool // at least one digit
+	RequireMixedCase bool // at least one uppercase and one lowercase letter
+	RequireSymbol    bool // at least one character that is not a letter, digit or space
+}
+
+// DefaultPasswordPolicy returns the policy in effect until
+// SetPasswordPolicy is called: 8 characters or more, with uppercase and
+// lowercase letters and a digit.
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{MinLength: 8, RequireDigit: true, RequireMixedCase: true}
+}
+
+// passwordPolicy is the policy of strong_password, guarded by registryMu.
+var passwordPolicy = DefaultPasswordPolicy()
+
+// SetPasswordPolicy sets the policy of the strong_password tag and of
+// IsStrongPassword. Its error messages describe the policy.
+//
+//	validation.SetPasswordPolicy(validation.PasswordPolicy{
+//		MinLength: 12, MaxLength: 128, RequireDigit: true, RequireSymbol: true,
+//	})
+func SetPasswordPolicy(p PasswordPolicy) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	passwordPolicy = p
+}
+
+// GetPasswordPolicy returns the policy set with SetPasswordPolicy.
+func GetPasswordPolicy() PasswordPolicy {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return passwordPolicy
+}
+
+// Allows reports whether password satisfies the policy.
+func (p PasswordPolicy) Allows(password string) bool {
+	n := utf8.RuneCountInString(password)
+	if n < p.MinLength || (p.MaxLength > 0 && n > p.MaxLength) {
+		return false
+	}
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	return (!p.RequireMixedCase || upper && lower) &&
+		(!p.RequireDigit || digit) &&
+		(!p.RequireSymbol || symbol)
+}
+
+// message returns the strong_password message describing the policy in
+// locale, false for locales other than English and French.
+func (p PasswordPolicy) message(locale string) (string, bool) {
+	type words struct {
+		prefix, atLeast, between, with, and, mixedCase, digit, symbol string
+	}
+	var w words
+	switch locale {
+	case DefaultLocale:
+		w = words{"The {field} field must contain ", "at least %d characters", "between %d and %d characters",
+			" with ", " and ", "uppercase and lowercase letters", "a digit", "a symbol"}
+	case "fr":
+		w = words{"Le champ {field} doit contenir ", "au moins %d caractères", "entre %d et %d caractères",
+			" avec ", " et ", "des majuscules et des minuscules", "un chiffre", "un symbole"}
+	default:
+		return "", false
+	}
+
+	msg := w.prefix + fmt.Sprintf(w.atLeast, p.MinLength)
+	if p.MaxLength > 0 {
+		msg = w.prefix + fmt.Sprintf(w.between, p.MinLength, p.MaxLength)
+	}
+	var reqs []string
+	if p.RequireMixedCase {
+		reqs = append(reqs, w.mixedCase)
+	}
+	if p.RequireDigit {
+		reqs = append(reqs, w.digit)
+	}
+	if p.RequireSymbol {
+		reqs = append(reqs, w.symbol)
+	}
+	if len(reqs) > 0 {
+		msg += w.with
+		if len(reqs) > 1 {
+			msg += strings.Join(reqs[:len(reqs)-1], ", ") + w.and
+		}
+		msg += reqs[len(reqs)-1]
+	}
+	return msg, true
+}
+
+// validateStrongPassword validates a password against the password policy.
+func validateStrongPassword(fl validator.FieldLevel) bool {
+	return GetPasswordPolicy().Allows(fl.Field().String())
+}
+
+// validatePasswordConfirmation validates that a confirmation field equals
+// the password field, named by the tag's parameter ("Password" by default):
+//
+//	Password             string `validate:"required,strong_password"`
+//	PasswordConfirmation string `validate:"password_confirmation"`
+func validatePasswordConfirmation(fl validator.FieldLevel) bool {
+	name := fl.Param()
+	if name == "" {
+		name = "Password"
+	}
+	parent := fl.Parent()
+	if parent.Kind() == reflect.Ptr {
+		parent = parent.Elem()
+	}
+	password := parent.FieldByName(name)
+	return password.IsValid() && password.Kind() == reflect.String && password.String() == fl.Field().String()
+}
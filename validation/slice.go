@@ -0,0 +1,39 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ValidateSlice validates every struct of a slice or array and returns the
+// errors keyed by index and field, e.g. "2.email". Elements may be structs
+// or pointers to structs; any other element, a nil pointer included, is
+// reported as an error, as is a value that is not a slice.
+func ValidateSlice(items any) (map[string]string, error) {
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("validation: ValidateSlice needs a slice, got %T", items)
+	}
+	result := make(map[string]string)
+	for i := 0; i < v.Len(); i++ {
+		item := v.Index(i)
+		for item.Kind() == reflect.Interface || item.Kind() == reflect.Ptr {
+			if item.IsNil() {
+				return nil, fmt.Errorf("validation: element %d is nil", i)
+			}
+			item = item.Elem()
+		}
+		if item.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("validation: element %d is a %s, not a struct", i, item.Type())
+		}
+		prefix := strconv.Itoa(i) + "."
+		for field, msg := range ValidateStruct(v.Index(i).Interface()) {
+			result[prefix+field] = msg
+		}
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
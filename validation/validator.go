@@ -1,11 +1,13 @@
 package validation
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/schema"
@@ -18,11 +20,11 @@ type Validator struct {
 	messages map[string]string
 }
 
-// New creates a new validator.
+// New creates a new validator, with the messages of the current locale.
 func New() *Validator {
 	v := &Validator{
 		validate: validator.New(),
-		messages: defaultMessages(),
+		messages: messagesFor(Locale()),
 	}
 
 	v.validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
@@ -38,6 +40,12 @@ func New() *Validator {
 
 	v.registerCustomValidators()
 
+	registryMu.RLock()
+	for tag, fn := range customValidators {
+		_ = v.validate.RegisterValidation(tag, fn)
+	}
+	registryMu.RUnlock()
+
 	return v
 }
 
@@ -51,7 +59,8 @@ func (v *Validator) ValidateVar(field interface{}, tag string) error {
 	return v.validate.Var(field, tag)
 }
 
-// ValidateStruct validates a struct and returns formatted errors.
+// ValidateStruct validates a struct and returns formatted errors, in the
+// locale set with SetLocale.
 func ValidateStruct(s interface{}) map[string]string {
 	v := New()
 	err := v.Validate(s)
@@ -59,7 +68,26 @@ func ValidateStruct(s interface{}) map[string]string {
 		return nil
 	}
 
-	return formatErrors(err, v.messages)
+	return formatErrors(err, v.messages, reflect.TypeOf(s))
+}
+
+// ValidateStructWithContext is ValidateStruct with the messages of the
+// locale of ctx (see WithLocale), followed by checks that need ctx, such as
+// Unique. A check only runs when its field passed the struct tags. The error
+// is that of a check that could not run, e.g. a failed query.
+func ValidateStructWithContext(ctx context.Context, s interface{}, checks ...ContextCheck) (map[string]string, error) {
+	messages := messagesFor(LocaleFromContext(ctx))
+	result := make(map[string]string)
+	if err := New().Validate(s); err != nil {
+		result = formatErrors(err, messages, reflect.TypeOf(s))
+	}
+	if err := runChecks(ctx, checks, messages, result); err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
 }
 
 // ValidateForm validates an HTTP form and binds to a struct.
@@ -95,8 +123,10 @@ func Must(s interface{}) {
 	}
 }
 
-// formatErrors formats validation errors.
-func formatErrors(err error, messages map[string]string) map[string]string {
+// formatErrors formats validation errors. A message registered with
+// FieldMessages or set in the field's `message` struct tag takes precedence
+// over the message of the tag. root is the type of the validated struct.
+func formatErrors(err error, messages map[string]string, root reflect.Type) map[string]string {
 	result := make(map[string]string)
 
 	if validationErrors, ok := err.(validator.ValidationErrors); ok {
@@ -105,22 +135,73 @@ func formatErrors(err error, messages map[string]string) map[string]string {
 			tag := e.Tag()
 			param := e.Param()
 
-			message, exists := messages[tag]
+			message, exists := fieldMessage(e, root)
+			if !exists {
+				message, exists = messages[tag]
+			}
 			if !exists {
 				message = fmt.Sprintf("Field %s is invalid", field)
 			}
 
-			message = strings.ReplaceAll(message, "{field}", field)
-			message = strings.ReplaceAll(message, "{param}", param)
-			message = strings.ReplaceAll(message, "{value}", fmt.Sprintf("%v", e.Value()))
-
-			result[field] = message
+			result[field] = formatMessage(message, field, param, fmt.Sprintf("%v", e.Value()))
 		}
 	}
 
 	return result
 }
 
+// formatMessage replaces the {field}, {param} and {value} placeholders of
+// message.
+func formatMessage(message, field, param, value string) string {
+	message = strings.ReplaceAll(message, "{field}", field)
+	message = strings.ReplaceAll(message, "{param}", param)
+	return strings.ReplaceAll(message, "{value}", value)
+}
+
+// fieldMessage returns the custom message of a failed field: FieldMessages
+// keyed by "Field.tag" (struct or json field name) first, then the
+// `message` struct tag of the field.
+func fieldMessage(e validator.FieldError, root reflect.Type) (string, bool) {
+	for _, name := range []string{e.StructField(), e.Field()} {
+		if msg, ok := lookupFieldMessage(name + "." + e.Tag()); ok {
+			return msg, true
+		}
+	}
+	if sf, ok := structField(root, e.StructNamespace()); ok {
+		if msg := sf.Tag.Get("message"); msg != "" {
+			return msg, true
+		}
+	}
+	return "", false
+}
+
+// structField resolves a namespace such as "User.Items[0].Name" to the
+// struct field it designates, starting from root.
+func structField(root reflect.Type, namespace string) (reflect.StructField, bool) {
+	var sf reflect.StructField
+	segments := strings.Split(namespace, ".")
+	if root == nil || len(segments) < 2 {
+		return sf, false
+	}
+	t := root
+	for _, seg := range segments[1:] {
+		// The previous segment may be a pointer or index a slice, array or map.
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return sf, false
+		}
+		name, _, _ := strings.Cut(seg, "[")
+		f, ok := t.FieldByName(name)
+		if !ok {
+			return sf, false
+		}
+		sf, t = f, f.Type
+	}
+	return sf, true
+}
+
 // HasErrors checks if there are any errors.
 func HasErrors(errors map[string]string) bool {
 	return len(errors) > 0
@@ -177,20 +258,81 @@ func OnlyErrors(errors map[string]string) []string {
 	return lo.Keys(errors)
 }
 
-// RegisterCustomMessage registers a custom message.
+// RegisterCustomMessage registers the message of a tag, replacing the
+// English one. It supports the {field}, {param} and {value} placeholders.
+// Messages of other locales still take precedence; translate it with
+// RegisterLocale.
 func RegisterCustomMessage(tag, message string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
 	customMessages[tag] = message
 }
 
-// RegisterValidation registers a custom validator.
+// FieldMessages registers messages for specific field/tag pairs, keyed by
+// "Field.tag" (e.g. "Email.email"). Field is either the struct field name
+// or its json name. They take precedence over the messages of the tag.
+func FieldMessages(messages map[string]string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for key, msg := range messages {
+		fieldMessages[key] = msg
+	}
+}
+
+// lookupFieldMessage returns the message registered with FieldMessages for
+// a "Field.tag" key.
+func lookupFieldMessage(key string) (string, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	msg, ok := fieldMessages[key]
+	return msg, ok
+}
+
+// RegisterValidation registers a custom validator for tag, keeping the
+// message of the tag. Prefer RegisterValidator, which sets both.
 func RegisterValidation(tag string, fn validator.Func) {
-	_ = validator.New().RegisterValidation(tag, fn)
+	if tag == "" || fn == nil {
+		panic("validation: RegisterValidation needs a tag and a function")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	customValidators[tag] = fn
+}
+
+// RegisterValidator registers a custom validator for tag, and its error
+// message. The message supports the {field}, {param} and {value}
+// placeholders of the built-in messages; an empty message falls back to
+// the generic one. Translate it with RegisterLocale. Registering a tag
+// again replaces its function and message, and a built-in tag can be
+// overridden the same way. Register validators at startup: validators
+// created before keep the previous definition.
+//
+//	validation.RegisterValidator("product_code", func(fl validator.FieldLevel) bool {
+//		return reProductCode.MatchString(fl.Field().String())
+//	}, "The {field} field must be a product code such as AB-1234")
+func RegisterValidator(tag string, fn validator.Func, message string) {
+	if tag == "" || fn == nil {
+		panic("validation: RegisterValidator needs a tag and a function")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	customValidators[tag] = fn
+	if message == "" {
+		delete(customMessages, tag)
+	} else {
+		customMessages[tag] = message
+	}
 }
 
-// Global variables for custom messages.
+// Global variables for custom validators and messages. customValidators,
+// customMessages, fieldMessages, locales and currentLocale are guarded by
+// registryMu.
 var (
-	customMessages = make(map[string]string)
-	decoder        = schema.NewDecoder()
+	registryMu       sync.RWMutex
+	customValidators = make(map[string]validator.Func)
+	customMessages   = make(map[string]string)
+	fieldMessages    = make(map[string]string)
+	decoder          = schema.NewDecoder()
 )
 
 func init() {
@@ -1,11 +1,14 @@
 package validation
 
 import (
+	"context"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -298,6 +301,10 @@ func TestValidatePhoneFR_Valid(t *testing.T) {
 		"+33 6 12 34 56 78",
 		"0712345678",
 		"0123456789",
+		"01 23 45 67 89",
+		"+33123456789",
+		"+33 (0)1 23 45 67 89",
+		"0033 6 12 34 56 78",
 	}
 
 	for _, phone := range validPhones {
@@ -309,10 +316,15 @@ func TestValidatePhoneFR_Valid(t *testing.T) {
 
 func TestValidatePhoneFR_Invalid(t *testing.T) {
 	invalidPhones := []string{
-		"12345678",    // Too short
-		"06123456789", // Too long
-		"abcd1234",    // Letters
-		"",            // Empty
+		"12345678",       // Too short
+		"06123456789",    // Too long
+		"abcd1234",       // Letters
+		"",               // Empty
+		"+3312345678",    // Too short after +33
+		"+331234567890",  // Too long after +33
+		"+33012345678",   // Missing digit after trunk prefix
+		"01 23 45 67 8",  // Too short with separators
+		"06 12 34 56 7a", // Letter
 	}
 
 	for _, phone := range invalidPhones {
@@ -322,6 +334,27 @@ func TestValidatePhoneFR_Invalid(t *testing.T) {
 	}
 }
 
+func TestNormalizePhoneFR(t *testing.T) {
+	tests := map[string]string{
+		"01 23 45 67 89":       "+33123456789",
+		"+33123456789":         "+33123456789",
+		"06.12.34.56.78":       "+33612345678",
+		"+33 (0)6 12 34 56 78": "+33612345678",
+		"0033612345678":        "+33612345678",
+		"612345678":            "+33612345678",
+		"0123":                 "",
+		"+3312345678":          "",
+		"01234567890":          "",
+		"":                     "",
+	}
+
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			assert.Equal(t, want, NormalizePhoneFR(input))
+		})
+	}
+}
+
 func TestValidatePostalCodeFR_Valid(t *testing.T) {
 	validCodes := []string{
 		"75001",
@@ -572,6 +605,188 @@ func TestMust(t *testing.T) {
 	})
 }
 
+type Signup struct {
+	Email   string   `json:"email" validate:"required,email"`
+	Company string   `json:"company" validate:"required" message:"Tell us where you work"`
+	Team    []Member `json:"team" validate:"dive"`
+}
+
+type Member struct {
+	Name string `json:"name" validate:"required" message:"Each member needs a name"`
+}
+
+func frenchErrors(t *testing.T, s interface{}) map[string]string {
+	t.Helper()
+	v := New()
+	err := v.Validate(s)
+	require.Error(t, err)
+	return formatErrors(err, frenchMessages(), reflect.TypeOf(s))
+}
+
+func TestFieldMessages_OverrideLocaleMessage(t *testing.T) {
+	signup := Signup{Email: "not-an-email", Company: "Acme"}
+
+	errors := frenchErrors(t, signup)
+	assert.Equal(t, "Le champ email doit être une adresse email valide", errors["email"])
+
+	FieldMessages(map[string]string{"Email.email": "Use your work email"})
+	t.Cleanup(func() { delete(fieldMessages, "Email.email") })
+
+	errors = frenchErrors(t, signup)
+	assert.Equal(t, "Use your work email", errors["email"])
+
+	// Only the registered tag is overridden.
+	errors = frenchErrors(t, Signup{Company: "Acme"})
+	assert.Equal(t, "Le champ email est obligatoire", errors["email"])
+}
+
+func TestFieldMessages_JSONName(t *testing.T) {
+	FieldMessages(map[string]string{"email.required": "We need an email for {field}"})
+	t.Cleanup(func() { delete(fieldMessages, "email.required") })
+
+	errors := ValidateStruct(Signup{Company: "Acme"})
+	assert.Equal(t, "We need an email for email", errors["email"])
+}
+
+func TestMessageStructTag(t *testing.T) {
+	errors := frenchErrors(t, Signup{Email: "jane@acme.com", Team: []Member{{Name: "Bob"}, {}}})
+	assert.Equal(t, "Tell us where you work", errors["company"])
+	assert.Equal(t, "Each member needs a name", errors["name"])
+}
+
+func TestSetLocale(t *testing.T) {
+	t.Cleanup(func() { SetLocale(DefaultLocale) })
+
+	SetLocale("fr")
+	assert.Equal(t, "Le champ email est obligatoire", ValidateStruct(Signup{Company: "Acme"})["email"])
+
+	// Unknown locales fall back to English.
+	SetLocale("xx")
+	assert.Equal(t, "The email field is required", ValidateStruct(Signup{Company: "Acme"})["email"])
+}
+
+func TestRegisterLocale(t *testing.T) {
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(locales, "de")
+		registryMu.Unlock()
+	})
+	RegisterLocale("de", map[string]string{"required": "Das Feld {field} ist erforderlich"})
+
+	ctx := WithLocale(context.Background(), "de")
+	errors, err := ValidateStructWithContext(ctx, User{Email: "nope", Age: 18})
+	require.NoError(t, err)
+	assert.Equal(t, "Das Feld password ist erforderlich", errors["password"])
+	assert.Equal(t, "The email field must be a valid email address", errors["email"], "missing translations fall back to English")
+
+	// The context locale does not change the global one.
+	assert.Equal(t, "The email field is required", ValidateStruct(Signup{Company: "Acme"})["email"])
+}
+
+func TestUnique(t *testing.T) {
+	taken := map[string]int{"ada@example.com": 1} // email -> user ID
+	unique := func(email string, exceptID int) ContextCheck {
+		return Unique("email", func(context.Context) (bool, error) {
+			id, ok := taken[email]
+			return ok && id != exceptID, nil
+		})
+	}
+	ada := User{Email: "ada@example.com", Password: "password", Age: 30}
+
+	errors, err := ValidateStructWithContext(context.Background(), ada, unique(ada.Email, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "The email has already been taken", errors["email"])
+
+	// Updating the record holding the value.
+	errors, err = ValidateStructWithContext(context.Background(), ada, unique(ada.Email, 1))
+	require.NoError(t, err)
+	assert.Nil(t, errors)
+
+	// Checks do not run on fields failing their tags.
+	errors, err = ValidateStructWithContext(context.Background(), User{Email: "nope", Password: "password", Age: 30},
+		Unique("email", func(context.Context) (bool, error) { return false, assert.AnError }))
+	require.NoError(t, err)
+	assert.Equal(t, "The email field must be a valid email address", errors["email"])
+
+	_, err = ValidateStructWithContext(context.Background(), ada,
+		Unique("email", func(context.Context) (bool, error) { return false, assert.AnError }))
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+type Registration struct {
+	Password             string `json:"password" validate:"required,strong_password"`
+	PasswordConfirmation string `json:"password_confirmation" validate:"password_confirmation"`
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	t.Cleanup(func() {
+		SetPasswordPolicy(DefaultPasswordPolicy())
+		SetLocale(DefaultLocale)
+	})
+
+	errors := ValidateStruct(Registration{Password: "short", PasswordConfirmation: "short"})
+	assert.Equal(t, "The password field must contain at least 8 characters with uppercase and lowercase letters and a digit", errors["password"])
+
+	SetPasswordPolicy(PasswordPolicy{MinLength: 12, MaxLength: 64, RequireDigit: true, RequireSymbol: true})
+	assert.False(t, IsStrongPassword("Password1234"))
+	assert.True(t, IsStrongPassword("password-1234"))
+	assert.False(t, IsStrongPassword(strings.Repeat("a-1", 22)))
+
+	errors = ValidateStruct(Registration{Password: "Password1234", PasswordConfirmation: "Password1234"})
+	assert.Equal(t, "The password field must contain between 12 and 64 characters with a digit and a symbol", errors["password"])
+	SetLocale("fr")
+	errors = ValidateStruct(Registration{Password: "Password1234", PasswordConfirmation: "Password1234"})
+	assert.Equal(t, "Le champ password doit contenir entre 12 et 64 caractères avec un chiffre et un symbole", errors["password"])
+}
+
+func TestPasswordConfirmation(t *testing.T) {
+	assert.Nil(t, ValidateStruct(Registration{Password: "Secret123", PasswordConfirmation: "Secret123"}))
+
+	errors := ValidateStruct(Registration{Password: "Secret123", PasswordConfirmation: "Secret124"})
+	assert.Equal(t, "The password_confirmation field does not match the password", errors["password_confirmation"])
+
+	type reset struct {
+		NewPassword string `validate:"required"`
+		Confirm     string `validate:"password_confirmation=NewPassword"`
+	}
+	assert.Nil(t, ValidateStruct(reset{NewPassword: "x", Confirm: "x"}))
+	assert.NotNil(t, ValidateStruct(reset{NewPassword: "x", Confirm: "y"}))
+}
+
+func TestWriteErrors(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteErrors(rec, ValidateStruct(User{Email: "nope", Password: "password", Age: 30}))
+
+	assert.Equal(t, 422, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"errors": {"email": "The email field must be a valid email address"}}`, rec.Body.String())
+}
+
+func TestValidateSlice(t *testing.T) {
+	users := []any{
+		User{Email: "ada@example.com", Password: "password", Age: 30},
+		&User{Email: "nope", Password: "password", Age: 30},
+		User{Email: "bob@example.com", Password: "short", Age: 30},
+	}
+	errors, err := ValidateSlice(users)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"1.email":    "The email field must be a valid email address",
+		"2.password": "The password field must be at least 8 characters",
+	}, errors)
+
+	errors, err = ValidateSlice([]User{{Email: "ada@example.com", Password: "password", Age: 30}})
+	require.NoError(t, err)
+	assert.Nil(t, errors)
+
+	_, err = ValidateSlice([]any{User{}, "user"})
+	assert.EqualError(t, err, "validation: element 1 is a string, not a struct")
+	_, err = ValidateSlice([]*User{nil})
+	assert.EqualError(t, err, "validation: element 0 is nil")
+	_, err = ValidateSlice(User{})
+	assert.Error(t, err)
+}
+
 // Benchmarks
 
 func BenchmarkValidateStruct_Valid(b *testing.B) {
@@ -617,3 +832,64 @@ func BenchmarkIsValidSIRET(b *testing.B) {
 		IsValidSIRET(siret)
 	}
 }
+
+func TestCustomValidators_EmptyOptional(t *testing.T) {
+	type optional struct {
+		Phone      string `json:"phone" validate:"phone_fr"`
+		PostalCode string `json:"postal_code" validate:"postal_code_fr"`
+		SIRET      string `json:"siret" validate:"siret"`
+		SIREN      string `json:"siren" validate:"siren"`
+		Slug       string `json:"slug" validate:"slug"`
+	}
+	assert.Nil(t, ValidateStruct(optional{}))
+
+	errors := ValidateStruct(optional{Phone: "123", Slug: "-bad-"})
+	require.NotNil(t, errors)
+	assert.Len(t, errors, 2)
+	assert.Contains(t, errors, "phone")
+	assert.Contains(t, errors, "slug")
+
+	type required struct {
+		Phone      string `json:"phone" validate:"required,phone_fr"`
+		PostalCode string `json:"postal_code" validate:"required,postal_code_fr"`
+		SIRET      string `json:"siret" validate:"required,siret"`
+		SIREN      string `json:"siren" validate:"required,siren"`
+		Slug       string `json:"slug" validate:"required,slug"`
+	}
+	errors = ValidateStruct(required{})
+	require.NotNil(t, errors)
+	assert.Len(t, errors, 5)
+	for _, field := range []string{"phone", "postal_code", "siret", "siren", "slug"} {
+		assert.Contains(t, errors, field)
+	}
+}
+
+type Part struct {
+	Code string `json:"code" validate:"required,product_code=4"`
+}
+
+func TestRegisterValidator(t *testing.T) {
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(customValidators, "product_code")
+		delete(customMessages, "product_code")
+		registryMu.Unlock()
+	})
+
+	RegisterValidator("product_code", func(fl validator.FieldLevel) bool {
+		return strings.HasPrefix(fl.Field().String(), "PC-")
+	}, "The {field} field must be a product code of {param} digits")
+
+	assert.Nil(t, ValidateStruct(Part{Code: "PC-1234"}))
+	assert.Equal(t, "The code field must be a product code of 4 digits", ValidateStruct(Part{Code: "1234"})["code"])
+
+	// Registering the tag again replaces the function and the message.
+	RegisterValidator("product_code", func(fl validator.FieldLevel) bool {
+		return strings.HasPrefix(fl.Field().String(), "PRD-")
+	}, "Invalid {field}")
+	assert.Equal(t, "Invalid code", ValidateStruct(Part{Code: "PC-1234"})["code"])
+	assert.Nil(t, ValidateStruct(Part{Code: "PRD-1234"}))
+
+	assert.Panics(t, func() { RegisterValidator("", func(validator.FieldLevel) bool { return true }, "") })
+	assert.Panics(t, func() { RegisterValidator("product_code", nil, "") })
+}
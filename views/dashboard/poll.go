@@ -0,0 +1,41 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/bozz33/sublimego/ui/layouts"
+	"github.com/bozz33/sublimego/widget"
+)
+
+// pollAttrs returns the Alpine attributes refreshing a Live widget: every
+// poll interval the HTML of /api/widgets/{id} replaces the widget, and its
+// scripts (e.g. chart setup) run again. Static widgets get none.
+func pollAttrs(ctx context.Context, w widget.Widget) templ.Attributes {
+	l, ok := w.(widget.Live)
+	if !ok || l.GetPollInterval() <= 0 {
+		return nil
+	}
+	base := strings.TrimRight(layouts.GetPanelConfigFromContext(ctx).Path, "/")
+	endpoint, _ := json.Marshal(base + "/api/widgets/" + url.PathEscape(l.GetID()))
+	return templ.Attributes{
+		"data-widget-id": l.GetID(),
+		"x-data":         "",
+		"x-init": fmt.Sprintf(`setInterval(() => fetch(%s, { headers: { Accept: 'application/json' } })
+	.then(r => r.ok ? r.json() : null)
+	.then(d => {
+		if (!d) return;
+		$el.innerHTML = d.html;
+		$el.querySelectorAll('script').forEach(s => {
+			const n = document.createElement('script');
+			[...s.attributes].forEach(a => n.setAttribute(a.name, a.value));
+			n.text = s.text;
+			s.replaceWith(n);
+		});
+	}), %d)`, endpoint, l.GetPollInterval().Milliseconds()),
+	}
+}
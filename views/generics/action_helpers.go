@@ -1,9 +1,12 @@
 package generics
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 
 	"github.com/bozz33/sublimego/actions"
+	"github.com/bozz33/sublimego/engine"
 )
 
 // actionModalDispatch builds the Alpine.js $dispatch call string for a confirmation modal.
@@ -12,7 +15,13 @@ func actionModalDispatch(a *actions.Action, item any) string {
 	if a.UrlResolver != nil {
 		url = a.UrlResolver(item)
 	}
-	method := a.Method
+	return confirmDispatch(a, url, a.Method)
+}
+
+// confirmDispatch builds the $dispatch call opening ActionConfirmModal for
+// a form posted to url. The details are JSON-encoded so labels may contain
+// quotes.
+func confirmDispatch(a *actions.Action, url, method string) string {
 	if method == "" {
 		method = "POST"
 	}
@@ -24,16 +33,41 @@ func actionModalDispatch(a *actions.Action, item any) string {
 	if cancelLabel == "" {
 		cancelLabel = "Cancel"
 	}
-	title := a.ModalTitle
-	desc := a.ModalDescription
 	color := a.Color
 	if color == "" {
 		color = "red"
 	}
-	return fmt.Sprintf(
-		`$dispatch('open-action-modal', { url: '%s', method: '%s', title: '%s', desc: '%s', confirmLabel: '%s', cancelLabel: '%s', color: '%s' })`,
-		url, method, title, desc, confirmLabel, cancelLabel, color,
-	)
+	detail, _ := json.Marshal(map[string]string{
+		"url":          url,
+		"method":       method,
+		"title":        a.ModalTitle,
+		"desc":         a.ModalDescription,
+		"confirmLabel": confirmLabel,
+		"cancelLabel":  cancelLabel,
+		"color":        color,
+	})
+	return fmt.Sprintf("$dispatch('open-action-modal', %s)", detail)
+}
+
+// rowActionURL returns the URL a row action is posted to.
+func rowActionURL(state engine.TableState, row engine.Row, a *actions.Action) string {
+	return fmt.Sprintf("%s/%s/action/%s", state.BaseURL, row.URLKey(), url.PathEscape(a.Name))
+}
+
+// rowActionItemClass returns Tailwind classes for an entry of the row
+// actions dropdown.
+func rowActionItemClass(color string) string {
+	base := "w-full flex items-center gap-2 px-3 py-2 text-sm text-left transition-colors "
+	switch color {
+	case "danger", "red":
+		return base + "text-red-600 dark:text-red-400 hover:bg-red-50 dark:hover:bg-red-900/20"
+	case "success", "green":
+		return base + "text-green-600 dark:text-green-400 hover:bg-green-50 dark:hover:bg-green-900/20"
+	case "warning", "yellow":
+		return base + "text-yellow-600 dark:text-yellow-400 hover:bg-yellow-50 dark:hover:bg-yellow-900/20"
+	default:
+		return base + "text-gray-700 dark:text-gray-300 hover:bg-gray-50 dark:hover:bg-gray-700"
+	}
 }
 
 // actionButtonClass returns Tailwind classes for a full action button by color.
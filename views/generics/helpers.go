@@ -1,10 +1,16 @@
 package generics
 
 import (
+	"context"
 	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/bozz33/sublimego/engine"
+	"github.com/bozz33/sublimego/form"
+	"github.com/bozz33/sublimego/table"
 )
 
 // getValueStr returns the value as a string
@@ -20,8 +26,11 @@ func isChecked(v any) bool {
 	if v == nil {
 		return false
 	}
-	if val, ok := v.(bool); ok {
+	switch val := v.(type) {
+	case bool:
 		return val
+	case string: // refilled from submitted values
+		return val == "true" || val == "on" || val == "1"
 	}
 	return false
 }
@@ -90,3 +99,171 @@ func min(a, b int) int { //nolint:unused
 	}
 	return b
 }
+
+// exportHref returns the export URL carrying the current filters, search and
+// sort, so the export matches the list.
+func exportHref(state engine.TableState) string {
+	q := listQuery(state)
+	if len(q) == 0 {
+		return state.ExportURL
+	}
+	sep := "?"
+	if strings.Contains(state.ExportURL, "?") {
+		sep = "&"
+	}
+	return state.ExportURL + sep + q.Encode()
+}
+
+// listQuery returns the current filters, search and sort of the list as
+// query params.
+func listQuery(state engine.TableState) url.Values {
+	q := url.Values{}
+	for key, value := range state.ActiveFilters {
+		if values := state.FilterValues[key]; len(values) > 0 {
+			q["filter_"+key] = values
+			continue
+		}
+		q.Set("filter_"+key, value)
+	}
+	if state.Search != "" {
+		q.Set("search", state.Search)
+	}
+	if state.SortKey != "" {
+		q.Set("sort", state.SortKey)
+		q.Set("dir", state.SortDir)
+	}
+	return q
+}
+
+// pageLinks returns the pagination links of the list, keeping its filters,
+// search and sort.
+func pageLinks(state engine.TableState) []table.PageLink {
+	p := state.Pagination
+	return table.PageLinks(listQuery(state), p.CurrentPage, p.LastPage, p.PerPage)
+}
+
+// pageHref returns the URL of page of the list with perPage rows.
+func pageHref(state engine.TableState, page, perPage int) string {
+	q := listQuery(state)
+	q.Set("page", strconv.Itoa(page))
+	q.Set("per_page", strconv.Itoa(perPage))
+	return "?" + q.Encode()
+}
+
+// filterSelected reports whether value is selected in the filter key,
+// including any of the values of a multi-select filter.
+func filterSelected(state engine.TableState, key, value string) bool {
+	if values, ok := state.FilterValues[key]; ok {
+		return slices.Contains(values, value)
+	}
+	return state.ActiveFilters[key] == value
+}
+
+// hasMultipleFilter reports whether a multi-select filter is shown. Those
+// do not submit on change, so the filter form gets an Apply button.
+func hasMultipleFilter(state engine.TableState) bool {
+	return slices.ContainsFunc(state.Filters, func(f engine.FilterDef) bool {
+		return f.Type == "select" && f.Multiple
+	})
+}
+
+// perPageOptions returns the per-page selector choices, including current
+// if it is not one of the standard sizes.
+func perPageOptions(current int) []int {
+	if slices.Contains(table.PerPageOptions, current) {
+		return table.PerPageOptions
+	}
+	opts := append(slices.Clone(table.PerPageOptions), current)
+	slices.Sort(opts)
+	return opts
+}
+
+// maskedInputData builds the Alpine x-data object of an input masked with
+// pattern. The formatting logic mirrors form.ApplyMask.
+func maskedInputData(pattern string, value any) string {
+	return fmt.Sprintf(`{
+	mask: %q, display: '', raw: '',
+	accepts(t, c) {
+		if (t === '#') return /\d/.test(c);
+		if (t === 'A') return /\p{L}/u.test(c);
+		return /[\p{L}\d]/u.test(c);
+	},
+	format(v) {
+		const src = (v || '').replace(/[^\p{L}\d]/gu, '');
+		let out = '', raw = '', j = 0;
+		for (const p of this.mask) {
+			if (j >= src.length) break;
+			if (p === '#' || p === 'A' || p === '*') {
+				while (j < src.length && !this.accepts(p, src[j])) j++;
+				if (j >= src.length) break;
+				out += src[j]; raw += src[j]; j++;
+			} else {
+				out += p;
+				if (src[j] === p) j++;
+			}
+		}
+		this.display = out;
+		this.raw = raw;
+	},
+	init() { this.format(%q) }
+}`, pattern, getValueStr(value))
+}
+
+// recordURL returns the link of a row's first cell: the row's custom URL,
+// the detail view, or the edit form when viewing is disabled ("" if both are).
+func recordURL(state engine.TableState, row engine.Row) string {
+	switch {
+	case row.RecordURL != "":
+		return row.RecordURL
+	case state.ActionEnabled(engine.ActionView):
+		return fmt.Sprintf("%s/%s", state.BaseURL, row.URLKey())
+	case state.ActionEnabled(engine.ActionEdit):
+		return fmt.Sprintf("%s/%s/edit", state.BaseURL, row.URLKey())
+	}
+	return ""
+}
+
+// applyWizardState applies the state set by the CRUD handler, if any, to wz
+// and returns it.
+func applyWizardState(ctx context.Context, wz *form.Wizard) form.WizardState {
+	st, ok := form.WizardStateFromContext(ctx)
+	if ok {
+		wz.Apply(st)
+	}
+	return st
+}
+
+// wizardStepClass returns the classes of the progress marker of step i.
+func wizardStepClass(wz *form.Wizard, i int) string {
+	switch {
+	case i < wz.Current:
+		return "bg-primary-600 text-white"
+	case i == wz.Current:
+		return "border-2 border-primary-600 text-primary-600"
+	default:
+		return "border-2 border-gray-300 text-gray-500 dark:border-gray-600 dark:text-gray-400"
+	}
+}
+
+// wizardErrors returns the error messages of the current step, in field
+// order.
+func wizardErrors(wz *form.Wizard) []string {
+	var msgs []string
+	seen := make(map[string]bool)
+	for _, name := range wz.StepFieldNames(wz.Current) {
+		msgs = append(msgs, wz.Errors[name]...)
+		seen[name] = true
+	}
+	// Errors of the ValidateStep callback may name other fields.
+	others := make([]string, 0, len(wz.Errors))
+	for name := range wz.Errors {
+		if !seen[name] {
+			others = append(others, name)
+		}
+	}
+	slices.Sort(others)
+	for _, name := range others {
+		msgs = append(msgs, wz.Errors[name]...)
+	}
+	return msgs
+}
@@ -227,6 +227,7 @@ func BuildRelationManagerState(
 			CanAttach:    string(rm.RelationType()) == "many_to_many",
 			CanDelete:    true,
 			RelationType: string(rm.RelationType()),
+			PivotFields:  rm.PivotFields(),
 		})
 	}
 	return state
@@ -6,10 +6,26 @@ import (
 
 	"github.com/a-h/templ"
 	"github.com/bozz33/sublimego/form"
+	"github.com/bozz33/sublimego/validation"
 )
 
-// RenderComponent is the smart switch that decides which template to call
+// RenderComponent is the smart switch that decides which template to call.
+// Fields are followed by their message from validation.WithErrors, if any.
 func RenderComponent(c form.Component) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if err := renderComponent(c).Render(ctx, w); err != nil {
+			return err
+		}
+		if field, ok := c.(interface{ Name() string }); ok {
+			if msg := validation.ErrorsFromContext(ctx)[field.Name()]; msg != "" {
+				return FieldError(msg).Render(ctx, w)
+			}
+		}
+		return nil
+	})
+}
+
+func renderComponent(c form.Component) templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
 		switch v := c.(type) {
 		// Layouts
@@ -19,10 +35,14 @@ func RenderComponent(c form.Component) templ.Component {
 			return Grid(v).Render(ctx, w)
 		case *form.Tabs:
 			return Tabs(v).Render(ctx, w)
+		case *form.ToggleGroupLayout:
+			return ToggleGroupView(v).Render(ctx, w)
 
 		// Fields
 		case *form.TextInput:
 			return TextInput(v).Render(ctx, w)
+		case *form.MaskedInputField:
+			return MaskedInputView(v).Render(ctx, w)
 		case *form.TextareaInput:
 			return Textarea(v).Render(ctx, w)
 		case *form.SelectInput:
@@ -31,6 +51,8 @@ func RenderComponent(c form.Component) templ.Component {
 			return CheckboxField(v).Render(ctx, w)
 		case *form.FileUploadInput:
 			return FileUploadField(v).Render(ctx, w)
+		case *form.ImageUploadInput:
+			return ImageUploadField(v).Render(ctx, w)
 		case *form.DatePicker:
 			return DatePickerField(v).Render(ctx, w)
 		case *form.HiddenField:
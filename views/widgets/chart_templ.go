@@ -12,6 +12,9 @@ import (
 	"github.com/bozz33/sublimego/widget"
 )
 
+// Chart renders a chart widget. Its ApexCharts options are emitted in a
+// JSON script block (id "<chart id>-config") that the script below, or any
+// other charting code, picks up.
 func Chart(w *widget.ChartWidget) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -40,7 +43,7 @@ func Chart(w *widget.ChartWidget) templ.Component {
 		var templ_7745c5c3_Var2 string
 		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(w.Label)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/widgets/chart.templ`, Line: 11, Col: 13}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/widgets/chart.templ`, Line: 14, Col: 13}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
 		if templ_7745c5c3_Err != nil {
@@ -53,7 +56,7 @@ func Chart(w *widget.ChartWidget) templ.Component {
 		var templ_7745c5c3_Var3 string
 		templ_7745c5c3_Var3, templ_7745c5c3_Err = templruntime.SanitizeStyleAttributeValues("height: " + w.Height + "px")
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/widgets/chart.templ`, Line: 14, Col: 67}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/widgets/chart.templ`, Line: 17, Col: 67}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 		if templ_7745c5c3_Err != nil {
@@ -66,13 +69,34 @@ func Chart(w *widget.ChartWidget) templ.Component {
 		var templ_7745c5c3_Var4 string
 		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(w.ID)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/widgets/chart.templ`, Line: 15, Col: 17}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/widgets/chart.templ`, Line: 18, Col: 17}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "\"></div></div><script>\r\n\t\t\t(function() {\r\n\t\t\t\tconst options = {\r\n\t\t\t\t\tseries: JSON.parse('{ w.GetSeriesJSON() }'),\r\n\t\t\t\t\tchart: {\r\n\t\t\t\t\t\ttype: '{ string(w.Type) }',\r\n\t\t\t\t\t\theight: { w.Height },\r\n\t\t\t\t\t\tfontFamily: 'Inter, sans-serif',\r\n\t\t\t\t\t\ttoolbar: { show: false },\r\n\t\t\t\t\t\tbackground: 'transparent'\r\n\t\t\t\t\t},\r\n\t\t\t\t\tcolors: JSON.parse('{ w.GetColorsJSON() }'),\r\n\t\t\t\t\tstroke: {\r\n\t\t\t\t\t\tcurve: 'smooth',\r\n\t\t\t\t\t\twidth: 2\r\n\t\t\t\t\t},\r\n\t\t\t\t\tdataLabels: { enabled: false },\r\n\t\t\t\t\txaxis: {\r\n\t\t\t\t\t\tcategories: JSON.parse('{ w.GetLabelsJSON() }'),\r\n\t\t\t\t\t\taxisBorder: { show: false },\r\n\t\t\t\t\t\taxisTicks: { show: false },\r\n\t\t\t\t\t\tlabels: {\r\n\t\t\t\t\t\t\tstyle: { colors: '#6b7280', fontSize: '12px' }\r\n\t\t\t\t\t\t}\r\n\t\t\t\t\t},\r\n\t\t\t\t\tgrid: {\r\n\t\t\t\t\t\tborderColor: '#e5e7eb',\r\n\t\t\t\t\t\tstrokeDashArray: 4,\r\n\t\t\t\t\t},\r\n\t\t\t\t\tplotOptions: {\r\n\t\t\t\t\t\tpie: {\r\n\t\t\t\t\t\t\tdonut: {\r\n\t\t\t\t\t\t\t\tsize: '70%',\r\n\t\t\t\t\t\t\t\tlabels: { show: true, total: { show: true, label: 'Total' } }\r\n\t\t\t\t\t\t\t}\r\n\t\t\t\t\t\t}\r\n\t\t\t\t\t},\r\n\t\t\t\t\tlabels: JSON.parse('{ w.GetLabelsJSON() }')\r\n\t\t\t\t};\r\n\r\n\t\t\t\tif (document.querySelector('#{ w.ID }')) {\r\n\t\t\t\t\tconst chart = new ApexCharts(document.querySelector('#{ w.ID }'), options);\r\n\t\t\t\t\tchart.render();\r\n\t\t\t\t}\r\n\t\t\t})();\r\n\t\t</script></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "\" data-chart=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var5 string
+		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(w.ID + "-config")
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/widgets/chart.templ`, Line: 18, Col: 49}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "\"></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templ.JSONScript(w.ID+"-config", w.Config()).Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "<script>\n\t\t\t(function() {\n\t\t\t\tconst config = document.currentScript.previousElementSibling;\n\t\t\t\tconst el = document.querySelector('[data-chart=\"' + config.id + '\"]');\n\t\t\t\tif (el && window.ApexCharts) {\n\t\t\t\t\tnew ApexCharts(el, JSON.parse(config.textContent)).render();\n\t\t\t\t}\n\t\t\t})();\n\t\t</script></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
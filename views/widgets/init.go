@@ -12,4 +12,7 @@ func init() {
 	widget.SetChartRenderer(func(w *widget.ChartWidget) templ.Component {
 		return Chart(w)
 	})
+	widget.SetTableRenderer(func(w *widget.TableWidget) templ.Component {
+		return Table(w)
+	})
 }
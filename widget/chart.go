@@ -2,6 +2,7 @@ package widget
 
 import (
 	"encoding/json"
+	"strconv"
 
 	"github.com/a-h/templ"
 )
@@ -13,12 +14,13 @@ const (
 	Line  ChartType = "area"
 	Bar   ChartType = "bar"
 	Donut ChartType = "donut"
+	Pie   ChartType = "pie"
 )
 
 // ChartDataSet represents a data series.
 type ChartDataSet struct {
-	Name string `json:"name"`
-	Data []int  `json:"data"`
+	Name string    `json:"name"`
+	Data []float64 `json:"data"`
 }
 
 // ChartWidget configures a complete chart.
@@ -29,7 +31,8 @@ type ChartWidget struct {
 	Series []ChartDataSet
 	Labels []string
 	Colors []string
-	Height string
+	Height string // plot height in pixels
+	Span   int    // dashboard grid columns, 1-12, 0 for the full row
 }
 
 // NewChart creates a new chart.
@@ -43,32 +46,121 @@ func NewChart(id, label string, t ChartType) *ChartWidget {
 	}
 }
 
+// ColumnSpan sets how many of the 12 dashboard grid columns the chart
+// takes on large screens. Charts span the full row by default.
+func (c *ChartWidget) ColumnSpan(n int) *ChartWidget {
+	c.Span = clampSpan(n)
+	return c
+}
+
+// SetHeight sets the plot height, in pixels (300 by default).
+func (c *ChartWidget) SetHeight(px int) *ChartWidget {
+	c.Height = strconv.Itoa(px)
+	return c
+}
+
 func (c *ChartWidget) SetLabels(labels []string) *ChartWidget {
 	c.Labels = labels
 	return c
 }
 
 func (c *ChartWidget) AddSeries(name string, data []int) *ChartWidget {
-	c.Series = append(c.Series, ChartDataSet{Name: name, Data: data})
+	values := make([]float64, len(data))
+	for i, v := range data {
+		values[i] = float64(v)
+	}
+	return c.AddDataset(name, values)
+}
+
+// AddDataset adds a labeled series of values. Bar charts with several
+// datasets show them as grouped bars.
+func (c *ChartWidget) AddDataset(label string, data []float64) *ChartWidget {
+	c.Series = append(c.Series, ChartDataSet{Name: label, Data: data})
 	return c
 }
 
-// GetSeriesJSON returns the series as JSON for JavaScript.
-func (c *ChartWidget) GetSeriesJSON() string {
-	b, err := json.Marshal(c.Series)
+// circular reports whether the chart is a pie or a donut.
+func (c *ChartWidget) circular() bool {
+	return c.Type == Donut || c.Type == Pie
+}
+
+// seriesData returns the series in the shape ApexCharts expects: the
+// datasets for axis charts, a flat list of values for pies and donuts
+// (the values of a single dataset, or the first value of each dataset).
+func (c *ChartWidget) seriesData() any {
+	if !c.circular() {
+		return c.Series
+	}
+	if len(c.Series) == 1 {
+		return c.Series[0].Data
+	}
+	values := make([]float64, len(c.Series))
+	for i, s := range c.Series {
+		if len(s.Data) > 0 {
+			values[i] = s.Data[0]
+		}
+	}
+	return values
+}
+
+// labels returns the chart labels, defaulting to the dataset names for pies
+// and donuts with one value per dataset.
+func (c *ChartWidget) labels() []string {
+	if len(c.Labels) > 0 || !c.circular() || len(c.Series) < 2 {
+		return c.Labels
+	}
+	names := make([]string, len(c.Series))
+	for i, s := range c.Series {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// Config returns the ApexCharts options of the chart. The chart template
+// emits them in a JSON script block read by the page script.
+func (c *ChartWidget) Config() map[string]any {
+	height, err := strconv.Atoi(c.Height)
 	if err != nil {
-		return "[]"
+		height = 300
+	}
+	labels := c.labels()
+	if labels == nil {
+		labels = []string{}
+	}
+	config := map[string]any{
+		"series": c.seriesData(),
+		"chart": map[string]any{
+			"type":       string(c.Type),
+			"height":     height,
+			"fontFamily": "Inter, sans-serif",
+			"toolbar":    map[string]any{"show": false},
+			"background": "transparent",
+		},
+		"colors":     c.Colors,
+		"dataLabels": map[string]any{"enabled": false},
+		"legend":     map[string]any{"show": c.circular() || len(c.Series) > 1},
 	}
-	if c.Type == Donut {
-		simpleData := make([]int, len(c.Series))
-		for i, s := range c.Series {
-			if len(s.Data) > 0 {
-				simpleData[i] = s.Data[0]
-			}
+	if c.circular() {
+		config["labels"] = labels
+	} else {
+		if c.Type == Line {
+			config["stroke"] = map[string]any{"curve": "smooth", "width": 2}
 		}
-		if b2, e2 := json.Marshal(simpleData); e2 == nil {
-			b = b2
+		config["xaxis"] = map[string]any{
+			"categories": labels,
+			"axisBorder": map[string]any{"show": false},
+			"axisTicks":  map[string]any{"show": false},
 		}
+		config["grid"] = map[string]any{"borderColor": "#e5e7eb", "strokeDashArray": 4}
+	}
+	return config
+}
+
+// GetSeriesJSON returns the series as JSON for JavaScript.
+func (c *ChartWidget) GetSeriesJSON() string {
+	b, err := json.Marshal(c.seriesData())
+	if err != nil {
+		return "[]"
 	}
 	return string(b)
 }
@@ -93,6 +185,9 @@ func (c *ChartWidget) GetColorsJSON() string {
 
 func (c *ChartWidget) GetType() string { return "chart" }
 
+// GetColumnSpan implements Spanned.
+func (c *ChartWidget) GetColumnSpan() int { return c.Span }
+
 // chartRenderFunc is set by views/widgets to avoid import cycles.
 var chartRenderFunc func(*ChartWidget) templ.Component
 
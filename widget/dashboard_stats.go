@@ -7,7 +7,8 @@ import (
 )
 
 // GetDashboardStats generates widgets for the dashboard page.
-// By default, the dashboard is empty. Developers can add their own widgets here.
+// By default, the dashboard is empty. Rather than editing this function,
+// applications can add widgets with Register or RegisterWidget.
 func GetDashboardStats(ctx context.Context, client *ent.Client) []Widget {
 	// Empty dashboard by default.
 	// To add widgets, uncomment and customize the code below:
@@ -33,6 +34,11 @@ func GetDashboardStats(ctx context.Context, client *ent.Client) []Widget {
 		)
 		widgets = append(widgets, stats)
 
+		signups := NewChart("signups", "Sign-ups", Line).
+			SetLabels([]string{"Jan", "Feb", "Mar", "Apr"}).
+			AddDataset("Users", []float64{12, 19, 24, 31})
+		widgets = append(widgets, signups)
+
 		return widgets
 	*/
 
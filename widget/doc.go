@@ -1,12 +1,15 @@
 // Package widget provides dashboard widget components.
 //
-// It includes stats cards and chart widgets for building admin dashboards.
+// It includes stats cards, chart and table widgets for building admin dashboards.
 // Widgets are rendered as Templ components and support various configurations.
 //
 // Features:
 //   - Stats cards with icons and trends
 //   - Chart widgets (line, bar, pie) using ApexCharts
-//   - Customizable colors and sizes
+//   - Table widgets listing a few records
+//   - Customizable colors and sizes: ColumnSpan places a widget over 1 to 12
+//     columns of the dashboard grid (the full row by default), SetHeight
+//     gives its height in pixels
 //   - Trend indicators (up/down)
 //   - Responsive design
 //
@@ -19,14 +22,39 @@
 //		SetIcon("users").
 //		SetTrend("+12%", "up")
 //
-//	// Chart widget
-//	chart := widget.NewChart().
-//		SetType("line").
-//		SetTitle("Revenue").
-//		SetData(revenueData).
-//		SetHeight(300)
+//	// Chart widget (Line, Bar, Donut or Pie), one dataset per series;
+//	// several datasets on a bar chart show as grouped bars
+//	chart := widget.NewChart("revenue", "Revenue", widget.Bar).
+//		SetLabels([]string{"Q1", "Q2", "Q3", "Q4"}).
+//		AddDataset("2024", []float64{12.5, 18, 21, 30}).
+//		AddDataset("2025", []float64{15, 22.5, 26, 34})
+//
+//	// Table widget listing a few records, half the dashboard width
+//	latest := widget.NewTable("latest-orders", "Latest orders",
+//		table.Text("Ref"), table.Badge("Status")).
+//		WithItems(orders).
+//		ViewAll("/admin/orders").
+//		ColumnSpan(6)
 //
 //	// Render widgets
 //	stats.Render(ctx)
 //	chart.Render(ctx)
+//
+// Dashboard widgets are registered once, at startup, and built per request.
+// Restrict (or a widget implementing Viewable) hides sensitive widgets from
+// viewers who may not see them:
+//
+//	widget.RegisterWidget("revenue", 10, func(ctx context.Context) widget.Widget {
+//		return widget.Restrict(revenueChart(ctx), func(ctx context.Context) bool {
+//			return auth.UserFromContext(ctx).HasRole("admin")
+//		})
+//	})
+//
+// PollInterval keeps a widget live: the dashboard fetches it again from
+// {panel path}/api/widgets/{id} and swaps it in without reloading the page.
+// The ID is the provider's, suffixed with "-1", "-2", ... when it returns
+// several widgets:
+//
+//	widget.RegisterWidget("latest-orders", 20, latestOrders).
+//		PollInterval(30 * time.Second)
 package widget
@@ -0,0 +1,40 @@
+package widget
+
+import "context"
+
+// Viewable is an optional interface for widgets and providers restricted to
+// some viewers, e.g. a revenue chart shown to admins only. GetAllWidgets
+// leaves out those whose CanView returns false.
+type Viewable interface {
+	CanView(ctx context.Context) bool
+}
+
+// restricted wraps a widget with a view permission.
+type restricted struct {
+	Widget
+	canView func(ctx context.Context) bool
+}
+
+func (r *restricted) CanView(ctx context.Context) bool { return r.canView(ctx) }
+
+// Restrict returns w shown only when canView allows it.
+func Restrict(w Widget, canView func(ctx context.Context) bool) Widget {
+	return &restricted{Widget: w, canView: canView}
+}
+
+// RegisterWidget registers a single widget under id, built per request so
+// its values stay current. Use Restrict on the built widget, or implement
+// Viewable, to limit who sees it.
+func RegisterWidget(id string, priority int, build func(ctx context.Context) Widget) *BaseProvider {
+	p := NewProvider(id).SetPriority(priority).WithWidgets(func(ctx context.Context) []Widget {
+		return []Widget{build(ctx)}
+	})
+	Register(p)
+	return p
+}
+
+// canView reports whether v may be shown in ctx.
+func canView(ctx context.Context, v any) bool {
+	viewable, ok := v.(Viewable)
+	return !ok || viewable.CanView(ctx)
+}
@@ -0,0 +1,74 @@
+package widget
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Live is implemented by the dashboard widgets refreshed in place: the page
+// fetches /api/widgets/{id} every poll interval and swaps in the new HTML.
+// GetAllWidgets returns the widgets of providers with a PollInterval as Live.
+type Live interface {
+	Widget
+	GetID() string
+	GetPollInterval() time.Duration
+}
+
+// poller is the optional interface of providers whose widgets are polled.
+type poller interface {
+	GetPollInterval() time.Duration
+}
+
+// live attaches the ID and poll interval of its provider to a widget.
+type live struct {
+	Widget
+	id       string
+	interval time.Duration
+}
+
+func (l *live) GetID() string                  { return l.id }
+func (l *live) GetPollInterval() time.Duration { return l.interval }
+
+// pollInterval returns the poll interval of p, 0 for static widgets.
+func pollInterval(p Provider) time.Duration {
+	if pp, ok := p.(poller); ok {
+		return pp.GetPollInterval()
+	}
+	return 0
+}
+
+// widgetID returns the ID of the i-th of n widgets of the provider id: the
+// provider ID itself for single-widget providers (see RegisterWidget).
+func widgetID(id string, i, n int) string {
+	if n == 1 {
+		return id
+	}
+	return id + "-" + strconv.Itoa(i+1)
+}
+
+// Find builds the widgets of the registered providers for ctx and returns
+// the Live widget with the given ID, so that its data is fetched again.
+// Returns nil if no enabled provider has such a widget for the viewer.
+func Find(ctx context.Context, id string) Live {
+	for _, w := range GetAllWidgets(ctx) {
+		if l, ok := w.(Live); ok && l.GetID() == id {
+			return l
+		}
+	}
+	return nil
+}
+
+// unwrap returns the widget wrapped by Restrict or a polling provider.
+func unwrap(w Widget) Widget {
+	for {
+		switch v := w.(type) {
+		case *restricted:
+			w = v.Widget
+		case *live:
+			w = v.Widget
+		default:
+			return w
+		}
+	}
+}
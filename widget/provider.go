@@ -4,6 +4,7 @@ import (
 	"context"
 	"sort"
 	"sync"
+	"time"
 )
 
 // Provider is the interface for declarative dashboard widget providers.
@@ -24,6 +25,8 @@ type BaseProvider struct {
 	priority int
 	enabled  bool
 	widgets  func(ctx context.Context) []Widget
+	canView  func(ctx context.Context) bool
+	poll     time.Duration
 }
 
 // NewProvider creates a new widget provider.
@@ -46,6 +49,30 @@ func (p *BaseProvider) GetWidgets(ctx context.Context) []Widget {
 	return []Widget{}
 }
 
+// CanView implements Viewable: every viewer is allowed unless SetCanView
+// was called.
+func (p *BaseProvider) CanView(ctx context.Context) bool {
+	return p.canView == nil || p.canView(ctx)
+}
+
+// SetCanView restricts all widgets of the provider to viewers for whom fn
+// returns true.
+func (p *BaseProvider) SetCanView(fn func(ctx context.Context) bool) *BaseProvider {
+	p.canView = fn
+	return p
+}
+
+// PollInterval makes the dashboard refresh the widgets of the provider
+// every d, without reloading the page. Static widgets (the default) are
+// rendered once.
+func (p *BaseProvider) PollInterval(d time.Duration) *BaseProvider {
+	p.poll = d
+	return p
+}
+
+// GetPollInterval returns the refresh interval, 0 for static widgets.
+func (p *BaseProvider) GetPollInterval() time.Duration { return p.poll }
+
 // SetPriority sets the display priority.
 func (p *BaseProvider) SetPriority(priority int) *BaseProvider {
 	p.priority = priority
@@ -96,14 +123,27 @@ func GetProviders() []Provider {
 	return sorted
 }
 
-// GetAllWidgets returns all widgets from all enabled providers.
+// GetAllWidgets returns the widgets of all enabled providers that the
+// viewer in ctx may see (see Viewable). Widgets of providers with a poll
+// interval are returned as Live.
 func GetAllWidgets(ctx context.Context) []Widget {
 	providers := GetProviders()
 	var allWidgets []Widget
 	
 	for _, p := range providers {
-		if p.IsEnabled(ctx) {
-			allWidgets = append(allWidgets, p.GetWidgets(ctx)...)
+		if !p.IsEnabled(ctx) || !canView(ctx, p) {
+			continue
+		}
+		widgets := p.GetWidgets(ctx)
+		interval := pollInterval(p)
+		for i, w := range widgets {
+			if !canView(ctx, w) {
+				continue
+			}
+			if interval > 0 {
+				w = &live{Widget: w, id: widgetID(p.GetID(), i, len(widgets)), interval: interval}
+			}
+			allWidgets = append(allWidgets, w)
 		}
 	}
 	
@@ -1,6 +1,10 @@
 package widget
 
-import "github.com/a-h/templ"
+import (
+	"strconv"
+
+	"github.com/a-h/templ"
+)
 
 // Widget is the interface all dashboard widgets must implement.
 type Widget interface {
@@ -8,6 +12,27 @@ type Widget interface {
 	Render() templ.Component
 }
 
+// Spanned is an optional interface for widgets that take only part of a
+// dashboard row, e.g. a table next to a chart.
+type Spanned interface {
+	// GetColumnSpan returns the grid columns taken, out of 12.
+	GetColumnSpan() int
+}
+
+// clampSpan limits a column span to the 12 columns of the dashboard grid.
+func clampSpan(n int) int {
+	return max(1, min(12, n))
+}
+
+// ColumnSpanOf returns the dashboard grid columns w takes, out of 12: its
+// GetColumnSpan if it implements Spanned, the full row otherwise.
+func ColumnSpanOf(w Widget) int {
+	if s, ok := unwrap(w).(Spanned); ok && s.GetColumnSpan() > 0 {
+		return min(12, s.GetColumnSpan())
+	}
+	return 12
+}
+
 // Stat represents a single statistic card.
 type Stat struct {
 	Label       string
@@ -21,7 +46,9 @@ type Stat struct {
 
 // StatsWidget is a container for multiple stats.
 type StatsWidget struct {
-	Stats []Stat
+	Stats  []Stat
+	Span   int    // dashboard grid columns, 1-12, 0 for the full row
+	Height string // minimum card height in pixels, "" for auto
 }
 
 // NewStats creates a new statistics widget.
@@ -29,8 +56,24 @@ func NewStats(stats ...Stat) *StatsWidget {
 	return &StatsWidget{Stats: stats}
 }
 
+// ColumnSpan sets how many of the 12 dashboard grid columns the widget
+// takes on large screens. Stats span the full row by default.
+func (s *StatsWidget) ColumnSpan(n int) *StatsWidget {
+	s.Span = clampSpan(n)
+	return s
+}
+
+// SetHeight sets the minimum height of the stat cards, in pixels.
+func (s *StatsWidget) SetHeight(px int) *StatsWidget {
+	s.Height = strconv.Itoa(px)
+	return s
+}
+
 func (s *StatsWidget) GetType() string { return "stats" }
 
+// GetColumnSpan implements Spanned.
+func (s *StatsWidget) GetColumnSpan() int { return s.Span }
+
 // renderFunc is set by the views/widgets package to avoid import cycles.
 var statsRenderFunc func(*StatsWidget) templ.Component
 
@@ -0,0 +1,91 @@
+package widget
+
+import (
+	"strconv"
+
+	"github.com/a-h/templ"
+	"github.com/bozz33/sublimego/table"
+)
+
+// TableWidget lists a few records on the dashboard, e.g. the latest five
+// orders, with the cells of the resource table columns.
+type TableWidget struct {
+	ID           string
+	Label        string
+	Columns      []table.Column
+	Items        []any
+	Span         int    // dashboard grid columns, 1-12
+	Height       string // maximum height of the rows in pixels, "" for auto
+	ViewAllURL   string // link to the full resource list, "" for none
+	ViewAllLabel string
+	EmptyText    string
+}
+
+// NewTable creates a table widget showing columns.
+func NewTable(id, label string, columns ...table.Column) *TableWidget {
+	return &TableWidget{
+		ID:           id,
+		Label:        label,
+		Columns:      columns,
+		Span:         12,
+		ViewAllLabel: "View all",
+		EmptyText:    "No records yet",
+	}
+}
+
+// WithItems sets the records listed, one per row.
+func (t *TableWidget) WithItems(items []any) *TableWidget {
+	t.Items = items
+	return t
+}
+
+// ColumnSpan sets how many of the 12 dashboard grid columns the widget
+// takes on large screens, e.g. 6 to sit next to another half-width widget.
+func (t *TableWidget) ColumnSpan(n int) *TableWidget {
+	t.Span = clampSpan(n)
+	return t
+}
+
+// SetHeight caps the height of the rows, in pixels; longer lists scroll.
+func (t *TableWidget) SetHeight(px int) *TableWidget {
+	t.Height = strconv.Itoa(px)
+	return t
+}
+
+// ViewAll adds a "View all" link to url, usually the resource list.
+func (t *TableWidget) ViewAll(url string) *TableWidget {
+	t.ViewAllURL = url
+	return t
+}
+
+// WithViewAllLabel sets the label of the "View all" link.
+func (t *TableWidget) WithViewAllLabel(label string) *TableWidget {
+	t.ViewAllLabel = label
+	return t
+}
+
+// WithEmptyText sets the text shown when there are no items.
+func (t *TableWidget) WithEmptyText(text string) *TableWidget {
+	t.EmptyText = text
+	return t
+}
+
+func (t *TableWidget) GetType() string { return "table" }
+
+// GetColumnSpan implements Spanned.
+func (t *TableWidget) GetColumnSpan() int { return t.Span }
+
+// tableRenderFunc is set by views/widgets to avoid import cycles.
+var tableRenderFunc func(*TableWidget) templ.Component
+
+// SetTableRenderer registers the render function.
+func SetTableRenderer(fn func(*TableWidget) templ.Component) {
+	tableRenderFunc = fn
+}
+
+func (t *TableWidget) Render() templ.Component {
+	if tableRenderFunc != nil {
+		return tableRenderFunc(t)
+	}
+	return templ.NopComponent
+}
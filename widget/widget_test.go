@@ -1,7 +1,12 @@
 package widget
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/bozz33/sublimego/table"
 )
 
 func TestNewStats(t *testing.T) {
@@ -166,3 +171,200 @@ func TestChartTypes(t *testing.T) {
 		t.Errorf("Expected Donut to be 'donut', got '%s'", Donut)
 	}
 }
+
+type adminOnly struct{ *StatsWidget }
+
+func (adminOnly) CanView(ctx context.Context) bool { return ctx.Value(roleKey{}) == "admin" }
+
+type roleKey struct{}
+
+func TestGetAllWidgets_CanView(t *testing.T) {
+	Clear()
+	defer Clear()
+
+	isAdmin := func(ctx context.Context) bool { return ctx.Value(roleKey{}) == "admin" }
+	RegisterWidget("users", 1, func(context.Context) Widget { return NewStats(Stat{Label: "Users"}) })
+	RegisterWidget("revenue", 2, func(context.Context) Widget { return Restrict(NewStats(Stat{Label: "Revenue"}), isAdmin) })
+	Register(NewProvider("orders").SetPriority(3).WithWidgets(func(context.Context) []Widget {
+		return []Widget{adminOnly{NewStats(Stat{Label: "Orders"})}}
+	}))
+	Register(NewProvider("finance").SetPriority(4).SetCanView(isAdmin).WithWidgets(func(context.Context) []Widget {
+		return []Widget{NewStats(Stat{Label: "Margin"})}
+	}))
+
+	labels := func(ctx context.Context) []string {
+		var out []string
+		for _, w := range GetAllWidgets(ctx) {
+			if s, ok := w.(*StatsWidget); ok {
+				out = append(out, s.Stats[0].Label)
+			} else {
+				out = append(out, w.GetType())
+			}
+		}
+		return out
+	}
+
+	if got := labels(context.Background()); len(got) != 1 || got[0] != "Users" {
+		t.Errorf("Expected only [Users] for a regular viewer, got %v", got)
+	}
+	admin := context.WithValue(context.Background(), roleKey{}, "admin")
+	if got := labels(admin); len(got) != 4 {
+		t.Errorf("Expected 4 widgets for an admin, got %v", got)
+	}
+}
+
+func TestChartAddDataset(t *testing.T) {
+	chart := NewChart("sales", "Sales", Bar).
+		SetLabels([]string{"Q1", "Q2"}).
+		AddDataset("2024", []float64{1.5, 2}).
+		AddDataset("2025", []float64{3, 4.25})
+
+	if len(chart.Series) != 2 {
+		t.Fatalf("Expected 2 datasets, got %d", len(chart.Series))
+	}
+	if json := chart.GetSeriesJSON(); json != `[{"name":"2024","data":[1.5,2]},{"name":"2025","data":[3,4.25]}]` {
+		t.Errorf("Unexpected series JSON: %s", json)
+	}
+
+	config := chart.Config()
+	if config["chart"].(map[string]any)["type"] != "bar" {
+		t.Errorf("Expected chart type 'bar', got %v", config["chart"])
+	}
+	if got := config["xaxis"].(map[string]any)["categories"].([]string); len(got) != 2 || got[0] != "Q1" {
+		t.Errorf("Expected the labels as categories, got %v", got)
+	}
+	if config["legend"].(map[string]any)["show"] != true {
+		t.Error("Expected a legend for grouped bars")
+	}
+}
+
+func TestChartPie(t *testing.T) {
+	chart := NewChart("share", "Share", Pie).
+		SetLabels([]string{"Web", "Store"}).
+		AddDataset("Orders", []float64{60, 40})
+
+	if json := chart.GetSeriesJSON(); json != "[60,40]" {
+		t.Errorf("Expected the dataset values, got '%s'", json)
+	}
+	config := chart.Config()
+	if got := config["labels"].([]string); len(got) != 2 || got[1] != "Store" {
+		t.Errorf("Expected the pie labels, got %v", got)
+	}
+	if _, ok := config["xaxis"]; ok {
+		t.Error("Expected no x axis on a pie chart")
+	}
+
+	// One value per dataset: the dataset names label the slices.
+	chart = NewChart("share", "Share", Pie).
+		AddSeries("Web", []int{60}).
+		AddSeries("Store", []int{40})
+	if got := chart.Config()["labels"].([]string); len(got) != 2 || got[0] != "Web" {
+		t.Errorf("Expected the dataset names as labels, got %v", got)
+	}
+}
+
+func TestNewTable(t *testing.T) {
+	w := NewTable("latest-orders", "Latest orders", table.Text("Ref"), table.Badge("Status")).
+		WithItems([]any{map[string]any{"Ref": "A1"}}).
+		ViewAll("/admin/orders")
+
+	if w.GetType() != "table" {
+		t.Errorf("Expected type 'table', got '%s'", w.GetType())
+	}
+	if len(w.Columns) != 2 || len(w.Items) != 1 {
+		t.Errorf("Expected 2 columns and 1 item, got %d and %d", len(w.Columns), len(w.Items))
+	}
+	if w.ViewAllURL != "/admin/orders" || w.ViewAllLabel != "View all" {
+		t.Errorf("Unexpected view all link: %q %q", w.ViewAllURL, w.ViewAllLabel)
+	}
+	if w.Span != 12 {
+		t.Errorf("Expected a full-width table by default, got span %d", w.Span)
+	}
+}
+
+func TestColumnSpanOf(t *testing.T) {
+	if got := ColumnSpanOf(NewTable("t", "T").ColumnSpan(6)); got != 6 {
+		t.Errorf("Expected span 6, got %d", got)
+	}
+	if got := ColumnSpanOf(NewTable("t", "T").ColumnSpan(20)); got != 12 {
+		t.Errorf("Expected the span clamped to 12, got %d", got)
+	}
+	if got := ColumnSpanOf(NewStats()); got != 12 {
+		t.Errorf("Expected stats to span the full row, got %d", got)
+	}
+	restricted := Restrict(NewTable("t", "T").ColumnSpan(4), func(context.Context) bool { return true })
+	if got := ColumnSpanOf(restricted); got != 4 {
+		t.Errorf("Expected the span of the restricted widget, got %d", got)
+	}
+}
+
+func TestPollInterval(t *testing.T) {
+	Clear()
+	defer Clear()
+
+	calls := 0
+	RegisterWidget("orders", 10, func(ctx context.Context) Widget {
+		calls++
+		return NewTable("orders", "Orders").ColumnSpan(6)
+	}).PollInterval(30 * time.Second)
+	Register(NewProvider("sales").SetPriority(20).WithWidgets(func(ctx context.Context) []Widget {
+		return []Widget{NewStats(), NewStats()}
+	}).PollInterval(time.Minute))
+	RegisterWidget("static", 30, func(ctx context.Context) Widget { return NewStats() })
+
+	widgets := GetAllWidgets(context.Background())
+	if len(widgets) != 4 {
+		t.Fatalf("Expected 4 widgets, got %d", len(widgets))
+	}
+	var ids []string
+	for _, w := range widgets {
+		if l, ok := w.(Live); ok {
+			ids = append(ids, l.GetID())
+		}
+	}
+	if strings.Join(ids, ",") != "orders,sales-1,sales-2" {
+		t.Errorf("Expected live widgets orders, sales-1, sales-2, got %v", ids)
+	}
+	if _, ok := widgets[3].(*StatsWidget); !ok {
+		t.Errorf("Expected the static widget unwrapped, got %T", widgets[3])
+	}
+	if got := ColumnSpanOf(widgets[0]); got != 6 {
+		t.Errorf("Expected the span of the polled widget, got %d", got)
+	}
+
+	calls = 0
+	w := Find(context.Background(), "orders")
+	if w == nil || w.GetPollInterval() != 30*time.Second || w.GetType() != "table" {
+		t.Fatalf("Expected the orders widget polled every 30s, got %v", w)
+	}
+	if calls != 1 {
+		t.Errorf("Expected the widget built again, got %d calls", calls)
+	}
+	if Find(context.Background(), "static") != nil {
+		t.Error("Expected static widgets not to be found")
+	}
+	if Find(context.Background(), "missing") != nil {
+		t.Error("Expected nil for an unknown ID")
+	}
+}
+
+func TestWidgetLayout(t *testing.T) {
+	stats := NewStats().ColumnSpan(4).SetHeight(140)
+	chart := NewChart("c", "C", Bar).ColumnSpan(8).SetHeight(240)
+	table := NewTable("t", "T").ColumnSpan(0).SetHeight(320)
+
+	for _, tt := range []struct {
+		w    Widget
+		want int
+	}{{stats, 4}, {chart, 8}, {table, 1}, {NewChart("d", "D", Line), 12}} {
+		if got := ColumnSpanOf(tt.w); got != tt.want {
+			t.Errorf("%s: expected span %d, got %d", tt.w.GetType(), tt.want, got)
+		}
+	}
+	if stats.Height != "140" || chart.Height != "240" || table.Height != "320" {
+		t.Errorf("Unexpected heights: %q %q %q", stats.Height, chart.Height, table.Height)
+	}
+	if got := chart.Config()["chart"].(map[string]any)["height"]; got != 240 {
+		t.Errorf("Expected the chart height in its options, got %v", got)
+	}
+}